@@ -0,0 +1,197 @@
+// Command heartsteal-admin manages users, sessions, migrations and seed data
+// directly against the configured database. It reads the same configuration
+// as the server, so every server flag (e.g. --db-name=heartsteal) works here
+// too. Logs go to stderr; results go to stdout as JSON or a table.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/bootstrap"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/repository"
+	"github.com/Simpolette/HeartSteal/server/internal/usecase"
+	"github.com/spf13/pflag"
+)
+
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+var errUsage = errors.New("usage")
+
+// command is one "<group> <action>" invocation. run returns the value printed
+// on success.
+type command struct {
+	name  string
+	args  string
+	help  string
+	flags func(*pflag.FlagSet)
+	run   func(ctx context.Context, env *cli, flags *pflag.FlagSet, args []string) (any, error)
+}
+
+var commands = []command{
+	usersCreate,
+	usersResetPassword,
+	usersGrantRole,
+	usersRevokeRole,
+	usersBan,
+	usersUnban,
+	sessionsList,
+	migrateUp,
+	migrateStatus,
+	seedFile,
+	seedGenerate,
+	seedClean,
+}
+
+// cli is what commands run against once the application is connected.
+type cli struct {
+	app    *bootstrap.Application
+	repos  repository.Repositories
+	admin  domain.AdminUsecase
+	dryRun bool
+	stdin  io.Reader
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	cmd, rest := findCommand(args)
+	if cmd == nil {
+		usage(stderr)
+		if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	flags, opts := newFlagSet(cmd)
+	if err := flags.Parse(rest); err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", cmd.name, err)
+		commandUsage(stderr, cmd, flags)
+		return exitUsage
+	}
+	if opts.help {
+		commandUsage(stderr, cmd, flags)
+		return exitOK
+	}
+	if opts.output != "json" && opts.output != "table" {
+		fmt.Fprintf(stderr, "--output must be json or table, got %q\n", opts.output)
+		return exitUsage
+	}
+
+	app := bootstrap.App(bootstrap.WithArgs(rest), bootstrap.WithLogOutput(stderr))
+	defer app.CloseDBConnection()
+	defer app.CloseRedisConnection()
+	defer app.CloseTracing(context.Background())
+
+	env := newCLI(&app, repository.NewMongoRepositories(app.Mongo.Database(app.Env.DBName)), opts.dryRun, stdin)
+	return execute(context.Background(), cmd, flags, env, opts.output, stdout, stderr)
+}
+
+// globalOptions are the flags every command accepts.
+type globalOptions struct {
+	output string
+	dryRun bool
+	help   bool
+}
+
+// newFlagSet builds the flags of cmd. Configuration flags are accepted so any
+// server setting can be passed, but anything else unknown is an error: a
+// mistyped --dry-run must never run the command for real.
+func newFlagSet(cmd *command) (*pflag.FlagSet, *globalOptions) {
+	opts := &globalOptions{}
+	flags := pflag.NewFlagSet(cmd.name, pflag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.StringVarP(&opts.output, "output", "o", "table", "output format: json or table")
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "report what would change without writing anything")
+	flags.BoolVarP(&opts.help, "help", "h", false, "show help for the command")
+	if cmd.flags != nil {
+		cmd.flags(flags)
+	}
+	bootstrap.AddEnvFlags(flags)
+	return flags, opts
+}
+
+// newCLI wires the commands to repos. With dryRun every write is only logged.
+func newCLI(app *bootstrap.Application, repos repository.Repositories, dryRun bool, stdin io.Reader) *cli {
+	if dryRun {
+		repos = repository.DryRun(repos, app.Logger)
+	}
+	return &cli{
+		app:    app,
+		repos:  repos,
+		admin:  usecase.NewAdminUseCase(repos.User, repos.Session, time.Duration(app.Env.ContextTimeout)*time.Second),
+		dryRun: dryRun,
+		stdin:  stdin,
+	}
+}
+
+// execute runs cmd once the application is connected and prints its result.
+func execute(ctx context.Context, cmd *command, flags *pflag.FlagSet, env *cli, output string, stdout, stderr io.Writer) int {
+	result, err := cmd.run(ctx, env, flags, flags.Args())
+	if errors.Is(err, errUsage) {
+		commandUsage(stderr, cmd, flags)
+		return exitUsage
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", cmd.name, err)
+		return exitError
+	}
+
+	if err := render(stdout, output, result); err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", cmd.name, err)
+		return exitError
+	}
+	if env.dryRun {
+		fmt.Fprintln(stderr, "dry run: nothing was written")
+	}
+	return exitOK
+}
+
+// findCommand matches the longest command name at the start of args, so
+// "migrate" alone selects "migrate up".
+func findCommand(args []string) (*command, []string) {
+	for n := min(2, len(args)); n > 0; n-- {
+		name := strings.Join(args[:n], " ")
+		for i := range commands {
+			if commands[i].name == name {
+				return &commands[i], args[n:]
+			}
+		}
+		if name == "migrate" {
+			return &migrateUp, args[n:]
+		}
+	}
+	return nil, args
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: heartsteal-admin <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-40s %s\n", strings.TrimSpace(cmd.name+" "+cmd.args), cmd.help)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Global flags:")
+	fmt.Fprintln(w, "  -o, --output json|table   output format (default table)")
+	fmt.Fprintln(w, "      --dry-run             report what would change without writing anything")
+	fmt.Fprintln(w, "      --config path         dotenv config file; any server setting can also be passed as a flag")
+}
+
+func commandUsage(w io.Writer, cmd *command, flags *pflag.FlagSet) {
+	fmt.Fprintf(w, "Usage: heartsteal-admin %s [flags]\n\n%s\n\nFlags:\n%s",
+		strings.TrimSpace(cmd.name+" "+cmd.args), cmd.help, flags.FlagUsages())
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Simpolette/HeartSteal/server/internal/bootstrap"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/repository"
+	"github.com/Simpolette/HeartSteal/server/internal/repository/memory"
+)
+
+func TestFindCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+		rest []string
+	}{
+		{name: "TwoWords", args: []string{"users", "ban", "johndoe"}, want: "users ban", rest: []string{"johndoe"}},
+		{name: "OneWord", args: []string{"seed", "--file", "users.json"}, want: "seed", rest: []string{"--file", "users.json"}},
+		{name: "LongestMatch", args: []string{"seed", "generate"}, want: "seed generate", rest: []string{}},
+		{name: "MigrateAlias", args: []string{"migrate", "--dry-run"}, want: "migrate up", rest: []string{"--dry-run"}},
+		{name: "Unknown", args: []string{"users", "delete"}, rest: []string{"users", "delete"}},
+		{name: "Empty", args: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, rest := findCommand(tt.args)
+
+			if tt.want == "" {
+				assert.Nil(t, cmd)
+			} else {
+				require.NotNil(t, cmd)
+				assert.Equal(t, tt.want, cmd.name)
+			}
+			assert.Equal(t, tt.rest, rest)
+		})
+	}
+}
+
+// TestRun covers what run decides before it connects to the database.
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stderr string
+	}{
+		{name: "NoArgs", args: nil, code: exitOK, stderr: "Usage: heartsteal-admin <command>"},
+		{name: "Help", args: []string{"help"}, code: exitOK, stderr: "Commands:"},
+		{name: "UnknownCommand", args: []string{"users", "delete"}, code: exitUsage, stderr: "Usage: heartsteal-admin <command>"},
+		{name: "CommandHelp", args: []string{"users", "ban", "--help"}, code: exitOK, stderr: "--reason"},
+		{name: "MistypedDryRun", args: []string{"users", "ban", "johndoe", "--reason", "spam", "--dryrun"}, code: exitUsage, stderr: "unknown flag: --dryrun"},
+		{name: "UnknownShorthand", args: []string{"users", "unban", "johndoe", "-n"}, code: exitUsage, stderr: "unknown shorthand flag: 'n'"},
+		{name: "InvalidOutput", args: []string{"sessions", "list", "johndoe", "-o", "yaml"}, code: exitUsage, stderr: "--output must be json or table"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			code := run(tt.args, strings.NewReader(""), &stdout, &stderr)
+
+			assert.Equal(t, tt.code, code)
+			assert.Contains(t, stderr.String(), tt.stderr)
+			assert.Empty(t, stdout.String())
+		})
+	}
+}
+
+func TestNewFlagSet(t *testing.T) {
+	t.Run("EveryCommand", func(t *testing.T) {
+		for i := range commands {
+			assert.NotPanics(t, func() { newFlagSet(&commands[i]) }, "%s flags clash with the configuration flags", commands[i].name)
+		}
+	})
+
+	t.Run("ConfigurationFlags", func(t *testing.T) {
+		flags, opts := newFlagSet(&usersBan)
+
+		err := flags.Parse([]string{"johndoe", "--db-name", "heartsteal", "--config=admin.env", "--reason", "spam", "--dry-run"})
+
+		require.NoError(t, err)
+		assert.True(t, opts.dryRun)
+		assert.Equal(t, []string{"johndoe"}, flags.Args())
+		reason, _ := flags.GetString("reason")
+		assert.Equal(t, "spam", reason)
+	})
+
+	t.Run("ConfigurationFlagsHidden", func(t *testing.T) {
+		flags, _ := newFlagSet(&usersBan)
+
+		assert.NotContains(t, flags.FlagUsages(), "db-name")
+	})
+}
+
+func TestRender(t *testing.T) {
+	id, _ := primitive.ObjectIDFromHex("64b7f0c2a1b2c3d4e5f60718")
+	user := &domain.User{ID: id, Username: "johndoe", Email: "john@example.com", Roles: []domain.Role{domain.RoleAdmin}}
+
+	t.Run("Table", func(t *testing.T) {
+		var out bytes.Buffer
+
+		require.NoError(t, render(&out, "table", user))
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 2)
+		assert.Equal(t, []string{"ID", "USERNAME", "EMAIL", "ROLES", "BANNED"}, strings.Fields(lines[0]))
+		assert.Equal(t, []string{id.Hex(), "johndoe", "john@example.com", "player,admin", "-"}, strings.Fields(lines[1]))
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		var out bytes.Buffer
+
+		require.NoError(t, render(&out, "json", []seedResult{{Username: "johndoe", Status: "created"}}))
+
+		var decoded []seedResult
+		require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+		assert.Equal(t, []seedResult{{Username: "johndoe", Status: "created"}}, decoded)
+	})
+
+	t.Run("Fallback", func(t *testing.T) {
+		var out bytes.Buffer
+
+		require.NoError(t, render(&out, "table", 3))
+
+		assert.Equal(t, []string{"RESULT", "3"}, strings.Fields(out.String()))
+	})
+}
+
+func newTestCLI(repos repository.Repositories, dryRun bool) *cli {
+	app := &bootstrap.Application{
+		Env:    &bootstrap.Env{ContextTimeout: 2},
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	return newCLI(app, repos, dryRun, strings.NewReader(""))
+}
+
+func TestExecute(t *testing.T) {
+	ctx := context.Background()
+	createArgs := []string{"--username", "johndoe", "--email", "john@example.com", "--password", "Sup3rSecret!"}
+
+	t.Run("Writes", func(t *testing.T) {
+		repos := repository.Repositories{User: memory.NewUserRepository(), Session: memory.NewSessionRepository()}
+		flags, _ := newFlagSet(&usersCreate)
+		require.NoError(t, flags.Parse(createArgs))
+		var stdout, stderr bytes.Buffer
+
+		code := execute(ctx, &usersCreate, flags, newTestCLI(repos, false), "json", &stdout, &stderr)
+
+		require.Equal(t, exitOK, code, stderr.String())
+		_, err := repos.User.GetByUsername(ctx, "johndoe")
+		assert.NoError(t, err)
+		assert.NotContains(t, stderr.String(), "dry run")
+	})
+
+	t.Run("DryRunWritesNothing", func(t *testing.T) {
+		repos := repository.Repositories{User: memory.NewUserRepository(), Session: memory.NewSessionRepository()}
+		flags, _ := newFlagSet(&usersCreate)
+		require.NoError(t, flags.Parse(append(createArgs, "--dry-run")))
+		var stdout, stderr bytes.Buffer
+
+		code := execute(ctx, &usersCreate, flags, newTestCLI(repos, true), "json", &stdout, &stderr)
+
+		require.Equal(t, exitOK, code, stderr.String())
+		var user domain.User
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &user))
+		assert.Equal(t, "johndoe", user.Username, "prints what would be created")
+		_, err := repos.User.GetByUsername(ctx, "johndoe")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+		assert.Contains(t, stderr.String(), "dry run: nothing was written")
+	})
+
+	t.Run("DryRunStillChecks", func(t *testing.T) {
+		repos := repository.Repositories{User: memory.NewUserRepository(), Session: memory.NewSessionRepository()}
+		flags, _ := newFlagSet(&usersBan)
+		require.NoError(t, flags.Parse([]string{"johndoe", "--reason", "spam"}))
+		var stdout, stderr bytes.Buffer
+
+		code := execute(ctx, &usersBan, flags, newTestCLI(repos, true), "table", &stdout, &stderr)
+
+		assert.Equal(t, exitError, code)
+		assert.Contains(t, stderr.String(), domain.ErrUserNotFound.Error())
+	})
+
+	t.Run("Usage", func(t *testing.T) {
+		flags, _ := newFlagSet(&usersBan)
+		require.NoError(t, flags.Parse([]string{"johndoe"}))
+		var stdout, stderr bytes.Buffer
+
+		code := execute(ctx, &usersBan, flags, newTestCLI(repository.Repositories{}, false), "table", &stdout, &stderr)
+
+		assert.Equal(t, exitUsage, code)
+		assert.Contains(t, stderr.String(), "Usage: heartsteal-admin users ban <username>")
+	})
+}
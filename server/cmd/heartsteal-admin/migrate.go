@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+
+	"github.com/Simpolette/HeartSteal/server/internal/migration"
+	"github.com/spf13/pflag"
+)
+
+var migrateUp = command{
+	name: "migrate up",
+	help: "apply pending database migrations (also: migrate)",
+	run: func(ctx context.Context, env *cli, _ *pflag.FlagSet, args []string) (any, error) {
+		if len(args) != 0 {
+			return nil, errUsage
+		}
+		migrator, err := newMigrator(env)
+		if err != nil {
+			return nil, err
+		}
+		applied, err := migrator.Up(ctx, env.dryRun)
+		return migrationResults(applied), err
+	},
+}
+
+var migrateStatus = command{
+	name: "migrate status",
+	help: "list migrations and when they were applied",
+	run: func(ctx context.Context, env *cli, _ *pflag.FlagSet, args []string) (any, error) {
+		if len(args) != 0 {
+			return nil, errUsage
+		}
+		migrator, err := newMigrator(env)
+		if err != nil {
+			return nil, err
+		}
+		return migrator.Status(ctx)
+	},
+}
+
+func newMigrator(env *cli) (*migration.Migrator, error) {
+	return migration.NewMigrator(env.app.Mongo.Database(env.app.Env.DBName), migration.All)
+}
+
+// migrationResult is what "migrate up" prints for each migration it ran or,
+// with --dry-run, would run.
+type migrationResult struct {
+	Version int    `json:"version"`
+	Name    string `json:"name"`
+}
+
+func migrationResults(migrations []migration.Migration) []migrationResult {
+	results := make([]migrationResult, 0, len(migrations))
+	for _, m := range migrations {
+		results = append(results, migrationResult{Version: m.Version, Name: m.Name})
+	}
+	return results
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/migration"
+	"github.com/Simpolette/HeartSteal/server/internal/seed"
+)
+
+func render(w io.Writer, format string, v any) error {
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	header, rows := tableRows(v)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func tableRows(v any) ([]string, [][]string) {
+	switch v := v.(type) {
+	case *domain.User:
+		banned := "-"
+		if v.IsBanned() {
+			banned = formatTime(*v.BannedAt) + " (" + v.BanReason + ")"
+		}
+		roles := []string{string(domain.RolePlayer)}
+		for _, r := range v.Roles {
+			roles = append(roles, string(r))
+		}
+		return []string{"ID", "USERNAME", "EMAIL", "ROLES", "BANNED"}, [][]string{
+			{v.ID.Hex(), v.Username, v.Email, strings.Join(roles, ","), banned},
+		}
+	case []domain.Session:
+		rows := make([][]string, 0, len(v))
+		for _, s := range v {
+			rows = append(rows, []string{s.ID.Hex(), formatTime(s.CreatedAt), formatTime(s.ExpiresAt)})
+		}
+		return []string{"ID", "CREATED", "EXPIRES"}, rows
+	case []migration.Status:
+		rows := make([][]string, 0, len(v))
+		for _, s := range v {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = formatTime(*s.AppliedAt)
+			}
+			rows = append(rows, []string{strconv.Itoa(s.Version), s.Name, applied})
+		}
+		return []string{"VERSION", "NAME", "APPLIED"}, rows
+	case []migrationResult:
+		rows := make([][]string, 0, len(v))
+		for _, m := range v {
+			rows = append(rows, []string{strconv.Itoa(m.Version), m.Name})
+		}
+		return []string{"VERSION", "NAME"}, rows
+	case []seedResult:
+		rows := make([][]string, 0, len(v))
+		for _, r := range v {
+			rows = append(rows, []string{r.Username, r.Status})
+		}
+		return []string{"USERNAME", "STATUS"}, rows
+	case seed.Result:
+		return []string{"SEED", "CREATED", "EXISTING", "RENAMED", "SKIPPED", "MATCHES", "DELETED", "PASSWORD"}, [][]string{
+			{strconv.FormatInt(v.Seed, 10), strconv.Itoa(v.Created), strconv.Itoa(v.Existing), strconv.Itoa(v.Renamed),
+				strconv.Itoa(v.Skipped), strconv.Itoa(v.Matches), strconv.Itoa(v.Deleted), v.Password},
+		}
+	}
+	return []string{"RESULT"}, [][]string{{fmt.Sprint(v)}}
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
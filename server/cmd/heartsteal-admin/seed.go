@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/seed"
+	"github.com/spf13/pflag"
+)
+
+// seedUser is one entry of a seed file:
+//
+//	[{"username": "alice", "email": "alice@example.com", "password": "...", "roles": ["admin"]}]
+type seedUser struct {
+	Username    string        `json:"username"`
+	Email       string        `json:"email"`
+	DisplayName string        `json:"display_name"`
+	Password    string        `json:"password"`
+	Roles       []domain.Role `json:"roles"`
+}
+
+type seedResult struct {
+	Username string `json:"username"`
+	Status   string `json:"status"`
+}
+
+var seedFile = command{
+	name: "seed",
+	args: "--file <path>",
+	help: "create the users in a JSON file; existing users are skipped",
+	flags: func(f *pflag.FlagSet) {
+		f.String("file", "", "path to the JSON seed file (required)")
+	},
+	run: func(ctx context.Context, env *cli, f *pflag.FlagSet, args []string) (any, error) {
+		path, _ := f.GetString("file")
+		if len(args) != 0 || path == "" {
+			return nil, errUsage
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var users []seedUser
+		if err := json.Unmarshal(data, &users); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		results := make([]seedResult, 0, len(users))
+		for _, su := range users {
+			err := env.admin.CreateUser(ctx, &domain.User{
+				Username:    su.Username,
+				Email:       su.Email,
+				DisplayName: su.DisplayName,
+				Password:    su.Password,
+				Roles:       su.Roles,
+			})
+			switch {
+			case errors.Is(err, domain.ErrEmailExists), errors.Is(err, domain.ErrUsernameExists):
+				results = append(results, seedResult{Username: su.Username, Status: "exists"})
+			case err != nil:
+				return results, fmt.Errorf("user %q: %w", su.Username, err)
+			default:
+				results = append(results, seedResult{Username: su.Username, Status: "created"})
+			}
+		}
+		return results, nil
+	},
+}
+
+var seedGenerate = command{
+	name: "seed generate",
+	help: "create deterministic development users with friendships, finished matches and stats; rerunning is a no-op",
+	flags: func(f *pflag.FlagSet) {
+		generatorFlags(f)
+		f.Int("friends", seed.DefaultConfig().FriendsPerUser, "friends per user")
+		f.String("password", seed.DefaultPassword, "password of every generated user")
+	},
+	run: func(ctx context.Context, env *cli, f *pflag.FlagSet, args []string) (any, error) {
+		if len(args) != 0 {
+			return nil, errUsage
+		}
+		cfg := generatorConfig(f)
+		cfg.FriendsPerUser, _ = f.GetInt("friends")
+		cfg.Password, _ = f.GetString("password")
+		return seed.NewSeeder(env.repos).Run(ctx, cfg)
+	},
+}
+
+var seedClean = command{
+	name:  "seed clean",
+	help:  "delete the users and matches \"seed generate\" created with the same --seed, --users and --matches",
+	flags: generatorFlags,
+	run: func(ctx context.Context, env *cli, f *pflag.FlagSet, args []string) (any, error) {
+		if len(args) != 0 {
+			return nil, errUsage
+		}
+		return seed.NewSeeder(env.repos).Clean(ctx, generatorConfig(f))
+	},
+}
+
+func generatorFlags(f *pflag.FlagSet) {
+	f.Int64("seed", seed.DefaultConfig().Seed, "random seed; the same seed always yields the same data")
+	f.Int("users", seed.DefaultConfig().Users, "number of users")
+	f.Int("matches", seed.DefaultConfig().MatchesPerUser, "finished matches each user hosts")
+}
+
+func generatorConfig(f *pflag.FlagSet) seed.Config {
+	cfg := seed.DefaultConfig()
+	cfg.Seed, _ = f.GetInt64("seed")
+	cfg.Users, _ = f.GetInt("users")
+	cfg.MatchesPerUser, _ = f.GetInt("matches")
+	return cfg
+}
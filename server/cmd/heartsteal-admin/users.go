@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/spf13/pflag"
+)
+
+var usersCreate = command{
+	name: "users create",
+	help: "create a user, bypassing the public signup rules",
+	flags: func(f *pflag.FlagSet) {
+		f.String("username", "", "username (required)")
+		f.String("email", "", "email address (required)")
+		f.String("display-name", "", "display name (defaults to the username)")
+		f.StringSlice("role", nil, "extra role to grant, repeatable: moderator, admin")
+		passwordFlags(f)
+	},
+	run: func(ctx context.Context, env *cli, f *pflag.FlagSet, args []string) (any, error) {
+		username, _ := f.GetString("username")
+		email, _ := f.GetString("email")
+		if len(args) != 0 || username == "" || email == "" {
+			return nil, errUsage
+		}
+		displayName, _ := f.GetString("display-name")
+		roleNames, _ := f.GetStringSlice("role")
+		password, err := readPassword(env, f)
+		if err != nil {
+			return nil, err
+		}
+
+		user := &domain.User{
+			Username:    username,
+			Email:       email,
+			DisplayName: displayName,
+			Password:    password,
+		}
+		for _, name := range roleNames {
+			role, err := domain.ParseRole(name)
+			if err != nil {
+				return nil, fmt.Errorf("%w %q", err, name)
+			}
+			if role != domain.RolePlayer {
+				user.Roles = append(user.Roles, role)
+			}
+		}
+
+		if err := env.admin.CreateUser(ctx, user); err != nil {
+			return nil, err
+		}
+		return user, nil
+	},
+}
+
+var usersResetPassword = command{
+	name:  "users reset-password",
+	args:  "<username>",
+	help:  "set a new password and end the user's sessions",
+	flags: passwordFlags,
+	run: func(ctx context.Context, env *cli, f *pflag.FlagSet, args []string) (any, error) {
+		if len(args) != 1 {
+			return nil, errUsage
+		}
+		password, err := readPassword(env, f)
+		if err != nil {
+			return nil, err
+		}
+		return env.admin.ResetPassword(ctx, args[0], password)
+	},
+}
+
+var usersGrantRole = command{
+	name: "users grant-role",
+	args: "<username> <role>",
+	help: "grant moderator or admin",
+	run: func(ctx context.Context, env *cli, _ *pflag.FlagSet, args []string) (any, error) {
+		if len(args) != 2 {
+			return nil, errUsage
+		}
+		return env.admin.GrantRole(ctx, args[0], domain.Role(args[1]))
+	},
+}
+
+var usersRevokeRole = command{
+	name: "users revoke-role",
+	args: "<username> <role>",
+	help: "revoke moderator or admin",
+	run: func(ctx context.Context, env *cli, _ *pflag.FlagSet, args []string) (any, error) {
+		if len(args) != 2 {
+			return nil, errUsage
+		}
+		return env.admin.RevokeRole(ctx, args[0], domain.Role(args[1]))
+	},
+}
+
+var usersBan = command{
+	name: "users ban",
+	args: "<username>",
+	help: "block logins and end the user's sessions",
+	flags: func(f *pflag.FlagSet) {
+		f.String("reason", "", "reason recorded with the ban (required)")
+	},
+	run: func(ctx context.Context, env *cli, f *pflag.FlagSet, args []string) (any, error) {
+		reason, _ := f.GetString("reason")
+		if len(args) != 1 || strings.TrimSpace(reason) == "" {
+			return nil, errUsage
+		}
+		return env.admin.Ban(ctx, args[0], reason)
+	},
+}
+
+var usersUnban = command{
+	name: "users unban",
+	args: "<username>",
+	help: "allow a banned user to log in again",
+	run: func(ctx context.Context, env *cli, _ *pflag.FlagSet, args []string) (any, error) {
+		if len(args) != 1 {
+			return nil, errUsage
+		}
+		return env.admin.Unban(ctx, args[0])
+	},
+}
+
+var sessionsList = command{
+	name: "sessions list",
+	args: "<username>",
+	help: "list the user's active sessions, newest first",
+	run: func(ctx context.Context, env *cli, _ *pflag.FlagSet, args []string) (any, error) {
+		if len(args) != 1 {
+			return nil, errUsage
+		}
+		return env.admin.ListSessions(ctx, args[0])
+	},
+}
+
+func passwordFlags(f *pflag.FlagSet) {
+	f.String("password", "", "new password; visible to other local users, prefer --password-stdin")
+	f.Bool("password-stdin", false, "read the password from the first line of stdin")
+}
+
+func readPassword(env *cli, f *pflag.FlagSet) (string, error) {
+	password, _ := f.GetString("password")
+	fromStdin, _ := f.GetBool("password-stdin")
+	switch {
+	case fromStdin && password != "":
+		return "", errors.New("--password and --password-stdin are mutually exclusive")
+	case fromStdin:
+		line, err := bufio.NewReader(env.stdin).ReadString('\n')
+		if line == "" && err != nil {
+			return "", fmt.Errorf("reading password from stdin: %w", err)
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	case password == "":
+		return "", errUsage
+	}
+	return password, nil
+}
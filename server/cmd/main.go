@@ -1,11 +1,21 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
-	"log"
 
 	route "github.com/Simpolette/HeartSteal/server/internal/route"
 	"github.com/Simpolette/HeartSteal/server/internal/bootstrap"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/logger"
+	"github.com/Simpolette/HeartSteal/server/internal/repository"
+	"github.com/Simpolette/HeartSteal/server/internal/seed"
 	"github.com/gin-gonic/gin"
 )
 
@@ -17,14 +27,115 @@ func main() {
 
 	db := app.Mongo.Database(env.DBName)
 	defer app.CloseDBConnection()
+	defer app.CloseRedisConnection()
+
+	app.WatchConfig()
 
 	timeout := time.Duration(env.ContextTimeout) * time.Second
 
-	gin := gin.Default()
+	gin := gin.New()
+
+	repos := repository.NewMongoRepositories(db)
+	if env.SeedOnStartup {
+		seedDevelopmentData(repos, env, timeout)
+	}
+
+	matches := route.Setup(&app, timeout, repos, gin)
+	abandonExpiredMatches(matches, &app)
+
+	srv := &http.Server{
+		Addr:              env.ServerAddress,
+		Handler:           gin,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	var metricsSrv *http.Server
+	if env.MetricsAddress != "" && env.MetricsAddress != env.ServerAddress {
+		metricsSrv = &http.Server{
+			Addr:              env.MetricsAddress,
+			Handler:           app.Metrics.Handler(),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			if err := metricsSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Fatal("Metrics server failed to start", "error", err)
+			}
+		}()
+	}
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatal("Server failed to start", "error", err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	// Fail readiness first so the load balancer stops sending new traffic,
+	// then drain in-flight requests.
+	app.Health.SetShuttingDown()
+	slog.Info("Shutting down server...")
 
-	route.Setup(env, timeout, db, gin)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(env.ShutdownTimeout)*time.Second)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		slog.Error("Server forced to shutdown", "error", err)
+	}
+
+	app.CloseRealtime(ctx)
+	app.CloseTracing(ctx)
+
+	if metricsSrv != nil {
+		if err := metricsSrv.Shutdown(ctx); err != nil {
+			slog.Error("Metrics server forced to shutdown", "error", err)
+		}
+	}
+}
+
+// seedDevelopmentData creates the deterministic development users and
+// matches. Existing ones are skipped, so restarts don't duplicate them.
+func seedDevelopmentData(repos repository.Repositories, env *bootstrap.Env, timeout time.Duration) {
+	cfg := seed.DefaultConfig()
+	cfg.Seed = env.SeedValue
+	cfg.Users = env.SeedUsers
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Users)*timeout)
+	defer cancel()
+
+	result, err := seed.NewSeeder(repos).Run(ctx, cfg)
+	if err != nil {
+		logger.Fatal("Development data can't be seeded", "error", err)
+	}
+	slog.Info("Development data seeded", "seed", result.Seed, "created", result.Created, "existing", result.Existing,
+		"renamed", result.Renamed, "skipped", result.Skipped, "matches", result.Matches, "password", result.Password)
+}
+
+// abandonExpiredMatches ends the matches whose instance stopped renewing
+// their lease, e.g. a previous run of this one or a crashed replica. Games
+// live in memory, so they can't be resumed elsewhere. It sweeps once before
+// serving and then once per lease period, since a lease only lapses that
+// long after its instance stopped. matches must be the usecase running this
+// process's matches, so the ones it runs are never abandoned.
+func abandonExpiredMatches(matches domain.MatchUsecase, app *bootstrap.Application) {
+	sweep := func() error {
+		count, err := matches.AbandonExpired(context.Background())
+		if count > 0 {
+			slog.Warn("Abandoned matches whose instance stopped running them", "count", count)
+		}
+		return err
+	}
 
-	if err := gin.Run(env.ServerAddress); err != nil {
-		log.Fatal("Server failed to start: ", err)
+	if err := sweep(); err != nil {
+		logger.Fatal("Expired matches can't be abandoned", "error", err)
 	}
-}
\ No newline at end of file
+	go func() {
+		for range time.Tick(time.Duration(app.Env.MatchLeaseSeconds) * time.Second) {
+			if err := sweep(); err != nil {
+				slog.Error("Expired matches can't be abandoned", "error", err)
+			}
+		}
+	}()
+}
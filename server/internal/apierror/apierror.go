@@ -0,0 +1,89 @@
+// Package apierror turns errors into the responses clients see. HTTP
+// middleware and the WebSocket gateway both render errors through it, so a
+// sentinel registered once answers the same way on either transport.
+package apierror
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/i18n"
+)
+
+type errorMapping struct {
+	target  error
+	status  int
+	code    domain.ErrorCode
+	message string
+}
+
+var (
+	mappingsMu sync.RWMutex
+	mappings   []errorMapping
+)
+
+// Register maps a sentinel error (matched with errors.Is) to the HTTP
+// status, code and public message returned to clients. Registering the same
+// target twice replaces the earlier mapping.
+func Register(target error, status int, code domain.ErrorCode, message string) {
+	mappingsMu.Lock()
+	defer mappingsMu.Unlock()
+
+	m := errorMapping{target: target, status: status, code: code, message: message}
+	for i := range mappings {
+		if mappings[i].target == target {
+			mappings[i] = m
+			return
+		}
+	}
+	mappings = append(mappings, m)
+}
+
+// Resolve converts any error into an AppError. An AppError anywhere in
+// the chain wins, then registered sentinels, then a generic internal error.
+func Resolve(err error) *domain.AppError {
+	var appErr *domain.AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	mappingsMu.RLock()
+	defer mappingsMu.RUnlock()
+
+	for _, m := range mappings {
+		if errors.Is(err, m.target) {
+			return domain.NewAppError(m.code, m.status, m.message, err)
+		}
+	}
+
+	return domain.InternalError(err)
+}
+
+// Localize renders the public message and field details in the locale
+// of ctx, keeping the English text when a catalog has no entry for the code.
+func Localize(ctx context.Context, appErr *domain.AppError) domain.ErrorResponse {
+	locale := i18n.LocaleFromContext(ctx)
+	bundle := i18n.Default()
+
+	resp := domain.ErrorResponse{
+		Code:    appErr.Code,
+		Message: appErr.Message,
+	}
+	if key := "error." + string(appErr.Code); bundle.Has(locale, key) || bundle.Has(i18n.DefaultLocale, key) {
+		resp.Message = bundle.Translate(locale, key, nil)
+	}
+
+	if len(appErr.Details) > 0 {
+		resp.Details = make([]domain.FieldError, len(appErr.Details))
+		for i, d := range appErr.Details {
+			if d.MessageKey != "" {
+				d.Message = bundle.Translate(locale, d.MessageKey, d.MessageParams)
+			}
+			resp.Details[i] = d
+		}
+	}
+
+	return resp
+}
@@ -0,0 +1,68 @@
+package apierror_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Simpolette/HeartSteal/server/internal/apierror"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/i18n"
+)
+
+func TestResolve(t *testing.T) {
+	errTeapot := errors.New("teapot")
+	apierror.Register(errTeapot, http.StatusTeapot, "TEAPOT", "I'm a teapot")
+
+	t.Run("WrappedSentinel", func(t *testing.T) {
+		appErr := apierror.Resolve(fmt.Errorf("brewing: %w", errTeapot))
+
+		assert.Equal(t, http.StatusTeapot, appErr.Status)
+		assert.Equal(t, domain.ErrorCode("TEAPOT"), appErr.Code)
+		assert.ErrorIs(t, appErr.Err, errTeapot)
+	})
+
+	t.Run("AppErrorWins", func(t *testing.T) {
+		wrapped := domain.NewAppError(domain.CodeInvalidRequest, http.StatusBadRequest, "Invalid request", errTeapot)
+
+		appErr := apierror.Resolve(fmt.Errorf("binding: %w", wrapped))
+
+		assert.Same(t, wrapped, appErr)
+	})
+
+	t.Run("ReRegisterReplaces", func(t *testing.T) {
+		errGone := errors.New("gone")
+		apierror.Register(errGone, http.StatusNotFound, "NOT_HERE", "Not here")
+		apierror.Register(errGone, http.StatusGone, "GONE", "Gone")
+
+		assert.Equal(t, http.StatusGone, apierror.Resolve(errGone).Status)
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		appErr := apierror.Resolve(errors.New("mongo: connection refused"))
+
+		assert.Equal(t, http.StatusInternalServerError, appErr.Status)
+		assert.Equal(t, domain.CodeInternal, appErr.Code)
+	})
+}
+
+func TestLocalize(t *testing.T) {
+	t.Run("Translated", func(t *testing.T) {
+		ctx := i18n.WithLocale(context.Background(), "vi")
+
+		resp := apierror.Localize(ctx, domain.NewAppError(domain.CodeUnauthorized, http.StatusUnauthorized, "Not authorized", nil))
+
+		assert.Equal(t, domain.CodeUnauthorized, resp.Code)
+		assert.Equal(t, "Chưa được xác thực", resp.Message)
+	})
+
+	t.Run("NoCatalogEntry", func(t *testing.T) {
+		resp := apierror.Localize(context.Background(), domain.NewAppError("TEAPOT", http.StatusTeapot, "I'm a teapot", nil))
+
+		assert.Equal(t, "I'm a teapot", resp.Message)
+	})
+}
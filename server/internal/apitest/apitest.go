@@ -0,0 +1,291 @@
+// Package apitest runs the production router from route.Setup on in-memory
+// dependencies and checks every request and response against the OpenAPI
+// document the router publishes, so handler tests double as contract tests.
+package apitest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Simpolette/HeartSteal/server/internal/bootstrap"
+	"github.com/Simpolette/HeartSteal/server/internal/health"
+	"github.com/Simpolette/HeartSteal/server/internal/matchmaking"
+	"github.com/Simpolette/HeartSteal/server/internal/metrics"
+	"github.com/Simpolette/HeartSteal/server/internal/ratelimit"
+	"github.com/Simpolette/HeartSteal/server/internal/repository"
+	"github.com/Simpolette/HeartSteal/server/internal/repository/memory"
+	"github.com/Simpolette/HeartSteal/server/internal/route"
+	tokenutil "github.com/Simpolette/HeartSteal/server/utils"
+)
+
+const (
+	openAPIPath = "/api/openapi.json"
+	// TokenSecret signs the access tokens the harness issues and accepts.
+	TokenSecret = "apitest-access-token-secret"
+)
+
+type Server struct {
+	t      testing.TB
+	Engine *gin.Engine
+	App    *bootstrap.Application
+	Repos  repository.Repositories
+
+	doc    *openapi3.T
+	router routers.Router
+}
+
+// Option adjusts the application before the router is built.
+type Option func(*bootstrap.Application)
+
+// WithoutRateLimit disables rate limiting, for tests that send many requests.
+func WithoutRateLimit() Option {
+	return func(app *bootstrap.Application) { app.RateLimiter = nil }
+}
+
+func WithEnv(configure func(*bootstrap.Env)) Option {
+	return func(app *bootstrap.Application) { configure(app.Env) }
+}
+
+var registerDecoders sync.Once
+
+func New(t testing.TB, opts ...Option) *Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	registerDecoders.Do(func() {
+		for _, contentType := range []string{"text/html", "text/plain", "text/javascript"} {
+			openapi3filter.RegisterBodyDecoder(contentType, openapi3filter.PlainBodyDecoder)
+		}
+	})
+
+	app := &bootstrap.Application{
+		Env: &bootstrap.Env{
+			AppEnv:                "test",
+			ServerAddress:         ":8080",
+			ContextTimeout:        2,
+			AccessTokenSecret:     TokenSecret,
+			AccessTokenExpiryHour: 1,
+			LogLevel:              "error",
+			CORSAllowedOrigins:    []string{"http://localhost:3000"},
+			CORSAllowedMethods:    []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+			CORSAllowedHeaders:    []string{"Origin", "Content-Type", "Accept", "Authorization"},
+			CORSAllowCredentials:  true,
+			FrameAncestors:        "'none'",
+			ReferrerPolicy:        "strict-origin-when-cross-origin",
+			WSSendQueueSize:       64,
+			WSMaxSubscriptions:    32,
+			WSMaxMessageBytes:     4096,
+			WSPingIntervalSeconds: 25,
+			WSTicketTTLSeconds:    30,
+			MatchmakingQueue:      "memory",
+			MatchAcceptSeconds:    15,
+			ReconnectGraceSeconds: 60,
+			DisconnectPolicy:      "forfeit",
+			DisconnectBotLevel:    "normal",
+		},
+		Logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Metrics:     metrics.New(),
+		Health:      health.NewRegistry(0),
+		RateLimiter: ratelimit.NewMemoryStore(),
+		Matchmaking: matchmaking.NewMemoryQueue(),
+	}
+	for _, opt := range opts {
+		opt(app)
+	}
+	app.Realtime = bootstrap.NewRealtimeHub(app.Env, app.Metrics)
+
+	s := &Server{
+		t:   t,
+		App: app,
+		Repos: repository.Repositories{
+			User:    memory.NewUserRepository(),
+			Session: memory.NewSessionRepository(),
+			Lobby:   memory.NewLobbyRepository(),
+			Ticket:  memory.NewTicketRepository(),
+			Match:   memory.NewMatchRepository(),
+			Rating:  memory.NewRatingRepository(),
+			Stats:   memory.NewStatsRepository(),
+			Tx:      memory.NewTransactor(),
+		},
+	}
+	s.Engine = gin.New()
+	route.Setup(app, time.Duration(app.Env.ContextTimeout)*time.Second, s.Repos, s.Engine)
+
+	w := httptest.NewRecorder()
+	s.Engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, openAPIPath, nil))
+	require.Equal(t, http.StatusOK, w.Code, "the router must publish its OpenAPI document")
+
+	doc, err := openapi3.NewLoader().LoadFromData(w.Body.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(context.Background()), "the published OpenAPI document is invalid")
+	s.doc = doc
+
+	s.router, err = legacy.NewRouter(doc)
+	require.NoError(t, err)
+	return s
+}
+
+// Document returns the OpenAPI document the router published.
+func (s *Server) Document() *openapi3.T {
+	return s.doc
+}
+
+// AccessToken issues a token for userID as the login endpoint would.
+func (s *Server) AccessToken(userID string) string {
+	version := 0
+	if user, err := s.Repos.User.GetByID(context.Background(), userID); err == nil {
+		version = user.TokenVersion
+	}
+	token, err := tokenutil.CreateAccessToken(userID, version, s.App.Env.AccessTokenSecret, s.App.Env.AccessTokenExpiryHour)
+	require.NoError(s.t, err)
+	return token
+}
+
+// RequestOption adjusts a request before it is sent.
+type RequestOption func(*http.Request)
+
+func WithHeader(key, value string) RequestOption {
+	return func(r *http.Request) { r.Header.Set(key, value) }
+}
+
+func WithToken(token string) RequestOption {
+	return WithHeader("Authorization", "Bearer "+token)
+}
+
+type Response struct {
+	*httptest.ResponseRecorder
+	t testing.TB
+}
+
+// JSON decodes the response body into v.
+func (r *Response) JSON(v any) {
+	r.t.Helper()
+	require.NoError(r.t, json.Unmarshal(r.Body.Bytes(), v), "body: %s", r.Body.String())
+}
+
+// Do sends a request through the router. body is encoded as JSON unless it is
+// a string or []byte, which are sent as is.
+//
+// The exchange is checked against the contract:
+//   - a route missing from the document must answer 404 or 405;
+//   - a request the document rejects must be answered with a 4xx;
+//   - every response must match the documented status, headers and body.
+func (s *Server) Do(method, path string, body any, opts ...RequestOption) *Response {
+	s.t.Helper()
+
+	raw := encodeBody(s.t, body)
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(method, path, bytes.NewReader(raw))
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		for _, opt := range opts {
+			opt(req)
+		}
+		return req
+	}
+
+	w := httptest.NewRecorder()
+	s.Engine.ServeHTTP(w, newRequest())
+	res := &Response{ResponseRecorder: w, t: s.t}
+
+	req := newRequest()
+	matched, pathParams, err := s.router.FindRoute(req)
+	if err != nil {
+		if w.Code != http.StatusNotFound && w.Code != http.StatusMethodNotAllowed {
+			s.t.Errorf("%s %s is not in the API contract but answered %d", method, path, w.Code)
+		}
+		return res
+	}
+
+	ctx := context.Background()
+	input := &openapi3filter.RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      matched,
+		Options: &openapi3filter.Options{
+			MultiError:         true,
+			AuthenticationFunc: bearerAuthentication,
+		},
+	}
+	if reqErr := openapi3filter.ValidateRequest(ctx, input); reqErr != nil && (w.Code < 400 || w.Code >= 500) {
+		s.t.Errorf("%s %s violates the API contract but answered %d:\n%v", method, path, w.Code, reqErr)
+	}
+
+	input.Options = &openapi3filter.Options{MultiError: true, IncludeResponseStatus: true}
+	resErr := openapi3filter.ValidateResponse(ctx, &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 w.Code,
+		Header:                 w.Header(),
+		Body:                   io.NopCloser(bytes.NewReader(w.Body.Bytes())),
+		Options:                input.Options,
+	})
+	if resErr != nil {
+		s.t.Errorf("%s %s answered %d outside the API contract:\n%v\nbody: %s", method, path, w.Code, resErr, w.Body.String())
+	}
+	return res
+}
+
+func (s *Server) GET(path string, opts ...RequestOption) *Response {
+	s.t.Helper()
+	return s.Do(http.MethodGet, path, nil, opts...)
+}
+
+func (s *Server) POST(path string, body any, opts ...RequestOption) *Response {
+	s.t.Helper()
+	return s.Do(http.MethodPost, path, body, opts...)
+}
+
+func (s *Server) PUT(path string, body any, opts ...RequestOption) *Response {
+	s.t.Helper()
+	return s.Do(http.MethodPut, path, body, opts...)
+}
+
+func (s *Server) PATCH(path string, body any, opts ...RequestOption) *Response {
+	s.t.Helper()
+	return s.Do(http.MethodPatch, path, body, opts...)
+}
+
+func (s *Server) DELETE(path string, opts ...RequestOption) *Response {
+	s.t.Helper()
+	return s.Do(http.MethodDelete, path, nil, opts...)
+}
+
+func encodeBody(t testing.TB, body any) []byte {
+	switch b := body.(type) {
+	case nil:
+		return nil
+	case string:
+		return []byte(b)
+	case []byte:
+		return b
+	}
+	raw, err := json.Marshal(body)
+	require.NoError(t, err)
+	return raw
+}
+
+// bearerAuthentication only checks that a bearer token is present; whether it
+// is valid is the server's decision and shows up in the response.
+func bearerAuthentication(_ context.Context, input *openapi3filter.AuthenticationInput) error {
+	if strings.HasPrefix(input.RequestValidationInput.Request.Header.Get("Authorization"), "Bearer ") {
+		return nil
+	}
+	return errors.New("missing bearer token")
+}
@@ -1,21 +1,158 @@
 package bootstrap
 
 import (
-    "go.mongodb.org/mongo-driver/mongo"
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/health"
+	"github.com/Simpolette/HeartSteal/server/internal/logger"
+	"github.com/Simpolette/HeartSteal/server/internal/matchmaking"
+	"github.com/Simpolette/HeartSteal/server/internal/metrics"
+	"github.com/Simpolette/HeartSteal/server/internal/ratelimit"
+	"github.com/Simpolette/HeartSteal/server/internal/realtime"
+	"github.com/Simpolette/HeartSteal/server/internal/tracing"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 type Application struct {
-	Env   *Env
-	Mongo *mongo.Client
+	Env      *Env
+	Logger   *slog.Logger
+	LogLevel *slog.LevelVar
+	Mongo   *mongo.Client
+	Health  *health.Registry
+	Metrics *metrics.Metrics
+
+	RateLimiter ratelimit.Store
+	Realtime    *realtime.Hub
+	Matchmaking matchmaking.Queue
+
+	args            []string
+	redis           *redis.Client
+	shutdownTracing func(context.Context) error
 }
 
-func App() Application {
-	app := &Application{}
-	app.Env = NewEnv()
-	app.Mongo = NewMongoDatabase(app.Env)
+// Option customises how App builds the application.
+type Option func(*appOptions)
+
+type appOptions struct {
+	args      []string
+	logOutput io.Writer
+}
+
+// WithArgs reads configuration flags from args instead of os.Args[1:], e.g.
+// so a CLI can pass only the arguments after its command name.
+func WithArgs(args []string) Option {
+	return func(o *appOptions) { o.args = args }
+}
+
+// WithLogOutput sends logs to w instead of stdout, e.g. so a CLI can keep
+// stdout for its own output.
+func WithLogOutput(w io.Writer) Option {
+	return func(o *appOptions) { o.logOutput = w }
+}
+
+func App(opts ...Option) Application {
+	o := appOptions{args: os.Args[1:], logOutput: os.Stdout}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	app := &Application{args: o.args}
+	app.Env = newEnv(o.args)
+	app.LogLevel = new(slog.LevelVar)
+	app.Logger = NewLogger(app.Env, app.LogLevel, o.logOutput)
+	app.Logger.Info("Configuration loaded", "config", app.Env.Redacted())
+	app.shutdownTracing = NewTracing(app.Env)
+	app.Metrics = metrics.New()
+	app.Mongo = NewMongoDatabase(app.Env, app.Metrics.MongoMonitor(), otelmongo.NewMonitor())
+	app.Health = NewHealthRegistry(app.Env, app.Mongo)
+	app.RateLimiter, app.redis = NewRateLimitStore(app.Env, app.Health)
+	app.Realtime = NewRealtimeHub(app.Env, app.Metrics)
+	app.Matchmaking = NewMatchmakingQueue(app.Env)
 	return *app
 }
 
+func NewHealthRegistry(env *Env, client *mongo.Client) *health.Registry {
+	registry := health.NewRegistry(time.Duration(env.ReadinessCacheSeconds) * time.Second)
+	registry.Register("mongo", time.Duration(env.ReadinessCheckTimeout)*time.Second, func(ctx context.Context) error {
+		return client.Ping(ctx, readpref.Primary())
+	})
+	return registry
+}
+
+// NewLogger builds the process-wide logger and installs it as the slog
+// default so that code without a request context logs the same way.
+func NewLogger(env *Env, level *slog.LevelVar, w io.Writer) *slog.Logger {
+	level.Set(logger.ParseLevel(env.LogLevel))
+	l := logger.NewWithLevel(level, env.LogFormat, w).With(slog.String("env", env.AppEnv))
+	slog.SetDefault(l)
+	return l
+}
+
+func NewTracing(env *Env) func(context.Context) error {
+	shutdown, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    env.TracingExporter,
+		Endpoint:    env.TracingEndpoint,
+		Insecure:    env.TracingInsecure,
+		SampleRatio: env.TracingSampleRatio,
+		Environment: env.AppEnv,
+	})
+	if err != nil {
+		logger.Fatal("Tracing can't be initialized", "error", err)
+	}
+	return shutdown
+}
+
+// WatchConfig applies reloadable settings from config file edits. Everything
+// else is reported as needing a restart.
+func (app *Application) WatchConfig() {
+	reloader := NewReloader(app.Env, app.LogLevel, app.Health)
+	WatchEnv(app.args, func(updated *Env) {
+		reloadable, restart := reloader.Apply(updated)
+		if len(restart) > 0 {
+			slog.Warn("Config changes require a restart", "keys", restart)
+		}
+		if len(reloadable) > 0 {
+			slog.Info("Config reloaded", "keys", reloadable)
+		}
+	})
+}
+
 func (app *Application) CloseDBConnection() {
 	CloseMongoDBConnection(app.Mongo)
-}
\ No newline at end of file
+}
+
+func (app *Application) CloseRedisConnection() {
+	if app.redis == nil {
+		return
+	}
+	if err := app.redis.Close(); err != nil {
+		slog.Error("Connection to Redis can't be closed", "error", err)
+	}
+}
+
+func (app *Application) CloseTracing(ctx context.Context) {
+	if app.shutdownTracing == nil {
+		return
+	}
+	if err := app.shutdownTracing(ctx); err != nil {
+		slog.Error("Tracing can't be flushed", "error", err)
+	}
+}
+
+// CloseRealtime disconnects WebSocket clients. http.Server.Shutdown doesn't
+// wait for them, since their connections have been hijacked.
+func (app *Application) CloseRealtime(ctx context.Context) {
+	if app.Realtime == nil {
+		return
+	}
+	if err := app.Realtime.Shutdown(ctx); err != nil {
+		slog.Error("WebSocket connections can't be closed", "error", err)
+	}
+}
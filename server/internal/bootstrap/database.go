@@ -3,17 +3,19 @@ package bootstrap
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
 	"net/url"
 
+	"github.com/Simpolette/HeartSteal/server/internal/logger"
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
     "go.mongodb.org/mongo-driver/mongo/options"
     "go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
-func NewMongoDatabase(env *Env) *mongo.Client {
+func NewMongoDatabase(env *Env, monitors ...*event.CommandMonitor) *mongo.Client {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
@@ -23,20 +25,65 @@ func NewMongoDatabase(env *Env) *mongo.Client {
 	mongodbURI := fmt.Sprintf("mongodb+srv://%s:%s@clusterdev.0ham19e.mongodb.net/?appName=ClusterDev", dbUser, dbPass)
 
 	clientOptions := options.Client().ApplyURI(mongodbURI)
+	if monitor := CombineMonitors(monitors...); monitor != nil {
+		clientOptions.SetMonitor(monitor)
+	}
 
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
-		log.Fatal(err)
+		logger.Fatal("Can't connect to MongoDB", "error", err)
 	}
 
 	err = client.Ping(ctx, readpref.Primary()) // Check Primary is reachable
 	if err != nil {
-		log.Fatal(err)
+		logger.Fatal("MongoDB primary is unreachable", "error", err)
 	}
 
 	return client
 }
 
+// CombineMonitors fans driver command events out to every non-nil monitor,
+// since the driver only accepts a single CommandMonitor.
+func CombineMonitors(monitors ...*event.CommandMonitor) *event.CommandMonitor {
+	active := make([]*event.CommandMonitor, 0, len(monitors))
+	for _, m := range monitors {
+		if m != nil {
+			active = append(active, m)
+		}
+	}
+
+	switch len(active) {
+	case 0:
+		return nil
+	case 1:
+		return active[0]
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			for _, m := range active {
+				if m.Started != nil {
+					m.Started(ctx, evt)
+				}
+			}
+		},
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			for _, m := range active {
+				if m.Succeeded != nil {
+					m.Succeeded(ctx, evt)
+				}
+			}
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			for _, m := range active {
+				if m.Failed != nil {
+					m.Failed(ctx, evt)
+				}
+			}
+		},
+	}
+}
+
 func CloseMongoDBConnection(client *mongo.Client) {
 	if client == nil {
 		return
@@ -44,8 +91,9 @@ func CloseMongoDBConnection(client *mongo.Client) {
 
 	err := client.Disconnect(context.TODO())
 	if err != nil {
-		log.Fatal(err)
+		slog.Error("Connection to MongoDB can't be closed", "error", err)
+		return
 	}
 
-	log.Println("Connection to MongoDB closed.")
+	slog.Info("Connection to MongoDB closed.")
 }
\ No newline at end of file
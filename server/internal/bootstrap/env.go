@@ -1,43 +1,305 @@
 package bootstrap
 
 import (
-	"log"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
 
+	"github.com/Simpolette/HeartSteal/server/internal/logger"
+
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
+// Env is loaded in layers, each overriding the previous one:
+// defaults < config file (optional) < environment variables < command-line flags.
+// Fields tagged secret:"true" are redacted from dumps and may be read from a
+// file named by <KEY>_FILE. Fields tagged reload:"true" are applied without a
+// restart when the config file changes.
 type Env struct {
-	AppEnv                 string `mapstructure:"APP_ENV"`
-	ServerAddress          string `mapstructure:"SERVER_ADDRESS"`
-	ContextTimeout         int    `mapstructure:"CONTEXT_TIMEOUT"`
-	DBHost                 string `mapstructure:"DB_HOST"`
-	DBPort                 string `mapstructure:"DB_PORT"`
-	DBUser                 string `mapstructure:"DB_USER"`
-	DBPass                 string `mapstructure:"DB_PASS"`
-	DBName                 string `mapstructure:"DB_NAME"`
-	AccessTokenExpiryHour  int    `mapstructure:"ACCESS_TOKEN_EXPIRY_HOUR"`
-	RefreshTokenExpiryHour int    `mapstructure:"REFRESH_TOKEN_EXPIRY_HOUR"`
-	AccessTokenSecret      string `mapstructure:"ACCESS_TOKEN_SECRET"`
-	RefreshTokenSecret     string `mapstructure:"REFRESH_TOKEN_SECRET"`
+	AppEnv                 string   `mapstructure:"APP_ENV"`
+	ServerAddress          string   `mapstructure:"SERVER_ADDRESS"`
+	MetricsAddress         string   `mapstructure:"METRICS_ADDRESS"`
+	ContextTimeout         int      `mapstructure:"CONTEXT_TIMEOUT"`
+	DBHost                 string   `mapstructure:"DB_HOST"`
+	DBPort                 string   `mapstructure:"DB_PORT"`
+	DBUser                 string   `mapstructure:"DB_USER"`
+	DBPass                 string   `mapstructure:"DB_PASS"                   secret:"true"`
+	DBName                 string   `mapstructure:"DB_NAME"`
+	AccessTokenExpiryHour  int      `mapstructure:"ACCESS_TOKEN_EXPIRY_HOUR"`
+	RefreshTokenExpiryHour int      `mapstructure:"REFRESH_TOKEN_EXPIRY_HOUR"`
+	AccessTokenSecret      string   `mapstructure:"ACCESS_TOKEN_SECRET"       secret:"true"`
+	RefreshTokenSecret     string   `mapstructure:"REFRESH_TOKEN_SECRET"      secret:"true"`
+	UserCacheSeconds       int      `mapstructure:"USER_CACHE_SECONDS"`
+	LogLevel               string   `mapstructure:"LOG_LEVEL"                 reload:"true"`
+	LogFormat              string   `mapstructure:"LOG_FORMAT"`
+	ShutdownTimeout        int      `mapstructure:"SHUTDOWN_TIMEOUT"`
+	ReadinessCheckTimeout  int      `mapstructure:"READINESS_CHECK_TIMEOUT"`
+	ReadinessCacheSeconds  int      `mapstructure:"READINESS_CACHE_SECONDS"   reload:"true"`
+	TracingExporter        string   `mapstructure:"TRACING_EXPORTER"`
+	TracingEndpoint        string   `mapstructure:"TRACING_ENDPOINT"`
+	TracingInsecure        bool     `mapstructure:"TRACING_INSECURE"`
+	TracingSampleRatio     float64  `mapstructure:"TRACING_SAMPLE_RATIO"`
+	RateLimitEnabled       bool     `mapstructure:"RATE_LIMIT_ENABLED"`
+	RateLimitStore         string   `mapstructure:"RATE_LIMIT_STORE"`
+	RedisAddress           string   `mapstructure:"REDIS_ADDRESS"`
+	RedisPassword          string   `mapstructure:"REDIS_PASSWORD"            secret:"true"`
+	RedisDB                int      `mapstructure:"REDIS_DB"`
+	CORSAllowedOrigins     []string `mapstructure:"CORS_ALLOWED_ORIGINS"`
+	CORSAllowedMethods     []string `mapstructure:"CORS_ALLOWED_METHODS"`
+	CORSAllowedHeaders     []string `mapstructure:"CORS_ALLOWED_HEADERS"`
+	CORSAllowCredentials   bool     `mapstructure:"CORS_ALLOW_CREDENTIALS"`
+	CORSMaxAgeSeconds      int      `mapstructure:"CORS_MAX_AGE_SECONDS"`
+	TrustedProxies         []string `mapstructure:"TRUSTED_PROXIES"`
+	HSTSMaxAgeSeconds      int      `mapstructure:"HSTS_MAX_AGE_SECONDS"`
+	FrameAncestors         string   `mapstructure:"FRAME_ANCESTORS"`
+	ReferrerPolicy         string   `mapstructure:"REFERRER_POLICY"`
+	SeedOnStartup          bool     `mapstructure:"SEED_ON_STARTUP"`
+	SeedValue              int64    `mapstructure:"SEED_VALUE"`
+	SeedUsers              int      `mapstructure:"SEED_USERS"`
+	WSSendQueueSize        int      `mapstructure:"WS_SEND_QUEUE_SIZE"`
+	WSMaxSubscriptions     int      `mapstructure:"WS_MAX_SUBSCRIPTIONS"`
+	WSMaxMessageBytes      int64    `mapstructure:"WS_MAX_MESSAGE_BYTES"`
+	WSPingIntervalSeconds  int      `mapstructure:"WS_PING_INTERVAL_SECONDS"`
+	WSTicketTTLSeconds     int      `mapstructure:"WS_TICKET_TTL_SECONDS"`
+	MatchmakingQueue       string   `mapstructure:"MATCHMAKING_QUEUE"`
+	MatchAcceptSeconds     int      `mapstructure:"MATCH_ACCEPT_SECONDS"`
+	SpectatorDelaySeconds  int      `mapstructure:"SPECTATOR_DELAY_SECONDS"`
+	ReconnectGraceSeconds  int      `mapstructure:"RECONNECT_GRACE_SECONDS"`
+	DisconnectPolicy       string   `mapstructure:"DISCONNECT_POLICY"`
+	DisconnectBotLevel     string   `mapstructure:"DISCONNECT_BOT_LEVEL"`
+	BotMoveSeconds         int      `mapstructure:"BOT_MOVE_SECONDS"`
+	MatchLeaseSeconds      int      `mapstructure:"MATCH_LEASE_SECONDS"`
+}
+
+const (
+	defaultConfigFile = ".env"
+	configFileEnv     = "CONFIG_FILE"
+	configFileFlag    = "config"
+)
+
+var envDefaults = map[string]any{
+	"APP_ENV":                   "development",
+	"SERVER_ADDRESS":            ":8080",
+	"CONTEXT_TIMEOUT":           2,
+	"ACCESS_TOKEN_EXPIRY_HOUR":  2,
+	"REFRESH_TOKEN_EXPIRY_HOUR": 168,
+	"USER_CACHE_SECONDS":        30,
+	"LOG_LEVEL":                 "info",
+	"LOG_FORMAT":                "json",
+	"METRICS_ADDRESS":           ":9090",
+	"SHUTDOWN_TIMEOUT":          15,
+	"READINESS_CHECK_TIMEOUT":   2,
+	"READINESS_CACHE_SECONDS":   5,
+	"TRACING_EXPORTER":          "none",
+	"TRACING_SAMPLE_RATIO":      1.0,
+	"RATE_LIMIT_ENABLED":        true,
+	"RATE_LIMIT_STORE":          "memory",
+	"REDIS_ADDRESS":             "localhost:6379",
+	"CORS_ALLOWED_ORIGINS":      []string{"http://localhost:3000"},
+	"CORS_ALLOWED_METHODS":      []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+	"CORS_ALLOWED_HEADERS":      []string{"Origin", "Content-Type", "Accept", "Authorization", "Accept-Language", "X-Request-ID"},
+	"CORS_ALLOW_CREDENTIALS":    true,
+	"CORS_MAX_AGE_SECONDS":      43200,
+	"TRUSTED_PROXIES":           []string{},
+	"HSTS_MAX_AGE_SECONDS":      0,
+	"FRAME_ANCESTORS":           "'none'",
+	"REFERRER_POLICY":           "strict-origin-when-cross-origin",
+	"SEED_VALUE":                1,
+	"SEED_USERS":                50,
+	"WS_SEND_QUEUE_SIZE":        64,
+	"WS_MAX_SUBSCRIPTIONS":      32,
+	"WS_MAX_MESSAGE_BYTES":      4096,
+	"WS_PING_INTERVAL_SECONDS":  25,
+	"WS_TICKET_TTL_SECONDS":     30,
+	"MATCHMAKING_QUEUE":         "memory",
+	"MATCH_ACCEPT_SECONDS":      15,
+	"SPECTATOR_DELAY_SECONDS":   30,
+	"RECONNECT_GRACE_SECONDS":   60,
+	"DISCONNECT_POLICY":         "forfeit",
+	"DISCONNECT_BOT_LEVEL":      "normal",
+	"BOT_MOVE_SECONDS":          1,
+	"MATCH_LEASE_SECONDS":       30,
 }
 
 func NewEnv() *Env {
-	env := Env{}
-	viper.SetConfigFile(".env")
+	return newEnv(os.Args[1:])
+}
 
-	err := viper.ReadInConfig()
+func newEnv(args []string) *Env {
+	env, err := LoadEnv(args)
 	if err != nil {
-		log.Fatal("Can't find the file .env : ", err)
+		logger.Fatal("Environment can't be loaded", "error", err)
 	}
+	return env
+}
 
-	err = viper.Unmarshal(&env)
+// LoadEnv builds and validates the configuration. All validation problems are
+// reported together rather than one per restart.
+func LoadEnv(args []string) (*Env, error) {
+	v, err := newEnvViper(args)
 	if err != nil {
-		log.Fatal("Environment can't be loaded: ", err)
+		return nil, err
+	}
+
+	env := Env{}
+	if err := v.Unmarshal(&env); err != nil {
+		return nil, err
 	}
 
-	if env.AppEnv == "development" {
-		log.Println("The App is running in development env")
+	if err := env.Validate(); err != nil {
+		return nil, err
 	}
 
-	return &env
-}
\ No newline at end of file
+	return &env, nil
+}
+
+// ConfigFile returns the config file the given arguments select and whether it
+// was requested explicitly.
+func ConfigFile(args []string) (string, bool) {
+	flags := newEnvFlagSet()
+	_ = flags.Parse(args)
+
+	if path, _ := flags.GetString(configFileFlag); path != "" {
+		return path, true
+	}
+	if path := os.Getenv(configFileEnv); path != "" {
+		return path, true
+	}
+	return defaultConfigFile, false
+}
+
+func newEnvViper(args []string) (*viper.Viper, error) {
+	v := viper.New()
+	keys := envKeys()
+
+	for key, value := range envDefaults {
+		v.SetDefault(key, value)
+	}
+
+	path, explicit := ConfigFile(args)
+	v.SetConfigFile(path)
+	v.SetConfigType("env")
+	if err := v.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if explicit || !(errors.As(err, &notFound) || errors.Is(err, os.ErrNotExist)) {
+			return nil, fmt.Errorf("config file %s: %w", path, err)
+		}
+	}
+
+	for _, key := range keys {
+		if err := v.BindEnv(key); err != nil {
+			return nil, err
+		}
+	}
+
+	flags := newEnvFlagSet()
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		if err := v.BindPFlag(key, flags.Lookup(flagName(key))); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := loadSecretFiles(v, flags); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// newEnvFlagSet exposes every key as --lower-kebab-case. Unknown flags are
+// ignored so binaries with their own flags (e.g. the admin CLI) can share args.
+func newEnvFlagSet() *pflag.FlagSet {
+	flags := pflag.NewFlagSet("env", pflag.ContinueOnError)
+	flags.ParseErrorsAllowlist.UnknownFlags = true
+	flags.Usage = func() {}
+	flags.String(configFileFlag, "", "path to a dotenv config file (default .env)")
+	for _, key := range envKeys() {
+		flags.String(flagName(key), "", "overrides "+key)
+	}
+	return flags
+}
+
+// AddEnvFlags registers every configuration flag on flags, hidden from its
+// usage. A binary with its own flags can then reject unknown flags and still
+// accept any server setting.
+func AddEnvFlags(flags *pflag.FlagSet) {
+	newEnvFlagSet().VisitAll(func(f *pflag.Flag) {
+		f.Hidden = true
+		flags.AddFlag(f)
+	})
+}
+
+// loadSecretFiles reads <KEY>_FILE for secret keys (Docker/Kubernetes secrets).
+// An explicit flag still wins over the file.
+func loadSecretFiles(v *viper.Viper, flags *pflag.FlagSet) error {
+	var errs []error
+	for _, key := range secretKeys() {
+		fileKey := key + "_FILE"
+		_ = v.BindEnv(fileKey)
+
+		path := v.GetString(fileKey)
+		if path == "" || flags.Changed(flagName(key)) {
+			continue
+		}
+
+		content, err := os.ReadFile(path) // #nosec G304 -- path comes from operator configuration
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", fileKey, err))
+			continue
+		}
+		v.Set(key, strings.TrimSpace(string(content)))
+	}
+	return errors.Join(errs...)
+}
+
+func flagName(key string) string {
+	return strings.ReplaceAll(strings.ToLower(key), "_", "-")
+}
+
+func envKeys() []string {
+	return taggedKeys(func(reflect.StructField) bool { return true })
+}
+
+func secretKeys() []string {
+	return taggedKeys(func(f reflect.StructField) bool { return f.Tag.Get("secret") == "true" })
+}
+
+func taggedKeys(match func(reflect.StructField) bool) []string {
+	t := reflect.TypeOf(Env{})
+	keys := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); match(f) {
+			keys = append(keys, f.Tag.Get("mapstructure"))
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Redacted returns every setting keyed by its variable name with secrets
+// masked, for logging on boot.
+func (env *Env) Redacted() map[string]any {
+	out := make(map[string]any)
+	v := reflect.ValueOf(*env)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		value := v.Field(i).Interface()
+		if f.Tag.Get("secret") == "true" {
+			if v.Field(i).IsZero() {
+				value = ""
+			} else {
+				value = "[REDACTED]"
+			}
+		}
+		out[f.Tag.Get("mapstructure")] = value
+	}
+	return out
+}
@@ -0,0 +1,96 @@
+package bootstrap
+
+import (
+	"log/slog"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/health"
+	"github.com/Simpolette/HeartSteal/server/internal/logger"
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// WatchEnv re-loads the configuration whenever the config file changes and
+// passes the validated result to apply. Invalid edits are logged and ignored so
+// a typo never takes down a running server. Without a config file it is a no-op.
+func WatchEnv(args []string, apply func(updated *Env)) {
+	path, _ := ConfigFile(args)
+	if _, err := os.Stat(path); err != nil {
+		return
+	}
+
+	w := viper.New()
+	w.SetConfigFile(path)
+	w.SetConfigType("env")
+	w.OnConfigChange(func(fsnotify.Event) {
+		updated, err := LoadEnv(args)
+		if err != nil {
+			slog.Warn("Config change rejected", "file", path, "error", err)
+			return
+		}
+		apply(updated)
+	})
+	w.WatchConfig()
+}
+
+// ChangedKeys compares two configurations and splits the changed keys into
+// those that can be applied live (reload:"true") and those needing a restart.
+func ChangedKeys(current, updated *Env) (reloadable []string, restart []string) {
+	cur := reflect.ValueOf(*current)
+	upd := reflect.ValueOf(*updated)
+	t := cur.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if reflect.DeepEqual(cur.Field(i).Interface(), upd.Field(i).Interface()) {
+			continue
+		}
+		key := t.Field(i).Tag.Get("mapstructure")
+		if t.Field(i).Tag.Get("reload") == "true" {
+			reloadable = append(reloadable, key)
+		} else {
+			restart = append(restart, key)
+		}
+	}
+	return reloadable, restart
+}
+
+// Reloader applies config edits to the running settings that support it. The
+// startup Env is never written; Current returns the configuration in effect.
+// Safe for concurrent use, as fsnotify callbacks may overlap with readers.
+type Reloader struct {
+	mu       sync.Mutex
+	current  Env
+	logLevel *slog.LevelVar
+	health   *health.Registry
+}
+
+func NewReloader(env *Env, logLevel *slog.LevelVar, health *health.Registry) *Reloader {
+	return &Reloader{current: *env, logLevel: logLevel, health: health}
+}
+
+// Apply switches to updated's reloadable settings and returns the changed
+// keys, split like ChangedKeys.
+func (r *Reloader) Apply(updated *Env) (reloadable []string, restart []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reloadable, restart = ChangedKeys(&r.current, updated)
+	if len(reloadable) == 0 {
+		return reloadable, restart
+	}
+
+	r.logLevel.Set(logger.ParseLevel(updated.LogLevel))
+	r.health.SetCacheTTL(time.Duration(updated.ReadinessCacheSeconds) * time.Second)
+	r.current.LogLevel = updated.LogLevel
+	r.current.ReadinessCacheSeconds = updated.ReadinessCacheSeconds
+	return reloadable, restart
+}
+
+// Current returns a copy of the configuration in effect.
+func (r *Reloader) Current() Env {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current
+}
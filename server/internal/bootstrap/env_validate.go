@@ -0,0 +1,103 @@
+package bootstrap
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/Simpolette/HeartSteal/server/internal/origin"
+)
+
+const productionSecretMinLength = 32
+
+// Validate checks every setting and returns all problems joined together.
+func (env *Env) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	oneOf := func(key, value string, allowed ...string) {
+		for _, a := range allowed {
+			if value == a {
+				return
+			}
+		}
+		errs = append(errs, fmt.Errorf("%s must be one of %s, got %q", key, strings.Join(allowed, ", "), value))
+	}
+
+	oneOf("APP_ENV", env.AppEnv, "development", "test", "staging", "production")
+	check(env.ServerAddress != "", "SERVER_ADDRESS is required")
+	check(env.ContextTimeout > 0, "CONTEXT_TIMEOUT must be a positive number of seconds, got %d", env.ContextTimeout)
+	check(env.ShutdownTimeout > 0, "SHUTDOWN_TIMEOUT must be a positive number of seconds, got %d", env.ShutdownTimeout)
+	check(env.ReadinessCheckTimeout > 0, "READINESS_CHECK_TIMEOUT must be a positive number of seconds, got %d", env.ReadinessCheckTimeout)
+	check(env.ReadinessCacheSeconds >= 0, "READINESS_CACHE_SECONDS must not be negative, got %d", env.ReadinessCacheSeconds)
+
+	check(env.DBUser != "", "DB_USER is required")
+	check(env.DBPass != "", "DB_PASS is required")
+	check(env.DBName != "", "DB_NAME is required")
+
+	check(env.AccessTokenSecret != "", "ACCESS_TOKEN_SECRET is required")
+	check(env.AccessTokenExpiryHour > 0, "ACCESS_TOKEN_EXPIRY_HOUR must be positive, got %d", env.AccessTokenExpiryHour)
+	check(env.RefreshTokenExpiryHour >= 0, "REFRESH_TOKEN_EXPIRY_HOUR must not be negative, got %d", env.RefreshTokenExpiryHour)
+	check(env.UserCacheSeconds >= 0, "USER_CACHE_SECONDS must not be negative, got %d", env.UserCacheSeconds)
+	if env.AppEnv == "production" {
+		check(len(env.AccessTokenSecret) >= productionSecretMinLength,
+			"ACCESS_TOKEN_SECRET must be at least %d characters in production", productionSecretMinLength)
+	}
+
+	oneOf("LOG_LEVEL", strings.ToLower(env.LogLevel), "debug", "info", "warn", "error")
+	oneOf("LOG_FORMAT", env.LogFormat, "json", "text")
+
+	oneOf("TRACING_EXPORTER", env.TracingExporter, "none", "stdout", "otlp")
+	check(env.TracingSampleRatio >= 0 && env.TracingSampleRatio <= 1,
+		"TRACING_SAMPLE_RATIO must be between 0 and 1, got %v", env.TracingSampleRatio)
+
+	oneOf("RATE_LIMIT_STORE", env.RateLimitStore, "memory", "redis")
+	if env.RateLimitEnabled && env.RateLimitStore == "redis" {
+		check(env.RedisAddress != "", "REDIS_ADDRESS is required when RATE_LIMIT_STORE is redis")
+	}
+
+	check(!(env.CORSAllowCredentials && slices.Contains(env.CORSAllowedOrigins, "*")),
+		"CORS_ALLOWED_ORIGINS can't contain \"*\" while CORS_ALLOW_CREDENTIALS is true")
+	if _, err := origin.NewMatcher(env.CORSAllowedOrigins); err != nil {
+		errs = append(errs, fmt.Errorf("CORS_ALLOWED_ORIGINS: %w", err))
+	}
+	check(env.CORSMaxAgeSeconds >= 0, "CORS_MAX_AGE_SECONDS must not be negative, got %d", env.CORSMaxAgeSeconds)
+	for _, proxy := range env.TrustedProxies {
+		check(validProxy(proxy), "TRUSTED_PROXIES must contain IPs or CIDR ranges, got %q", proxy)
+	}
+	check(env.HSTSMaxAgeSeconds >= 0, "HSTS_MAX_AGE_SECONDS must not be negative, got %d", env.HSTSMaxAgeSeconds)
+	oneOf("REFERRER_POLICY", env.ReferrerPolicy, "no-referrer", "no-referrer-when-downgrade", "origin",
+		"origin-when-cross-origin", "same-origin", "strict-origin", "strict-origin-when-cross-origin", "unsafe-url")
+
+	check(!env.SeedOnStartup || env.AppEnv == "development", "SEED_ON_STARTUP is only allowed when APP_ENV is development")
+	check(env.SeedUsers > 0, "SEED_USERS must be positive, got %d", env.SeedUsers)
+
+	check(env.WSSendQueueSize > 0, "WS_SEND_QUEUE_SIZE must be positive, got %d", env.WSSendQueueSize)
+	check(env.WSMaxSubscriptions > 0, "WS_MAX_SUBSCRIPTIONS must be positive, got %d", env.WSMaxSubscriptions)
+	check(env.WSMaxMessageBytes > 0, "WS_MAX_MESSAGE_BYTES must be positive, got %d", env.WSMaxMessageBytes)
+	check(env.WSPingIntervalSeconds > 0, "WS_PING_INTERVAL_SECONDS must be a positive number of seconds, got %d", env.WSPingIntervalSeconds)
+	check(env.WSTicketTTLSeconds > 0, "WS_TICKET_TTL_SECONDS must be a positive number of seconds, got %d", env.WSTicketTTLSeconds)
+
+	oneOf("MATCHMAKING_QUEUE", env.MatchmakingQueue, "memory")
+	check(env.MatchAcceptSeconds > 0, "MATCH_ACCEPT_SECONDS must be a positive number of seconds, got %d", env.MatchAcceptSeconds)
+	check(env.SpectatorDelaySeconds >= 0, "SPECTATOR_DELAY_SECONDS can't be negative, got %d", env.SpectatorDelaySeconds)
+	check(env.ReconnectGraceSeconds > 0, "RECONNECT_GRACE_SECONDS must be a positive number of seconds, got %d", env.ReconnectGraceSeconds)
+	oneOf("DISCONNECT_POLICY", env.DisconnectPolicy, "forfeit", "bot")
+	oneOf("DISCONNECT_BOT_LEVEL", env.DisconnectBotLevel, "easy", "normal", "hard")
+	check(env.BotMoveSeconds >= 0, "BOT_MOVE_SECONDS can't be negative, got %d", env.BotMoveSeconds)
+	check(env.MatchLeaseSeconds >= 3, "MATCH_LEASE_SECONDS must be at least 3 seconds, got %d", env.MatchLeaseSeconds)
+
+	return errors.Join(errs...)
+}
+
+func validProxy(proxy string) bool {
+	if _, _, err := net.ParseCIDR(proxy); err == nil {
+		return true
+	}
+	return net.ParseIP(proxy) != nil
+}
@@ -0,0 +1,19 @@
+package bootstrap
+
+import (
+	"github.com/Simpolette/HeartSteal/server/internal/logger"
+	"github.com/Simpolette/HeartSteal/server/internal/matchmaking"
+)
+
+// NewMatchmakingQueue picks the queue backend from MATCHMAKING_QUEUE. Only
+// the in-process queue exists so far, so matchmaking needs a single
+// instance.
+func NewMatchmakingQueue(env *Env) matchmaking.Queue {
+	switch env.MatchmakingQueue {
+	case "", "memory":
+		return matchmaking.NewMemoryQueue()
+	default:
+		logger.Fatal("Unknown matchmaking queue", "queue", env.MatchmakingQueue)
+		return nil
+	}
+}
@@ -0,0 +1,37 @@
+package bootstrap
+
+import (
+	"context"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/health"
+	"github.com/Simpolette/HeartSteal/server/internal/logger"
+	"github.com/Simpolette/HeartSteal/server/internal/ratelimit"
+	"github.com/redis/go-redis/v9"
+)
+
+// NewRateLimitStore picks the limiter backend from RATE_LIMIT_STORE. The Redis
+// client, when used, is returned so it can be closed on shutdown.
+func NewRateLimitStore(env *Env, registry *health.Registry) (ratelimit.Store, *redis.Client) {
+	if !env.RateLimitEnabled {
+		return nil, nil
+	}
+
+	switch env.RateLimitStore {
+	case "", "memory":
+		return ratelimit.NewMemoryStore(), nil
+	case "redis":
+		client := redis.NewClient(&redis.Options{
+			Addr:     env.RedisAddress,
+			Password: env.RedisPassword,
+			DB:       env.RedisDB,
+		})
+		registry.Register("redis", time.Duration(env.ReadinessCheckTimeout)*time.Second, func(ctx context.Context) error {
+			return client.Ping(ctx).Err()
+		})
+		return ratelimit.NewRedisStore(client), client
+	default:
+		logger.Fatal("Unknown rate limit store", "store", env.RateLimitStore)
+		return nil, nil
+	}
+}
@@ -0,0 +1,32 @@
+package bootstrap
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/logger"
+	"github.com/Simpolette/HeartSteal/server/internal/metrics"
+	"github.com/Simpolette/HeartSteal/server/internal/origin"
+	"github.com/Simpolette/HeartSteal/server/internal/realtime"
+)
+
+// NewRealtimeHub builds the WebSocket hub. Browsers always send an Origin on
+// WebSocket upgrades, so it must be one CORS allows; clients that send none
+// are not browsers and are accepted.
+func NewRealtimeHub(env *Env, m *metrics.Metrics) *realtime.Hub {
+	origins, err := origin.NewMatcher(env.CORSAllowedOrigins)
+	if err != nil {
+		logger.Fatal("Could not configure WebSocket origins", "error", err)
+	}
+
+	cfg := realtime.DefaultConfig()
+	cfg.SendQueueSize = env.WSSendQueueSize
+	cfg.MaxSubscriptions = env.WSMaxSubscriptions
+	cfg.MaxMessageBytes = env.WSMaxMessageBytes
+	cfg.PingInterval = time.Duration(env.WSPingIntervalSeconds) * time.Second
+	cfg.CheckOrigin = func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		return origin == "" || origins.Match(origin)
+	}
+	return realtime.NewHub(cfg, m)
+}
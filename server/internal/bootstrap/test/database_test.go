@@ -0,0 +1,65 @@
+package bootstrap_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/event"
+
+	"github.com/Simpolette/HeartSteal/server/internal/bootstrap"
+)
+
+// recordingMonitor appends the name of every event it sees to calls.
+func recordingMonitor(name string, calls *[]string) *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started:   func(context.Context, *event.CommandStartedEvent) { *calls = append(*calls, name+".started") },
+		Succeeded: func(context.Context, *event.CommandSucceededEvent) { *calls = append(*calls, name+".succeeded") },
+		Failed:    func(context.Context, *event.CommandFailedEvent) { *calls = append(*calls, name+".failed") },
+	}
+}
+
+func TestCombineMonitors(t *testing.T) {
+	t.Run("None", func(t *testing.T) {
+		assert.Nil(t, bootstrap.CombineMonitors())
+		assert.Nil(t, bootstrap.CombineMonitors(nil, nil))
+	})
+
+	t.Run("Single", func(t *testing.T) {
+		var calls []string
+		monitor := recordingMonitor("metrics", &calls)
+
+		assert.Same(t, monitor, bootstrap.CombineMonitors(nil, monitor))
+	})
+
+	t.Run("FansOutInOrder", func(t *testing.T) {
+		var calls []string
+		combined := bootstrap.CombineMonitors(recordingMonitor("metrics", &calls), nil, recordingMonitor("tracing", &calls))
+		require.NotNil(t, combined)
+		ctx := context.Background()
+
+		combined.Started(ctx, &event.CommandStartedEvent{})
+		combined.Succeeded(ctx, &event.CommandSucceededEvent{})
+		combined.Failed(ctx, &event.CommandFailedEvent{})
+
+		assert.Equal(t, []string{
+			"metrics.started", "tracing.started",
+			"metrics.succeeded", "tracing.succeeded",
+			"metrics.failed", "tracing.failed",
+		}, calls)
+	})
+
+	t.Run("PartialMonitors", func(t *testing.T) {
+		var calls []string
+		startedOnly := &event.CommandMonitor{
+			Started: func(context.Context, *event.CommandStartedEvent) { calls = append(calls, "started-only") },
+		}
+		combined := bootstrap.CombineMonitors(startedOnly, recordingMonitor("tracing", &calls))
+
+		combined.Started(context.Background(), &event.CommandStartedEvent{})
+		combined.Failed(context.Background(), &event.CommandFailedEvent{})
+
+		assert.Equal(t, []string{"started-only", "tracing.started", "tracing.failed"}, calls)
+	})
+}
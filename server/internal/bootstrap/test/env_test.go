@@ -0,0 +1,271 @@
+package bootstrap_test
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Simpolette/HeartSteal/server/internal/bootstrap"
+	"github.com/Simpolette/HeartSteal/server/internal/health"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+// requiredEnv sets the settings that have no default.
+func requiredEnv(t *testing.T) {
+	t.Setenv("DB_USER", "user")
+	t.Setenv("DB_PASS", "pass")
+	t.Setenv("DB_NAME", "heartsteal")
+	t.Setenv("ACCESS_TOKEN_SECRET", "secret")
+}
+
+func TestEnv_Load(t *testing.T) {
+	t.Run("WithoutConfigFile", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		requiredEnv(t)
+
+		env, err := bootstrap.LoadEnv(nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, ":8080", env.ServerAddress)
+		assert.Equal(t, 2, env.ContextTimeout)
+		assert.Equal(t, "secret", env.AccessTokenSecret)
+	})
+
+	t.Run("LayerPrecedence", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		requiredEnv(t)
+		config := writeFile(t, "app.env", "SERVER_ADDRESS=:7000\nCONTEXT_TIMEOUT=5\nLOG_LEVEL=warn\n")
+		t.Setenv("CONTEXT_TIMEOUT", "9")
+
+		env, err := bootstrap.LoadEnv([]string{"--config", config, "--log-level", "debug"})
+
+		require.NoError(t, err)
+		assert.Equal(t, ":7000", env.ServerAddress, "file overrides default")
+		assert.Equal(t, 9, env.ContextTimeout, "env overrides file")
+		assert.Equal(t, "debug", env.LogLevel, "flag overrides everything")
+	})
+
+	t.Run("MissingExplicitConfigFile", func(t *testing.T) {
+		requiredEnv(t)
+
+		_, err := bootstrap.LoadEnv([]string{"--config", "/does/not/exist.env"})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("SecretFromFile", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		requiredEnv(t)
+		t.Setenv("ACCESS_TOKEN_SECRET", "")
+		t.Setenv("ACCESS_TOKEN_SECRET_FILE", writeFile(t, "secret", "from-file\n"))
+
+		env, err := bootstrap.LoadEnv(nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, "from-file", env.AccessTokenSecret)
+	})
+
+	t.Run("AggregatedValidationErrors", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		t.Setenv("CONTEXT_TIMEOUT", "0")
+		t.Setenv("USER_CACHE_SECONDS", "-1")
+		t.Setenv("LOG_FORMAT", "xml")
+
+		_, err := bootstrap.LoadEnv(nil)
+
+		require.Error(t, err)
+		for _, key := range []string{"CONTEXT_TIMEOUT", "LOG_FORMAT", "DB_USER", "DB_PASS", "DB_NAME", "ACCESS_TOKEN_SECRET", "USER_CACHE_SECONDS"} {
+			assert.Contains(t, err.Error(), key)
+		}
+	})
+
+	t.Run("CommaSeparatedLists", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		requiredEnv(t)
+		t.Setenv("CORS_ALLOWED_ORIGINS", "https://heartsteal.gg,https://*.heartsteal.gg")
+		t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8,192.168.1.10")
+
+		env, err := bootstrap.LoadEnv(nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://heartsteal.gg", "https://*.heartsteal.gg"}, env.CORSAllowedOrigins)
+		assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.10"}, env.TrustedProxies)
+	})
+
+	t.Run("InvalidCORSAndProxies", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		requiredEnv(t)
+		t.Setenv("CORS_ALLOWED_ORIGINS", "*")
+		t.Setenv("TRUSTED_PROXIES", "load-balancer")
+
+		_, err := bootstrap.LoadEnv(nil)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "CORS_ALLOW_CREDENTIALS")
+		assert.Contains(t, err.Error(), "TRUSTED_PROXIES")
+	})
+
+	t.Run("SeedOnlyInDevelopment", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		requiredEnv(t)
+		t.Setenv("SEED_ON_STARTUP", "true")
+
+		_, err := bootstrap.LoadEnv(nil)
+		assert.NoError(t, err)
+
+		t.Setenv("APP_ENV", "staging")
+		_, err = bootstrap.LoadEnv(nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "SEED_ON_STARTUP")
+	})
+
+	t.Run("InvalidWebSocketLimits", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		requiredEnv(t)
+		t.Setenv("WS_SEND_QUEUE_SIZE", "0")
+		t.Setenv("WS_TICKET_TTL_SECONDS", "-1")
+
+		_, err := bootstrap.LoadEnv(nil)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "WS_SEND_QUEUE_SIZE")
+		assert.Contains(t, err.Error(), "WS_TICKET_TTL_SECONDS")
+	})
+
+	t.Run("InvalidMatchmaking", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		requiredEnv(t)
+		t.Setenv("MATCHMAKING_QUEUE", "redis")
+		t.Setenv("MATCH_ACCEPT_SECONDS", "0")
+		t.Setenv("SPECTATOR_DELAY_SECONDS", "-1")
+		t.Setenv("RECONNECT_GRACE_SECONDS", "0")
+		t.Setenv("DISCONNECT_POLICY", "kick")
+		t.Setenv("DISCONNECT_BOT_LEVEL", "expert")
+		t.Setenv("BOT_MOVE_SECONDS", "-1")
+		t.Setenv("MATCH_LEASE_SECONDS", "1")
+
+		_, err := bootstrap.LoadEnv(nil)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "MATCHMAKING_QUEUE")
+		assert.Contains(t, err.Error(), "MATCH_ACCEPT_SECONDS")
+		assert.Contains(t, err.Error(), "SPECTATOR_DELAY_SECONDS")
+		assert.Contains(t, err.Error(), "RECONNECT_GRACE_SECONDS")
+		assert.Contains(t, err.Error(), "DISCONNECT_POLICY")
+		assert.Contains(t, err.Error(), "DISCONNECT_BOT_LEVEL")
+		assert.Contains(t, err.Error(), "BOT_MOVE_SECONDS")
+		assert.Contains(t, err.Error(), "MATCH_LEASE_SECONDS")
+	})
+
+	t.Run("UnknownFlagsIgnored", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+		requiredEnv(t)
+
+		_, err := bootstrap.LoadEnv([]string{"--output", "json", "users", "list"})
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestEnv_Redacted(t *testing.T) {
+	env := &bootstrap.Env{DBUser: "user", DBPass: "hunter2", AccessTokenSecret: "s3cr3t"}
+
+	dump := env.Redacted()
+
+	assert.Equal(t, "user", dump["DB_USER"])
+	assert.Equal(t, "[REDACTED]", dump["DB_PASS"])
+	assert.Equal(t, "[REDACTED]", dump["ACCESS_TOKEN_SECRET"])
+	assert.Equal(t, "", dump["REDIS_PASSWORD"])
+}
+
+func TestEnv_ChangedKeys(t *testing.T) {
+	current := &bootstrap.Env{LogLevel: "info", ServerAddress: ":8080"}
+	updated := &bootstrap.Env{LogLevel: "debug", ServerAddress: ":9000"}
+
+	reloadable, restart := bootstrap.ChangedKeys(current, updated)
+
+	assert.Equal(t, []string{"LOG_LEVEL"}, reloadable)
+	assert.Equal(t, []string{"SERVER_ADDRESS"}, restart)
+}
+
+func TestReloader_Apply(t *testing.T) {
+	t.Run("ReloadableOnly", func(t *testing.T) {
+		level := new(slog.LevelVar)
+		current := &bootstrap.Env{LogLevel: "info", ServerAddress: ":8080"}
+		reloader := bootstrap.NewReloader(current, level, health.NewRegistry(0))
+
+		reloadable, restart := reloader.Apply(&bootstrap.Env{LogLevel: "debug", ServerAddress: ":9000", ReadinessCacheSeconds: 3})
+
+		assert.Equal(t, []string{"LOG_LEVEL", "READINESS_CACHE_SECONDS"}, reloadable)
+		assert.Equal(t, []string{"SERVER_ADDRESS"}, restart)
+		assert.Equal(t, slog.LevelDebug, level.Level())
+		assert.Equal(t, "debug", reloader.Current().LogLevel)
+		assert.Equal(t, 3, reloader.Current().ReadinessCacheSeconds)
+		assert.Equal(t, ":8080", reloader.Current().ServerAddress)
+		assert.Equal(t, "info", current.LogLevel, "the startup env is never written")
+	})
+
+	// Run with -race: config rewrites are applied from fsnotify callbacks
+	// while other goroutines read the settings.
+	t.Run("ConfigRewrites", func(t *testing.T) {
+		requiredEnv(t)
+		config := writeFile(t, "app.env", "LOG_LEVEL=info\n")
+		args := []string{"--config", config}
+		env, err := bootstrap.LoadEnv(args)
+		require.NoError(t, err)
+		level := new(slog.LevelVar)
+		reloader := bootstrap.NewReloader(env, level, health.NewRegistry(0))
+		bootstrap.WatchEnv(args, func(updated *bootstrap.Env) { reloader.Apply(updated) })
+
+		done := make(chan struct{})
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					_ = reloader.Current().ReadinessCacheSeconds
+					_ = level.Level()
+				}
+			}
+		}()
+		var applies sync.WaitGroup
+		levels := []string{"debug", "warn", "error"}
+		for i := range 10 {
+			content := fmt.Sprintf("LOG_LEVEL=%s\nREADINESS_CACHE_SECONDS=%d\n", levels[i%len(levels)], i)
+			require.NoError(t, os.WriteFile(config, []byte(content), 0o600))
+			// Overlapping callbacks and direct applies must not race.
+			applies.Add(1)
+			go func() {
+				defer applies.Done()
+				reloader.Apply(&bootstrap.Env{LogLevel: "info", ReadinessCacheSeconds: i})
+			}()
+			time.Sleep(10 * time.Millisecond)
+		}
+		applies.Wait()
+		require.NoError(t, os.WriteFile(config, []byte("LOG_LEVEL=warn\nREADINESS_CACHE_SECONDS=42\n"), 0o600))
+
+		assert.Eventually(t, func() bool {
+			current := reloader.Current()
+			return current.LogLevel == "warn" && current.ReadinessCacheSeconds == 42
+		}, 2*time.Second, 10*time.Millisecond)
+		close(done)
+		wg.Wait()
+		assert.Equal(t, slog.LevelWarn, level.Level())
+	})
+}
@@ -0,0 +1,138 @@
+// Package bot plays HeartSteal for seats without a player at the keyboard:
+// bot members a lobby host added and players who dropped out of a match.
+// A Bot only sees its seat's game.View and answers with the action a
+// player's client would send, so its moves go through the same checks as
+// everyone else's. Like game, it does no I/O.
+package bot
+
+import (
+	"errors"
+	"math/rand/v2"
+
+	"github.com/Simpolette/HeartSteal/server/internal/game"
+)
+
+var ErrUnknownLevel = errors.New("unknown bot level")
+
+// seedStream separates a bot's random stream from the engine's.
+const seedStream = 0x426f745374726d
+
+type Level string
+
+const (
+	// Easy plays a random legal move.
+	Easy Level = "easy"
+	// Normal plays the move a simple heuristic scores best.
+	Normal Level = "normal"
+	// Hard plays the move that leaves it best placed once every opponent
+	// has answered it.
+	Hard Level = "hard"
+
+	DefaultLevel = Normal
+)
+
+func (l Level) Valid() bool {
+	switch l {
+	case Easy, Normal, Hard:
+		return true
+	}
+	return false
+}
+
+// Bot chooses the move for the seat of the view it is given. It is only
+// asked on that seat's turn.
+type Bot interface {
+	Act(view game.View) game.Action
+}
+
+// New returns a bot playing at level. Bots that pick at random draw from
+// seed, so the same seed and views give the same moves.
+func New(level Level, seed int64) (Bot, error) {
+	rng := rand.New(rand.NewPCG(uint64(seed), seedStream))
+	switch level {
+	case Easy:
+		return &randomBot{rng: rng}, nil
+	case Normal:
+		return &scoringBot{score: heuristic, rng: rng}, nil
+	case Hard:
+		return &scoringBot{score: lookahead, rng: rng}, nil
+	}
+	return nil, ErrUnknownLevel
+}
+
+// Legal lists the actions view's seat may take, in hand order and then by
+// target seat. It is empty unless it is that seat's turn.
+func Legal(view game.View) []game.Action {
+	seat := view.Seat
+	if view.Over || seat < 0 || seat != view.Turn {
+		return nil
+	}
+	var actions []game.Action
+	for _, card := range view.Players[seat].Hand {
+		if card.Kind == game.CardShield {
+			actions = append(actions, game.Action{Seat: seat, Card: card.ID, Target: seat})
+			continue
+		}
+		for target, p := range view.Players {
+			if target != seat && !p.Out {
+				actions = append(actions, game.Action{Seat: seat, Card: card.ID, Target: target})
+			}
+		}
+	}
+	return actions
+}
+
+// pass is what a bot answers when it has no move; the game rejects it.
+func pass(view game.View) game.Action {
+	return game.Action{Seat: view.Seat, Card: -1, Target: -1}
+}
+
+type randomBot struct {
+	rng *rand.Rand
+}
+
+func (b *randomBot) Act(view game.View) game.Action {
+	actions := Legal(view)
+	if len(actions) == 0 {
+		return pass(view)
+	}
+	return actions[b.rng.IntN(len(actions))]
+}
+
+// scoringBot plays the legal action score rates highest, breaking ties at
+// random.
+type scoringBot struct {
+	score func(view game.View, a game.Action, card game.Card) float64
+	rng   *rand.Rand
+}
+
+func (b *scoringBot) Act(view game.View) game.Action {
+	actions := Legal(view)
+	if len(actions) == 0 {
+		return pass(view)
+	}
+	hand := view.Players[view.Seat].Hand
+	var best []game.Action
+	var bestScore float64
+	for _, a := range actions {
+		score := b.score(view, a, cardByID(hand, a.Card))
+		switch {
+		case len(best) == 0 || score > bestScore+epsilon:
+			best, bestScore = []game.Action{a}, score
+		case score > bestScore-epsilon:
+			best = append(best, a)
+		}
+	}
+	return best[b.rng.IntN(len(best))]
+}
+
+const epsilon = 1e-9
+
+func cardByID(hand []game.Card, id int) game.Card {
+	for _, c := range hand {
+		if c.ID == id {
+			return c
+		}
+	}
+	return game.Card{}
+}
@@ -0,0 +1,164 @@
+package bot
+
+import "github.com/Simpolette/HeartSteal/server/internal/game"
+
+// knockout is what putting an opponent out is worth on top of the hearts
+// it takes, since they can't steal back.
+const knockout = 2
+
+// heuristic rates a move by what it wins at once: the hearts it takes, more
+// for knocking a player out or hitting the leader. Shields are worth more
+// the fewer hearts there are to lose, and big cards cost a little so they
+// are kept for when they count.
+func heuristic(view game.View, a game.Action, card game.Card) float64 {
+	me := view.Players[view.Seat]
+	if card.Kind == game.CardShield {
+		switch {
+		case me.Shielded:
+			return -1
+		case me.Hearts <= 2:
+			return 2
+		}
+		return 1
+	}
+	target := view.Players[a.Target]
+	if target.Shielded {
+		// Breaking a shield takes nothing, so spend the smallest card on it.
+		return 0.5 - 0.1*float64(card.Value)
+	}
+	stolen := min(card.Value, target.Hearts)
+	score := float64(stolen) + 0.1*float64(target.Hearts) - 0.1*float64(card.Value)
+	if stolen == target.Hearts {
+		score += knockout
+	}
+	return score
+}
+
+// lookahead plays a on a model of the table, lets every opponent answer it
+// with the card they can expect to hold, aimed at whoever they would most
+// likely hit, and rates where that leaves the bot once its turn comes back.
+func lookahead(view game.View, a game.Action, card game.Card) float64 {
+	if card.Kind == game.CardShield && view.Players[view.Seat].Shielded {
+		// A second shield adds nothing.
+		return -100
+	}
+	t := newTable(view)
+	t.play(view.Seat, a.Target, card)
+	odds := oddsOf(view.Unseen())
+	for seat := (view.Seat + 1) % len(t.hearts); seat != view.Seat; seat = (seat + 1) % len(t.hearts) {
+		if !t.out[seat] && view.Players[seat].HandCount > 0 {
+			t.answer(seat, odds)
+		}
+	}
+	return t.rate(view.Seat, odds) - 0.05*float64(card.Value)
+}
+
+// odds describes a card drawn from the ones a bot can't see: a steal with
+// probability steal, taking value hearts on average, and a shield otherwise.
+type odds struct {
+	steal float64
+	value float64
+}
+
+func oddsOf(unseen []game.Card) odds {
+	steals, total := 0, 0
+	for _, c := range unseen {
+		if c.Kind == game.CardSteal {
+			steals++
+			total += c.Value
+		}
+	}
+	if steals == 0 {
+		return odds{}
+	}
+	return odds{steal: float64(steals) / float64(len(unseen)), value: float64(total) / float64(steals)}
+}
+
+// table models the public part of a game in expectation: opponents' answers
+// are averaged over the cards they might hold, so hearts may be fractions
+// and shielded is the chance a player's shield is up.
+type table struct {
+	hearts   []float64
+	shielded []float64
+	out      []bool
+}
+
+func newTable(view game.View) *table {
+	t := &table{
+		hearts:   make([]float64, len(view.Players)),
+		shielded: make([]float64, len(view.Players)),
+		out:      make([]bool, len(view.Players)),
+	}
+	for i, p := range view.Players {
+		t.hearts[i], t.out[i] = float64(p.Hearts), p.Out
+		if p.Shielded {
+			t.shielded[i] = 1
+		}
+	}
+	return t
+}
+
+func (t *table) play(seat, target int, card game.Card) {
+	if card.Kind == game.CardShield {
+		t.shielded[seat] = 1
+		return
+	}
+	t.steal(seat, target, float64(card.Value), 1)
+}
+
+// steal has seat play a steal of value on target with probability chance.
+func (t *table) steal(seat, target int, value, chance float64) {
+	hits := chance * (1 - t.shielded[target])
+	t.shielded[target] *= 1 - chance
+	stolen := hits * min(value, t.hearts[target])
+	t.hearts[target] -= stolen
+	t.hearts[seat] += stolen
+	// Less than half a heart left most likely means none.
+	if t.hearts[target] < 0.5 {
+		t.hearts[seat] += t.hearts[target]
+		t.hearts[target], t.out[target] = 0, true
+	}
+}
+
+// answer has seat play the card it can expect to hold: a shield for itself
+// or a steal on the likeliest target, the player with the most hearts who
+// is least likely to be shielded.
+func (t *table) answer(seat int, o odds) {
+	t.shielded[seat] += (1 - o.steal) * (1 - t.shielded[seat])
+	target := -1
+	for i := range t.hearts {
+		if i == seat || t.out[i] {
+			continue
+		}
+		if target < 0 || t.exposure(i) > t.exposure(target) {
+			target = i
+		}
+	}
+	if target >= 0 {
+		t.steal(seat, target, o.value, o.steal)
+	}
+}
+
+// exposure is how many hearts a steal on i can expect to take.
+func (t *table) exposure(i int) float64 {
+	return t.hearts[i] * (1 - t.shielded[i])
+}
+
+// rate scores the table for seat: its lead over the strongest opponent,
+// with knockouts on top and a shield worth the steal it may stop.
+func (t *table) rate(seat int, o odds) float64 {
+	if t.out[seat] {
+		return -100
+	}
+	score, rival := t.hearts[seat], 0.0
+	for i, h := range t.hearts {
+		switch {
+		case i == seat:
+		case t.out[i]:
+			score += knockout
+		default:
+			rival = max(rival, h)
+		}
+	}
+	return score + t.shielded[seat]*o.steal*o.value - rival
+}
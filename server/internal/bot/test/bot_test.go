@@ -0,0 +1,173 @@
+package bot_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Simpolette/HeartSteal/server/internal/bot"
+	"github.com/Simpolette/HeartSteal/server/internal/game"
+)
+
+// play has bots at levels play a game from seed to the end, failing on any
+// move the game rejects, and returns the placements.
+func play(t *testing.T, seed int64, levels ...bot.Level) []int {
+	t.Helper()
+	s, _, err := game.New(game.Config{Players: len(levels)}, seed)
+	require.NoError(t, err)
+	bots := make([]bot.Bot, len(levels))
+	for seat, level := range levels {
+		bots[seat], err = bot.New(level, seed+int64(seat))
+		require.NoError(t, err)
+	}
+	for !s.Over {
+		action := bots[s.Turn].Act(s.View(s.Turn))
+		_, err := game.Apply(s, action)
+		require.NoError(t, err, "%s bot at seat %d", levels[s.Turn], s.Turn)
+	}
+	return s.Placements
+}
+
+// wins counts the games of a duel between a and b that a wins outright,
+// swapping seats every game.
+func wins(t *testing.T, games int, a, b bot.Level) int {
+	won := 0
+	for i := range games {
+		seat := i % 2
+		levels := []bot.Level{b, b}
+		levels[seat] = a
+		placements := play(t, int64(i), levels...)
+		if placements[seat] == 1 && placements[1-seat] == 2 {
+			won++
+		}
+	}
+	return won
+}
+
+func steal(id, value int) game.Card {
+	return game.Card{ID: id, Kind: game.CardSteal, Value: value}
+}
+
+func shield(id int) game.Card {
+	return game.Card{ID: id, Kind: game.CardShield}
+}
+
+// duel is seat 0's view of its turn against one opponent.
+func duel(me, opponent game.PlayerView) game.View {
+	return game.View{Seat: 0, Players: []game.PlayerView{me, opponent}, DeckCount: 6, Discard: []game.Card{}, TurnNumber: 3}
+}
+
+func TestBot_New(t *testing.T) {
+	for _, level := range []bot.Level{bot.Easy, bot.Normal, bot.Hard} {
+		t.Run(string(level), func(t *testing.T) {
+			b, err := bot.New(level, 1)
+
+			require.NoError(t, err)
+			assert.NotNil(t, b)
+			assert.True(t, level.Valid())
+		})
+	}
+
+	t.Run("ErrorUnknownLevel", func(t *testing.T) {
+		_, err := bot.New("expert", 1)
+
+		assert.ErrorIs(t, err, bot.ErrUnknownLevel)
+		assert.False(t, bot.Level("expert").Valid())
+	})
+}
+
+func TestBot_Legal(t *testing.T) {
+	view := game.View{
+		Seat:    1,
+		Turn:    1,
+		Players: []game.PlayerView{{Hearts: 2}, {Hearts: 4, Hand: []game.Card{steal(3, 2), shield(5)}}, {Out: true}},
+	}
+
+	assert.Equal(t, []game.Action{
+		{Seat: 1, Card: 3, Target: 0},
+		{Seat: 1, Card: 5, Target: 1},
+	}, bot.Legal(view))
+
+	view.Turn = 0
+	assert.Empty(t, bot.Legal(view), "not its turn")
+}
+
+func TestBot_Act(t *testing.T) {
+	levels := []bot.Level{bot.Easy, bot.Normal, bot.Hard}
+
+	t.Run("PlaysLegalMoves", func(t *testing.T) {
+		for i := range 300 {
+			table := make([]bot.Level, game.MinPlayers+i%(game.MaxPlayers-game.MinPlayers+1))
+			for seat := range table {
+				table[seat] = levels[(i+seat)%len(levels)]
+			}
+			play(t, int64(i), table...)
+		}
+	})
+
+	t.Run("SameSeedSameGame", func(t *testing.T) {
+		for _, level := range levels {
+			assert.Equal(t, play(t, 7, level, level, level), play(t, 7, level, level, level))
+		}
+	})
+
+	t.Run("NotItsTurn", func(t *testing.T) {
+		s, _, err := game.New(game.Config{Players: 2}, 1)
+		require.NoError(t, err)
+		for _, level := range levels {
+			b, err := bot.New(level, 1)
+			require.NoError(t, err)
+
+			action := b.Act(s.View(1 - s.Turn))
+
+			assert.Error(t, game.Validate(s, action))
+		}
+	})
+
+	for _, level := range []bot.Level{bot.Normal, bot.Hard} {
+		b, err := bot.New(level, 1)
+		require.NoError(t, err)
+
+		t.Run(string(level)+"/KnocksOut", func(t *testing.T) {
+			view := duel(game.PlayerView{Hearts: 3, Hand: []game.Card{shield(6), steal(0, 1), steal(5, 2)}}, game.PlayerView{Hearts: 2, HandCount: 3})
+
+			assert.Equal(t, game.Action{Seat: 0, Card: 5, Target: 1}, b.Act(view))
+		})
+
+		t.Run(string(level)+"/KeepsSpareShield", func(t *testing.T) {
+			view := duel(game.PlayerView{Hearts: 2, Shielded: true, Hand: []game.Card{shield(6), steal(0, 1)}}, game.PlayerView{Hearts: 6, HandCount: 3})
+
+			assert.Equal(t, game.Action{Seat: 0, Card: 0, Target: 1}, b.Act(view))
+		})
+
+		t.Run(string(level)+"/BreaksShieldWithSmallestSteal", func(t *testing.T) {
+			view := duel(game.PlayerView{Hearts: 4, Hand: []game.Card{steal(5, 3), steal(0, 1), steal(3, 2)}}, game.PlayerView{Hearts: 4, Shielded: true, HandCount: 3})
+
+			assert.Equal(t, game.Action{Seat: 0, Card: 0, Target: 1}, b.Act(view))
+		})
+	}
+
+	t.Run("hard/HitsTheLeader", func(t *testing.T) {
+		b, err := bot.New(bot.Hard, 1)
+		require.NoError(t, err)
+		view := game.View{
+			Seat:    0,
+			Players: []game.PlayerView{{Hearts: 3, Hand: []game.Card{steal(0, 1)}}, {Hearts: 2, HandCount: 3}, {Hearts: 7, HandCount: 3}},
+			Discard: []game.Card{},
+		}
+
+		assert.Equal(t, game.Action{Seat: 0, Card: 0, Target: 2}, b.Act(view))
+	})
+}
+
+// Harder levels should win more duels against easier ones than they lose.
+func TestBot_Strength(t *testing.T) {
+	for _, pair := range [][2]bot.Level{{bot.Normal, bot.Easy}, {bot.Hard, bot.Easy}, {bot.Hard, bot.Normal}} {
+		t.Run(string(pair[0])+"Beats"+string(pair[1]), func(t *testing.T) {
+			stronger, weaker := wins(t, 1000, pair[0], pair[1]), wins(t, 1000, pair[1], pair[0])
+
+			assert.Greater(t, stronger, weaker)
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "context"
+
+// AdminUsecase backs operator tooling. It is not exposed over HTTP.
+type AdminUsecase interface {
+	// CreateUser registers user with a plain-text password and extra roles,
+	// bypassing the public signup rules (e.g. reserved usernames).
+	CreateUser(c context.Context, user *User) error
+	// ResetPassword sets a new password and ends the user's sessions.
+	ResetPassword(c context.Context, username string, password string) (*User, error)
+	GrantRole(c context.Context, username string, role Role) (*User, error)
+	RevokeRole(c context.Context, username string, role Role) (*User, error)
+	// Ban blocks future logins and ends the user's sessions.
+	Ban(c context.Context, username string, reason string) (*User, error)
+	Unban(c context.Context, username string) (*User, error)
+	ListSessions(c context.Context, username string) ([]Session, error)
+}
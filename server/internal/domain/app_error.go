@@ -0,0 +1,143 @@
+package domain
+
+import "net/http"
+
+// ErrorCode is a stable, machine-readable identifier returned to clients in
+// ErrorResponse.Code. Never rename an existing code; clients switch on them.
+type ErrorCode string
+
+const (
+	CodeInternal             ErrorCode = "INTERNAL_ERROR"
+	CodeInvalidRequest       ErrorCode = "INVALID_REQUEST"
+	CodeNotFound             ErrorCode = "NOT_FOUND"
+	CodeUnauthorized         ErrorCode = "UNAUTHORIZED"
+	CodeInvalidToken         ErrorCode = "INVALID_TOKEN"
+	CodeInvalidCredentials   ErrorCode = "INVALID_CREDENTIALS"
+	CodeUserNotFound         ErrorCode = "USER_NOT_FOUND"
+	CodeEmailExists          ErrorCode = "EMAIL_EXISTS"
+	CodeUsernameExists       ErrorCode = "USERNAME_EXISTS"
+	CodeRateLimited          ErrorCode = "RATE_LIMITED"
+	CodeUserBanned           ErrorCode = "USER_BANNED"
+	CodeInvalidCursor        ErrorCode = "INVALID_CURSOR"
+	CodeLobbyNotFound        ErrorCode = "LOBBY_NOT_FOUND"
+	CodeLobbyFull            ErrorCode = "LOBBY_FULL"
+	CodeAlreadyInLobby       ErrorCode = "ALREADY_IN_LOBBY"
+	CodeNotInLobby           ErrorCode = "NOT_IN_LOBBY"
+	CodeNotLobbyHost         ErrorCode = "NOT_LOBBY_HOST"
+	CodeCannotKickSelf       ErrorCode = "CANNOT_KICK_SELF"
+	CodeLobbyConflict        ErrorCode = "LOBBY_CONFLICT"
+	CodeInvalidTicket        ErrorCode = "INVALID_TICKET"
+	CodeUpgradeRequired      ErrorCode = "UPGRADE_REQUIRED"
+	CodeUnknownTopic         ErrorCode = "UNKNOWN_TOPIC"
+	CodeTopicForbidden       ErrorCode = "TOPIC_FORBIDDEN"
+	CodeTooManySubscriptions ErrorCode = "TOO_MANY_SUBSCRIPTIONS"
+	CodeLobbyInGame          ErrorCode = "LOBBY_IN_GAME"
+	CodeMatchNotFound        ErrorCode = "MATCH_NOT_FOUND"
+	CodeMatchOver            ErrorCode = "MATCH_OVER"
+	CodeNotYourTurn          ErrorCode = "NOT_YOUR_TURN"
+	CodeInvalidMove          ErrorCode = "INVALID_MOVE"
+	CodeAlreadyQueued        ErrorCode = "ALREADY_QUEUED"
+	CodeNotQueued            ErrorCode = "NOT_QUEUED"
+	CodeNoPendingMatch       ErrorCode = "NO_PENDING_MATCH"
+	CodeMatchmakingConflict  ErrorCode = "MATCHMAKING_CONFLICT"
+	CodeUnknownGameMode      ErrorCode = "UNKNOWN_GAME_MODE"
+	CodePartyTooLarge        ErrorCode = "PARTY_TOO_LARGE"
+	CodePartyNotFriends      ErrorCode = "PARTY_NOT_FRIENDS"
+	CodeNotRanked            ErrorCode = "NOT_RANKED"
+	CodeUnknownScope         ErrorCode = "UNKNOWN_LEADERBOARD_SCOPE"
+	CodeReplayNotFound       ErrorCode = "REPLAY_NOT_FOUND"
+	CodeSpectatingForbidden  ErrorCode = "SPECTATING_FORBIDDEN"
+	CodeUnknownPolicy        ErrorCode = "UNKNOWN_SPECTATOR_POLICY"
+	CodeUnknownBotLevel      ErrorCode = "UNKNOWN_BOT_LEVEL"
+	CodeAlreadyInMatch       ErrorCode = "ALREADY_IN_MATCH"
+)
+
+// ErrorCodes lists every code the API can return. Each one must have an
+// "error.<CODE>" entry in every shipped message catalog.
+var ErrorCodes = []ErrorCode{
+	CodeInternal,
+	CodeInvalidRequest,
+	CodeNotFound,
+	CodeUnauthorized,
+	CodeInvalidToken,
+	CodeInvalidCredentials,
+	CodeUserNotFound,
+	CodeEmailExists,
+	CodeUsernameExists,
+	CodeRateLimited,
+	CodeUserBanned,
+	CodeInvalidCursor,
+	CodeLobbyNotFound,
+	CodeLobbyFull,
+	CodeAlreadyInLobby,
+	CodeNotInLobby,
+	CodeNotLobbyHost,
+	CodeCannotKickSelf,
+	CodeLobbyConflict,
+	CodeInvalidTicket,
+	CodeUpgradeRequired,
+	CodeUnknownTopic,
+	CodeTopicForbidden,
+	CodeTooManySubscriptions,
+	CodeLobbyInGame,
+	CodeMatchNotFound,
+	CodeMatchOver,
+	CodeNotYourTurn,
+	CodeInvalidMove,
+	CodeAlreadyQueued,
+	CodeNotQueued,
+	CodeNoPendingMatch,
+	CodeMatchmakingConflict,
+	CodeUnknownGameMode,
+	CodePartyTooLarge,
+	CodePartyNotFriends,
+	CodeNotRanked,
+	CodeUnknownScope,
+	CodeReplayNotFound,
+	CodeSpectatingForbidden,
+	CodeUnknownPolicy,
+	CodeUnknownBotLevel,
+	CodeAlreadyInMatch,
+}
+
+// AppError is the typed error surfaced by the HTTP layer. Message is safe to
+// show to clients; Err is the internal cause and is only logged.
+type AppError struct {
+	Code    ErrorCode
+	Status  int
+	Message string
+	Details []FieldError
+	Err     error
+}
+
+func NewAppError(code ErrorCode, status int, message string, cause error) *AppError {
+	return &AppError{
+		Code:    code,
+		Status:  status,
+		Message: message,
+		Err:     cause,
+	}
+}
+
+func (e *AppError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError reports invalid client input field by field.
+func ValidationError(details []FieldError, cause error) *AppError {
+	err := NewAppError(CodeInvalidRequest, http.StatusBadRequest, "Request validation failed", cause)
+	err.Details = details
+	return err
+}
+
+// InternalError wraps an unexpected cause without exposing it to clients.
+func InternalError(cause error) *AppError {
+	return NewAppError(CodeInternal, http.StatusInternalServerError, "Internal server error", cause)
+}
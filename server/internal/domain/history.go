@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	CollectionStats = "player_stats"
+)
+
+// MatchResult is how a finished match went for one player. Sharing first
+// place counts as a win.
+type MatchResult string
+
+const (
+	MatchWon  MatchResult = "win"
+	MatchLost MatchResult = "loss"
+)
+
+// ResultOf returns the result of finishing at placement.
+func ResultOf(placement int) MatchResult {
+	if placement == 1 {
+		return MatchWon
+	}
+	return MatchLost
+}
+
+// MatchFilter narrows a match history. Zero fields match every match.
+type MatchFilter struct {
+	Mode   GameMode
+	Ranked *bool
+	// Result is from the point of view of the player whose history it is.
+	Result MatchResult
+}
+
+// HistoryEntry is a finished match from one player's point of view.
+type HistoryEntry struct {
+	Match
+	// Self is the entry of the player whose history it is.
+	Self MatchPlayer
+}
+
+// PlayerStats aggregates a user's finished matches. It is updated as each
+// match is saved rather than computed from the history on read.
+type PlayerStats struct {
+	UserID primitive.ObjectID `bson:"_id"    json:"user_id"`
+	Games  int                `bson:"games"  json:"games"`
+	Wins   int                `bson:"wins"   json:"wins"`
+	Losses int                `bson:"losses" json:"losses"`
+	// Streak is the current run of results: wins when positive, losses
+	// when negative.
+	Streak        int `bson:"streak"          json:"streak"`
+	BestWinStreak int `bson:"best_win_streak" json:"best_win_streak"`
+	// ModeGames counts games per matchmaking mode; lobby matches have none.
+	ModeGames    map[GameMode]int `bson:"mode_games"               json:"mode_games"`
+	LastPlayedAt *time.Time       `bson:"last_played_at,omitempty" json:"last_played_at,omitempty"`
+}
+
+// Add counts one more finished match.
+func (s *PlayerStats) Add(mode GameMode, result MatchResult, at time.Time) {
+	s.Games++
+	if result == MatchWon {
+		s.Wins++
+		s.Streak = max(s.Streak, 0) + 1
+		s.BestWinStreak = max(s.BestWinStreak, s.Streak)
+	} else {
+		s.Losses++
+		s.Streak = min(s.Streak, 0) - 1
+	}
+	if mode != "" {
+		if s.ModeGames == nil {
+			s.ModeGames = make(map[GameMode]int)
+		}
+		s.ModeGames[mode]++
+	}
+	if s.LastPlayedAt == nil || at.After(*s.LastPlayedAt) {
+		s.LastPlayedAt = &at
+	}
+}
+
+// FavoriteMode is the mode played most, ties going to the first in
+// GameModes. It is empty until a matchmaking game was played.
+func (s *PlayerStats) FavoriteMode() GameMode {
+	favorite := GameMode("")
+	for _, mode := range GameModes {
+		if s.ModeGames[mode] > s.ModeGames[favorite] {
+			favorite = mode
+		}
+	}
+	return favorite
+}
+
+// WinRate is the share of games won, 0 before the first game.
+func (s *PlayerStats) WinRate() float64 {
+	if s.Games == 0 {
+		return 0
+	}
+	return float64(s.Wins) / float64(s.Games)
+}
+
+type StatsRepository interface {
+	// Get returns userID's stats, zeroed if they never finished a match.
+	Get(c context.Context, userID primitive.ObjectID) (*PlayerStats, error)
+	// Save creates or replaces the stats of stats.UserID.
+	Save(c context.Context, stats *PlayerStats) error
+	// Delete removes userID's stats, if any.
+	Delete(c context.Context, userID primitive.ObjectID) error
+}
+
+type HistoryUsecase interface {
+	// Record adds a finished match to its players' stats. Call it inside
+	// the transaction that saves the match, so a result counts exactly once.
+	Record(c context.Context, match *Match) error
+	// Matches returns a page of username's finished matches, most recent
+	// first, and the cursor of the next one.
+	Matches(c context.Context, username string, filter MatchFilter, limit int, cursor string) ([]HistoryEntry, string, error)
+	// Stats returns username's aggregated stats.
+	Stats(c context.Context, username string) (*PlayerStats, error)
+}
@@ -0,0 +1,160 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Simpolette/HeartSteal/server/internal/bot"
+)
+
+var (
+	ErrLobbyNotFound   = errors.New("lobby not found")
+	ErrLobbyFull       = errors.New("lobby is full")
+	ErrAlreadyInLobby  = errors.New("user is already in another lobby")
+	ErrNotInLobby      = errors.New("user is not in the lobby")
+	ErrNotLobbyHost    = errors.New("only the lobby host can do this")
+	ErrCannotKickSelf  = errors.New("the host can't kick themselves")
+	ErrLobbyConflict   = errors.New("lobby was modified concurrently")
+	ErrInviteCodeTaken = errors.New("invite code already in use")
+)
+
+const (
+	CollectionLobby = "lobbies"
+	// IndexLobbyMember is the unique index on members.user_id that keeps a
+	// user in at most one lobby.
+	IndexLobbyMember = "members_user_id_unique"
+)
+
+const (
+	LobbyMinCapacity = 2
+	LobbyMaxCapacity = 8
+
+	DefaultTurnSeconds = 30
+	InviteCodeLength   = 6
+)
+
+type LobbyVisibility string
+
+const (
+	LobbyPublic  LobbyVisibility = "public"
+	LobbyPrivate LobbyVisibility = "private"
+)
+
+type LobbyStatus string
+
+const (
+	// LobbyOpen rooms accept players until they are full.
+	LobbyOpen LobbyStatus = "open"
+	// LobbyInGame rooms are playing the match named by MatchID. They start
+	// one as soon as they fill up and close once it ends.
+	LobbyInGame LobbyStatus = "in_game"
+)
+
+// Events published on LobbyTopic as members come and go.
+const (
+	EventLobbyUpdated = "lobby.updated"
+	EventLobbyClosed  = "lobby.closed"
+	// EventLobbyKicked is published on the kicked player's UserTopic.
+	EventLobbyKicked = "lobby.kicked"
+)
+
+// LobbyEvent is the payload of events that only need to name the lobby.
+type LobbyEvent struct {
+	LobbyID string `json:"lobby_id"`
+}
+
+type LobbySettings struct {
+	TurnSeconds int `bson:"turn_seconds" json:"turn_seconds"`
+}
+
+// LobbyMember keeps the names a room shows so listing rooms doesn't need a
+// user lookup per member. Bot members have an ID of their own that names no
+// user.
+type LobbyMember struct {
+	UserID      primitive.ObjectID `bson:"user_id"      json:"user_id"`
+	Username    string             `bson:"username"     json:"username"`
+	DisplayName string             `bson:"display_name" json:"display_name"`
+	JoinedAt    time.Time          `bson:"joined_at"    json:"joined_at"`
+	// Bot is the level of a member the server plays for.
+	Bot bot.Level `bson:"bot,omitempty" json:"bot,omitempty"`
+}
+
+// Lobby is a room players gather in before a match. Members are kept in join
+// order, which decides who becomes host when the host leaves. Version is
+// bumped on every update so concurrent joins and leaves can't overwrite each
+// other.
+type Lobby struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name       string             `bson:"name"          json:"name"`
+	Visibility LobbyVisibility    `bson:"visibility"    json:"visibility"`
+	Status     LobbyStatus        `bson:"status"        json:"status"`
+	Capacity   int                `bson:"capacity"      json:"capacity"`
+	Settings   LobbySettings      `bson:"settings"      json:"settings"`
+	InviteCode string             `bson:"invite_code"   json:"invite_code"`
+	HostID     primitive.ObjectID `bson:"host_id"       json:"host_id"`
+	Members    []LobbyMember      `bson:"members"       json:"members"`
+	MatchID    primitive.ObjectID `bson:"match_id,omitempty" json:"match_id,omitzero"`
+	Version    int64              `bson:"version"       json:"-"`
+	CreatedAt  time.Time          `bson:"created_at"    json:"created_at"`
+	UpdatedAt  time.Time          `bson:"updated_at"    json:"updated_at"`
+}
+
+func (l *Lobby) IsFull() bool {
+	return len(l.Members) >= l.Capacity
+}
+
+// MemberIndex returns the position of userID in Members, or -1.
+func (l *Lobby) MemberIndex(userID primitive.ObjectID) int {
+	for i, m := range l.Members {
+		if m.UserID == userID {
+			return i
+		}
+	}
+	return -1
+}
+
+func (l *Lobby) IsMember(userID primitive.ObjectID) bool {
+	return l.MemberIndex(userID) >= 0
+}
+
+type LobbyRepository interface {
+	// Create fails with ErrInviteCodeTaken if another lobby has the code.
+	Create(c context.Context, lobby *Lobby) error
+	// Update saves lobby if nobody changed it since it was read and bumps its
+	// Version; otherwise it fails with ErrLobbyConflict.
+	Update(c context.Context, lobby *Lobby) error
+	// Delete removes lobby under the same version check as Update.
+	Delete(c context.Context, lobby *Lobby) error
+	GetByID(c context.Context, id string) (*Lobby, error)
+	GetByInviteCode(c context.Context, code string) (*Lobby, error)
+	GetByMember(c context.Context, userID primitive.ObjectID) (*Lobby, error)
+	// ListOpenPublic returns public lobbies that are open and not full,
+	// newest first, starting after the cursor.
+	ListOpenPublic(c context.Context, limit int, after *Cursor) ([]Lobby, error)
+}
+
+type LobbyUsecase interface {
+	Create(c context.Context, userID string, lobby *Lobby) error
+	// Get returns a lobby; private lobbies are only visible to members.
+	Get(c context.Context, userID string, lobbyID string) (*Lobby, error)
+	// Current returns the lobby userID is in.
+	Current(c context.Context, userID string) (*Lobby, error)
+	// List returns a page of joinable public lobbies and the cursor of the
+	// next page, empty on the last one.
+	List(c context.Context, limit int, cursor string) ([]Lobby, string, error)
+	// Join adds userID to a public lobby. Private lobbies need JoinByCode.
+	// The join that fills a lobby starts its match.
+	Join(c context.Context, userID string, lobbyID string) (*Lobby, error)
+	JoinByCode(c context.Context, userID string, code string) (*Lobby, error)
+	// Leave removes userID, hands the host role to the longest-standing
+	// player and closes the lobby when no players are left, returning nil.
+	Leave(c context.Context, userID string, lobbyID string) (*Lobby, error)
+	// Kick removes memberID, bots included; only the host may kick.
+	Kick(c context.Context, userID string, lobbyID string, memberID string) (*Lobby, error)
+	// AddBot fills a seat of an open lobby with a bot playing at level; only
+	// the host may add bots. Like a join, filling the lobby starts its match.
+	AddBot(c context.Context, userID string, lobbyID string, level bot.Level) (*Lobby, error)
+}
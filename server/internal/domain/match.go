@@ -0,0 +1,254 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Simpolette/HeartSteal/server/internal/bot"
+	"github.com/Simpolette/HeartSteal/server/internal/game"
+	"github.com/Simpolette/HeartSteal/server/internal/replay"
+)
+
+var (
+	ErrMatchNotFound = errors.New("match not found")
+	ErrLobbyInGame   = errors.New("lobby is playing a match")
+	// ErrAlreadyInMatch keeps a player of a running match out of the queue.
+	ErrAlreadyInMatch = errors.New("user is already playing a match")
+	// ErrReplayNotFound is returned for matches that haven't finished or
+	// finished without an action log.
+	ErrReplayNotFound = errors.New("match has no replay")
+)
+
+const (
+	CollectionMatch = "matches"
+)
+
+// Events published while a match runs. EventMatchUpdated goes to MatchTopic
+// with what every player may see, and to SpectateTopic with the same events,
+// delayed for ranked matches; EventMatchPrivate goes to each player's
+// UserTopic with the cards only they may see. EventMatchPresence goes to
+// MatchTopic when a player drops, comes back or is replaced.
+const (
+	EventMatchUpdated  = "match.updated"
+	EventMatchPrivate  = "match.private"
+	EventMatchPresence = "match.presence"
+)
+
+// DisconnectPolicy is what happens to a player who stays disconnected past
+// the reconnect grace period.
+type DisconnectPolicy string
+
+const (
+	// ForfeitOnDisconnect puts them out of the game.
+	ForfeitOnDisconnect DisconnectPolicy = "forfeit"
+	// BotOnDisconnect has a bot play their seat until they come back.
+	BotOnDisconnect DisconnectPolicy = "bot"
+)
+
+type MatchStatus string
+
+const (
+	MatchActive   MatchStatus = "active"
+	MatchFinished MatchStatus = "finished"
+	// MatchAbandoned matches were cut short, e.g. by a server restart.
+	MatchAbandoned MatchStatus = "abandoned"
+)
+
+type MatchPlayer struct {
+	UserID      primitive.ObjectID `bson:"user_id"      json:"user_id"`
+	Username    string             `bson:"username"     json:"username"`
+	DisplayName string             `bson:"display_name" json:"display_name"`
+	Seat        int                `bson:"seat"         json:"seat"`
+	// RatingChange is how much a ranked match moved the player's rating.
+	RatingChange float64 `bson:"rating_change,omitempty" json:"rating_change,omitempty"`
+	// Placement is the player's final rank once the match is finished, kept
+	// next to them so histories can filter on it.
+	Placement int `bson:"placement,omitempty" json:"placement,omitempty"`
+	// Bot is the level of a seat the server plays for from the start; its
+	// UserID names no user.
+	Bot bot.Level `bson:"bot,omitempty" json:"bot,omitempty"`
+}
+
+// Match is the stored record of a game. The game itself lives in memory while
+// it runs; Seed is kept so it can be dealt again.
+type Match struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"      json:"id"`
+	LobbyID     primitive.ObjectID `bson:"lobby_id,omitempty" json:"lobby_id,omitzero"`
+	Seed        int64              `bson:"seed"               json:"-"`
+	TurnSeconds int                `bson:"turn_seconds"       json:"turn_seconds"`
+	Players     []MatchPlayer      `bson:"players"            json:"players"`
+	Status      MatchStatus        `bson:"status"             json:"status"`
+	// Mode is set on matches made by matchmaking; only Ranked ones change
+	// ratings.
+	Mode   GameMode `bson:"mode,omitempty" json:"mode,omitempty"`
+	Ranked bool     `bson:"ranked"         json:"ranked"`
+	// Placements holds each seat's final rank once the match is finished.
+	Placements []int      `bson:"placements,omitempty"  json:"placements,omitempty"`
+	CreatedAt  time.Time  `bson:"created_at"            json:"created_at"`
+	FinishedAt *time.Time `bson:"finished_at,omitempty" json:"finished_at,omitempty"`
+	// Actions is the action log in replay.Encode's form, saved when the
+	// match finishes.
+	Actions []byte `bson:"actions,omitempty" json:"-"`
+	// Owner is the instance running the match. Its claim lapses at
+	// LeaseExpiresAt unless the instance renews it, after which any
+	// instance may abandon the match.
+	Owner          string    `bson:"owner,omitempty"  json:"-"`
+	LeaseExpiresAt time.Time `bson:"lease_expires_at" json:"-"`
+}
+
+// GameConfig is the configuration the match's game is dealt with.
+func (m *Match) GameConfig() game.Config {
+	return game.Config{Players: len(m.Players)}
+}
+
+// Replay returns what it takes to play the finished match again.
+func (m *Match) Replay() (replay.Replay, error) {
+	if m.Status != MatchFinished || len(m.Actions) == 0 {
+		return replay.Replay{}, ErrReplayNotFound
+	}
+	actions, err := replay.Decode(m.Actions)
+	if err != nil {
+		return replay.Replay{}, err
+	}
+	return replay.Replay{Config: m.GameConfig(), Seed: m.Seed, Actions: actions}, nil
+}
+
+// Seat returns the seat of userID, or -1 if they don't play in the match.
+func (m *Match) Seat(userID primitive.ObjectID) int {
+	for _, p := range m.Players {
+		if p.UserID == userID {
+			return p.Seat
+		}
+	}
+	return -1
+}
+
+// Player returns userID's entry, or nil if they don't play in the match.
+func (m *Match) Player(userID primitive.ObjectID) *MatchPlayer {
+	for i := range m.Players {
+		if m.Players[i].UserID == userID {
+			return &m.Players[i]
+		}
+	}
+	return nil
+}
+
+// Duration is how long a finished match took, zero while it runs.
+func (m *Match) Duration() time.Duration {
+	if m.FinishedAt == nil {
+		return 0
+	}
+	return m.FinishedAt.Sub(m.CreatedAt)
+}
+
+// MatchUpdate is the payload of EventMatchUpdated and EventMatchPrivate.
+// TurnDeadline is when the player to move will be played for.
+type MatchUpdate struct {
+	MatchID      string       `json:"match_id"`
+	Events       []game.Event `json:"events"`
+	TurnDeadline *time.Time   `json:"turn_deadline,omitempty"`
+	// Spectators is how many users were watching when the events happened.
+	Spectators int `json:"spectators"`
+}
+
+// SeatPresence is whether the player at Seat follows the match. While they
+// are away their seat is held until GraceDeadline; Bot is set while a bot
+// plays for them. Bot seats always count as connected.
+type SeatPresence struct {
+	Seat          int        `json:"seat"`
+	Connected     bool       `json:"connected"`
+	GraceDeadline *time.Time `json:"grace_deadline,omitempty"`
+	Bot           bool       `json:"bot"`
+}
+
+// MatchPresence is the payload of EventMatchPresence.
+type MatchPresence struct {
+	MatchID string `json:"match_id"`
+	SeatPresence
+}
+
+// MatchResume is what a player needs to pick a running match up again: the
+// game as their seat sees it, the events after the last one they had, as
+// they may see them, and who is connected. State is nil once it is over.
+type MatchResume struct {
+	Match        *Match
+	State        *game.View
+	Events       []game.Event
+	TurnDeadline *time.Time
+	Presence     []SeatPresence
+}
+
+// MatchReplay is a finished match rebuilt after its first Step actions.
+// Events are the ones the last of them caused.
+type MatchReplay struct {
+	Match   *Match
+	Actions []game.Action
+	Step    int
+	State   *game.State
+	Events  []game.Event
+}
+
+type MatchRepository interface {
+	Create(c context.Context, match *Match) error
+	Update(c context.Context, match *Match) error
+	GetByID(c context.Context, id string) (*Match, error)
+	// Delete removes a match; it fails with ErrMatchNotFound if there is
+	// none.
+	Delete(c context.Context, id primitive.ObjectID) error
+	ListByStatus(c context.Context, status MatchStatus) ([]Match, error)
+	// ListActiveByPlayer returns the active matches userID plays in.
+	ListActiveByPlayer(c context.Context, userID primitive.ObjectID) ([]Match, error)
+	// ListFinishedByPlayer returns up to limit finished matches userID
+	// played that pass filter, most recently finished first, starting after
+	// the cursor or at the latest when it is nil.
+	ListFinishedByPlayer(c context.Context, userID primitive.ObjectID, filter MatchFilter, limit int, after *Cursor) ([]Match, error)
+	// RenewLease extends owner's claim on an active match to expiresAt. It
+	// fails with ErrMatchNotFound once the match is over or owned by
+	// another instance.
+	RenewLease(c context.Context, id primitive.ObjectID, owner string, expiresAt time.Time) error
+	// Finish stores the result of a match owner ran. Like RenewLease, it
+	// fails with ErrMatchNotFound once the match is no longer active and
+	// owner's, e.g. after another instance abandoned it.
+	Finish(c context.Context, match *Match, owner string) error
+	// ListLeaseExpired returns the active matches whose lease lapsed before
+	// now.
+	ListLeaseExpired(c context.Context, now time.Time) ([]Match, error)
+	// AbandonLeaseExpired marks the match abandoned at now if it is still
+	// active and its lease lapsed before now, and reports whether it did.
+	// The check and the write are one operation, so an owner renewing the
+	// lease at the same time keeps its match.
+	AbandonLeaseExpired(c context.Context, id primitive.ObjectID, now time.Time) (bool, error)
+}
+
+type MatchUsecase interface {
+	// Start deals a game for match.Players, stores the match and runs it
+	// until it ends. The caller fills in the players, seats and turn time.
+	Start(c context.Context, match *Match) error
+	// Get returns a match userID plays in and, while it runs, the game as
+	// their seat sees it.
+	Get(c context.Context, userID string, matchID string) (*Match, *game.View, error)
+	// Act plays action for userID's seat and returns the resulting view.
+	Act(c context.Context, userID string, matchID string, action game.Action) (*game.View, error)
+	// SetConnected records whether userID follows a match they play in. A
+	// player who drops keeps their seat for the reconnect grace period,
+	// after which the disconnect policy applies.
+	SetConnected(c context.Context, userID string, matchID string, connected bool) error
+	// Resume returns a match userID plays in with, while it runs, what they
+	// need to catch up: the events numbered after after and who is
+	// connected.
+	Resume(c context.Context, userID string, matchID string, after int) (*MatchResume, error)
+	// Replay rebuilds a finished match after step actions; a negative step
+	// or one past the end rebuilds it to the end. Any user may replay a
+	// finished match, since nothing is hidden anymore.
+	Replay(c context.Context, matchID string, step int) (*MatchReplay, error)
+	// Spectate returns a match as spectators see it, without checking who
+	// asks; SpectatorUsecase does. Ranked matches are shown delayed.
+	Spectate(c context.Context, matchID string) (*SpectatedMatch, error)
+	// AbandonExpired marks the active matches whose owner stopped renewing
+	// their lease abandoned, e.g. after the instance running them crashed,
+	// and closes their lobbies. It returns how many there were.
+	AbandonExpired(c context.Context) (int, error)
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Simpolette/HeartSteal/server/internal/matchmaking"
+)
+
+var (
+	ErrUnknownGameMode = errors.New("unknown game mode")
+	ErrPartyTooLarge   = errors.New("party has more players than the game mode seats")
+	ErrPartyNotFriends = errors.New("party members must be friends of the player queueing")
+)
+
+type GameMode string
+
+const (
+	// ModeDuel is one against one.
+	ModeDuel GameMode = "duel"
+	// ModeTable seats four players.
+	ModeTable GameMode = "table"
+)
+
+var GameModes = []GameMode{ModeDuel, ModeTable}
+
+// Players returns how many seats a match of the mode has, 0 if the mode is
+// unknown.
+func (m GameMode) Players() int {
+	switch m {
+	case ModeDuel:
+		return 2
+	case ModeTable:
+		return 4
+	}
+	return 0
+}
+
+// Events published on each queued player's UserTopic.
+const (
+	EventMatchmakingQueued    = "matchmaking.queued"
+	EventMatchmakingFound     = "matchmaking.found"
+	EventMatchmakingAccepted  = "matchmaking.accepted"
+	EventMatchmakingStarted   = "matchmaking.started"
+	EventMatchmakingRequeued  = "matchmaking.requeued"
+	EventMatchmakingCancelled = "matchmaking.cancelled"
+)
+
+// Reasons given with EventMatchmakingCancelled.
+const (
+	// MatchmakingCancelled means someone in the party cancelled or declined.
+	MatchmakingCancelled = "cancelled"
+	// MatchmakingTimedOut means the party didn't accept a match in time.
+	MatchmakingTimedOut = "timeout"
+)
+
+// MatchmakingEvent is the payload of the matchmaking events; each sets the
+// fields that apply to it.
+type MatchmakingEvent struct {
+	TicketID   string     `json:"ticket_id,omitempty"`
+	ProposalID string     `json:"proposal_id,omitempty"`
+	Mode       GameMode   `json:"mode"`
+	Players    int        `json:"players,omitempty"`
+	Accepted   int        `json:"accepted,omitempty"`
+	Deadline   *time.Time `json:"deadline,omitempty"`
+	MatchID    string     `json:"match_id,omitempty"`
+	Reason     string     `json:"reason,omitempty"`
+}
+
+// RatingSource tells matchmaking how strong players are in a mode. Players
+// missing from the result are rated matchmaking.DefaultRating.
+type RatingSource interface {
+	Ratings(c context.Context, mode GameMode, userIDs []primitive.ObjectID) (map[primitive.ObjectID]float64, error)
+}
+
+type MatchmakingUsecase interface {
+	// Enqueue queues userID for mode together with party, the IDs of
+	// friends who play on the same side of the queue.
+	Enqueue(c context.Context, userID string, mode GameMode, party []string) (*matchmaking.Ticket, error)
+	// Current returns the ticket holding userID. Its ProposalID is set while
+	// a match found for it waits to be accepted.
+	Current(c context.Context, userID string) (*matchmaking.Ticket, error)
+	// Cancel takes userID's whole party off the queue. Cancelling once a
+	// match is found declines it and queues the other players again.
+	Cancel(c context.Context, userID string) error
+	// Accept confirms the match found for userID. When the last player
+	// accepts, the match starts and its MatchID is set.
+	Accept(c context.Context, userID string) (*matchmaking.Proposal, error)
+	// Run matches waiting tickets again as their windows widen and expires
+	// unanswered proposals, until ctx is done.
+	Run(ctx context.Context)
+}
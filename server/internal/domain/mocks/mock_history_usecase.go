@@ -0,0 +1,212 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/Simpolette/HeartSteal/server/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockHistoryUsecase is an autogenerated mock type for the HistoryUsecase type
+type MockHistoryUsecase struct {
+	mock.Mock
+}
+
+type MockHistoryUsecase_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockHistoryUsecase) EXPECT() *MockHistoryUsecase_Expecter {
+	return &MockHistoryUsecase_Expecter{mock: &_m.Mock}
+}
+
+// Matches provides a mock function with given fields: c, username, filter, limit, cursor
+func (_m *MockHistoryUsecase) Matches(c context.Context, username string, filter domain.MatchFilter, limit int, cursor string) ([]domain.HistoryEntry, string, error) {
+	ret := _m.Called(c, username, filter, limit, cursor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Matches")
+	}
+
+	var r0 []domain.HistoryEntry
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.MatchFilter, int, string) ([]domain.HistoryEntry, string, error)); ok {
+		return rf(c, username, filter, limit, cursor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.MatchFilter, int, string) []domain.HistoryEntry); ok {
+		r0 = rf(c, username, filter, limit, cursor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.HistoryEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.MatchFilter, int, string) string); ok {
+		r1 = rf(c, username, filter, limit, cursor)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, domain.MatchFilter, int, string) error); ok {
+		r2 = rf(c, username, filter, limit, cursor)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockHistoryUsecase_Matches_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Matches'
+type MockHistoryUsecase_Matches_Call struct {
+	*mock.Call
+}
+
+// Matches is a helper method to define mock.On call
+//   - c context.Context
+//   - username string
+//   - filter domain.MatchFilter
+//   - limit int
+//   - cursor string
+func (_e *MockHistoryUsecase_Expecter) Matches(c interface{}, username interface{}, filter interface{}, limit interface{}, cursor interface{}) *MockHistoryUsecase_Matches_Call {
+	return &MockHistoryUsecase_Matches_Call{Call: _e.mock.On("Matches", c, username, filter, limit, cursor)}
+}
+
+func (_c *MockHistoryUsecase_Matches_Call) Run(run func(c context.Context, username string, filter domain.MatchFilter, limit int, cursor string)) *MockHistoryUsecase_Matches_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(domain.MatchFilter), args[3].(int), args[4].(string))
+	})
+	return _c
+}
+
+func (_c *MockHistoryUsecase_Matches_Call) Return(_a0 []domain.HistoryEntry, _a1 string, _a2 error) *MockHistoryUsecase_Matches_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockHistoryUsecase_Matches_Call) RunAndReturn(run func(context.Context, string, domain.MatchFilter, int, string) ([]domain.HistoryEntry, string, error)) *MockHistoryUsecase_Matches_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Record provides a mock function with given fields: c, match
+func (_m *MockHistoryUsecase) Record(c context.Context, match *domain.Match) error {
+	ret := _m.Called(c, match)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Record")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Match) error); ok {
+		r0 = rf(c, match)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockHistoryUsecase_Record_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Record'
+type MockHistoryUsecase_Record_Call struct {
+	*mock.Call
+}
+
+// Record is a helper method to define mock.On call
+//   - c context.Context
+//   - match *domain.Match
+func (_e *MockHistoryUsecase_Expecter) Record(c interface{}, match interface{}) *MockHistoryUsecase_Record_Call {
+	return &MockHistoryUsecase_Record_Call{Call: _e.mock.On("Record", c, match)}
+}
+
+func (_c *MockHistoryUsecase_Record_Call) Run(run func(c context.Context, match *domain.Match)) *MockHistoryUsecase_Record_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.Match))
+	})
+	return _c
+}
+
+func (_c *MockHistoryUsecase_Record_Call) Return(_a0 error) *MockHistoryUsecase_Record_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockHistoryUsecase_Record_Call) RunAndReturn(run func(context.Context, *domain.Match) error) *MockHistoryUsecase_Record_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Stats provides a mock function with given fields: c, username
+func (_m *MockHistoryUsecase) Stats(c context.Context, username string) (*domain.PlayerStats, error) {
+	ret := _m.Called(c, username)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stats")
+	}
+
+	var r0 *domain.PlayerStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.PlayerStats, error)); ok {
+		return rf(c, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.PlayerStats); ok {
+		r0 = rf(c, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.PlayerStats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockHistoryUsecase_Stats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Stats'
+type MockHistoryUsecase_Stats_Call struct {
+	*mock.Call
+}
+
+// Stats is a helper method to define mock.On call
+//   - c context.Context
+//   - username string
+func (_e *MockHistoryUsecase_Expecter) Stats(c interface{}, username interface{}) *MockHistoryUsecase_Stats_Call {
+	return &MockHistoryUsecase_Stats_Call{Call: _e.mock.On("Stats", c, username)}
+}
+
+func (_c *MockHistoryUsecase_Stats_Call) Run(run func(c context.Context, username string)) *MockHistoryUsecase_Stats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockHistoryUsecase_Stats_Call) Return(_a0 *domain.PlayerStats, _a1 error) *MockHistoryUsecase_Stats_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockHistoryUsecase_Stats_Call) RunAndReturn(run func(context.Context, string) (*domain.PlayerStats, error)) *MockHistoryUsecase_Stats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockHistoryUsecase creates a new instance of MockHistoryUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockHistoryUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockHistoryUsecase {
+	mock := &MockHistoryUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
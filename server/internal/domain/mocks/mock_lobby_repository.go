@@ -0,0 +1,417 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/Simpolette/HeartSteal/server/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockLobbyRepository is an autogenerated mock type for the LobbyRepository type
+type MockLobbyRepository struct {
+	mock.Mock
+}
+
+type MockLobbyRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockLobbyRepository) EXPECT() *MockLobbyRepository_Expecter {
+	return &MockLobbyRepository_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function with given fields: c, lobby
+func (_m *MockLobbyRepository) Create(c context.Context, lobby *domain.Lobby) error {
+	ret := _m.Called(c, lobby)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Lobby) error); ok {
+		r0 = rf(c, lobby)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockLobbyRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockLobbyRepository_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - c context.Context
+//   - lobby *domain.Lobby
+func (_e *MockLobbyRepository_Expecter) Create(c interface{}, lobby interface{}) *MockLobbyRepository_Create_Call {
+	return &MockLobbyRepository_Create_Call{Call: _e.mock.On("Create", c, lobby)}
+}
+
+func (_c *MockLobbyRepository_Create_Call) Run(run func(c context.Context, lobby *domain.Lobby)) *MockLobbyRepository_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.Lobby))
+	})
+	return _c
+}
+
+func (_c *MockLobbyRepository_Create_Call) Return(_a0 error) *MockLobbyRepository_Create_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockLobbyRepository_Create_Call) RunAndReturn(run func(context.Context, *domain.Lobby) error) *MockLobbyRepository_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function with given fields: c, lobby
+func (_m *MockLobbyRepository) Delete(c context.Context, lobby *domain.Lobby) error {
+	ret := _m.Called(c, lobby)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Lobby) error); ok {
+		r0 = rf(c, lobby)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockLobbyRepository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockLobbyRepository_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - c context.Context
+//   - lobby *domain.Lobby
+func (_e *MockLobbyRepository_Expecter) Delete(c interface{}, lobby interface{}) *MockLobbyRepository_Delete_Call {
+	return &MockLobbyRepository_Delete_Call{Call: _e.mock.On("Delete", c, lobby)}
+}
+
+func (_c *MockLobbyRepository_Delete_Call) Run(run func(c context.Context, lobby *domain.Lobby)) *MockLobbyRepository_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.Lobby))
+	})
+	return _c
+}
+
+func (_c *MockLobbyRepository_Delete_Call) Return(_a0 error) *MockLobbyRepository_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockLobbyRepository_Delete_Call) RunAndReturn(run func(context.Context, *domain.Lobby) error) *MockLobbyRepository_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByID provides a mock function with given fields: c, id
+func (_m *MockLobbyRepository) GetByID(c context.Context, id string) (*domain.Lobby, error) {
+	ret := _m.Called(c, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *domain.Lobby
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.Lobby, error)); ok {
+		return rf(c, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Lobby); ok {
+		r0 = rf(c, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Lobby)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLobbyRepository_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
+type MockLobbyRepository_GetByID_Call struct {
+	*mock.Call
+}
+
+// GetByID is a helper method to define mock.On call
+//   - c context.Context
+//   - id string
+func (_e *MockLobbyRepository_Expecter) GetByID(c interface{}, id interface{}) *MockLobbyRepository_GetByID_Call {
+	return &MockLobbyRepository_GetByID_Call{Call: _e.mock.On("GetByID", c, id)}
+}
+
+func (_c *MockLobbyRepository_GetByID_Call) Run(run func(c context.Context, id string)) *MockLobbyRepository_GetByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockLobbyRepository_GetByID_Call) Return(_a0 *domain.Lobby, _a1 error) *MockLobbyRepository_GetByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockLobbyRepository_GetByID_Call) RunAndReturn(run func(context.Context, string) (*domain.Lobby, error)) *MockLobbyRepository_GetByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByInviteCode provides a mock function with given fields: c, code
+func (_m *MockLobbyRepository) GetByInviteCode(c context.Context, code string) (*domain.Lobby, error) {
+	ret := _m.Called(c, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByInviteCode")
+	}
+
+	var r0 *domain.Lobby
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.Lobby, error)); ok {
+		return rf(c, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Lobby); ok {
+		r0 = rf(c, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Lobby)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLobbyRepository_GetByInviteCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByInviteCode'
+type MockLobbyRepository_GetByInviteCode_Call struct {
+	*mock.Call
+}
+
+// GetByInviteCode is a helper method to define mock.On call
+//   - c context.Context
+//   - code string
+func (_e *MockLobbyRepository_Expecter) GetByInviteCode(c interface{}, code interface{}) *MockLobbyRepository_GetByInviteCode_Call {
+	return &MockLobbyRepository_GetByInviteCode_Call{Call: _e.mock.On("GetByInviteCode", c, code)}
+}
+
+func (_c *MockLobbyRepository_GetByInviteCode_Call) Run(run func(c context.Context, code string)) *MockLobbyRepository_GetByInviteCode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockLobbyRepository_GetByInviteCode_Call) Return(_a0 *domain.Lobby, _a1 error) *MockLobbyRepository_GetByInviteCode_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockLobbyRepository_GetByInviteCode_Call) RunAndReturn(run func(context.Context, string) (*domain.Lobby, error)) *MockLobbyRepository_GetByInviteCode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByMember provides a mock function with given fields: c, userID
+func (_m *MockLobbyRepository) GetByMember(c context.Context, userID primitive.ObjectID) (*domain.Lobby, error) {
+	ret := _m.Called(c, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByMember")
+	}
+
+	var r0 *domain.Lobby
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) (*domain.Lobby, error)); ok {
+		return rf(c, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) *domain.Lobby); ok {
+		r0 = rf(c, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Lobby)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID) error); ok {
+		r1 = rf(c, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLobbyRepository_GetByMember_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByMember'
+type MockLobbyRepository_GetByMember_Call struct {
+	*mock.Call
+}
+
+// GetByMember is a helper method to define mock.On call
+//   - c context.Context
+//   - userID primitive.ObjectID
+func (_e *MockLobbyRepository_Expecter) GetByMember(c interface{}, userID interface{}) *MockLobbyRepository_GetByMember_Call {
+	return &MockLobbyRepository_GetByMember_Call{Call: _e.mock.On("GetByMember", c, userID)}
+}
+
+func (_c *MockLobbyRepository_GetByMember_Call) Run(run func(c context.Context, userID primitive.ObjectID)) *MockLobbyRepository_GetByMember_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(primitive.ObjectID))
+	})
+	return _c
+}
+
+func (_c *MockLobbyRepository_GetByMember_Call) Return(_a0 *domain.Lobby, _a1 error) *MockLobbyRepository_GetByMember_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockLobbyRepository_GetByMember_Call) RunAndReturn(run func(context.Context, primitive.ObjectID) (*domain.Lobby, error)) *MockLobbyRepository_GetByMember_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListOpenPublic provides a mock function with given fields: c, limit, after
+func (_m *MockLobbyRepository) ListOpenPublic(c context.Context, limit int, after *domain.Cursor) ([]domain.Lobby, error) {
+	ret := _m.Called(c, limit, after)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOpenPublic")
+	}
+
+	var r0 []domain.Lobby
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *domain.Cursor) ([]domain.Lobby, error)); ok {
+		return rf(c, limit, after)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *domain.Cursor) []domain.Lobby); ok {
+		r0 = rf(c, limit, after)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Lobby)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *domain.Cursor) error); ok {
+		r1 = rf(c, limit, after)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLobbyRepository_ListOpenPublic_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListOpenPublic'
+type MockLobbyRepository_ListOpenPublic_Call struct {
+	*mock.Call
+}
+
+// ListOpenPublic is a helper method to define mock.On call
+//   - c context.Context
+//   - limit int
+//   - after *domain.Cursor
+func (_e *MockLobbyRepository_Expecter) ListOpenPublic(c interface{}, limit interface{}, after interface{}) *MockLobbyRepository_ListOpenPublic_Call {
+	return &MockLobbyRepository_ListOpenPublic_Call{Call: _e.mock.On("ListOpenPublic", c, limit, after)}
+}
+
+func (_c *MockLobbyRepository_ListOpenPublic_Call) Run(run func(c context.Context, limit int, after *domain.Cursor)) *MockLobbyRepository_ListOpenPublic_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(*domain.Cursor))
+	})
+	return _c
+}
+
+func (_c *MockLobbyRepository_ListOpenPublic_Call) Return(_a0 []domain.Lobby, _a1 error) *MockLobbyRepository_ListOpenPublic_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockLobbyRepository_ListOpenPublic_Call) RunAndReturn(run func(context.Context, int, *domain.Cursor) ([]domain.Lobby, error)) *MockLobbyRepository_ListOpenPublic_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function with given fields: c, lobby
+func (_m *MockLobbyRepository) Update(c context.Context, lobby *domain.Lobby) error {
+	ret := _m.Called(c, lobby)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Lobby) error); ok {
+		r0 = rf(c, lobby)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockLobbyRepository_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type MockLobbyRepository_Update_Call struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//   - c context.Context
+//   - lobby *domain.Lobby
+func (_e *MockLobbyRepository_Expecter) Update(c interface{}, lobby interface{}) *MockLobbyRepository_Update_Call {
+	return &MockLobbyRepository_Update_Call{Call: _e.mock.On("Update", c, lobby)}
+}
+
+func (_c *MockLobbyRepository_Update_Call) Run(run func(c context.Context, lobby *domain.Lobby)) *MockLobbyRepository_Update_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.Lobby))
+	})
+	return _c
+}
+
+func (_c *MockLobbyRepository_Update_Call) Return(_a0 error) *MockLobbyRepository_Update_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockLobbyRepository_Update_Call) RunAndReturn(run func(context.Context, *domain.Lobby) error) *MockLobbyRepository_Update_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockLobbyRepository creates a new instance of MockLobbyRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockLobbyRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockLobbyRepository {
+	mock := &MockLobbyRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
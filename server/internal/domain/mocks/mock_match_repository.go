@@ -0,0 +1,635 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/Simpolette/HeartSteal/server/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+
+	time "time"
+)
+
+// MockMatchRepository is an autogenerated mock type for the MatchRepository type
+type MockMatchRepository struct {
+	mock.Mock
+}
+
+type MockMatchRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockMatchRepository) EXPECT() *MockMatchRepository_Expecter {
+	return &MockMatchRepository_Expecter{mock: &_m.Mock}
+}
+
+// AbandonLeaseExpired provides a mock function with given fields: c, id, now
+func (_m *MockMatchRepository) AbandonLeaseExpired(c context.Context, id primitive.ObjectID, now time.Time) (bool, error) {
+	ret := _m.Called(c, id, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AbandonLeaseExpired")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, time.Time) (bool, error)); ok {
+		return rf(c, id, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, time.Time) bool); ok {
+		r0 = rf(c, id, now)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID, time.Time) error); ok {
+		r1 = rf(c, id, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockMatchRepository_AbandonLeaseExpired_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AbandonLeaseExpired'
+type MockMatchRepository_AbandonLeaseExpired_Call struct {
+	*mock.Call
+}
+
+// AbandonLeaseExpired is a helper method to define mock.On call
+//   - c context.Context
+//   - id primitive.ObjectID
+//   - now time.Time
+func (_e *MockMatchRepository_Expecter) AbandonLeaseExpired(c interface{}, id interface{}, now interface{}) *MockMatchRepository_AbandonLeaseExpired_Call {
+	return &MockMatchRepository_AbandonLeaseExpired_Call{Call: _e.mock.On("AbandonLeaseExpired", c, id, now)}
+}
+
+func (_c *MockMatchRepository_AbandonLeaseExpired_Call) Run(run func(c context.Context, id primitive.ObjectID, now time.Time)) *MockMatchRepository_AbandonLeaseExpired_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(primitive.ObjectID), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockMatchRepository_AbandonLeaseExpired_Call) Return(_a0 bool, _a1 error) *MockMatchRepository_AbandonLeaseExpired_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockMatchRepository_AbandonLeaseExpired_Call) RunAndReturn(run func(context.Context, primitive.ObjectID, time.Time) (bool, error)) *MockMatchRepository_AbandonLeaseExpired_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Create provides a mock function with given fields: c, match
+func (_m *MockMatchRepository) Create(c context.Context, match *domain.Match) error {
+	ret := _m.Called(c, match)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Match) error); ok {
+		r0 = rf(c, match)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockMatchRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockMatchRepository_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - c context.Context
+//   - match *domain.Match
+func (_e *MockMatchRepository_Expecter) Create(c interface{}, match interface{}) *MockMatchRepository_Create_Call {
+	return &MockMatchRepository_Create_Call{Call: _e.mock.On("Create", c, match)}
+}
+
+func (_c *MockMatchRepository_Create_Call) Run(run func(c context.Context, match *domain.Match)) *MockMatchRepository_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.Match))
+	})
+	return _c
+}
+
+func (_c *MockMatchRepository_Create_Call) Return(_a0 error) *MockMatchRepository_Create_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockMatchRepository_Create_Call) RunAndReturn(run func(context.Context, *domain.Match) error) *MockMatchRepository_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function with given fields: c, id
+func (_m *MockMatchRepository) Delete(c context.Context, id primitive.ObjectID) error {
+	ret := _m.Called(c, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) error); ok {
+		r0 = rf(c, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockMatchRepository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockMatchRepository_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - c context.Context
+//   - id primitive.ObjectID
+func (_e *MockMatchRepository_Expecter) Delete(c interface{}, id interface{}) *MockMatchRepository_Delete_Call {
+	return &MockMatchRepository_Delete_Call{Call: _e.mock.On("Delete", c, id)}
+}
+
+func (_c *MockMatchRepository_Delete_Call) Run(run func(c context.Context, id primitive.ObjectID)) *MockMatchRepository_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(primitive.ObjectID))
+	})
+	return _c
+}
+
+func (_c *MockMatchRepository_Delete_Call) Return(_a0 error) *MockMatchRepository_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockMatchRepository_Delete_Call) RunAndReturn(run func(context.Context, primitive.ObjectID) error) *MockMatchRepository_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Finish provides a mock function with given fields: c, match, owner
+func (_m *MockMatchRepository) Finish(c context.Context, match *domain.Match, owner string) error {
+	ret := _m.Called(c, match, owner)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Finish")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Match, string) error); ok {
+		r0 = rf(c, match, owner)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockMatchRepository_Finish_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Finish'
+type MockMatchRepository_Finish_Call struct {
+	*mock.Call
+}
+
+// Finish is a helper method to define mock.On call
+//   - c context.Context
+//   - match *domain.Match
+//   - owner string
+func (_e *MockMatchRepository_Expecter) Finish(c interface{}, match interface{}, owner interface{}) *MockMatchRepository_Finish_Call {
+	return &MockMatchRepository_Finish_Call{Call: _e.mock.On("Finish", c, match, owner)}
+}
+
+func (_c *MockMatchRepository_Finish_Call) Run(run func(c context.Context, match *domain.Match, owner string)) *MockMatchRepository_Finish_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.Match), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockMatchRepository_Finish_Call) Return(_a0 error) *MockMatchRepository_Finish_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockMatchRepository_Finish_Call) RunAndReturn(run func(context.Context, *domain.Match, string) error) *MockMatchRepository_Finish_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByID provides a mock function with given fields: c, id
+func (_m *MockMatchRepository) GetByID(c context.Context, id string) (*domain.Match, error) {
+	ret := _m.Called(c, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *domain.Match
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.Match, error)); ok {
+		return rf(c, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Match); ok {
+		r0 = rf(c, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Match)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockMatchRepository_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
+type MockMatchRepository_GetByID_Call struct {
+	*mock.Call
+}
+
+// GetByID is a helper method to define mock.On call
+//   - c context.Context
+//   - id string
+func (_e *MockMatchRepository_Expecter) GetByID(c interface{}, id interface{}) *MockMatchRepository_GetByID_Call {
+	return &MockMatchRepository_GetByID_Call{Call: _e.mock.On("GetByID", c, id)}
+}
+
+func (_c *MockMatchRepository_GetByID_Call) Run(run func(c context.Context, id string)) *MockMatchRepository_GetByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockMatchRepository_GetByID_Call) Return(_a0 *domain.Match, _a1 error) *MockMatchRepository_GetByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockMatchRepository_GetByID_Call) RunAndReturn(run func(context.Context, string) (*domain.Match, error)) *MockMatchRepository_GetByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListActiveByPlayer provides a mock function with given fields: c, userID
+func (_m *MockMatchRepository) ListActiveByPlayer(c context.Context, userID primitive.ObjectID) ([]domain.Match, error) {
+	ret := _m.Called(c, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListActiveByPlayer")
+	}
+
+	var r0 []domain.Match
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) ([]domain.Match, error)); ok {
+		return rf(c, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) []domain.Match); ok {
+		r0 = rf(c, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Match)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID) error); ok {
+		r1 = rf(c, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockMatchRepository_ListActiveByPlayer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListActiveByPlayer'
+type MockMatchRepository_ListActiveByPlayer_Call struct {
+	*mock.Call
+}
+
+// ListActiveByPlayer is a helper method to define mock.On call
+//   - c context.Context
+//   - userID primitive.ObjectID
+func (_e *MockMatchRepository_Expecter) ListActiveByPlayer(c interface{}, userID interface{}) *MockMatchRepository_ListActiveByPlayer_Call {
+	return &MockMatchRepository_ListActiveByPlayer_Call{Call: _e.mock.On("ListActiveByPlayer", c, userID)}
+}
+
+func (_c *MockMatchRepository_ListActiveByPlayer_Call) Run(run func(c context.Context, userID primitive.ObjectID)) *MockMatchRepository_ListActiveByPlayer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(primitive.ObjectID))
+	})
+	return _c
+}
+
+func (_c *MockMatchRepository_ListActiveByPlayer_Call) Return(_a0 []domain.Match, _a1 error) *MockMatchRepository_ListActiveByPlayer_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockMatchRepository_ListActiveByPlayer_Call) RunAndReturn(run func(context.Context, primitive.ObjectID) ([]domain.Match, error)) *MockMatchRepository_ListActiveByPlayer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListByStatus provides a mock function with given fields: c, status
+func (_m *MockMatchRepository) ListByStatus(c context.Context, status domain.MatchStatus) ([]domain.Match, error) {
+	ret := _m.Called(c, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByStatus")
+	}
+
+	var r0 []domain.Match
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.MatchStatus) ([]domain.Match, error)); ok {
+		return rf(c, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.MatchStatus) []domain.Match); ok {
+		r0 = rf(c, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Match)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.MatchStatus) error); ok {
+		r1 = rf(c, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockMatchRepository_ListByStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListByStatus'
+type MockMatchRepository_ListByStatus_Call struct {
+	*mock.Call
+}
+
+// ListByStatus is a helper method to define mock.On call
+//   - c context.Context
+//   - status domain.MatchStatus
+func (_e *MockMatchRepository_Expecter) ListByStatus(c interface{}, status interface{}) *MockMatchRepository_ListByStatus_Call {
+	return &MockMatchRepository_ListByStatus_Call{Call: _e.mock.On("ListByStatus", c, status)}
+}
+
+func (_c *MockMatchRepository_ListByStatus_Call) Run(run func(c context.Context, status domain.MatchStatus)) *MockMatchRepository_ListByStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.MatchStatus))
+	})
+	return _c
+}
+
+func (_c *MockMatchRepository_ListByStatus_Call) Return(_a0 []domain.Match, _a1 error) *MockMatchRepository_ListByStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockMatchRepository_ListByStatus_Call) RunAndReturn(run func(context.Context, domain.MatchStatus) ([]domain.Match, error)) *MockMatchRepository_ListByStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListFinishedByPlayer provides a mock function with given fields: c, userID, filter, limit, after
+func (_m *MockMatchRepository) ListFinishedByPlayer(c context.Context, userID primitive.ObjectID, filter domain.MatchFilter, limit int, after *domain.Cursor) ([]domain.Match, error) {
+	ret := _m.Called(c, userID, filter, limit, after)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListFinishedByPlayer")
+	}
+
+	var r0 []domain.Match
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, domain.MatchFilter, int, *domain.Cursor) ([]domain.Match, error)); ok {
+		return rf(c, userID, filter, limit, after)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, domain.MatchFilter, int, *domain.Cursor) []domain.Match); ok {
+		r0 = rf(c, userID, filter, limit, after)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Match)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID, domain.MatchFilter, int, *domain.Cursor) error); ok {
+		r1 = rf(c, userID, filter, limit, after)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockMatchRepository_ListFinishedByPlayer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListFinishedByPlayer'
+type MockMatchRepository_ListFinishedByPlayer_Call struct {
+	*mock.Call
+}
+
+// ListFinishedByPlayer is a helper method to define mock.On call
+//   - c context.Context
+//   - userID primitive.ObjectID
+//   - filter domain.MatchFilter
+//   - limit int
+//   - after *domain.Cursor
+func (_e *MockMatchRepository_Expecter) ListFinishedByPlayer(c interface{}, userID interface{}, filter interface{}, limit interface{}, after interface{}) *MockMatchRepository_ListFinishedByPlayer_Call {
+	return &MockMatchRepository_ListFinishedByPlayer_Call{Call: _e.mock.On("ListFinishedByPlayer", c, userID, filter, limit, after)}
+}
+
+func (_c *MockMatchRepository_ListFinishedByPlayer_Call) Run(run func(c context.Context, userID primitive.ObjectID, filter domain.MatchFilter, limit int, after *domain.Cursor)) *MockMatchRepository_ListFinishedByPlayer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(primitive.ObjectID), args[2].(domain.MatchFilter), args[3].(int), args[4].(*domain.Cursor))
+	})
+	return _c
+}
+
+func (_c *MockMatchRepository_ListFinishedByPlayer_Call) Return(_a0 []domain.Match, _a1 error) *MockMatchRepository_ListFinishedByPlayer_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockMatchRepository_ListFinishedByPlayer_Call) RunAndReturn(run func(context.Context, primitive.ObjectID, domain.MatchFilter, int, *domain.Cursor) ([]domain.Match, error)) *MockMatchRepository_ListFinishedByPlayer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListLeaseExpired provides a mock function with given fields: c, now
+func (_m *MockMatchRepository) ListLeaseExpired(c context.Context, now time.Time) ([]domain.Match, error) {
+	ret := _m.Called(c, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListLeaseExpired")
+	}
+
+	var r0 []domain.Match
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]domain.Match, error)); ok {
+		return rf(c, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []domain.Match); ok {
+		r0 = rf(c, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Match)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(c, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockMatchRepository_ListLeaseExpired_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListLeaseExpired'
+type MockMatchRepository_ListLeaseExpired_Call struct {
+	*mock.Call
+}
+
+// ListLeaseExpired is a helper method to define mock.On call
+//   - c context.Context
+//   - now time.Time
+func (_e *MockMatchRepository_Expecter) ListLeaseExpired(c interface{}, now interface{}) *MockMatchRepository_ListLeaseExpired_Call {
+	return &MockMatchRepository_ListLeaseExpired_Call{Call: _e.mock.On("ListLeaseExpired", c, now)}
+}
+
+func (_c *MockMatchRepository_ListLeaseExpired_Call) Run(run func(c context.Context, now time.Time)) *MockMatchRepository_ListLeaseExpired_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockMatchRepository_ListLeaseExpired_Call) Return(_a0 []domain.Match, _a1 error) *MockMatchRepository_ListLeaseExpired_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockMatchRepository_ListLeaseExpired_Call) RunAndReturn(run func(context.Context, time.Time) ([]domain.Match, error)) *MockMatchRepository_ListLeaseExpired_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RenewLease provides a mock function with given fields: c, id, owner, expiresAt
+func (_m *MockMatchRepository) RenewLease(c context.Context, id primitive.ObjectID, owner string, expiresAt time.Time) error {
+	ret := _m.Called(c, id, owner, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RenewLease")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, string, time.Time) error); ok {
+		r0 = rf(c, id, owner, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockMatchRepository_RenewLease_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RenewLease'
+type MockMatchRepository_RenewLease_Call struct {
+	*mock.Call
+}
+
+// RenewLease is a helper method to define mock.On call
+//   - c context.Context
+//   - id primitive.ObjectID
+//   - owner string
+//   - expiresAt time.Time
+func (_e *MockMatchRepository_Expecter) RenewLease(c interface{}, id interface{}, owner interface{}, expiresAt interface{}) *MockMatchRepository_RenewLease_Call {
+	return &MockMatchRepository_RenewLease_Call{Call: _e.mock.On("RenewLease", c, id, owner, expiresAt)}
+}
+
+func (_c *MockMatchRepository_RenewLease_Call) Run(run func(c context.Context, id primitive.ObjectID, owner string, expiresAt time.Time)) *MockMatchRepository_RenewLease_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(primitive.ObjectID), args[2].(string), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockMatchRepository_RenewLease_Call) Return(_a0 error) *MockMatchRepository_RenewLease_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockMatchRepository_RenewLease_Call) RunAndReturn(run func(context.Context, primitive.ObjectID, string, time.Time) error) *MockMatchRepository_RenewLease_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function with given fields: c, match
+func (_m *MockMatchRepository) Update(c context.Context, match *domain.Match) error {
+	ret := _m.Called(c, match)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Match) error); ok {
+		r0 = rf(c, match)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockMatchRepository_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type MockMatchRepository_Update_Call struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//   - c context.Context
+//   - match *domain.Match
+func (_e *MockMatchRepository_Expecter) Update(c interface{}, match interface{}) *MockMatchRepository_Update_Call {
+	return &MockMatchRepository_Update_Call{Call: _e.mock.On("Update", c, match)}
+}
+
+func (_c *MockMatchRepository_Update_Call) Run(run func(c context.Context, match *domain.Match)) *MockMatchRepository_Update_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.Match))
+	})
+	return _c
+}
+
+func (_c *MockMatchRepository_Update_Call) Return(_a0 error) *MockMatchRepository_Update_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockMatchRepository_Update_Call) RunAndReturn(run func(context.Context, *domain.Match) error) *MockMatchRepository_Update_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockMatchRepository creates a new instance of MockMatchRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockMatchRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockMatchRepository {
+	mock := &MockMatchRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
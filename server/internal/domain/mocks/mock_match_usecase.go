@@ -0,0 +1,501 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/Simpolette/HeartSteal/server/internal/domain"
+	game "github.com/Simpolette/HeartSteal/server/internal/game"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockMatchUsecase is an autogenerated mock type for the MatchUsecase type
+type MockMatchUsecase struct {
+	mock.Mock
+}
+
+type MockMatchUsecase_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockMatchUsecase) EXPECT() *MockMatchUsecase_Expecter {
+	return &MockMatchUsecase_Expecter{mock: &_m.Mock}
+}
+
+// AbandonExpired provides a mock function with given fields: c
+func (_m *MockMatchUsecase) AbandonExpired(c context.Context) (int, error) {
+	ret := _m.Called(c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AbandonExpired")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(c)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(c)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockMatchUsecase_AbandonExpired_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AbandonExpired'
+type MockMatchUsecase_AbandonExpired_Call struct {
+	*mock.Call
+}
+
+// AbandonExpired is a helper method to define mock.On call
+//   - c context.Context
+func (_e *MockMatchUsecase_Expecter) AbandonExpired(c interface{}) *MockMatchUsecase_AbandonExpired_Call {
+	return &MockMatchUsecase_AbandonExpired_Call{Call: _e.mock.On("AbandonExpired", c)}
+}
+
+func (_c *MockMatchUsecase_AbandonExpired_Call) Run(run func(c context.Context)) *MockMatchUsecase_AbandonExpired_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockMatchUsecase_AbandonExpired_Call) Return(_a0 int, _a1 error) *MockMatchUsecase_AbandonExpired_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockMatchUsecase_AbandonExpired_Call) RunAndReturn(run func(context.Context) (int, error)) *MockMatchUsecase_AbandonExpired_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Act provides a mock function with given fields: c, userID, matchID, action
+func (_m *MockMatchUsecase) Act(c context.Context, userID string, matchID string, action game.Action) (*game.View, error) {
+	ret := _m.Called(c, userID, matchID, action)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Act")
+	}
+
+	var r0 *game.View
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, game.Action) (*game.View, error)); ok {
+		return rf(c, userID, matchID, action)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, game.Action) *game.View); ok {
+		r0 = rf(c, userID, matchID, action)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*game.View)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, game.Action) error); ok {
+		r1 = rf(c, userID, matchID, action)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockMatchUsecase_Act_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Act'
+type MockMatchUsecase_Act_Call struct {
+	*mock.Call
+}
+
+// Act is a helper method to define mock.On call
+//   - c context.Context
+//   - userID string
+//   - matchID string
+//   - action game.Action
+func (_e *MockMatchUsecase_Expecter) Act(c interface{}, userID interface{}, matchID interface{}, action interface{}) *MockMatchUsecase_Act_Call {
+	return &MockMatchUsecase_Act_Call{Call: _e.mock.On("Act", c, userID, matchID, action)}
+}
+
+func (_c *MockMatchUsecase_Act_Call) Run(run func(c context.Context, userID string, matchID string, action game.Action)) *MockMatchUsecase_Act_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(game.Action))
+	})
+	return _c
+}
+
+func (_c *MockMatchUsecase_Act_Call) Return(_a0 *game.View, _a1 error) *MockMatchUsecase_Act_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockMatchUsecase_Act_Call) RunAndReturn(run func(context.Context, string, string, game.Action) (*game.View, error)) *MockMatchUsecase_Act_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Get provides a mock function with given fields: c, userID, matchID
+func (_m *MockMatchUsecase) Get(c context.Context, userID string, matchID string) (*domain.Match, *game.View, error) {
+	ret := _m.Called(c, userID, matchID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *domain.Match
+	var r1 *game.View
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.Match, *game.View, error)); ok {
+		return rf(c, userID, matchID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.Match); ok {
+		r0 = rf(c, userID, matchID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Match)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) *game.View); ok {
+		r1 = rf(c, userID, matchID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*game.View)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string) error); ok {
+		r2 = rf(c, userID, matchID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockMatchUsecase_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type MockMatchUsecase_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - c context.Context
+//   - userID string
+//   - matchID string
+func (_e *MockMatchUsecase_Expecter) Get(c interface{}, userID interface{}, matchID interface{}) *MockMatchUsecase_Get_Call {
+	return &MockMatchUsecase_Get_Call{Call: _e.mock.On("Get", c, userID, matchID)}
+}
+
+func (_c *MockMatchUsecase_Get_Call) Run(run func(c context.Context, userID string, matchID string)) *MockMatchUsecase_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockMatchUsecase_Get_Call) Return(_a0 *domain.Match, _a1 *game.View, _a2 error) *MockMatchUsecase_Get_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockMatchUsecase_Get_Call) RunAndReturn(run func(context.Context, string, string) (*domain.Match, *game.View, error)) *MockMatchUsecase_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Replay provides a mock function with given fields: c, matchID, step
+func (_m *MockMatchUsecase) Replay(c context.Context, matchID string, step int) (*domain.MatchReplay, error) {
+	ret := _m.Called(c, matchID, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Replay")
+	}
+
+	var r0 *domain.MatchReplay
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) (*domain.MatchReplay, error)); ok {
+		return rf(c, matchID, step)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) *domain.MatchReplay); ok {
+		r0 = rf(c, matchID, step)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.MatchReplay)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(c, matchID, step)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockMatchUsecase_Replay_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Replay'
+type MockMatchUsecase_Replay_Call struct {
+	*mock.Call
+}
+
+// Replay is a helper method to define mock.On call
+//   - c context.Context
+//   - matchID string
+//   - step int
+func (_e *MockMatchUsecase_Expecter) Replay(c interface{}, matchID interface{}, step interface{}) *MockMatchUsecase_Replay_Call {
+	return &MockMatchUsecase_Replay_Call{Call: _e.mock.On("Replay", c, matchID, step)}
+}
+
+func (_c *MockMatchUsecase_Replay_Call) Run(run func(c context.Context, matchID string, step int)) *MockMatchUsecase_Replay_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockMatchUsecase_Replay_Call) Return(_a0 *domain.MatchReplay, _a1 error) *MockMatchUsecase_Replay_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockMatchUsecase_Replay_Call) RunAndReturn(run func(context.Context, string, int) (*domain.MatchReplay, error)) *MockMatchUsecase_Replay_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Resume provides a mock function with given fields: c, userID, matchID, after
+func (_m *MockMatchUsecase) Resume(c context.Context, userID string, matchID string, after int) (*domain.MatchResume, error) {
+	ret := _m.Called(c, userID, matchID, after)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Resume")
+	}
+
+	var r0 *domain.MatchResume
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) (*domain.MatchResume, error)); ok {
+		return rf(c, userID, matchID, after)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) *domain.MatchResume); ok {
+		r0 = rf(c, userID, matchID, after)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.MatchResume)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int) error); ok {
+		r1 = rf(c, userID, matchID, after)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockMatchUsecase_Resume_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Resume'
+type MockMatchUsecase_Resume_Call struct {
+	*mock.Call
+}
+
+// Resume is a helper method to define mock.On call
+//   - c context.Context
+//   - userID string
+//   - matchID string
+//   - after int
+func (_e *MockMatchUsecase_Expecter) Resume(c interface{}, userID interface{}, matchID interface{}, after interface{}) *MockMatchUsecase_Resume_Call {
+	return &MockMatchUsecase_Resume_Call{Call: _e.mock.On("Resume", c, userID, matchID, after)}
+}
+
+func (_c *MockMatchUsecase_Resume_Call) Run(run func(c context.Context, userID string, matchID string, after int)) *MockMatchUsecase_Resume_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockMatchUsecase_Resume_Call) Return(_a0 *domain.MatchResume, _a1 error) *MockMatchUsecase_Resume_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockMatchUsecase_Resume_Call) RunAndReturn(run func(context.Context, string, string, int) (*domain.MatchResume, error)) *MockMatchUsecase_Resume_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetConnected provides a mock function with given fields: c, userID, matchID, connected
+func (_m *MockMatchUsecase) SetConnected(c context.Context, userID string, matchID string, connected bool) error {
+	ret := _m.Called(c, userID, matchID, connected)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetConnected")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool) error); ok {
+		r0 = rf(c, userID, matchID, connected)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockMatchUsecase_SetConnected_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetConnected'
+type MockMatchUsecase_SetConnected_Call struct {
+	*mock.Call
+}
+
+// SetConnected is a helper method to define mock.On call
+//   - c context.Context
+//   - userID string
+//   - matchID string
+//   - connected bool
+func (_e *MockMatchUsecase_Expecter) SetConnected(c interface{}, userID interface{}, matchID interface{}, connected interface{}) *MockMatchUsecase_SetConnected_Call {
+	return &MockMatchUsecase_SetConnected_Call{Call: _e.mock.On("SetConnected", c, userID, matchID, connected)}
+}
+
+func (_c *MockMatchUsecase_SetConnected_Call) Run(run func(c context.Context, userID string, matchID string, connected bool)) *MockMatchUsecase_SetConnected_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(bool))
+	})
+	return _c
+}
+
+func (_c *MockMatchUsecase_SetConnected_Call) Return(_a0 error) *MockMatchUsecase_SetConnected_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockMatchUsecase_SetConnected_Call) RunAndReturn(run func(context.Context, string, string, bool) error) *MockMatchUsecase_SetConnected_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Spectate provides a mock function with given fields: c, matchID
+func (_m *MockMatchUsecase) Spectate(c context.Context, matchID string) (*domain.SpectatedMatch, error) {
+	ret := _m.Called(c, matchID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Spectate")
+	}
+
+	var r0 *domain.SpectatedMatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.SpectatedMatch, error)); ok {
+		return rf(c, matchID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.SpectatedMatch); ok {
+		r0 = rf(c, matchID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.SpectatedMatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, matchID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockMatchUsecase_Spectate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Spectate'
+type MockMatchUsecase_Spectate_Call struct {
+	*mock.Call
+}
+
+// Spectate is a helper method to define mock.On call
+//   - c context.Context
+//   - matchID string
+func (_e *MockMatchUsecase_Expecter) Spectate(c interface{}, matchID interface{}) *MockMatchUsecase_Spectate_Call {
+	return &MockMatchUsecase_Spectate_Call{Call: _e.mock.On("Spectate", c, matchID)}
+}
+
+func (_c *MockMatchUsecase_Spectate_Call) Run(run func(c context.Context, matchID string)) *MockMatchUsecase_Spectate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockMatchUsecase_Spectate_Call) Return(_a0 *domain.SpectatedMatch, _a1 error) *MockMatchUsecase_Spectate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockMatchUsecase_Spectate_Call) RunAndReturn(run func(context.Context, string) (*domain.SpectatedMatch, error)) *MockMatchUsecase_Spectate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Start provides a mock function with given fields: c, match
+func (_m *MockMatchUsecase) Start(c context.Context, match *domain.Match) error {
+	ret := _m.Called(c, match)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Start")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Match) error); ok {
+		r0 = rf(c, match)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockMatchUsecase_Start_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Start'
+type MockMatchUsecase_Start_Call struct {
+	*mock.Call
+}
+
+// Start is a helper method to define mock.On call
+//   - c context.Context
+//   - match *domain.Match
+func (_e *MockMatchUsecase_Expecter) Start(c interface{}, match interface{}) *MockMatchUsecase_Start_Call {
+	return &MockMatchUsecase_Start_Call{Call: _e.mock.On("Start", c, match)}
+}
+
+func (_c *MockMatchUsecase_Start_Call) Run(run func(c context.Context, match *domain.Match)) *MockMatchUsecase_Start_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.Match))
+	})
+	return _c
+}
+
+func (_c *MockMatchUsecase_Start_Call) Return(_a0 error) *MockMatchUsecase_Start_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockMatchUsecase_Start_Call) RunAndReturn(run func(context.Context, *domain.Match) error) *MockMatchUsecase_Start_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockMatchUsecase creates a new instance of MockMatchUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockMatchUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockMatchUsecase {
+	mock := &MockMatchUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,149 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// MockPublisher is an autogenerated mock type for the Publisher type
+type MockPublisher struct {
+	mock.Mock
+}
+
+type MockPublisher_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockPublisher) EXPECT() *MockPublisher_Expecter {
+	return &MockPublisher_Expecter{mock: &_m.Mock}
+}
+
+// Publish provides a mock function with given fields: topic, event, data
+func (_m *MockPublisher) Publish(topic string, event string, data interface{}) {
+	_m.Called(topic, event, data)
+}
+
+// MockPublisher_Publish_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Publish'
+type MockPublisher_Publish_Call struct {
+	*mock.Call
+}
+
+// Publish is a helper method to define mock.On call
+//   - topic string
+//   - event string
+//   - data interface{}
+func (_e *MockPublisher_Expecter) Publish(topic interface{}, event interface{}, data interface{}) *MockPublisher_Publish_Call {
+	return &MockPublisher_Publish_Call{Call: _e.mock.On("Publish", topic, event, data)}
+}
+
+func (_c *MockPublisher_Publish_Call) Run(run func(topic string, event string, data interface{})) *MockPublisher_Publish_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string), args[2].(interface{}))
+	})
+	return _c
+}
+
+func (_c *MockPublisher_Publish_Call) Return() *MockPublisher_Publish_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockPublisher_Publish_Call) RunAndReturn(run func(string, string, interface{})) *MockPublisher_Publish_Call {
+	_c.Run(run)
+	return _c
+}
+
+// Subscribers provides a mock function with given fields: topic
+func (_m *MockPublisher) Subscribers(topic string) []string {
+	ret := _m.Called(topic)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Subscribers")
+	}
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string) []string); ok {
+		r0 = rf(topic)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
+// MockPublisher_Subscribers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Subscribers'
+type MockPublisher_Subscribers_Call struct {
+	*mock.Call
+}
+
+// Subscribers is a helper method to define mock.On call
+//   - topic string
+func (_e *MockPublisher_Expecter) Subscribers(topic interface{}) *MockPublisher_Subscribers_Call {
+	return &MockPublisher_Subscribers_Call{Call: _e.mock.On("Subscribers", topic)}
+}
+
+func (_c *MockPublisher_Subscribers_Call) Run(run func(topic string)) *MockPublisher_Subscribers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *MockPublisher_Subscribers_Call) Return(_a0 []string) *MockPublisher_Subscribers_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPublisher_Subscribers_Call) RunAndReturn(run func(string) []string) *MockPublisher_Subscribers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Unsubscribe provides a mock function with given fields: topic, userID
+func (_m *MockPublisher) Unsubscribe(topic string, userID string) {
+	_m.Called(topic, userID)
+}
+
+// MockPublisher_Unsubscribe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Unsubscribe'
+type MockPublisher_Unsubscribe_Call struct {
+	*mock.Call
+}
+
+// Unsubscribe is a helper method to define mock.On call
+//   - topic string
+//   - userID string
+func (_e *MockPublisher_Expecter) Unsubscribe(topic interface{}, userID interface{}) *MockPublisher_Unsubscribe_Call {
+	return &MockPublisher_Unsubscribe_Call{Call: _e.mock.On("Unsubscribe", topic, userID)}
+}
+
+func (_c *MockPublisher_Unsubscribe_Call) Run(run func(topic string, userID string)) *MockPublisher_Unsubscribe_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockPublisher_Unsubscribe_Call) Return() *MockPublisher_Unsubscribe_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockPublisher_Unsubscribe_Call) RunAndReturn(run func(string, string)) *MockPublisher_Unsubscribe_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewMockPublisher creates a new instance of MockPublisher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockPublisher(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockPublisher {
+	mock := &MockPublisher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,389 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/Simpolette/HeartSteal/server/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockRatingRepository is an autogenerated mock type for the RatingRepository type
+type MockRatingRepository struct {
+	mock.Mock
+}
+
+type MockRatingRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockRatingRepository) EXPECT() *MockRatingRepository_Expecter {
+	return &MockRatingRepository_Expecter{mock: &_m.Mock}
+}
+
+// CountAhead provides a mock function with given fields: c, mode, userIDs, pos
+func (_m *MockRatingRepository) CountAhead(c context.Context, mode domain.GameMode, userIDs []primitive.ObjectID, pos domain.RankCursor) (int, error) {
+	ret := _m.Called(c, mode, userIDs, pos)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountAhead")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.GameMode, []primitive.ObjectID, domain.RankCursor) (int, error)); ok {
+		return rf(c, mode, userIDs, pos)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.GameMode, []primitive.ObjectID, domain.RankCursor) int); ok {
+		r0 = rf(c, mode, userIDs, pos)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.GameMode, []primitive.ObjectID, domain.RankCursor) error); ok {
+		r1 = rf(c, mode, userIDs, pos)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRatingRepository_CountAhead_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountAhead'
+type MockRatingRepository_CountAhead_Call struct {
+	*mock.Call
+}
+
+// CountAhead is a helper method to define mock.On call
+//   - c context.Context
+//   - mode domain.GameMode
+//   - userIDs []primitive.ObjectID
+//   - pos domain.RankCursor
+func (_e *MockRatingRepository_Expecter) CountAhead(c interface{}, mode interface{}, userIDs interface{}, pos interface{}) *MockRatingRepository_CountAhead_Call {
+	return &MockRatingRepository_CountAhead_Call{Call: _e.mock.On("CountAhead", c, mode, userIDs, pos)}
+}
+
+func (_c *MockRatingRepository_CountAhead_Call) Run(run func(c context.Context, mode domain.GameMode, userIDs []primitive.ObjectID, pos domain.RankCursor)) *MockRatingRepository_CountAhead_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.GameMode), args[2].([]primitive.ObjectID), args[3].(domain.RankCursor))
+	})
+	return _c
+}
+
+func (_c *MockRatingRepository_CountAhead_Call) Return(_a0 int, _a1 error) *MockRatingRepository_CountAhead_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRatingRepository_CountAhead_Call) RunAndReturn(run func(context.Context, domain.GameMode, []primitive.ObjectID, domain.RankCursor) (int, error)) *MockRatingRepository_CountAhead_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Get provides a mock function with given fields: c, userID, mode
+func (_m *MockRatingRepository) Get(c context.Context, userID primitive.ObjectID, mode domain.GameMode) (*domain.PlayerRating, error) {
+	ret := _m.Called(c, userID, mode)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *domain.PlayerRating
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, domain.GameMode) (*domain.PlayerRating, error)); ok {
+		return rf(c, userID, mode)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, domain.GameMode) *domain.PlayerRating); ok {
+		r0 = rf(c, userID, mode)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.PlayerRating)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID, domain.GameMode) error); ok {
+		r1 = rf(c, userID, mode)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRatingRepository_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type MockRatingRepository_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - c context.Context
+//   - userID primitive.ObjectID
+//   - mode domain.GameMode
+func (_e *MockRatingRepository_Expecter) Get(c interface{}, userID interface{}, mode interface{}) *MockRatingRepository_Get_Call {
+	return &MockRatingRepository_Get_Call{Call: _e.mock.On("Get", c, userID, mode)}
+}
+
+func (_c *MockRatingRepository_Get_Call) Run(run func(c context.Context, userID primitive.ObjectID, mode domain.GameMode)) *MockRatingRepository_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(primitive.ObjectID), args[2].(domain.GameMode))
+	})
+	return _c
+}
+
+func (_c *MockRatingRepository_Get_Call) Return(_a0 *domain.PlayerRating, _a1 error) *MockRatingRepository_Get_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRatingRepository_Get_Call) RunAndReturn(run func(context.Context, primitive.ObjectID, domain.GameMode) (*domain.PlayerRating, error)) *MockRatingRepository_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAhead provides a mock function with given fields: c, mode, userIDs, pos, limit
+func (_m *MockRatingRepository) ListAhead(c context.Context, mode domain.GameMode, userIDs []primitive.ObjectID, pos domain.RankCursor, limit int) ([]domain.PlayerRating, error) {
+	ret := _m.Called(c, mode, userIDs, pos, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAhead")
+	}
+
+	var r0 []domain.PlayerRating
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.GameMode, []primitive.ObjectID, domain.RankCursor, int) ([]domain.PlayerRating, error)); ok {
+		return rf(c, mode, userIDs, pos, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.GameMode, []primitive.ObjectID, domain.RankCursor, int) []domain.PlayerRating); ok {
+		r0 = rf(c, mode, userIDs, pos, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.PlayerRating)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.GameMode, []primitive.ObjectID, domain.RankCursor, int) error); ok {
+		r1 = rf(c, mode, userIDs, pos, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRatingRepository_ListAhead_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAhead'
+type MockRatingRepository_ListAhead_Call struct {
+	*mock.Call
+}
+
+// ListAhead is a helper method to define mock.On call
+//   - c context.Context
+//   - mode domain.GameMode
+//   - userIDs []primitive.ObjectID
+//   - pos domain.RankCursor
+//   - limit int
+func (_e *MockRatingRepository_Expecter) ListAhead(c interface{}, mode interface{}, userIDs interface{}, pos interface{}, limit interface{}) *MockRatingRepository_ListAhead_Call {
+	return &MockRatingRepository_ListAhead_Call{Call: _e.mock.On("ListAhead", c, mode, userIDs, pos, limit)}
+}
+
+func (_c *MockRatingRepository_ListAhead_Call) Run(run func(c context.Context, mode domain.GameMode, userIDs []primitive.ObjectID, pos domain.RankCursor, limit int)) *MockRatingRepository_ListAhead_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.GameMode), args[2].([]primitive.ObjectID), args[3].(domain.RankCursor), args[4].(int))
+	})
+	return _c
+}
+
+func (_c *MockRatingRepository_ListAhead_Call) Return(_a0 []domain.PlayerRating, _a1 error) *MockRatingRepository_ListAhead_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRatingRepository_ListAhead_Call) RunAndReturn(run func(context.Context, domain.GameMode, []primitive.ObjectID, domain.RankCursor, int) ([]domain.PlayerRating, error)) *MockRatingRepository_ListAhead_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListByUsers provides a mock function with given fields: c, mode, userIDs
+func (_m *MockRatingRepository) ListByUsers(c context.Context, mode domain.GameMode, userIDs []primitive.ObjectID) ([]domain.PlayerRating, error) {
+	ret := _m.Called(c, mode, userIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByUsers")
+	}
+
+	var r0 []domain.PlayerRating
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.GameMode, []primitive.ObjectID) ([]domain.PlayerRating, error)); ok {
+		return rf(c, mode, userIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.GameMode, []primitive.ObjectID) []domain.PlayerRating); ok {
+		r0 = rf(c, mode, userIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.PlayerRating)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.GameMode, []primitive.ObjectID) error); ok {
+		r1 = rf(c, mode, userIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRatingRepository_ListByUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListByUsers'
+type MockRatingRepository_ListByUsers_Call struct {
+	*mock.Call
+}
+
+// ListByUsers is a helper method to define mock.On call
+//   - c context.Context
+//   - mode domain.GameMode
+//   - userIDs []primitive.ObjectID
+func (_e *MockRatingRepository_Expecter) ListByUsers(c interface{}, mode interface{}, userIDs interface{}) *MockRatingRepository_ListByUsers_Call {
+	return &MockRatingRepository_ListByUsers_Call{Call: _e.mock.On("ListByUsers", c, mode, userIDs)}
+}
+
+func (_c *MockRatingRepository_ListByUsers_Call) Run(run func(c context.Context, mode domain.GameMode, userIDs []primitive.ObjectID)) *MockRatingRepository_ListByUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.GameMode), args[2].([]primitive.ObjectID))
+	})
+	return _c
+}
+
+func (_c *MockRatingRepository_ListByUsers_Call) Return(_a0 []domain.PlayerRating, _a1 error) *MockRatingRepository_ListByUsers_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRatingRepository_ListByUsers_Call) RunAndReturn(run func(context.Context, domain.GameMode, []primitive.ObjectID) ([]domain.PlayerRating, error)) *MockRatingRepository_ListByUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListRanked provides a mock function with given fields: c, mode, userIDs, after, limit
+func (_m *MockRatingRepository) ListRanked(c context.Context, mode domain.GameMode, userIDs []primitive.ObjectID, after *domain.RankCursor, limit int) ([]domain.PlayerRating, error) {
+	ret := _m.Called(c, mode, userIDs, after, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRanked")
+	}
+
+	var r0 []domain.PlayerRating
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.GameMode, []primitive.ObjectID, *domain.RankCursor, int) ([]domain.PlayerRating, error)); ok {
+		return rf(c, mode, userIDs, after, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.GameMode, []primitive.ObjectID, *domain.RankCursor, int) []domain.PlayerRating); ok {
+		r0 = rf(c, mode, userIDs, after, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.PlayerRating)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.GameMode, []primitive.ObjectID, *domain.RankCursor, int) error); ok {
+		r1 = rf(c, mode, userIDs, after, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRatingRepository_ListRanked_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListRanked'
+type MockRatingRepository_ListRanked_Call struct {
+	*mock.Call
+}
+
+// ListRanked is a helper method to define mock.On call
+//   - c context.Context
+//   - mode domain.GameMode
+//   - userIDs []primitive.ObjectID
+//   - after *domain.RankCursor
+//   - limit int
+func (_e *MockRatingRepository_Expecter) ListRanked(c interface{}, mode interface{}, userIDs interface{}, after interface{}, limit interface{}) *MockRatingRepository_ListRanked_Call {
+	return &MockRatingRepository_ListRanked_Call{Call: _e.mock.On("ListRanked", c, mode, userIDs, after, limit)}
+}
+
+func (_c *MockRatingRepository_ListRanked_Call) Run(run func(c context.Context, mode domain.GameMode, userIDs []primitive.ObjectID, after *domain.RankCursor, limit int)) *MockRatingRepository_ListRanked_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.GameMode), args[2].([]primitive.ObjectID), args[3].(*domain.RankCursor), args[4].(int))
+	})
+	return _c
+}
+
+func (_c *MockRatingRepository_ListRanked_Call) Return(_a0 []domain.PlayerRating, _a1 error) *MockRatingRepository_ListRanked_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRatingRepository_ListRanked_Call) RunAndReturn(run func(context.Context, domain.GameMode, []primitive.ObjectID, *domain.RankCursor, int) ([]domain.PlayerRating, error)) *MockRatingRepository_ListRanked_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Save provides a mock function with given fields: c, rating
+func (_m *MockRatingRepository) Save(c context.Context, rating *domain.PlayerRating) error {
+	ret := _m.Called(c, rating)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.PlayerRating) error); ok {
+		r0 = rf(c, rating)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockRatingRepository_Save_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Save'
+type MockRatingRepository_Save_Call struct {
+	*mock.Call
+}
+
+// Save is a helper method to define mock.On call
+//   - c context.Context
+//   - rating *domain.PlayerRating
+func (_e *MockRatingRepository_Expecter) Save(c interface{}, rating interface{}) *MockRatingRepository_Save_Call {
+	return &MockRatingRepository_Save_Call{Call: _e.mock.On("Save", c, rating)}
+}
+
+func (_c *MockRatingRepository_Save_Call) Run(run func(c context.Context, rating *domain.PlayerRating)) *MockRatingRepository_Save_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.PlayerRating))
+	})
+	return _c
+}
+
+func (_c *MockRatingRepository_Save_Call) Return(_a0 error) *MockRatingRepository_Save_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockRatingRepository_Save_Call) RunAndReturn(run func(context.Context, *domain.PlayerRating) error) *MockRatingRepository_Save_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockRatingRepository creates a new instance of MockRatingRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRatingRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRatingRepository {
+	mock := &MockRatingRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,99 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/Simpolette/HeartSteal/server/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockRatingSource is an autogenerated mock type for the RatingSource type
+type MockRatingSource struct {
+	mock.Mock
+}
+
+type MockRatingSource_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockRatingSource) EXPECT() *MockRatingSource_Expecter {
+	return &MockRatingSource_Expecter{mock: &_m.Mock}
+}
+
+// Ratings provides a mock function with given fields: c, mode, userIDs
+func (_m *MockRatingSource) Ratings(c context.Context, mode domain.GameMode, userIDs []primitive.ObjectID) (map[primitive.ObjectID]float64, error) {
+	ret := _m.Called(c, mode, userIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ratings")
+	}
+
+	var r0 map[primitive.ObjectID]float64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.GameMode, []primitive.ObjectID) (map[primitive.ObjectID]float64, error)); ok {
+		return rf(c, mode, userIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.GameMode, []primitive.ObjectID) map[primitive.ObjectID]float64); ok {
+		r0 = rf(c, mode, userIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[primitive.ObjectID]float64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.GameMode, []primitive.ObjectID) error); ok {
+		r1 = rf(c, mode, userIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRatingSource_Ratings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Ratings'
+type MockRatingSource_Ratings_Call struct {
+	*mock.Call
+}
+
+// Ratings is a helper method to define mock.On call
+//   - c context.Context
+//   - mode domain.GameMode
+//   - userIDs []primitive.ObjectID
+func (_e *MockRatingSource_Expecter) Ratings(c interface{}, mode interface{}, userIDs interface{}) *MockRatingSource_Ratings_Call {
+	return &MockRatingSource_Ratings_Call{Call: _e.mock.On("Ratings", c, mode, userIDs)}
+}
+
+func (_c *MockRatingSource_Ratings_Call) Run(run func(c context.Context, mode domain.GameMode, userIDs []primitive.ObjectID)) *MockRatingSource_Ratings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.GameMode), args[2].([]primitive.ObjectID))
+	})
+	return _c
+}
+
+func (_c *MockRatingSource_Ratings_Call) Return(_a0 map[primitive.ObjectID]float64, _a1 error) *MockRatingSource_Ratings_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRatingSource_Ratings_Call) RunAndReturn(run func(context.Context, domain.GameMode, []primitive.ObjectID) (map[primitive.ObjectID]float64, error)) *MockRatingSource_Ratings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockRatingSource creates a new instance of MockRatingSource. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRatingSource(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRatingSource {
+	mock := &MockRatingSource{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,277 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/Simpolette/HeartSteal/server/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockRatingUsecase is an autogenerated mock type for the RatingUsecase type
+type MockRatingUsecase struct {
+	mock.Mock
+}
+
+type MockRatingUsecase_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockRatingUsecase) EXPECT() *MockRatingUsecase_Expecter {
+	return &MockRatingUsecase_Expecter{mock: &_m.Mock}
+}
+
+// AroundMe provides a mock function with given fields: c, userID, mode, limit
+func (_m *MockRatingUsecase) AroundMe(c context.Context, userID string, mode domain.GameMode, limit int) ([]domain.LeaderboardEntry, error) {
+	ret := _m.Called(c, userID, mode, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AroundMe")
+	}
+
+	var r0 []domain.LeaderboardEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.GameMode, int) ([]domain.LeaderboardEntry, error)); ok {
+		return rf(c, userID, mode, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.GameMode, int) []domain.LeaderboardEntry); ok {
+		r0 = rf(c, userID, mode, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.LeaderboardEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.GameMode, int) error); ok {
+		r1 = rf(c, userID, mode, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRatingUsecase_AroundMe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AroundMe'
+type MockRatingUsecase_AroundMe_Call struct {
+	*mock.Call
+}
+
+// AroundMe is a helper method to define mock.On call
+//   - c context.Context
+//   - userID string
+//   - mode domain.GameMode
+//   - limit int
+func (_e *MockRatingUsecase_Expecter) AroundMe(c interface{}, userID interface{}, mode interface{}, limit interface{}) *MockRatingUsecase_AroundMe_Call {
+	return &MockRatingUsecase_AroundMe_Call{Call: _e.mock.On("AroundMe", c, userID, mode, limit)}
+}
+
+func (_c *MockRatingUsecase_AroundMe_Call) Run(run func(c context.Context, userID string, mode domain.GameMode, limit int)) *MockRatingUsecase_AroundMe_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(domain.GameMode), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *MockRatingUsecase_AroundMe_Call) Return(_a0 []domain.LeaderboardEntry, _a1 error) *MockRatingUsecase_AroundMe_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRatingUsecase_AroundMe_Call) RunAndReturn(run func(context.Context, string, domain.GameMode, int) ([]domain.LeaderboardEntry, error)) *MockRatingUsecase_AroundMe_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Leaderboard provides a mock function with given fields: c, userID, mode, scope, limit, cursor
+func (_m *MockRatingUsecase) Leaderboard(c context.Context, userID string, mode domain.GameMode, scope domain.LeaderboardScope, limit int, cursor string) ([]domain.LeaderboardEntry, string, error) {
+	ret := _m.Called(c, userID, mode, scope, limit, cursor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Leaderboard")
+	}
+
+	var r0 []domain.LeaderboardEntry
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.GameMode, domain.LeaderboardScope, int, string) ([]domain.LeaderboardEntry, string, error)); ok {
+		return rf(c, userID, mode, scope, limit, cursor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.GameMode, domain.LeaderboardScope, int, string) []domain.LeaderboardEntry); ok {
+		r0 = rf(c, userID, mode, scope, limit, cursor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.LeaderboardEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.GameMode, domain.LeaderboardScope, int, string) string); ok {
+		r1 = rf(c, userID, mode, scope, limit, cursor)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, domain.GameMode, domain.LeaderboardScope, int, string) error); ok {
+		r2 = rf(c, userID, mode, scope, limit, cursor)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockRatingUsecase_Leaderboard_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Leaderboard'
+type MockRatingUsecase_Leaderboard_Call struct {
+	*mock.Call
+}
+
+// Leaderboard is a helper method to define mock.On call
+//   - c context.Context
+//   - userID string
+//   - mode domain.GameMode
+//   - scope domain.LeaderboardScope
+//   - limit int
+//   - cursor string
+func (_e *MockRatingUsecase_Expecter) Leaderboard(c interface{}, userID interface{}, mode interface{}, scope interface{}, limit interface{}, cursor interface{}) *MockRatingUsecase_Leaderboard_Call {
+	return &MockRatingUsecase_Leaderboard_Call{Call: _e.mock.On("Leaderboard", c, userID, mode, scope, limit, cursor)}
+}
+
+func (_c *MockRatingUsecase_Leaderboard_Call) Run(run func(c context.Context, userID string, mode domain.GameMode, scope domain.LeaderboardScope, limit int, cursor string)) *MockRatingUsecase_Leaderboard_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(domain.GameMode), args[3].(domain.LeaderboardScope), args[4].(int), args[5].(string))
+	})
+	return _c
+}
+
+func (_c *MockRatingUsecase_Leaderboard_Call) Return(_a0 []domain.LeaderboardEntry, _a1 string, _a2 error) *MockRatingUsecase_Leaderboard_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockRatingUsecase_Leaderboard_Call) RunAndReturn(run func(context.Context, string, domain.GameMode, domain.LeaderboardScope, int, string) ([]domain.LeaderboardEntry, string, error)) *MockRatingUsecase_Leaderboard_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Ratings provides a mock function with given fields: c, mode, userIDs
+func (_m *MockRatingUsecase) Ratings(c context.Context, mode domain.GameMode, userIDs []primitive.ObjectID) (map[primitive.ObjectID]float64, error) {
+	ret := _m.Called(c, mode, userIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ratings")
+	}
+
+	var r0 map[primitive.ObjectID]float64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.GameMode, []primitive.ObjectID) (map[primitive.ObjectID]float64, error)); ok {
+		return rf(c, mode, userIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.GameMode, []primitive.ObjectID) map[primitive.ObjectID]float64); ok {
+		r0 = rf(c, mode, userIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[primitive.ObjectID]float64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.GameMode, []primitive.ObjectID) error); ok {
+		r1 = rf(c, mode, userIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRatingUsecase_Ratings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Ratings'
+type MockRatingUsecase_Ratings_Call struct {
+	*mock.Call
+}
+
+// Ratings is a helper method to define mock.On call
+//   - c context.Context
+//   - mode domain.GameMode
+//   - userIDs []primitive.ObjectID
+func (_e *MockRatingUsecase_Expecter) Ratings(c interface{}, mode interface{}, userIDs interface{}) *MockRatingUsecase_Ratings_Call {
+	return &MockRatingUsecase_Ratings_Call{Call: _e.mock.On("Ratings", c, mode, userIDs)}
+}
+
+func (_c *MockRatingUsecase_Ratings_Call) Run(run func(c context.Context, mode domain.GameMode, userIDs []primitive.ObjectID)) *MockRatingUsecase_Ratings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.GameMode), args[2].([]primitive.ObjectID))
+	})
+	return _c
+}
+
+func (_c *MockRatingUsecase_Ratings_Call) Return(_a0 map[primitive.ObjectID]float64, _a1 error) *MockRatingUsecase_Ratings_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRatingUsecase_Ratings_Call) RunAndReturn(run func(context.Context, domain.GameMode, []primitive.ObjectID) (map[primitive.ObjectID]float64, error)) *MockRatingUsecase_Ratings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Record provides a mock function with given fields: c, match
+func (_m *MockRatingUsecase) Record(c context.Context, match *domain.Match) error {
+	ret := _m.Called(c, match)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Record")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Match) error); ok {
+		r0 = rf(c, match)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockRatingUsecase_Record_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Record'
+type MockRatingUsecase_Record_Call struct {
+	*mock.Call
+}
+
+// Record is a helper method to define mock.On call
+//   - c context.Context
+//   - match *domain.Match
+func (_e *MockRatingUsecase_Expecter) Record(c interface{}, match interface{}) *MockRatingUsecase_Record_Call {
+	return &MockRatingUsecase_Record_Call{Call: _e.mock.On("Record", c, match)}
+}
+
+func (_c *MockRatingUsecase_Record_Call) Run(run func(c context.Context, match *domain.Match)) *MockRatingUsecase_Record_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.Match))
+	})
+	return _c
+}
+
+func (_c *MockRatingUsecase_Record_Call) Return(_a0 error) *MockRatingUsecase_Record_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockRatingUsecase_Record_Call) RunAndReturn(run func(context.Context, *domain.Match) error) *MockRatingUsecase_Record_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockRatingUsecase creates a new instance of MockRatingUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRatingUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRatingUsecase {
+	mock := &MockRatingUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,205 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/Simpolette/HeartSteal/server/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+
+	time "time"
+)
+
+// MockSessionRepository is an autogenerated mock type for the SessionRepository type
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+type MockSessionRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockSessionRepository) EXPECT() *MockSessionRepository_Expecter {
+	return &MockSessionRepository_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function with given fields: c, session
+func (_m *MockSessionRepository) Create(c context.Context, session *domain.Session) error {
+	ret := _m.Called(c, session)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Session) error); ok {
+		r0 = rf(c, session)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockSessionRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockSessionRepository_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - c context.Context
+//   - session *domain.Session
+func (_e *MockSessionRepository_Expecter) Create(c interface{}, session interface{}) *MockSessionRepository_Create_Call {
+	return &MockSessionRepository_Create_Call{Call: _e.mock.On("Create", c, session)}
+}
+
+func (_c *MockSessionRepository_Create_Call) Run(run func(c context.Context, session *domain.Session)) *MockSessionRepository_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.Session))
+	})
+	return _c
+}
+
+func (_c *MockSessionRepository_Create_Call) Return(_a0 error) *MockSessionRepository_Create_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSessionRepository_Create_Call) RunAndReturn(run func(context.Context, *domain.Session) error) *MockSessionRepository_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteByUser provides a mock function with given fields: c, userID
+func (_m *MockSessionRepository) DeleteByUser(c context.Context, userID primitive.ObjectID) (int64, error) {
+	ret := _m.Called(c, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteByUser")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) (int64, error)); ok {
+		return rf(c, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) int64); ok {
+		r0 = rf(c, userID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID) error); ok {
+		r1 = rf(c, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSessionRepository_DeleteByUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteByUser'
+type MockSessionRepository_DeleteByUser_Call struct {
+	*mock.Call
+}
+
+// DeleteByUser is a helper method to define mock.On call
+//   - c context.Context
+//   - userID primitive.ObjectID
+func (_e *MockSessionRepository_Expecter) DeleteByUser(c interface{}, userID interface{}) *MockSessionRepository_DeleteByUser_Call {
+	return &MockSessionRepository_DeleteByUser_Call{Call: _e.mock.On("DeleteByUser", c, userID)}
+}
+
+func (_c *MockSessionRepository_DeleteByUser_Call) Run(run func(c context.Context, userID primitive.ObjectID)) *MockSessionRepository_DeleteByUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(primitive.ObjectID))
+	})
+	return _c
+}
+
+func (_c *MockSessionRepository_DeleteByUser_Call) Return(_a0 int64, _a1 error) *MockSessionRepository_DeleteByUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSessionRepository_DeleteByUser_Call) RunAndReturn(run func(context.Context, primitive.ObjectID) (int64, error)) *MockSessionRepository_DeleteByUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListActiveByUser provides a mock function with given fields: c, userID, now
+func (_m *MockSessionRepository) ListActiveByUser(c context.Context, userID primitive.ObjectID, now time.Time) ([]domain.Session, error) {
+	ret := _m.Called(c, userID, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListActiveByUser")
+	}
+
+	var r0 []domain.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, time.Time) ([]domain.Session, error)); ok {
+		return rf(c, userID, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, time.Time) []domain.Session); ok {
+		r0 = rf(c, userID, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID, time.Time) error); ok {
+		r1 = rf(c, userID, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSessionRepository_ListActiveByUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListActiveByUser'
+type MockSessionRepository_ListActiveByUser_Call struct {
+	*mock.Call
+}
+
+// ListActiveByUser is a helper method to define mock.On call
+//   - c context.Context
+//   - userID primitive.ObjectID
+//   - now time.Time
+func (_e *MockSessionRepository_Expecter) ListActiveByUser(c interface{}, userID interface{}, now interface{}) *MockSessionRepository_ListActiveByUser_Call {
+	return &MockSessionRepository_ListActiveByUser_Call{Call: _e.mock.On("ListActiveByUser", c, userID, now)}
+}
+
+func (_c *MockSessionRepository_ListActiveByUser_Call) Run(run func(c context.Context, userID primitive.ObjectID, now time.Time)) *MockSessionRepository_ListActiveByUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(primitive.ObjectID), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockSessionRepository_ListActiveByUser_Call) Return(_a0 []domain.Session, _a1 error) *MockSessionRepository_ListActiveByUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSessionRepository_ListActiveByUser_Call) RunAndReturn(run func(context.Context, primitive.ObjectID, time.Time) ([]domain.Session, error)) *MockSessionRepository_ListActiveByUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockSessionRepository creates a new instance of MockSessionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockSessionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockSessionRepository {
+	mock := &MockSessionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,193 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/Simpolette/HeartSteal/server/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockSpectatorUsecase is an autogenerated mock type for the SpectatorUsecase type
+type MockSpectatorUsecase struct {
+	mock.Mock
+}
+
+type MockSpectatorUsecase_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockSpectatorUsecase) EXPECT() *MockSpectatorUsecase_Expecter {
+	return &MockSpectatorUsecase_Expecter{mock: &_m.Mock}
+}
+
+// CanWatch provides a mock function with given fields: c, userID, matchID
+func (_m *MockSpectatorUsecase) CanWatch(c context.Context, userID string, matchID string) error {
+	ret := _m.Called(c, userID, matchID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CanWatch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, userID, matchID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockSpectatorUsecase_CanWatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CanWatch'
+type MockSpectatorUsecase_CanWatch_Call struct {
+	*mock.Call
+}
+
+// CanWatch is a helper method to define mock.On call
+//   - c context.Context
+//   - userID string
+//   - matchID string
+func (_e *MockSpectatorUsecase_Expecter) CanWatch(c interface{}, userID interface{}, matchID interface{}) *MockSpectatorUsecase_CanWatch_Call {
+	return &MockSpectatorUsecase_CanWatch_Call{Call: _e.mock.On("CanWatch", c, userID, matchID)}
+}
+
+func (_c *MockSpectatorUsecase_CanWatch_Call) Run(run func(c context.Context, userID string, matchID string)) *MockSpectatorUsecase_CanWatch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockSpectatorUsecase_CanWatch_Call) Return(_a0 error) *MockSpectatorUsecase_CanWatch_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSpectatorUsecase_CanWatch_Call) RunAndReturn(run func(context.Context, string, string) error) *MockSpectatorUsecase_CanWatch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetPolicy provides a mock function with given fields: c, userID, policy
+func (_m *MockSpectatorUsecase) SetPolicy(c context.Context, userID string, policy domain.SpectatorPolicy) error {
+	ret := _m.Called(c, userID, policy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetPolicy")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.SpectatorPolicy) error); ok {
+		r0 = rf(c, userID, policy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockSpectatorUsecase_SetPolicy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetPolicy'
+type MockSpectatorUsecase_SetPolicy_Call struct {
+	*mock.Call
+}
+
+// SetPolicy is a helper method to define mock.On call
+//   - c context.Context
+//   - userID string
+//   - policy domain.SpectatorPolicy
+func (_e *MockSpectatorUsecase_Expecter) SetPolicy(c interface{}, userID interface{}, policy interface{}) *MockSpectatorUsecase_SetPolicy_Call {
+	return &MockSpectatorUsecase_SetPolicy_Call{Call: _e.mock.On("SetPolicy", c, userID, policy)}
+}
+
+func (_c *MockSpectatorUsecase_SetPolicy_Call) Run(run func(c context.Context, userID string, policy domain.SpectatorPolicy)) *MockSpectatorUsecase_SetPolicy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(domain.SpectatorPolicy))
+	})
+	return _c
+}
+
+func (_c *MockSpectatorUsecase_SetPolicy_Call) Return(_a0 error) *MockSpectatorUsecase_SetPolicy_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSpectatorUsecase_SetPolicy_Call) RunAndReturn(run func(context.Context, string, domain.SpectatorPolicy) error) *MockSpectatorUsecase_SetPolicy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Watch provides a mock function with given fields: c, userID, matchID
+func (_m *MockSpectatorUsecase) Watch(c context.Context, userID string, matchID string) (*domain.SpectatedMatch, error) {
+	ret := _m.Called(c, userID, matchID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Watch")
+	}
+
+	var r0 *domain.SpectatedMatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.SpectatedMatch, error)); ok {
+		return rf(c, userID, matchID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.SpectatedMatch); ok {
+		r0 = rf(c, userID, matchID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.SpectatedMatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(c, userID, matchID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSpectatorUsecase_Watch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Watch'
+type MockSpectatorUsecase_Watch_Call struct {
+	*mock.Call
+}
+
+// Watch is a helper method to define mock.On call
+//   - c context.Context
+//   - userID string
+//   - matchID string
+func (_e *MockSpectatorUsecase_Expecter) Watch(c interface{}, userID interface{}, matchID interface{}) *MockSpectatorUsecase_Watch_Call {
+	return &MockSpectatorUsecase_Watch_Call{Call: _e.mock.On("Watch", c, userID, matchID)}
+}
+
+func (_c *MockSpectatorUsecase_Watch_Call) Run(run func(c context.Context, userID string, matchID string)) *MockSpectatorUsecase_Watch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockSpectatorUsecase_Watch_Call) Return(_a0 *domain.SpectatedMatch, _a1 error) *MockSpectatorUsecase_Watch_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSpectatorUsecase_Watch_Call) RunAndReturn(run func(context.Context, string, string) (*domain.SpectatedMatch, error)) *MockSpectatorUsecase_Watch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockSpectatorUsecase creates a new instance of MockSpectatorUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockSpectatorUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockSpectatorUsecase {
+	mock := &MockSpectatorUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,192 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/Simpolette/HeartSteal/server/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockStatsRepository is an autogenerated mock type for the StatsRepository type
+type MockStatsRepository struct {
+	mock.Mock
+}
+
+type MockStatsRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockStatsRepository) EXPECT() *MockStatsRepository_Expecter {
+	return &MockStatsRepository_Expecter{mock: &_m.Mock}
+}
+
+// Delete provides a mock function with given fields: c, userID
+func (_m *MockStatsRepository) Delete(c context.Context, userID primitive.ObjectID) error {
+	ret := _m.Called(c, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) error); ok {
+		r0 = rf(c, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStatsRepository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockStatsRepository_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - c context.Context
+//   - userID primitive.ObjectID
+func (_e *MockStatsRepository_Expecter) Delete(c interface{}, userID interface{}) *MockStatsRepository_Delete_Call {
+	return &MockStatsRepository_Delete_Call{Call: _e.mock.On("Delete", c, userID)}
+}
+
+func (_c *MockStatsRepository_Delete_Call) Run(run func(c context.Context, userID primitive.ObjectID)) *MockStatsRepository_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(primitive.ObjectID))
+	})
+	return _c
+}
+
+func (_c *MockStatsRepository_Delete_Call) Return(_a0 error) *MockStatsRepository_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStatsRepository_Delete_Call) RunAndReturn(run func(context.Context, primitive.ObjectID) error) *MockStatsRepository_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Get provides a mock function with given fields: c, userID
+func (_m *MockStatsRepository) Get(c context.Context, userID primitive.ObjectID) (*domain.PlayerStats, error) {
+	ret := _m.Called(c, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *domain.PlayerStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) (*domain.PlayerStats, error)); ok {
+		return rf(c, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) *domain.PlayerStats); ok {
+		r0 = rf(c, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.PlayerStats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID) error); ok {
+		r1 = rf(c, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockStatsRepository_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type MockStatsRepository_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - c context.Context
+//   - userID primitive.ObjectID
+func (_e *MockStatsRepository_Expecter) Get(c interface{}, userID interface{}) *MockStatsRepository_Get_Call {
+	return &MockStatsRepository_Get_Call{Call: _e.mock.On("Get", c, userID)}
+}
+
+func (_c *MockStatsRepository_Get_Call) Run(run func(c context.Context, userID primitive.ObjectID)) *MockStatsRepository_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(primitive.ObjectID))
+	})
+	return _c
+}
+
+func (_c *MockStatsRepository_Get_Call) Return(_a0 *domain.PlayerStats, _a1 error) *MockStatsRepository_Get_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockStatsRepository_Get_Call) RunAndReturn(run func(context.Context, primitive.ObjectID) (*domain.PlayerStats, error)) *MockStatsRepository_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Save provides a mock function with given fields: c, stats
+func (_m *MockStatsRepository) Save(c context.Context, stats *domain.PlayerStats) error {
+	ret := _m.Called(c, stats)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.PlayerStats) error); ok {
+		r0 = rf(c, stats)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockStatsRepository_Save_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Save'
+type MockStatsRepository_Save_Call struct {
+	*mock.Call
+}
+
+// Save is a helper method to define mock.On call
+//   - c context.Context
+//   - stats *domain.PlayerStats
+func (_e *MockStatsRepository_Expecter) Save(c interface{}, stats interface{}) *MockStatsRepository_Save_Call {
+	return &MockStatsRepository_Save_Call{Call: _e.mock.On("Save", c, stats)}
+}
+
+func (_c *MockStatsRepository_Save_Call) Run(run func(c context.Context, stats *domain.PlayerStats)) *MockStatsRepository_Save_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.PlayerStats))
+	})
+	return _c
+}
+
+func (_c *MockStatsRepository_Save_Call) Return(_a0 error) *MockStatsRepository_Save_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockStatsRepository_Save_Call) RunAndReturn(run func(context.Context, *domain.PlayerStats) error) *MockStatsRepository_Save_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockStatsRepository creates a new instance of MockStatsRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockStatsRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockStatsRepository {
+	mock := &MockStatsRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
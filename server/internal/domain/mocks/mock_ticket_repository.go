@@ -0,0 +1,146 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/Simpolette/HeartSteal/server/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// MockTicketRepository is an autogenerated mock type for the TicketRepository type
+type MockTicketRepository struct {
+	mock.Mock
+}
+
+type MockTicketRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockTicketRepository) EXPECT() *MockTicketRepository_Expecter {
+	return &MockTicketRepository_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function with given fields: c, ticket
+func (_m *MockTicketRepository) Create(c context.Context, ticket *domain.Ticket) error {
+	ret := _m.Called(c, ticket)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Ticket) error); ok {
+		r0 = rf(c, ticket)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockTicketRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockTicketRepository_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - c context.Context
+//   - ticket *domain.Ticket
+func (_e *MockTicketRepository_Expecter) Create(c interface{}, ticket interface{}) *MockTicketRepository_Create_Call {
+	return &MockTicketRepository_Create_Call{Call: _e.mock.On("Create", c, ticket)}
+}
+
+func (_c *MockTicketRepository_Create_Call) Run(run func(c context.Context, ticket *domain.Ticket)) *MockTicketRepository_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.Ticket))
+	})
+	return _c
+}
+
+func (_c *MockTicketRepository_Create_Call) Return(_a0 error) *MockTicketRepository_Create_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockTicketRepository_Create_Call) RunAndReturn(run func(context.Context, *domain.Ticket) error) *MockTicketRepository_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Take provides a mock function with given fields: c, value, now
+func (_m *MockTicketRepository) Take(c context.Context, value string, now time.Time) (*domain.Ticket, error) {
+	ret := _m.Called(c, value, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Take")
+	}
+
+	var r0 *domain.Ticket
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (*domain.Ticket, error)); ok {
+		return rf(c, value, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) *domain.Ticket); ok {
+		r0 = rf(c, value, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Ticket)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(c, value, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockTicketRepository_Take_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Take'
+type MockTicketRepository_Take_Call struct {
+	*mock.Call
+}
+
+// Take is a helper method to define mock.On call
+//   - c context.Context
+//   - value string
+//   - now time.Time
+func (_e *MockTicketRepository_Expecter) Take(c interface{}, value interface{}, now interface{}) *MockTicketRepository_Take_Call {
+	return &MockTicketRepository_Take_Call{Call: _e.mock.On("Take", c, value, now)}
+}
+
+func (_c *MockTicketRepository_Take_Call) Run(run func(c context.Context, value string, now time.Time)) *MockTicketRepository_Take_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockTicketRepository_Take_Call) Return(_a0 *domain.Ticket, _a1 error) *MockTicketRepository_Take_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockTicketRepository_Take_Call) RunAndReturn(run func(context.Context, string, time.Time) (*domain.Ticket, error)) *MockTicketRepository_Take_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockTicketRepository creates a new instance of MockTicketRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockTicketRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockTicketRepository {
+	mock := &MockTicketRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// Cursor marks the last item of a page in lists sorted newest first by a
+// timestamp, with the ID breaking ties. Clients only see it encoded.
+type Cursor struct {
+	Time time.Time
+	ID   primitive.ObjectID
+}
+
+func (c Cursor) Encode() string {
+	raw := strconv.FormatInt(c.Time.UnixNano(), 10) + ":" + c.ID.Hex()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor from Encode. An empty string means the first
+// page and returns nil.
+func DecodeCursor(s string) (*Cursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	nanos, hex, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	id, err := primitive.ObjectIDFromHex(hex)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &Cursor{Time: time.Unix(0, n).UTC(), ID: id}, nil
+}
+
+// Before reports whether an item sorted by (t, id) comes after the cursor in
+// a newest-first list.
+func (c *Cursor) Before(t time.Time, id primitive.ObjectID) bool {
+	if c == nil {
+		return true
+	}
+	if !t.Equal(c.Time) {
+		return t.Before(c.Time)
+	}
+	return id.Hex() < c.ID.Hex()
+}
+
+// PageLimit clamps a client-supplied limit to [1, MaxPageLimit], using
+// DefaultPageLimit when it is unset.
+func PageLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultPageLimit
+	}
+	return min(limit, MaxPageLimit)
+}
+
+// RankCursor marks the last entry of a leaderboard page. Boards are sorted
+// by rating, highest first, with the user ID breaking ties; Rank carries the
+// entry's place so the next page can keep counting.
+type RankCursor struct {
+	Rating float64
+	UserID primitive.ObjectID
+	Rank   int
+}
+
+func (c RankCursor) Encode() string {
+	raw := strconv.FormatFloat(c.Rating, 'g', -1, 64) + ":" + c.UserID.Hex() + ":" + strconv.Itoa(c.Rank)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeRankCursor parses a cursor from RankCursor.Encode. An empty string
+// means the top of the board and returns nil.
+func DecodeRankCursor(s string) (*RankCursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 3 {
+		return nil, ErrInvalidCursor
+	}
+	rating, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil || math.IsNaN(rating) || math.IsInf(rating, 0) {
+		return nil, ErrInvalidCursor
+	}
+	id, err := primitive.ObjectIDFromHex(parts[1])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	rank, err := strconv.Atoi(parts[2])
+	if err != nil || rank < 1 {
+		return nil, ErrInvalidCursor
+	}
+	return &RankCursor{Rating: rating, UserID: id, Rank: rank}, nil
+}
+
+// Ahead reports whether an entry sorted by (rating, userID) is ranked before
+// the cursor.
+func (c RankCursor) Ahead(rating float64, userID primitive.ObjectID) bool {
+	if rating != c.Rating {
+		return rating > c.Rating
+	}
+	return userID.Hex() < c.UserID.Hex()
+}
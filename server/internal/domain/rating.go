@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Simpolette/HeartSteal/server/internal/rating"
+)
+
+var (
+	ErrNotRanked               = errors.New("player has no rating in this mode")
+	ErrUnknownLeaderboardScope = errors.New("unknown leaderboard scope")
+)
+
+const (
+	CollectionRating = "ratings"
+)
+
+// RatingPeriod is one Glicko-2 rating period. A player's RD grows by a
+// period's worth for each period they don't play ranked.
+const RatingPeriod = 24 * time.Hour
+
+type LeaderboardScope string
+
+const (
+	LeaderboardGlobal LeaderboardScope = "global"
+	// LeaderboardFriends ranks the caller among their friends.
+	LeaderboardFriends LeaderboardScope = "friends"
+)
+
+// PlayerRating is a user's rating in one game mode. Names are copied when a
+// ranked match is recorded, so leaderboards need no user lookups.
+type PlayerRating struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty"  json:"-"`
+	UserID       primitive.ObjectID `bson:"user_id"        json:"user_id"`
+	Username     string             `bson:"username"       json:"username"`
+	DisplayName  string             `bson:"display_name"   json:"display_name"`
+	Mode         GameMode           `bson:"mode"           json:"mode"`
+	Rating       float64            `bson:"rating"         json:"rating"`
+	RD           float64            `bson:"rd"             json:"rd"`
+	Volatility   float64            `bson:"volatility"     json:"-"`
+	Games        int                `bson:"games"          json:"games"`
+	LastPlayedAt time.Time          `bson:"last_played_at" json:"last_played_at"`
+}
+
+// Glicko returns the rating as of now: RD has grown for every rating period
+// since the player last played.
+func (r *PlayerRating) Glicko(now time.Time) rating.Rating {
+	current := rating.Rating{Rating: r.Rating, RD: r.RD, Volatility: r.Volatility}
+	return current.Decay(float64(now.Sub(r.LastPlayedAt)) / float64(RatingPeriod))
+}
+
+type LeaderboardEntry struct {
+	Rank int
+	PlayerRating
+}
+
+type RatingRepository interface {
+	// Get returns userID's rating in mode, or ErrNotRanked.
+	Get(c context.Context, userID primitive.ObjectID, mode GameMode) (*PlayerRating, error)
+	// ListByUsers returns the ratings userIDs have in mode. Users who never
+	// played it ranked are left out.
+	ListByUsers(c context.Context, mode GameMode, userIDs []primitive.ObjectID) ([]PlayerRating, error)
+	// Save creates or replaces the rating of rating.UserID in rating.Mode.
+	Save(c context.Context, rating *PlayerRating) error
+	// ListRanked returns up to limit ratings in board order, starting after
+	// the cursor or at the top when it is nil. A non-nil userIDs limits the
+	// board to those users.
+	ListRanked(c context.Context, mode GameMode, userIDs []primitive.ObjectID, after *RankCursor, limit int) ([]PlayerRating, error)
+	// ListAhead returns up to limit ratings ranked just before pos, in board
+	// order.
+	ListAhead(c context.Context, mode GameMode, userIDs []primitive.ObjectID, pos RankCursor, limit int) ([]PlayerRating, error)
+	// CountAhead counts the ratings ranked before pos.
+	CountAhead(c context.Context, mode GameMode, userIDs []primitive.ObjectID, pos RankCursor) (int, error)
+}
+
+type RatingUsecase interface {
+	RatingSource
+	// Record rates the players of a finished ranked match and fills in each
+	// player's RatingChange. Call it inside the transaction that saves the
+	// match, so a result is rated exactly once.
+	Record(c context.Context, match *Match) error
+	// Leaderboard returns a page of mode's board and the cursor of the next
+	// one. The friends board holds userID and their friends.
+	Leaderboard(c context.Context, userID string, mode GameMode, scope LeaderboardScope, limit int, cursor string) ([]LeaderboardEntry, string, error)
+	// AroundMe returns userID's entry on the global board with up to limit
+	// players on each side.
+	AroundMe(c context.Context, userID string, mode GameMode, limit int) ([]LeaderboardEntry, error)
+}
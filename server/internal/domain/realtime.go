@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidTicket        = errors.New("realtime ticket is invalid or expired")
+	ErrUpgradeRequired      = errors.New("websocket upgrade required")
+	ErrUnknownTopic         = errors.New("unknown realtime topic")
+	ErrTopicForbidden       = errors.New("not allowed to subscribe to topic")
+	ErrTooManySubscriptions = errors.New("too many realtime subscriptions")
+)
+
+const (
+	CollectionTicket = "realtime_tickets"
+)
+
+// Topics are "<kind>:<id>". Subscribing to one is checked by the authorizer
+// registered for its kind.
+const (
+	TopicUser  = "user"
+	TopicLobby = "lobby"
+	TopicMatch = "match"
+	// TopicSpectate carries a match's public events to its spectators.
+	TopicSpectate = "spectate"
+)
+
+func UserTopic(userID string) string {
+	return TopicUser + ":" + userID
+}
+
+func LobbyTopic(lobbyID string) string {
+	return TopicLobby + ":" + lobbyID
+}
+
+func MatchTopic(matchID string) string {
+	return TopicMatch + ":" + matchID
+}
+
+func SpectateTopic(matchID string) string {
+	return TopicSpectate + ":" + matchID
+}
+
+// Ticket lets a client open a WebSocket without putting its access token in
+// the URL. It is issued to an authenticated user and can be redeemed once.
+type Ticket struct {
+	Value     string             `bson:"_id"`
+	UserID    primitive.ObjectID `bson:"user_id"`
+	ExpiresAt time.Time          `bson:"expires_at"`
+}
+
+type TicketRepository interface {
+	Create(c context.Context, ticket *Ticket) error
+	// Take deletes the ticket and returns it if it had not expired at now.
+	// A missing or expired ticket is ErrInvalidTicket.
+	Take(c context.Context, value string, now time.Time) (*Ticket, error)
+}
+
+type RealtimeUsecase interface {
+	IssueTicket(c context.Context, userID string) (*Ticket, error)
+	// RedeemTicket returns the ID of the user the ticket was issued to.
+	RedeemTicket(c context.Context, value string) (string, error)
+}
+
+// Publisher pushes events to the clients subscribed to a topic. Delivery is
+// best effort: clients that are not connected miss the event.
+type Publisher interface {
+	Publish(topic, event string, data any)
+	// Unsubscribe drops userID's subscriptions to topic, e.g. once they have
+	// lost access to it.
+	Unsubscribe(topic, userID string)
+	// Subscribers returns the IDs of the users subscribed to topic, each
+	// once however many connections they have.
+	Subscribers(topic string) []string
+}
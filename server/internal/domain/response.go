@@ -4,13 +4,31 @@ import "errors"
 
 var (
 	ErrInternalServerError = errors.New("internal server error")
+	ErrRateLimited         = errors.New("rate limit exceeded")
 )
 
 type SuccessResponse struct {
-	Message string      `json:"message"`         
-	Data    interface{} `json:"data,omitempty"`  
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
 }
 
 type ErrorResponse struct {
+	Code    ErrorCode    `json:"code,omitempty"`
+	Message string       `json:"message"`
+	Details []FieldError `json:"details,omitempty"`
+}
+
+// FieldError describes one invalid input field. Field uses the name the
+// client sent (JSON key, query key or path parameter), Rule is the failed
+// validation tag.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
 	Message string `json:"message"`
-}
\ No newline at end of file
+
+	// MessageKey and MessageParams let the response be re-rendered in the
+	// client's locale; they are not serialized.
+	MessageKey    string         `json:"-"`
+	MessageParams map[string]any `json:"-"`
+}
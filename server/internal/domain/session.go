@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	CollectionSession = "sessions"
+)
+
+// Session records a successful login: an audit trail of who is signed in and
+// until when. Deleting it does not invalidate a token that was already
+// issued; User.RevokeTokens does.
+type Session struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    primitive.ObjectID `bson:"user_id"       json:"user_id"`
+	CreatedAt time.Time          `bson:"created_at"    json:"created_at"`
+	ExpiresAt time.Time          `bson:"expires_at"    json:"expires_at"`
+}
+
+type SessionRepository interface {
+	Create(c context.Context, session *Session) error
+	// ListActiveByUser returns the sessions of userID that have not expired,
+	// newest first.
+	ListActiveByUser(c context.Context, userID primitive.ObjectID, now time.Time) ([]Session, error)
+	DeleteByUser(c context.Context, userID primitive.ObjectID) (int64, error)
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/game"
+)
+
+var (
+	ErrSpectatingForbidden    = errors.New("the players don't allow spectating")
+	ErrUnknownSpectatorPolicy = errors.New("unknown spectator policy")
+)
+
+// SpectatorPolicy is who a player lets watch the matches they play. A
+// spectator must be allowed by every player in the match.
+type SpectatorPolicy string
+
+const (
+	SpectateOff     SpectatorPolicy = "off"
+	SpectateFriends SpectatorPolicy = "friends"
+	SpectatePublic  SpectatorPolicy = "public"
+
+	// DefaultSpectatorPolicy applies to users who never chose one.
+	DefaultSpectatorPolicy = SpectateFriends
+)
+
+var SpectatorPolicies = []SpectatorPolicy{SpectateOff, SpectateFriends, SpectatePublic}
+
+func (p SpectatorPolicy) Valid() bool {
+	return slices.Contains(SpectatorPolicies, p)
+}
+
+// SpectatedMatch is a match as its spectators see it. State is the public
+// view, Delay behind the game, while the match runs.
+type SpectatedMatch struct {
+	Match      *Match
+	State      *game.View
+	Spectators int
+	Delay      time.Duration
+}
+
+type SpectatorUsecase interface {
+	// SetPolicy changes who may watch userID's matches. Spectators who lose
+	// access to a match userID is playing are dropped from it.
+	SetPolicy(c context.Context, userID string, policy SpectatorPolicy) error
+	// Watch returns the match as spectators see it, if every player lets
+	// userID watch.
+	Watch(c context.Context, userID string, matchID string) (*SpectatedMatch, error)
+	// CanWatch checks the players' policies without building the view.
+	CanWatch(c context.Context, userID string, matchID string) error
+}
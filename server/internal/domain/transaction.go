@@ -0,0 +1,11 @@
+package domain
+
+import "context"
+
+// Transactor runs fn in a transaction: the repository calls fn makes with
+// the context it is given are committed together or not at all. fn may run
+// more than once when the transaction is retried, so it must not have other
+// side effects.
+type Transactor interface {
+	WithTransaction(c context.Context, fn func(ctx context.Context) error) error
+}
@@ -1,38 +1,117 @@
 package domain
 
 import (
-	"errors"
+	"cmp"
 	"context"
-	"time"
+	"errors"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"slices"
+	"time"
 )
 
 var (
-	ErrUserNotFound = errors.New("user not found")
-	ErrEmailExists  = errors.New("email already exists")
-	ErrUsernameExists  = errors.New("username already exists")
+	ErrUserNotFound   = errors.New("user not found")
+	ErrEmailExists    = errors.New("email already exists")
+	ErrUsernameExists = errors.New("username already exists")
 
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrInvalidToken       = errors.New("invalid access token")
+	ErrUnauthorized       = errors.New("not authorized")
+	ErrUserBanned         = errors.New("user is banned")
+	ErrInvalidRole        = errors.New("invalid role")
+	ErrInvalidPassword    = errors.New("password must be 8 to 72 characters long")
 )
 
 const (
 	CollectionUser = "users"
 )
 
+// Role grants access beyond a regular player. Every user implicitly has
+// RolePlayer; only the extra roles are stored.
+type Role string
+
+const (
+	RolePlayer    Role = "player"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+var Roles = []Role{RolePlayer, RoleModerator, RoleAdmin}
+
+func ParseRole(s string) (Role, error) {
+	for _, r := range Roles {
+		if string(r) == s {
+			return r, nil
+		}
+	}
+	return "", ErrInvalidRole
+}
+
+// Password length bounds; bcrypt ignores anything past 72 bytes.
+const (
+	PasswordMinLength = 8
+	PasswordMaxLength = 72
+)
+
 type User struct {
-	ID       		primitive.ObjectID 	 `bson:"_id,omitempty"   json:"id"`
-	Username     	string             	 `bson:"username"        json:"username"`
-	Email    		string             	 `bson:"email"           json:"email"`
-	Password 		string             	 `bson:"password"        json:"-"`
-	AvatarUrl		string				 `bson:"avatar_url"      json:"avatar_url"`
-	FriendsList 	[]primitive.ObjectID `bson:"friends_list"    json:"friends_list"`
-	CreatedAt 		time.Time 			 `bson:"created_at"      json:"created_at"`
-	UpdatedAt 		time.Time 			 `bson:"updated_at"      json:"updated_at"`
+	ID          primitive.ObjectID   `bson:"_id,omitempty"   json:"id"`
+	Username    string               `bson:"username"        json:"username"`
+	DisplayName string               `bson:"display_name"    json:"display_name"`
+	Email       string               `bson:"email"           json:"email"`
+	Password    string               `bson:"password"        json:"-"`
+	AvatarUrl   string               `bson:"avatar_url"      json:"avatar_url"`
+	Locale      string               `bson:"locale,omitempty" json:"locale,omitempty"`
+	FriendsList []primitive.ObjectID `bson:"friends_list"    json:"friends_list"`
+	Roles       []Role               `bson:"roles,omitempty" json:"roles,omitempty"`
+	BannedAt    *time.Time           `bson:"banned_at,omitempty" json:"banned_at,omitempty"`
+	BanReason   string               `bson:"ban_reason,omitempty" json:"ban_reason,omitempty"`
+	CreatedAt   time.Time            `bson:"created_at"      json:"created_at"`
+	UpdatedAt   time.Time            `bson:"updated_at"      json:"updated_at"`
+	// SpectatorPolicy stays empty until the user picks one, which means
+	// DefaultSpectatorPolicy.
+	SpectatorPolicy SpectatorPolicy `bson:"spectator_policy,omitempty" json:"spectator_policy,omitempty"`
+	// TokenVersion is signed into access tokens. Bumping it revokes every
+	// token issued before.
+	TokenVersion int `bson:"token_version,omitempty" json:"-"`
+}
+
+func (u *User) HasRole(role Role) bool {
+	if role == RolePlayer {
+		return true
+	}
+	for _, r := range u.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsSpectator reports whether the user lets viewerID watch their
+// matches.
+func (u *User) AllowsSpectator(viewerID primitive.ObjectID) bool {
+	switch cmp.Or(u.SpectatorPolicy, DefaultSpectatorPolicy) {
+	case SpectatePublic:
+		return true
+	case SpectateFriends:
+		return slices.Contains(u.FriendsList, viewerID)
+	}
+	return false
+}
+
+func (u *User) IsBanned() bool {
+	return u.BannedAt != nil
+}
+
+// RevokeTokens invalidates every access token issued to u so far.
+func (u *User) RevokeTokens() {
+	u.TokenVersion++
 }
 
 type UserRepository interface {
 	Create(c context.Context, user *User) error
+	Update(c context.Context, user *User) error
+	Delete(c context.Context, id primitive.ObjectID) error
 	GetByUsername(c context.Context, username string) (*User, error)
 	GetByEmail(c context.Context, email string) (*User, error)
 	GetByID(c context.Context, id string) (*User, error)
@@ -41,4 +120,4 @@ type UserRepository interface {
 type UserUsecase interface {
 	Register(c context.Context, user *User) error
 	Login(c context.Context, email string, password string) (string, error)
-}
\ No newline at end of file
+}
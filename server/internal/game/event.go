@@ -0,0 +1,59 @@
+package game
+
+type EventType string
+
+const (
+	EventStarted     EventType = "started"
+	EventDealt       EventType = "dealt"
+	EventTurnStarted EventType = "turn_started"
+	EventCardPlayed  EventType = "card_played"
+	EventCardDrawn   EventType = "card_drawn"
+	EventPlayerOut   EventType = "player_out"
+	EventGameOver    EventType = "game_over"
+)
+
+// Event records one thing that happened in a game. Seq numbers events from 1
+// in the order they happened, so a client can tell whether it missed any.
+// Seat is -1 for events that concern nobody in particular.
+type Event struct {
+	Seq  int       `json:"seq"`
+	Type EventType `json:"type"`
+	Seat int       `json:"seat"`
+	// Target is set on card_played.
+	Target *int `json:"target,omitempty"`
+	// Card is the card played or drawn, Cards the hand dealt.
+	Card  *Card  `json:"card,omitempty"`
+	Cards []Card `json:"cards,omitempty"`
+	// Count is the number of cards dealt or drawn.
+	Count int `json:"count,omitempty"`
+	// Hearts is the starting hearts on started and the hearts taken on
+	// card_played.
+	Hearts  int  `json:"hearts,omitempty"`
+	Blocked bool `json:"blocked,omitempty"`
+	Auto    bool `json:"auto,omitempty"`
+	// Forfeit marks a player_out the player gave up rather than lost.
+	Forfeit    bool  `json:"forfeit,omitempty"`
+	TurnNumber int   `json:"turn_number,omitempty"`
+	Placements []int `json:"placements,omitempty"`
+	// Private marks Card and Cards as visible to the player at Seat only.
+	Private bool `json:"-"`
+}
+
+// Public returns the event as everyone else may see it.
+func (e Event) Public() Event {
+	if e.Private {
+		e.Count = max(e.Count, len(e.Cards))
+		e.Card, e.Cards = nil, nil
+		e.Private = false
+	}
+	return e
+}
+
+// For returns the event as the player at seat may see it; -1 sees what
+// spectators do.
+func (e Event) For(seat int) Event {
+	if e.Private && e.Seat == seat {
+		return e
+	}
+	return e.Public()
+}
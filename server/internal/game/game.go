@@ -0,0 +1,176 @@
+// Package game holds the rules of HeartSteal as a pure state machine: a State,
+// the Actions players take and the deterministic transitions between them.
+// It does no I/O and reads no clock, so the same seed and actions always
+// produce the same game.
+//
+// Every player starts with a few hearts and a hidden hand of cards. On their
+// turn a player plays one card and draws a replacement while the deck lasts.
+// A steal card takes hearts from another player, unless a shield blocks it;
+// a shield protects its owner from the next steal. A player left without
+// hearts is out. The game ends when one player is left or nobody has cards
+// to play, and players are ranked by the hearts they hold.
+package game
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+)
+
+var (
+	ErrInvalidConfig = errors.New("invalid game configuration")
+	ErrGameOver      = errors.New("the game is over")
+	ErrNotYourTurn   = errors.New("it isn't this player's turn")
+	ErrCardNotInHand = errors.New("the card isn't in the player's hand")
+	ErrInvalidTarget = errors.New("the card can't target this player")
+	ErrUnknownSeat   = errors.New("no player sits at this seat")
+	ErrAlreadyOut    = errors.New("the player is already out")
+)
+
+const (
+	MinPlayers = 2
+	MaxPlayers = 8
+
+	DefaultHearts   = 4
+	DefaultHandSize = 3
+)
+
+// seedStream separates the engine's random stream from other users of the
+// same seed.
+const seedStream = 0x4865617274537465
+
+type CardKind string
+
+const (
+	// CardSteal takes Value hearts from the target.
+	CardSteal CardKind = "steal"
+	// CardShield blocks the next steal against whoever played it.
+	CardShield CardKind = "shield"
+)
+
+// Card IDs are unique within a game, so actions can name a card regardless
+// of where it sits in a hand.
+type Card struct {
+	ID    int      `json:"id"`
+	Kind  CardKind `json:"kind"`
+	Value int      `json:"value,omitempty"`
+}
+
+// deckPerPlayer is added to the deck once for every player.
+var deckPerPlayer = []Card{
+	{Kind: CardSteal, Value: 1},
+	{Kind: CardSteal, Value: 1},
+	{Kind: CardSteal, Value: 1},
+	{Kind: CardSteal, Value: 2},
+	{Kind: CardSteal, Value: 2},
+	{Kind: CardSteal, Value: 3},
+	{Kind: CardShield},
+	{Kind: CardShield},
+}
+
+type Config struct {
+	Players  int
+	Hearts   int
+	HandSize int
+}
+
+func (cfg Config) withDefaults() Config {
+	if cfg.Hearts == 0 {
+		cfg.Hearts = DefaultHearts
+	}
+	if cfg.HandSize == 0 {
+		cfg.HandSize = DefaultHandSize
+	}
+	return cfg
+}
+
+type Player struct {
+	Hearts   int    `json:"hearts"`
+	Hand     []Card `json:"hand"`
+	Shielded bool   `json:"shielded"`
+	Out      bool   `json:"out"`
+	// OutAt is the turn the player was knocked out on, which ranks players
+	// who are out against each other.
+	OutAt int `json:"out_at,omitempty"`
+}
+
+// State is the whole game, including what players must not see; View
+// returns what a single seat may know.
+type State struct {
+	Seed    int64    `json:"seed"`
+	Players []Player `json:"players"`
+	// Deck is the draw pile, top card first.
+	Deck    []Card `json:"deck"`
+	Discard []Card `json:"discard"`
+	// Turn is the seat to act and TurnNumber counts turns from 1.
+	Turn       int  `json:"turn"`
+	TurnNumber int  `json:"turn_number"`
+	Over       bool `json:"over"`
+	// Placements holds each seat's final rank once the game is over; tied
+	// seats share a rank.
+	Placements []int `json:"placements,omitempty"`
+	// Seq is the sequence number of the last event.
+	Seq int `json:"seq"`
+}
+
+// New shuffles a deck with seed, deals the hands and picks who starts. The
+// returned events describe the setup.
+func New(cfg Config, seed int64) (*State, []Event, error) {
+	cfg = cfg.withDefaults()
+	if cfg.Players < MinPlayers || cfg.Players > MaxPlayers || cfg.Hearts < 1 || cfg.HandSize < 1 {
+		return nil, nil, ErrInvalidConfig
+	}
+
+	rng := rand.New(rand.NewPCG(uint64(seed), seedStream))
+	deck := make([]Card, 0, cfg.Players*len(deckPerPlayer))
+	for range cfg.Players {
+		for _, card := range deckPerPlayer {
+			card.ID = len(deck)
+			deck = append(deck, card)
+		}
+	}
+	rng.Shuffle(len(deck), func(i, j int) { deck[i], deck[j] = deck[j], deck[i] })
+
+	s := &State{Seed: seed, Players: make([]Player, cfg.Players), Deck: deck, Discard: []Card{}}
+	events := []Event{s.event(Event{Type: EventStarted, Seat: -1, Hearts: cfg.Hearts})}
+	for seat := range s.Players {
+		hand := s.draw(cfg.HandSize)
+		s.Players[seat] = Player{Hearts: cfg.Hearts, Hand: hand}
+		events = append(events, s.event(Event{Type: EventDealt, Seat: seat, Cards: cloneCards(hand), Private: true}))
+	}
+	s.Turn = rng.IntN(cfg.Players)
+	s.TurnNumber = 1
+	events = append(events, s.event(Event{Type: EventTurnStarted, Seat: s.Turn, TurnNumber: s.TurnNumber}))
+	return s, events, nil
+}
+
+// Clone returns a deep copy that can be changed without affecting s.
+func (s *State) Clone() *State {
+	c := *s
+	c.Players = make([]Player, len(s.Players))
+	for i, p := range s.Players {
+		p.Hand = slices.Clone(p.Hand)
+		c.Players[i] = p
+	}
+	c.Deck = slices.Clone(s.Deck)
+	c.Discard = slices.Clone(s.Discard)
+	c.Placements = slices.Clone(s.Placements)
+	return &c
+}
+
+func (s *State) draw(n int) []Card {
+	n = min(n, len(s.Deck))
+	cards := cloneCards(s.Deck[:n])
+	s.Deck = s.Deck[n:]
+	return cards
+}
+
+func (s *State) event(e Event) Event {
+	s.Seq++
+	e.Seq = s.Seq
+	return e
+}
+
+func cloneCards(cards []Card) []Card {
+	return append([]Card{}, cards...)
+}
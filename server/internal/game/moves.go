@@ -0,0 +1,45 @@
+package game
+
+// LegalActions lists every action the player to move may take, in hand
+// order and then by target seat.
+func LegalActions(s *State) []Action {
+	if s.Over {
+		return nil
+	}
+	var actions []Action
+	for _, card := range s.Players[s.Turn].Hand {
+		if card.Kind == CardShield {
+			actions = append(actions, Action{Seat: s.Turn, Card: card.ID, Target: s.Turn})
+			continue
+		}
+		for target, p := range s.Players {
+			if target != s.Turn && !p.Out {
+				actions = append(actions, Action{Seat: s.Turn, Card: card.ID, Target: target})
+			}
+		}
+	}
+	return actions
+}
+
+// AutoAction is the move played for a player whose turn timed out: the
+// oldest card in their hand, aimed at the opponent with the most hearts. It
+// depends on s alone so replaying a game repeats it.
+func AutoAction(s *State) Action {
+	seat := s.Turn
+	card := s.Players[seat].Hand[0]
+	action := Action{Seat: seat, Card: card.ID, Target: seat, Auto: true}
+	if card.Kind != CardSteal {
+		return action
+	}
+	best := -1
+	for target, p := range s.Players {
+		if target == seat || p.Out {
+			continue
+		}
+		if best < 0 || p.Hearts > s.Players[best].Hearts {
+			best = target
+		}
+	}
+	action.Target = best
+	return action
+}
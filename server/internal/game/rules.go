@@ -0,0 +1,192 @@
+package game
+
+import "slices"
+
+// Action is a player's move: Seat plays Card on Target. Shields always target
+// their owner, so Target is ignored for them. Auto marks moves the server
+// made for a player who ran out of time. Forfeit gives up instead: the
+// player is out at once, even out of turn, and Card and Target are ignored.
+type Action struct {
+	Seat    int  `json:"seat"`
+	Card    int  `json:"card"`
+	Target  int  `json:"target"`
+	Auto    bool `json:"auto,omitempty"`
+	Forfeit bool `json:"forfeit,omitempty"`
+}
+
+// Validate reports why a can't be applied to s, or nil if it can.
+func Validate(s *State, a Action) error {
+	_, err := s.check(a)
+	return err
+}
+
+// check validates a and returns the position of its card in the hand.
+func (s *State) check(a Action) (int, error) {
+	if s.Over {
+		return -1, ErrGameOver
+	}
+	if a.Seat < 0 || a.Seat >= len(s.Players) {
+		return -1, ErrUnknownSeat
+	}
+	if a.Forfeit {
+		if s.Players[a.Seat].Out {
+			return -1, ErrAlreadyOut
+		}
+		return -1, nil
+	}
+	if a.Seat != s.Turn {
+		return -1, ErrNotYourTurn
+	}
+	i := slices.IndexFunc(s.Players[a.Seat].Hand, func(c Card) bool { return c.ID == a.Card })
+	if i < 0 {
+		return -1, ErrCardNotInHand
+	}
+	if s.Players[a.Seat].Hand[i].Kind == CardSteal {
+		if a.Target == a.Seat || a.Target < 0 || a.Target >= len(s.Players) || s.Players[a.Target].Out {
+			return -1, ErrInvalidTarget
+		}
+	}
+	return i, nil
+}
+
+// Apply validates a and, if it is legal, changes s accordingly. s is left
+// untouched when an error is returned.
+func Apply(s *State, a Action) ([]Event, error) {
+	i, err := s.check(a)
+	if err != nil {
+		return nil, err
+	}
+	if a.Forfeit {
+		return s.forfeit(a.Seat), nil
+	}
+
+	player := &s.Players[a.Seat]
+	card := player.Hand[i]
+	player.Hand = slices.Delete(player.Hand, i, i+1)
+	s.Discard = append(s.Discard, card)
+
+	played := Event{Type: EventCardPlayed, Seat: a.Seat, Card: &card, Auto: a.Auto}
+	var events []Event
+	switch card.Kind {
+	case CardShield:
+		player.Shielded = true
+		target := a.Seat
+		played.Target = &target
+		events = append(events, s.event(played))
+	case CardSteal:
+		target := a.Target
+		played.Target = &target
+		victim := &s.Players[target]
+		if victim.Shielded {
+			victim.Shielded = false
+			played.Blocked = true
+		} else {
+			stolen := min(card.Value, victim.Hearts)
+			victim.Hearts -= stolen
+			player.Hearts += stolen
+			played.Hearts = stolen
+		}
+		events = append(events, s.event(played))
+		if victim.Hearts == 0 {
+			victim.Out, victim.OutAt = true, s.TurnNumber
+			s.Discard = append(s.Discard, victim.Hand...)
+			victim.Hand = nil
+			events = append(events, s.event(Event{Type: EventPlayerOut, Seat: target}))
+		}
+	}
+
+	if drawn := s.draw(1); len(drawn) > 0 {
+		player.Hand = append(player.Hand, drawn[0])
+		events = append(events, s.event(Event{Type: EventCardDrawn, Seat: a.Seat, Card: &drawn[0], Count: 1, Private: true}))
+	}
+	return append(events, s.advance(a.Seat)), nil
+}
+
+// forfeit puts seat out as if they had lost their last heart. The turn only
+// moves on if it was theirs.
+func (s *State) forfeit(seat int) []Event {
+	player := &s.Players[seat]
+	player.Out, player.OutAt = true, s.TurnNumber
+	s.Discard = append(s.Discard, player.Hand...)
+	player.Hand = nil
+	events := []Event{s.event(Event{Type: EventPlayerOut, Seat: seat, Forfeit: true})}
+	if seat == s.Turn || s.finished() {
+		events = append(events, s.advance(seat))
+	}
+	return events
+}
+
+// advance ends the game if it is finished and otherwise passes the turn on
+// from seat.
+func (s *State) advance(seat int) Event {
+	if s.finished() {
+		s.Over = true
+		s.Placements = s.rank()
+		return s.event(Event{Type: EventGameOver, Seat: -1, Placements: slices.Clone(s.Placements)})
+	}
+	s.Turn = s.next(seat)
+	s.TurnNumber++
+	return s.event(Event{Type: EventTurnStarted, Seat: s.Turn, TurnNumber: s.TurnNumber})
+}
+
+// finished reports whether one player is left or nobody can play. A hand
+// only empties once the deck has, so nobody will get cards again.
+func (s *State) finished() bool {
+	alive, playable := 0, 0
+	for _, p := range s.Players {
+		if !p.Out {
+			alive++
+			if len(p.Hand) > 0 {
+				playable++
+			}
+		}
+	}
+	return alive <= 1 || playable == 0
+}
+
+// next returns the seat after seat that is still in and has a card to play;
+// finished must be false.
+func (s *State) next(seat int) int {
+	for {
+		seat = (seat + 1) % len(s.Players)
+		if p := s.Players[seat]; !p.Out && len(p.Hand) > 0 {
+			return seat
+		}
+	}
+}
+
+// rank orders players who are still in by hearts, then players who are out
+// by how long they lasted.
+func (s *State) rank() []int {
+	better := func(a, b Player) bool {
+		switch {
+		case a.Out != b.Out:
+			return !a.Out
+		case !a.Out:
+			return a.Hearts > b.Hearts
+		default:
+			return a.OutAt > b.OutAt
+		}
+	}
+	placements := make([]int, len(s.Players))
+	for i, p := range s.Players {
+		placements[i] = 1
+		for _, other := range s.Players {
+			if better(other, p) {
+				placements[i]++
+			}
+		}
+	}
+	return placements
+}
+
+// Winners returns the seats ranked first once the game is over.
+func (s *State) Winners() []int {
+	var seats []int
+	for seat, place := range s.Placements {
+		if place == 1 {
+			seats = append(seats, seat)
+		}
+	}
+	return seats
+}
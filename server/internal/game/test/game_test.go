@@ -0,0 +1,399 @@
+package game_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Simpolette/HeartSteal/server/internal/game"
+)
+
+func steal(id, value int) game.Card {
+	return game.Card{ID: id, Kind: game.CardSteal, Value: value}
+}
+
+func shield(id int) game.Card {
+	return game.Card{ID: id, Kind: game.CardShield}
+}
+
+// threePlayers is a game where seat 0 is to move with a hand of one card of
+// each kind; the others hold one card each.
+func threePlayers() *game.State {
+	return &game.State{
+		Players: []game.Player{
+			{Hearts: 3, Hand: []game.Card{steal(0, 2), shield(1)}},
+			{Hearts: 3, Hand: []game.Card{steal(2, 1)}},
+			{Hearts: 1, Hand: []game.Card{steal(3, 1)}},
+		},
+		Deck:       []game.Card{steal(4, 3), steal(5, 1)},
+		Turn:       0,
+		TurnNumber: 1,
+	}
+}
+
+func TestGame_New(t *testing.T) {
+	t.Run("Deterministic", func(t *testing.T) {
+		a, aEvents, err := game.New(game.Config{Players: 4}, 42)
+		require.NoError(t, err)
+		b, bEvents, err := game.New(game.Config{Players: 4}, 42)
+		require.NoError(t, err)
+
+		assert.Equal(t, a, b)
+		assert.Equal(t, aEvents, bEvents)
+
+		other, _, err := game.New(game.Config{Players: 4}, 43)
+		require.NoError(t, err)
+		assert.NotEqual(t, a.Deck, other.Deck)
+	})
+
+	t.Run("Deals", func(t *testing.T) {
+		s, events, err := game.New(game.Config{Players: 3}, 7)
+		require.NoError(t, err)
+
+		seen := map[int]bool{}
+		for _, p := range s.Players {
+			assert.Equal(t, game.DefaultHearts, p.Hearts)
+			assert.Len(t, p.Hand, game.DefaultHandSize)
+			for _, c := range p.Hand {
+				seen[c.ID] = true
+			}
+		}
+		for _, c := range s.Deck {
+			seen[c.ID] = true
+		}
+		assert.Len(t, seen, 24, "every card is dealt or in the deck exactly once")
+		assert.Equal(t, 3*8-3*game.DefaultHandSize, len(s.Deck))
+		assert.Equal(t, 1, s.TurnNumber)
+
+		require.Len(t, events, 5)
+		assert.Equal(t, game.EventStarted, events[0].Type)
+		assert.Equal(t, game.EventDealt, events[1].Type)
+		assert.Equal(t, s.Players[0].Hand, events[1].Cards)
+		assert.Equal(t, game.EventTurnStarted, events[4].Type)
+		assert.Equal(t, s.Turn, events[4].Seat)
+		assert.Equal(t, 5, s.Seq)
+	})
+
+	t.Run("InvalidConfig", func(t *testing.T) {
+		for _, players := range []int{0, 1, game.MaxPlayers + 1} {
+			_, _, err := game.New(game.Config{Players: players}, 1)
+			assert.ErrorIs(t, err, game.ErrInvalidConfig, "players=%d", players)
+		}
+	})
+}
+
+func TestGame_Apply(t *testing.T) {
+	t.Run("Steal", func(t *testing.T) {
+		s := threePlayers()
+
+		events, err := game.Apply(s, game.Action{Seat: 0, Card: 0, Target: 1})
+
+		require.NoError(t, err)
+		assert.Equal(t, 5, s.Players[0].Hearts)
+		assert.Equal(t, 1, s.Players[1].Hearts)
+		assert.Equal(t, []game.Card{shield(1), steal(4, 3)}, s.Players[0].Hand)
+		assert.Equal(t, []game.Card{steal(0, 2)}, s.Discard)
+		assert.Equal(t, 1, s.Turn)
+		assert.Equal(t, 2, s.TurnNumber)
+
+		types := []game.EventType{}
+		for _, e := range events {
+			types = append(types, e.Type)
+		}
+		assert.Equal(t, []game.EventType{game.EventCardPlayed, game.EventCardDrawn, game.EventTurnStarted}, types)
+		assert.Equal(t, 2, events[0].Hearts)
+		assert.Equal(t, 1, *events[0].Target)
+		assert.Equal(t, []int{1, 2, 3}, []int{events[0].Seq, events[1].Seq, events[2].Seq})
+	})
+
+	t.Run("ShieldBlocksNextSteal", func(t *testing.T) {
+		s := threePlayers()
+		s.Players[1].Shielded = true
+
+		events, err := game.Apply(s, game.Action{Seat: 0, Card: 0, Target: 1})
+
+		require.NoError(t, err)
+		assert.True(t, events[0].Blocked)
+		assert.Equal(t, 3, s.Players[1].Hearts)
+		assert.False(t, s.Players[1].Shielded)
+	})
+
+	t.Run("PlayShield", func(t *testing.T) {
+		s := threePlayers()
+
+		events, err := game.Apply(s, game.Action{Seat: 0, Card: 1, Target: 2})
+
+		require.NoError(t, err)
+		assert.True(t, s.Players[0].Shielded)
+		assert.Equal(t, 0, *events[0].Target, "shields always target their owner")
+	})
+
+	t.Run("KnocksOut", func(t *testing.T) {
+		s := threePlayers()
+
+		events, err := game.Apply(s, game.Action{Seat: 0, Card: 0, Target: 2})
+
+		require.NoError(t, err)
+		assert.True(t, s.Players[2].Out)
+		assert.Empty(t, s.Players[2].Hand)
+		assert.Equal(t, 1, events[0].Hearts, "only the hearts the target had are taken")
+		assert.Equal(t, game.EventPlayerOut, events[1].Type)
+		assert.Equal(t, 2, events[1].Seat)
+	})
+
+	t.Run("SkipsPlayersWhoAreOut", func(t *testing.T) {
+		s := threePlayers()
+		s.Turn = 1
+		s.Players[2].Out, s.Players[2].Hand = true, nil
+
+		_, err := game.Apply(s, game.Action{Seat: 1, Card: 2, Target: 0})
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, s.Turn)
+	})
+
+	t.Run("LastPlayerStandingWins", func(t *testing.T) {
+		s := &game.State{
+			Players: []game.Player{
+				{Hearts: 5, Hand: []game.Card{steal(0, 1)}},
+				{Hearts: 1, Hand: []game.Card{steal(1, 1)}},
+			},
+			TurnNumber: 9,
+		}
+
+		events, err := game.Apply(s, game.Action{Seat: 0, Card: 0, Target: 1})
+
+		require.NoError(t, err)
+		assert.True(t, s.Over)
+		assert.Equal(t, []int{1, 2}, s.Placements)
+		assert.Equal(t, []int{0}, s.Winners())
+		last := events[len(events)-1]
+		assert.Equal(t, game.EventGameOver, last.Type)
+		assert.Equal(t, []int{1, 2}, last.Placements)
+
+		_, err = game.Apply(s, game.Action{Seat: 1, Card: 1, Target: 0})
+		assert.ErrorIs(t, err, game.ErrGameOver)
+	})
+
+	t.Run("EndsWhenNobodyCanPlay", func(t *testing.T) {
+		s := &game.State{
+			Players: []game.Player{
+				{Hearts: 2, Hand: []game.Card{shield(0)}},
+				{Hearts: 2},
+				{Hearts: 1, Out: false},
+				{Hearts: 0, Out: true, OutAt: 3},
+			},
+			TurnNumber: 12,
+		}
+
+		_, err := game.Apply(s, game.Action{Seat: 0, Card: 0})
+
+		require.NoError(t, err)
+		assert.True(t, s.Over)
+		assert.Equal(t, []int{1, 1, 3, 4}, s.Placements, "equal hearts share a rank")
+		assert.Equal(t, []int{0, 1}, s.Winners())
+	})
+
+	t.Run("ForfeitOnTurn", func(t *testing.T) {
+		s := threePlayers()
+
+		events, err := game.Apply(s, game.Action{Seat: 0, Forfeit: true})
+
+		require.NoError(t, err)
+		assert.True(t, s.Players[0].Out)
+		assert.Equal(t, 1, s.Players[0].OutAt)
+		assert.Empty(t, s.Players[0].Hand)
+		assert.Len(t, s.Discard, 2, "the hand is discarded")
+		require.Len(t, events, 2)
+		assert.Equal(t, game.EventPlayerOut, events[0].Type)
+		assert.True(t, events[0].Forfeit)
+		assert.Equal(t, game.EventTurnStarted, events[1].Type)
+		assert.Equal(t, 1, s.Turn)
+		assert.Equal(t, 2, s.TurnNumber)
+	})
+
+	t.Run("ForfeitOutOfTurn", func(t *testing.T) {
+		s := threePlayers()
+
+		events, err := game.Apply(s, game.Action{Seat: 1, Forfeit: true})
+
+		require.NoError(t, err)
+		assert.True(t, s.Players[1].Out)
+		require.Len(t, events, 1, "the turn stays where it was")
+		assert.Equal(t, 0, s.Turn)
+		assert.Equal(t, 1, s.TurnNumber)
+	})
+
+	t.Run("ForfeitEndsGame", func(t *testing.T) {
+		s := threePlayers()
+		s.Players[2].Out, s.Players[2].OutAt, s.Players[2].Hand = true, 0, nil
+
+		events, err := game.Apply(s, game.Action{Seat: 1, Forfeit: true})
+
+		require.NoError(t, err)
+		assert.True(t, s.Over)
+		assert.Equal(t, []int{1, 2, 3}, s.Placements, "forfeiting outlasts players already out")
+		assert.Equal(t, game.EventGameOver, events[len(events)-1].Type)
+	})
+
+	t.Run("ErrorForfeitWhenOut", func(t *testing.T) {
+		s := threePlayers()
+		s.Players[2].Out = true
+
+		_, err := game.Apply(s, game.Action{Seat: 2, Forfeit: true})
+
+		assert.ErrorIs(t, err, game.ErrAlreadyOut)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		cases := map[string]struct {
+			action game.Action
+			err    error
+		}{
+			"NotYourTurn":     {game.Action{Seat: 1, Card: 2, Target: 0}, game.ErrNotYourTurn},
+			"UnknownSeat":     {game.Action{Seat: 5, Card: 0, Target: 1}, game.ErrUnknownSeat},
+			"CardNotInHand":   {game.Action{Seat: 0, Card: 2, Target: 1}, game.ErrCardNotInHand},
+			"StealFromSelf":   {game.Action{Seat: 0, Card: 0, Target: 0}, game.ErrInvalidTarget},
+			"StealFromNobody": {game.Action{Seat: 0, Card: 0, Target: 3}, game.ErrInvalidTarget},
+		}
+		for name, tc := range cases {
+			t.Run(name, func(t *testing.T) {
+				s := threePlayers()
+				before := s.Clone()
+
+				_, err := game.Apply(s, tc.action)
+
+				assert.ErrorIs(t, err, tc.err)
+				assert.Equal(t, before, s, "a rejected action changes nothing")
+				assert.ErrorIs(t, game.Validate(s, tc.action), tc.err)
+			})
+		}
+
+		t.Run("StealFromPlayerWhoIsOut", func(t *testing.T) {
+			s := threePlayers()
+			s.Players[2].Out = true
+
+			assert.ErrorIs(t, game.Validate(s, game.Action{Seat: 0, Card: 0, Target: 2}), game.ErrInvalidTarget)
+		})
+	})
+}
+
+func TestGame_LegalActions(t *testing.T) {
+	s := threePlayers()
+
+	actions := game.LegalActions(s)
+
+	assert.Equal(t, []game.Action{
+		{Seat: 0, Card: 0, Target: 1},
+		{Seat: 0, Card: 0, Target: 2},
+		{Seat: 0, Card: 1, Target: 0},
+	}, actions)
+	for _, a := range actions {
+		assert.NoError(t, game.Validate(s, a))
+	}
+}
+
+func TestGame_AutoAction(t *testing.T) {
+	t.Run("StealsFromRichestOpponent", func(t *testing.T) {
+		s := threePlayers()
+		s.Players[2].Hearts = 4
+
+		a := game.AutoAction(s)
+
+		assert.Equal(t, game.Action{Seat: 0, Card: 0, Target: 2, Auto: true}, a)
+		assert.NoError(t, game.Validate(s, a))
+	})
+
+	t.Run("PlaysShield", func(t *testing.T) {
+		s := threePlayers()
+		s.Players[0].Hand = []game.Card{shield(1), steal(0, 2)}
+
+		assert.Equal(t, game.Action{Seat: 0, Card: 1, Target: 0, Auto: true}, game.AutoAction(s))
+	})
+}
+
+func TestGame_View(t *testing.T) {
+	s := threePlayers()
+
+	v := s.View(1)
+
+	assert.Equal(t, 1, v.Seat)
+	assert.Equal(t, 2, v.DeckCount)
+	assert.Nil(t, v.Players[0].Hand)
+	assert.Equal(t, 2, v.Players[0].HandCount)
+	assert.Equal(t, []game.Card{steal(2, 1)}, v.Players[1].Hand)
+
+	spectator := s.View(-1)
+	for _, p := range spectator.Players {
+		assert.Nil(t, p.Hand)
+	}
+
+	revealed := s.Reveal()
+	assert.Equal(t, -1, revealed.Seat)
+	for i, p := range revealed.Players {
+		assert.Equal(t, s.Players[i].Hand, p.Hand)
+	}
+}
+
+func TestGame_Unseen(t *testing.T) {
+	s, _, err := game.New(game.Config{Players: 3}, 11)
+	require.NoError(t, err)
+	for range 4 {
+		_, err := game.Apply(s, game.AutoAction(s))
+		require.NoError(t, err)
+	}
+
+	want := slices.Clone(s.Deck)
+	for seat, p := range s.Players {
+		if seat != 1 {
+			want = append(want, p.Hand...)
+		}
+	}
+	slices.SortFunc(want, func(a, b game.Card) int { return a.ID - b.ID })
+	assert.Equal(t, want, s.View(1).Unseen())
+}
+
+func TestGame_Event(t *testing.T) {
+	card := steal(4, 3)
+	drawn := game.Event{Type: game.EventCardDrawn, Seat: 1, Card: &card, Count: 1, Private: true}
+
+	assert.Equal(t, &card, drawn.For(1).Card)
+	assert.Nil(t, drawn.For(0).Card)
+	assert.Nil(t, drawn.Public().Card)
+	assert.Equal(t, 1, drawn.Public().Count)
+
+	dealt := game.Event{Type: game.EventDealt, Seat: 0, Cards: []game.Card{card, card}, Private: true}
+	assert.Nil(t, dealt.Public().Cards)
+	assert.Equal(t, 2, dealt.Public().Count)
+}
+
+// TestGame_RandomPlay plays many games with random legal moves and checks
+// that every one ends and that hearts are only ever moved, never created.
+func TestGame_RandomPlay(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	for i := range 200 {
+		players := game.MinPlayers + i%(game.MaxPlayers-game.MinPlayers+1)
+		s, _, err := game.New(game.Config{Players: players}, rng.Int64())
+		require.NoError(t, err)
+		total := players * game.DefaultHearts
+		maxTurns := players * 8
+
+		for turns := 0; !s.Over; turns++ {
+			require.Less(t, turns, maxTurns, "a game can't outlast its deck")
+			actions := game.LegalActions(s)
+			require.NotEmpty(t, actions)
+			_, err := game.Apply(s, actions[rng.IntN(len(actions))])
+			require.NoError(t, err)
+
+			hearts := 0
+			for _, p := range s.Players {
+				hearts += p.Hearts
+			}
+			require.Equal(t, total, hearts)
+		}
+		assert.NotEmpty(t, s.Winners())
+	}
+}
@@ -0,0 +1,83 @@
+package game
+
+import "slices"
+
+type PlayerView struct {
+	Hearts    int  `json:"hearts"`
+	HandCount int  `json:"hand_count"`
+	Shielded  bool `json:"shielded"`
+	Out       bool `json:"out"`
+	// Hand is only shown to the player holding it.
+	Hand []Card `json:"hand,omitempty"`
+}
+
+// View is what one seat may know about a game: every hand but its own is
+// reduced to a count and the deck order stays hidden.
+type View struct {
+	// Seat is the viewer's seat, -1 for spectators.
+	Seat       int          `json:"seat"`
+	Players    []PlayerView `json:"players"`
+	DeckCount  int          `json:"deck_count"`
+	Discard    []Card       `json:"discard"`
+	Turn       int          `json:"turn"`
+	TurnNumber int          `json:"turn_number"`
+	Over       bool         `json:"over"`
+	Placements []int        `json:"placements,omitempty"`
+	Seq        int          `json:"seq"`
+}
+
+// View returns the game as seen from seat; -1 views it as a spectator.
+func (s *State) View(seat int) View {
+	v := View{
+		Seat:       seat,
+		Players:    make([]PlayerView, len(s.Players)),
+		DeckCount:  len(s.Deck),
+		Discard:    cloneCards(s.Discard),
+		Turn:       s.Turn,
+		TurnNumber: s.TurnNumber,
+		Over:       s.Over,
+		Seq:        s.Seq,
+	}
+	for i, p := range s.Players {
+		v.Players[i] = PlayerView{Hearts: p.Hearts, HandCount: len(p.Hand), Shielded: p.Shielded, Out: p.Out}
+		if i == seat {
+			v.Players[i].Hand = cloneCards(p.Hand)
+		}
+	}
+	v.Placements = slices.Clone(s.Placements)
+	return v
+}
+
+// Reveal returns the game with every hand shown, for replays of finished
+// games. Seat is -1 and the deck order stays out.
+func (s *State) Reveal() View {
+	v := s.View(-1)
+	for i, p := range s.Players {
+		v.Players[i].Hand = cloneCards(p.Hand)
+	}
+	return v
+}
+
+// Unseen returns the cards v's seat can't see: the deck and the other
+// players' hands. Every game deals the same cards for a number of players,
+// so they follow from what was played and the viewer's own hand.
+func (v View) Unseen() []Card {
+	seen := make(map[int]bool, len(v.Discard))
+	for _, c := range v.Discard {
+		seen[c.ID] = true
+	}
+	if v.Seat >= 0 && v.Seat < len(v.Players) {
+		for _, c := range v.Players[v.Seat].Hand {
+			seen[c.ID] = true
+		}
+	}
+	var unseen []Card
+	for id := range len(v.Players) * len(deckPerPlayer) {
+		if !seen[id] {
+			card := deckPerPlayer[id%len(deckPerPlayer)]
+			card.ID = id
+			unseen = append(unseen, card)
+		}
+	}
+	return unseen
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/validation"
+	"github.com/gin-gonic/gin"
+)
+
+// bindJSON, bindQuery and bindURI wrap gin's binders so that every input
+// source reports failures with the same field-level ErrorResponse.
+func bindJSON(c *gin.Context, obj any) error {
+	return toValidationError(c.ShouldBindJSON(obj))
+}
+
+func bindQuery(c *gin.Context, obj any) error {
+	return toValidationError(c.ShouldBindQuery(obj))
+}
+
+func bindURI(c *gin.Context, obj any) error {
+	return toValidationError(c.ShouldBindUri(obj))
+}
+
+func toValidationError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if details, ok := validation.Translate(err); ok {
+		return domain.ValidationError(details, err)
+	}
+	return invalidRequest(err)
+}
@@ -0,0 +1,9 @@
+package handler
+
+import "github.com/gin-gonic/gin"
+
+// currentUserID is the user JwtAuthMiddleware authenticated. It is empty on
+// public routes.
+func currentUserID(c *gin.Context) string {
+	return c.GetString("x-user-id")
+}
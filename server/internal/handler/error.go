@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Simpolette/HeartSteal/server/internal/apierror"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+)
+
+func invalidRequest(cause error) *domain.AppError {
+	return domain.NewAppError(domain.CodeInvalidRequest, http.StatusBadRequest, "Request validation failed", cause)
+}
+
+// resultLabel turns an outcome into a low-cardinality metrics label.
+func resultLabel(err error) string {
+	if err == nil {
+		return "success"
+	}
+	return strings.ToLower(string(apierror.Resolve(err).Code))
+}
+
+// NotFound renders unknown routes with the standard error shape.
+func NotFound(c *gin.Context) {
+	_ = c.Error(domain.NewAppError(domain.CodeNotFound, http.StatusNotFound, "Route not found", nil))
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Simpolette/HeartSteal/server/internal/health"
+	"github.com/Simpolette/HeartSteal/server/internal/openapi"
+	"github.com/gin-gonic/gin"
+)
+
+var LivenessOperation = openapi.Operation{
+	Summary:   "Liveness probe",
+	Tags:      []string{"operations"},
+	Responses: []openapi.Response{{Status: http.StatusOK, Body: health.Report{}}},
+}
+
+var ReadinessOperation = openapi.Operation{
+	Summary: "Readiness probe",
+	Tags:    []string{"operations"},
+	Responses: []openapi.Response{
+		{Status: http.StatusOK, Body: health.Report{}},
+		{Status: http.StatusServiceUnavailable, Description: "A dependency is down or the server is shutting down", Body: health.Report{}},
+	},
+}
+
+type HealthHandler struct {
+	Registry *health.Registry
+}
+
+func NewHealthHandler(registry *health.Registry) *HealthHandler {
+	return &HealthHandler{
+		Registry: registry,
+	}
+}
+
+func (h *HealthHandler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, h.Registry.Liveness())
+}
+
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	report := h.Registry.Readiness(c.Request.Context())
+	if report.Status != health.StatusUp {
+		c.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/i18n"
+	"github.com/Simpolette/HeartSteal/server/internal/openapi"
+)
+
+type usernameURI struct {
+	Username string `uri:"username" binding:"required"`
+}
+
+type matchHistoryQuery struct {
+	Mode   domain.GameMode    `form:"mode"   binding:"omitempty,oneof=duel table"`
+	Ranked *bool              `form:"ranked"`
+	Result domain.MatchResult `form:"result" binding:"omitempty,oneof=win loss"`
+	Limit  int                `form:"limit"  binding:"omitempty,min=1,max=100"`
+	Cursor string             `form:"cursor"`
+}
+
+// historyMatchResponse is a finished match as the history's owner saw it:
+// Placement, Result and RatingChange are theirs.
+type historyMatchResponse struct {
+	ID              string                `json:"id"`
+	Mode            domain.GameMode       `json:"mode,omitempty"`
+	Ranked          bool                  `json:"ranked"`
+	Placement       int                   `json:"placement"`
+	Result          domain.MatchResult    `json:"result"`
+	RatingChange    float64               `json:"rating_change,omitempty"`
+	DurationSeconds int                   `json:"duration_seconds"`
+	Players         []matchPlayerResponse `json:"players"`
+	CreatedAt       time.Time             `json:"created_at"`
+	FinishedAt      time.Time             `json:"finished_at"`
+}
+
+type matchHistoryResponse struct {
+	Matches    []historyMatchResponse `json:"matches"`
+	NextCursor string                 `json:"next_cursor,omitempty"`
+}
+
+type playerStatsResponse struct {
+	Games   int     `json:"games"`
+	Wins    int     `json:"wins"`
+	Losses  int     `json:"losses"`
+	WinRate float64 `json:"win_rate"`
+	// Streak is the current run of results: wins when positive, losses
+	// when negative.
+	Streak        int                     `json:"streak"`
+	BestWinStreak int                     `json:"best_win_streak"`
+	FavoriteMode  domain.GameMode         `json:"favorite_mode,omitempty"`
+	ModeGames     map[domain.GameMode]int `json:"mode_games"`
+	LastPlayedAt  *time.Time              `json:"last_played_at,omitempty"`
+}
+
+var MatchHistoryOperation = openapi.Operation{
+	Summary:     "List a player's finished matches",
+	Description: "Most recently finished first. result is from the player's point of view; sharing first place counts as a win. Pass next_cursor back as cursor to get the following page.",
+	Tags:        []string{"players"},
+	Params:      usernameURI{},
+	Query:       matchHistoryQuery{},
+	Responses:   []openapi.Response{{Status: http.StatusOK, Body: domain.SuccessResponse{}, Data: matchHistoryResponse{}}},
+	Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound},
+}
+
+var PlayerStatsOperation = openapi.Operation{
+	Summary:     "Get a player's match statistics",
+	Description: "Totals over every finished match. Players who never finished one get zeroes.",
+	Tags:        []string{"players"},
+	Params:      usernameURI{},
+	Responses:   []openapi.Response{{Status: http.StatusOK, Body: domain.SuccessResponse{}, Data: playerStatsResponse{}}},
+	Errors:      []int{http.StatusUnauthorized, http.StatusNotFound},
+}
+
+type HistoryHandler struct {
+	HistoryUseCase domain.HistoryUsecase
+}
+
+func NewHistoryHandler(usecase domain.HistoryUsecase) *HistoryHandler {
+	return &HistoryHandler{
+		HistoryUseCase: usecase,
+	}
+}
+
+func (h *HistoryHandler) Matches(c *gin.Context) {
+	var uri usernameURI
+	if err := bindURI(c, &uri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	var query matchHistoryQuery
+	if err := bindQuery(c, &query); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	filter := domain.MatchFilter{Mode: query.Mode, Ranked: query.Ranked, Result: query.Result}
+	matches, next, err := h.HistoryUseCase.Matches(c.Request.Context(), uri.Username, filter, query.Limit, query.Cursor)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponse{
+		Message: i18n.T(c.Request.Context(), "success.match_history_listed", nil),
+		Data:    toMatchHistoryResponse(matches, next),
+	})
+}
+
+func (h *HistoryHandler) Stats(c *gin.Context) {
+	var uri usernameURI
+	if err := bindURI(c, &uri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	stats, err := h.HistoryUseCase.Stats(c.Request.Context(), uri.Username)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	modeGames := stats.ModeGames
+	if modeGames == nil {
+		modeGames = map[domain.GameMode]int{}
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{
+		Message: i18n.T(c.Request.Context(), "success.player_stats_found", nil),
+		Data: playerStatsResponse{
+			Games:         stats.Games,
+			Wins:          stats.Wins,
+			Losses:        stats.Losses,
+			WinRate:       stats.WinRate(),
+			Streak:        stats.Streak,
+			BestWinStreak: stats.BestWinStreak,
+			FavoriteMode:  stats.FavoriteMode(),
+			ModeGames:     modeGames,
+			LastPlayedAt:  stats.LastPlayedAt,
+		},
+	})
+}
+
+func toMatchHistoryResponse(entries []domain.HistoryEntry, next string) matchHistoryResponse {
+	res := matchHistoryResponse{Matches: make([]historyMatchResponse, 0, len(entries)), NextCursor: next}
+	for i := range entries {
+		e := &entries[i]
+		res.Matches = append(res.Matches, historyMatchResponse{
+			ID:              e.ID.Hex(),
+			Mode:            e.Mode,
+			Ranked:          e.Ranked,
+			Placement:       e.Self.Placement,
+			Result:          domain.ResultOf(e.Self.Placement),
+			RatingChange:    e.Self.RatingChange,
+			DurationSeconds: int(e.Duration().Seconds()),
+			Players:         toMatchResponse(&e.Match, nil).Players,
+			CreatedAt:       e.CreatedAt,
+			FinishedAt:      *e.FinishedAt,
+		})
+	}
+	return res
+}
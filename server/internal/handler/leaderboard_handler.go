@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/i18n"
+	"github.com/Simpolette/HeartSteal/server/internal/openapi"
+)
+
+type leaderboardURI struct {
+	Mode domain.GameMode `uri:"mode" binding:"required,oneof=duel table"`
+}
+
+type leaderboardQuery struct {
+	Scope  domain.LeaderboardScope `form:"scope"  binding:"omitempty,oneof=global friends"`
+	Limit  int                     `form:"limit"  binding:"omitempty,min=1,max=100"`
+	Cursor string                  `form:"cursor"`
+}
+
+// aroundMeQuery's limit counts the players on each side of the caller.
+type aroundMeQuery struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+type leaderboardEntryResponse struct {
+	Rank        int     `json:"rank"`
+	UserID      string  `json:"user_id"`
+	Username    string  `json:"username"`
+	DisplayName string  `json:"display_name"`
+	Rating      float64 `json:"rating"`
+	// RD is the rating deviation: how far the true rating may be from
+	// Rating. It grows while the player doesn't play ranked.
+	RD           float64   `json:"rd"`
+	Games        int       `json:"games"`
+	LastPlayedAt time.Time `json:"last_played_at"`
+}
+
+type leaderboardResponse struct {
+	Entries    []leaderboardEntryResponse `json:"entries"`
+	NextCursor string                     `json:"next_cursor,omitempty"`
+}
+
+var LeaderboardOperation = openapi.Operation{
+	Summary:     "List a game mode's leaderboard",
+	Description: "Highest rating first. scope=friends ranks the caller among their friends. Pass next_cursor back as cursor to get the following page.",
+	Tags:        []string{"leaderboards"},
+	Params:      leaderboardURI{},
+	Query:       leaderboardQuery{},
+	Responses:   []openapi.Response{{Status: http.StatusOK, Body: domain.SuccessResponse{}, Data: leaderboardResponse{}}},
+	Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized},
+}
+
+var AroundMeOperation = openapi.Operation{
+	Summary:     "Get the caller's place on a game mode's leaderboard",
+	Description: "The caller's entry with up to limit players ranked above and below.",
+	Tags:        []string{"leaderboards"},
+	Params:      leaderboardURI{},
+	Query:       aroundMeQuery{},
+	Responses:   []openapi.Response{{Status: http.StatusOK, Body: domain.SuccessResponse{}, Data: leaderboardResponse{}}},
+	Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound},
+}
+
+type LeaderboardHandler struct {
+	RatingUseCase domain.RatingUsecase
+}
+
+func NewLeaderboardHandler(usecase domain.RatingUsecase) *LeaderboardHandler {
+	return &LeaderboardHandler{
+		RatingUseCase: usecase,
+	}
+}
+
+func (h *LeaderboardHandler) List(c *gin.Context) {
+	var uri leaderboardURI
+	if err := bindURI(c, &uri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	var query leaderboardQuery
+	if err := bindQuery(c, &query); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	entries, next, err := h.RatingUseCase.Leaderboard(c.Request.Context(), currentUserID(c), uri.Mode, query.Scope, query.Limit, query.Cursor)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponse{
+		Message: i18n.T(c.Request.Context(), "success.leaderboard_listed", nil),
+		Data:    toLeaderboardResponse(entries, next),
+	})
+}
+
+func (h *LeaderboardHandler) AroundMe(c *gin.Context) {
+	var uri leaderboardURI
+	if err := bindURI(c, &uri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	var query aroundMeQuery
+	if err := bindQuery(c, &query); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	entries, err := h.RatingUseCase.AroundMe(c.Request.Context(), currentUserID(c), uri.Mode, query.Limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponse{
+		Message: i18n.T(c.Request.Context(), "success.leaderboard_listed", nil),
+		Data:    toLeaderboardResponse(entries, ""),
+	})
+}
+
+func toLeaderboardResponse(entries []domain.LeaderboardEntry, next string) leaderboardResponse {
+	res := leaderboardResponse{Entries: make([]leaderboardEntryResponse, 0, len(entries)), NextCursor: next}
+	for _, e := range entries {
+		res.Entries = append(res.Entries, leaderboardEntryResponse{
+			Rank:         e.Rank,
+			UserID:       e.UserID.Hex(),
+			Username:     e.Username,
+			DisplayName:  e.DisplayName,
+			Rating:       e.Rating,
+			RD:           e.RD,
+			Games:        e.Games,
+			LastPlayedAt: e.LastPlayedAt,
+		})
+	}
+	return res
+}
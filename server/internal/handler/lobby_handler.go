@@ -0,0 +1,357 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Simpolette/HeartSteal/server/internal/bot"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/i18n"
+	"github.com/Simpolette/HeartSteal/server/internal/openapi"
+)
+
+type createLobbyRequest struct {
+	Name       string                 `json:"name"       binding:"required,displayname"`
+	Visibility domain.LobbyVisibility `json:"visibility" binding:"omitempty,oneof=public private"`
+	Capacity   int                    `json:"capacity"   binding:"required,min=2,max=8"`
+	Settings   lobbySettingsRequest   `json:"settings,omitempty"`
+}
+
+type lobbySettingsRequest struct {
+	TurnSeconds int `json:"turn_seconds" binding:"omitempty,min=10,max=120"`
+}
+
+type joinLobbyByCodeRequest struct {
+	InviteCode string `json:"invite_code" binding:"required,len=6"`
+}
+
+type listLobbiesQuery struct {
+	Limit  int    `form:"limit"  binding:"omitempty,min=1,max=100"`
+	Cursor string `form:"cursor"`
+}
+
+type lobbyURI struct {
+	ID string `uri:"id" binding:"required"`
+}
+
+type lobbyMemberURI struct {
+	ID     string `uri:"id"      binding:"required"`
+	UserID string `uri:"user_id" binding:"required"`
+}
+
+type addBotRequest struct {
+	Level bot.Level `json:"level" binding:"required,oneof=easy normal hard"`
+}
+
+type lobbyMemberResponse struct {
+	UserID      string    `json:"user_id"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"display_name"`
+	JoinedAt    time.Time `json:"joined_at"`
+	// Bot is the level of a member the server plays for.
+	Bot bot.Level `json:"bot,omitempty"`
+}
+
+type lobbyResponse struct {
+	ID         string                 `json:"id"`
+	Name       string                 `json:"name"`
+	Visibility domain.LobbyVisibility `json:"visibility"`
+	Status     domain.LobbyStatus     `json:"status"`
+	Capacity   int                    `json:"capacity"`
+	Settings   domain.LobbySettings   `json:"settings"`
+	// InviteCode is only shown to members.
+	InviteCode string                `json:"invite_code,omitempty"`
+	HostID     string                `json:"host_id"`
+	Members    []lobbyMemberResponse `json:"members"`
+	// MatchID is the match an in_game lobby is playing.
+	MatchID   string    `json:"match_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type lobbyListResponse struct {
+	Lobbies    []lobbyResponse `json:"lobbies"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+var lobbyErrors = []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusConflict}
+
+var CreateLobbyOperation = openapi.Operation{
+	Summary:   "Create a lobby hosted by the caller",
+	Tags:      []string{"lobbies"},
+	Request:   createLobbyRequest{},
+	Responses: []openapi.Response{{Status: http.StatusCreated, Body: domain.SuccessResponse{}, Data: lobbyResponse{}}},
+	Errors:    []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusConflict, http.StatusTooManyRequests},
+}
+
+var ListLobbiesOperation = openapi.Operation{
+	Summary:     "List joinable public lobbies",
+	Description: "Newest first. Pass next_cursor back as cursor to get the following page.",
+	Tags:        []string{"lobbies"},
+	Query:       listLobbiesQuery{},
+	Responses:   []openapi.Response{{Status: http.StatusOK, Body: domain.SuccessResponse{}, Data: lobbyListResponse{}}},
+	Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized},
+}
+
+var CurrentLobbyOperation = openapi.Operation{
+	Summary:   "Get the lobby the caller is in",
+	Tags:      []string{"lobbies"},
+	Responses: []openapi.Response{{Status: http.StatusOK, Body: domain.SuccessResponse{}, Data: lobbyResponse{}}},
+	Errors:    []int{http.StatusUnauthorized, http.StatusNotFound},
+}
+
+var GetLobbyOperation = openapi.Operation{
+	Summary:   "Get a lobby",
+	Tags:      []string{"lobbies"},
+	Params:    lobbyURI{},
+	Responses: []openapi.Response{{Status: http.StatusOK, Body: domain.SuccessResponse{}, Data: lobbyResponse{}}},
+	Errors:    []int{http.StatusUnauthorized, http.StatusNotFound},
+}
+
+var JoinLobbyOperation = openapi.Operation{
+	Summary:     "Join a public lobby",
+	Description: "The join that fills the lobby starts its match; the lobby comes back in_game with its match_id.",
+	Tags:        []string{"lobbies"},
+	Params:      lobbyURI{},
+	Responses:   []openapi.Response{{Status: http.StatusOK, Body: domain.SuccessResponse{}, Data: lobbyResponse{}}},
+	Errors:      lobbyErrors,
+}
+
+var JoinLobbyByCodeOperation = openapi.Operation{
+	Summary:     "Join a public or private lobby with its invite code",
+	Description: "The join that fills the lobby starts its match; the lobby comes back in_game with its match_id.",
+	Tags:        []string{"lobbies"},
+	Request:     joinLobbyByCodeRequest{},
+	Responses:   []openapi.Response{{Status: http.StatusOK, Body: domain.SuccessResponse{}, Data: lobbyResponse{}}},
+	Errors:      lobbyErrors,
+}
+
+var LeaveLobbyOperation = openapi.Operation{
+	Summary:     "Leave a lobby",
+	Description: "If the host leaves, the player who joined earliest becomes host. The last player leaving closes the lobby, bots and all. Nobody can leave while the lobby plays its match.",
+	Tags:        []string{"lobbies"},
+	Params:      lobbyURI{},
+	Responses:   []openapi.Response{{Status: http.StatusOK, Body: domain.SuccessResponse{}}},
+	Errors:      lobbyErrors,
+}
+
+var KickLobbyMemberOperation = openapi.Operation{
+	Summary:   "Remove a member from the lobby (host only)",
+	Tags:      []string{"lobbies"},
+	Params:    lobbyMemberURI{},
+	Responses: []openapi.Response{{Status: http.StatusOK, Body: domain.SuccessResponse{}, Data: lobbyResponse{}}},
+	Errors:    []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict},
+}
+
+var AddLobbyBotOperation = openapi.Operation{
+	Summary:     "Fill a seat of the lobby with a bot (host only)",
+	Description: "easy bots play random legal moves, normal ones follow a heuristic and hard ones look a round ahead. Bots play through the same move checks as players and can be kicked like members. Adding the bot that fills the lobby starts its match.",
+	Tags:        []string{"lobbies"},
+	Params:      lobbyURI{},
+	Request:     addBotRequest{},
+	Responses:   []openapi.Response{{Status: http.StatusOK, Body: domain.SuccessResponse{}, Data: lobbyResponse{}}},
+	Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict},
+}
+
+type LobbyHandler struct {
+	LobbyUseCase domain.LobbyUsecase
+}
+
+func NewLobbyHandler(usecase domain.LobbyUsecase) *LobbyHandler {
+	return &LobbyHandler{
+		LobbyUseCase: usecase,
+	}
+}
+
+func (h *LobbyHandler) Create(c *gin.Context) {
+	var req createLobbyRequest
+	if err := bindJSON(c, &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	lobby := &domain.Lobby{
+		Name:       req.Name,
+		Visibility: req.Visibility,
+		Capacity:   req.Capacity,
+		Settings:   domain.LobbySettings{TurnSeconds: req.Settings.TurnSeconds},
+	}
+	if err := h.LobbyUseCase.Create(c.Request.Context(), currentUserID(c), lobby); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.respond(c, http.StatusCreated, "success.lobby_created", lobby)
+}
+
+func (h *LobbyHandler) List(c *gin.Context) {
+	var query listLobbiesQuery
+	if err := bindQuery(c, &query); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	lobbies, next, err := h.LobbyUseCase.List(c.Request.Context(), query.Limit, query.Cursor)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	viewer := currentUserID(c)
+	res := lobbyListResponse{Lobbies: make([]lobbyResponse, 0, len(lobbies)), NextCursor: next}
+	for i := range lobbies {
+		res.Lobbies = append(res.Lobbies, toLobbyResponse(&lobbies[i], viewer))
+	}
+	c.JSON(http.StatusOK, domain.SuccessResponse{
+		Message: i18n.T(c.Request.Context(), "success.lobbies_listed", nil),
+		Data:    res,
+	})
+}
+
+func (h *LobbyHandler) Current(c *gin.Context) {
+	lobby, err := h.LobbyUseCase.Current(c.Request.Context(), currentUserID(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.respond(c, http.StatusOK, "success.lobby_found", lobby)
+}
+
+func (h *LobbyHandler) Get(c *gin.Context) {
+	var uri lobbyURI
+	if err := bindURI(c, &uri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	lobby, err := h.LobbyUseCase.Get(c.Request.Context(), currentUserID(c), uri.ID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.respond(c, http.StatusOK, "success.lobby_found", lobby)
+}
+
+func (h *LobbyHandler) Join(c *gin.Context) {
+	var uri lobbyURI
+	if err := bindURI(c, &uri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	lobby, err := h.LobbyUseCase.Join(c.Request.Context(), currentUserID(c), uri.ID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.respond(c, http.StatusOK, "success.lobby_joined", lobby)
+}
+
+func (h *LobbyHandler) JoinByCode(c *gin.Context) {
+	var req joinLobbyByCodeRequest
+	if err := bindJSON(c, &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	lobby, err := h.LobbyUseCase.JoinByCode(c.Request.Context(), currentUserID(c), req.InviteCode)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.respond(c, http.StatusOK, "success.lobby_joined", lobby)
+}
+
+func (h *LobbyHandler) Leave(c *gin.Context) {
+	var uri lobbyURI
+	if err := bindURI(c, &uri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if _, err := h.LobbyUseCase.Leave(c.Request.Context(), currentUserID(c), uri.ID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponse{Message: i18n.T(c.Request.Context(), "success.lobby_left", nil)})
+}
+
+func (h *LobbyHandler) Kick(c *gin.Context) {
+	var uri lobbyMemberURI
+	if err := bindURI(c, &uri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	lobby, err := h.LobbyUseCase.Kick(c.Request.Context(), currentUserID(c), uri.ID, uri.UserID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.respond(c, http.StatusOK, "success.lobby_member_kicked", lobby)
+}
+
+func (h *LobbyHandler) AddBot(c *gin.Context) {
+	var uri lobbyURI
+	if err := bindURI(c, &uri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	var req addBotRequest
+	if err := bindJSON(c, &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	lobby, err := h.LobbyUseCase.AddBot(c.Request.Context(), currentUserID(c), uri.ID, req.Level)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.respond(c, http.StatusOK, "success.lobby_bot_added", lobby)
+}
+
+func (h *LobbyHandler) respond(c *gin.Context, status int, messageKey string, lobby *domain.Lobby) {
+	c.JSON(status, domain.SuccessResponse{
+		Message: i18n.T(c.Request.Context(), messageKey, nil),
+		Data:    toLobbyResponse(lobby, currentUserID(c)),
+	})
+}
+
+func toLobbyResponse(lobby *domain.Lobby, viewerID string) lobbyResponse {
+	res := lobbyResponse{
+		ID:         lobby.ID.Hex(),
+		Name:       lobby.Name,
+		Visibility: lobby.Visibility,
+		Status:     lobby.Status,
+		Capacity:   lobby.Capacity,
+		Settings:   lobby.Settings,
+		HostID:     lobby.HostID.Hex(),
+		Members:    make([]lobbyMemberResponse, 0, len(lobby.Members)),
+		CreatedAt:  lobby.CreatedAt,
+	}
+	if !lobby.MatchID.IsZero() {
+		res.MatchID = lobby.MatchID.Hex()
+	}
+	for _, m := range lobby.Members {
+		res.Members = append(res.Members, lobbyMemberResponse{
+			UserID:      m.UserID.Hex(),
+			Username:    m.Username,
+			DisplayName: m.DisplayName,
+			JoinedAt:    m.JoinedAt,
+			Bot:         m.Bot,
+		})
+		if m.UserID.Hex() == viewerID {
+			res.InviteCode = lobby.InviteCode
+		}
+	}
+	return res
+}
@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Simpolette/HeartSteal/server/internal/bot"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/game"
+	"github.com/Simpolette/HeartSteal/server/internal/i18n"
+	"github.com/Simpolette/HeartSteal/server/internal/openapi"
+)
+
+type matchURI struct {
+	ID string `uri:"id" binding:"required"`
+}
+
+// playCardRequest names the card by ID since card IDs are unique in a match.
+// Target is the seat a steal is aimed at; shields ignore it.
+type playCardRequest struct {
+	Card   *int `json:"card"   binding:"required,min=0"`
+	Target int  `json:"target" binding:"omitempty,min=0,max=7"`
+}
+
+// replayQuery's step counts actions from the deal; leave it out to get the
+// end of the match.
+type replayQuery struct {
+	Step *int `form:"step" binding:"omitempty,min=0"`
+}
+
+// resumeQuery's after is the seq of the last event the client has; leave it
+// out to get every event.
+type resumeQuery struct {
+	After int `form:"after" binding:"omitempty,min=0"`
+}
+
+type matchPlayerResponse struct {
+	UserID      string `json:"user_id"`
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name"`
+	Seat        int    `json:"seat"`
+	// RatingChange is set once a ranked match is over.
+	RatingChange float64 `json:"rating_change,omitempty"`
+	// Placement is set once the match is over.
+	Placement int `json:"placement,omitempty"`
+	// Bot is the level of a seat the server plays for from the start.
+	Bot bot.Level `json:"bot,omitempty"`
+}
+
+type matchResponse struct {
+	ID          string                `json:"id"`
+	LobbyID     string                `json:"lobby_id,omitempty"`
+	Mode        domain.GameMode       `json:"mode,omitempty"`
+	Ranked      bool                  `json:"ranked"`
+	Status      domain.MatchStatus    `json:"status"`
+	TurnSeconds int                   `json:"turn_seconds"`
+	Players     []matchPlayerResponse `json:"players"`
+	Placements  []int                 `json:"placements,omitempty"`
+	CreatedAt   time.Time             `json:"created_at"`
+	FinishedAt  *time.Time            `json:"finished_at,omitempty"`
+	// State is the game as the caller's seat sees it, while it runs.
+	State *game.View `json:"state,omitempty"`
+}
+
+// resumeResponse catches a client up after it lost its connection: the
+// match with the caller's view, the events it missed as the caller may see
+// them and whose seat is held. Events and presence are empty once the match
+// is over.
+type resumeResponse struct {
+	Match        matchResponse         `json:"match"`
+	Events       []game.Event          `json:"events"`
+	TurnDeadline *time.Time            `json:"turn_deadline,omitempty"`
+	Presence     []domain.SeatPresence `json:"presence"`
+}
+
+// replayResponse holds the whole log, so a client can step through it
+// locally, and the game after Step actions. Nothing is hidden anymore:
+// State shows every hand and Events keep every card. Seed is a string
+// because it doesn't fit a JSON number.
+type replayResponse struct {
+	Match   matchResponse `json:"match"`
+	Seed    string        `json:"seed"`
+	Actions []game.Action `json:"actions"`
+	Steps   int           `json:"steps"`
+	Step    int           `json:"step"`
+	State   game.View     `json:"state"`
+	Events  []game.Event  `json:"events"`
+}
+
+var GetMatchOperation = openapi.Operation{
+	Summary:     "Get a match the caller plays in",
+	Description: "While the match runs, state holds the game as the caller's seat sees it: their own hand and every other hand as a count.",
+	Tags:        []string{"matches"},
+	Params:      matchURI{},
+	Responses:   []openapi.Response{{Status: http.StatusOK, Body: domain.SuccessResponse{}, Data: matchResponse{}}},
+	Errors:      []int{http.StatusUnauthorized, http.StatusNotFound},
+}
+
+var PlayCardOperation = openapi.Operation{
+	Summary:     "Play a card on the caller's turn",
+	Description: "The server checks the move against the rules and applies it; the returned state is the caller's view afterwards.",
+	Tags:        []string{"matches"},
+	Params:      matchURI{},
+	Request:     playCardRequest{},
+	Responses:   []openapi.Response{{Status: http.StatusOK, Body: domain.SuccessResponse{}, Data: game.View{}}},
+	Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusConflict},
+}
+
+var ResumeMatchOperation = openapi.Operation{
+	Summary:     "Catch up on a match after reconnecting",
+	Description: "Returns the caller's view of the match, every event numbered after after as their seat may see it, and which players are connected. Subscribing to match:<match_id> again reclaims the caller's seat if its grace period hasn't run out.",
+	Tags:        []string{"matches"},
+	Params:      matchURI{},
+	Query:       resumeQuery{},
+	Responses:   []openapi.Response{{Status: http.StatusOK, Body: domain.SuccessResponse{}, Data: resumeResponse{}}},
+	Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound},
+}
+
+var ReplayOperation = openapi.Operation{
+	Summary:     "Replay a finished match",
+	Description: "Rebuilds the match from its seed and action log after step actions, with every hand and the deck visible. Any player may replay any finished match.",
+	Tags:        []string{"matches"},
+	Params:      matchURI{},
+	Query:       replayQuery{},
+	Responses:   []openapi.Response{{Status: http.StatusOK, Body: domain.SuccessResponse{}, Data: replayResponse{}}},
+	Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound},
+}
+
+type MatchHandler struct {
+	MatchUseCase domain.MatchUsecase
+}
+
+func NewMatchHandler(usecase domain.MatchUsecase) *MatchHandler {
+	return &MatchHandler{
+		MatchUseCase: usecase,
+	}
+}
+
+func (h *MatchHandler) Get(c *gin.Context) {
+	var uri matchURI
+	if err := bindURI(c, &uri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	match, view, err := h.MatchUseCase.Get(c.Request.Context(), currentUserID(c), uri.ID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponse{
+		Message: i18n.T(c.Request.Context(), "success.match_found", nil),
+		Data:    toMatchResponse(match, view),
+	})
+}
+
+func (h *MatchHandler) Play(c *gin.Context) {
+	var uri matchURI
+	if err := bindURI(c, &uri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	var req playCardRequest
+	if err := bindJSON(c, &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	view, err := h.MatchUseCase.Act(c.Request.Context(), currentUserID(c), uri.ID, game.Action{Card: *req.Card, Target: req.Target})
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponse{
+		Message: i18n.T(c.Request.Context(), "success.card_played", nil),
+		Data:    view,
+	})
+}
+
+func (h *MatchHandler) Resume(c *gin.Context) {
+	var uri matchURI
+	if err := bindURI(c, &uri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	var query resumeQuery
+	if err := bindQuery(c, &query); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	resume, err := h.MatchUseCase.Resume(c.Request.Context(), currentUserID(c), uri.ID, query.After)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponse{
+		Message: i18n.T(c.Request.Context(), "success.match_resumed", nil),
+		Data: resumeResponse{
+			Match:        toMatchResponse(resume.Match, resume.State),
+			Events:       resume.Events,
+			TurnDeadline: resume.TurnDeadline,
+			Presence:     resume.Presence,
+		},
+	})
+}
+
+func (h *MatchHandler) Replay(c *gin.Context) {
+	var uri matchURI
+	if err := bindURI(c, &uri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	var query replayQuery
+	if err := bindQuery(c, &query); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	step := -1
+	if query.Step != nil {
+		step = *query.Step
+	}
+
+	r, err := h.MatchUseCase.Replay(c.Request.Context(), uri.ID, step)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponse{
+		Message: i18n.T(c.Request.Context(), "success.replay_found", nil),
+		Data: replayResponse{
+			Match:   toMatchResponse(r.Match, nil),
+			Seed:    strconv.FormatInt(r.Match.Seed, 10),
+			Actions: r.Actions,
+			Steps:   len(r.Actions),
+			Step:    r.Step,
+			State:   r.State.Reveal(),
+			Events:  r.Events,
+		},
+	})
+}
+
+func toMatchResponse(match *domain.Match, view *game.View) matchResponse {
+	res := matchResponse{
+		ID:          match.ID.Hex(),
+		Mode:        match.Mode,
+		Ranked:      match.Ranked,
+		Status:      match.Status,
+		TurnSeconds: match.TurnSeconds,
+		Players:     make([]matchPlayerResponse, 0, len(match.Players)),
+		Placements:  match.Placements,
+		CreatedAt:   match.CreatedAt,
+		FinishedAt:  match.FinishedAt,
+		State:       view,
+	}
+	if !match.LobbyID.IsZero() {
+		res.LobbyID = match.LobbyID.Hex()
+	}
+	for _, p := range match.Players {
+		res.Players = append(res.Players, matchPlayerResponse{
+			UserID:       p.UserID.Hex(),
+			Username:     p.Username,
+			DisplayName:  p.DisplayName,
+			Seat:         p.Seat,
+			RatingChange: p.RatingChange,
+			Placement:    p.Placement,
+			Bot:          p.Bot,
+		})
+	}
+	return res
+}
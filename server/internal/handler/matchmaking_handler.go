@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/i18n"
+	"github.com/Simpolette/HeartSteal/server/internal/matchmaking"
+	"github.com/Simpolette/HeartSteal/server/internal/openapi"
+)
+
+// enqueueRequest names the friends queueing with the caller by user ID.
+type enqueueRequest struct {
+	Mode  domain.GameMode `json:"mode"  binding:"required,oneof=duel table"`
+	Party []string        `json:"party" binding:"omitempty,max=7"`
+}
+
+type queuedPlayerResponse struct {
+	UserID      string  `json:"user_id"`
+	Username    string  `json:"username"`
+	DisplayName string  `json:"display_name"`
+	Rating      float64 `json:"rating"`
+}
+
+type queueTicketResponse struct {
+	ID       string                 `json:"id"`
+	Mode     domain.GameMode        `json:"mode"`
+	Players  []queuedPlayerResponse `json:"players"`
+	QueuedAt time.Time              `json:"queued_at"`
+	// ProposalID is set while a match found for the ticket waits to be
+	// accepted.
+	ProposalID string `json:"proposal_id,omitempty"`
+}
+
+type matchProposalResponse struct {
+	ID       string                 `json:"id"`
+	Mode     domain.GameMode        `json:"mode"`
+	Players  []queuedPlayerResponse `json:"players"`
+	Accepted []string               `json:"accepted"`
+	Deadline time.Time              `json:"deadline"`
+	// MatchID is set once every player accepted.
+	MatchID string `json:"match_id,omitempty"`
+}
+
+var EnqueueOperation = openapi.Operation{
+	Summary:     "Queue for a match, alone or with friends",
+	Description: "Players are matched with others of similar rating; the range widens the longer they wait. Progress is pushed on the caller's user topic as matchmaking.* events.",
+	Tags:        []string{"matchmaking"},
+	Request:     enqueueRequest{},
+	Responses:   []openapi.Response{{Status: http.StatusCreated, Body: domain.SuccessResponse{}, Data: queueTicketResponse{}}},
+	Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusConflict, http.StatusTooManyRequests},
+}
+
+var CurrentQueueTicketOperation = openapi.Operation{
+	Summary:   "Get the caller's place in the queue",
+	Tags:      []string{"matchmaking"},
+	Responses: []openapi.Response{{Status: http.StatusOK, Body: domain.SuccessResponse{}, Data: queueTicketResponse{}}},
+	Errors:    []int{http.StatusUnauthorized, http.StatusNotFound},
+}
+
+var CancelQueueOperation = openapi.Operation{
+	Summary:     "Leave the queue with the caller's party",
+	Description: "Cancelling once a match is found declines it; the other players are queued again.",
+	Tags:        []string{"matchmaking"},
+	Responses:   []openapi.Response{{Status: http.StatusOK, Body: domain.SuccessResponse{}}},
+	Errors:      []int{http.StatusUnauthorized, http.StatusNotFound, http.StatusConflict},
+}
+
+var AcceptMatchOperation = openapi.Operation{
+	Summary:     "Accept the match found for the caller",
+	Description: "Every player must accept before the deadline. The last one to accept starts the match and gets its match_id; everyone is told with matchmaking.started.",
+	Tags:        []string{"matchmaking"},
+	Responses:   []openapi.Response{{Status: http.StatusOK, Body: domain.SuccessResponse{}, Data: matchProposalResponse{}}},
+	Errors:      []int{http.StatusUnauthorized, http.StatusNotFound},
+}
+
+type MatchmakingHandler struct {
+	MatchmakingUseCase domain.MatchmakingUsecase
+}
+
+func NewMatchmakingHandler(usecase domain.MatchmakingUsecase) *MatchmakingHandler {
+	return &MatchmakingHandler{
+		MatchmakingUseCase: usecase,
+	}
+}
+
+func (h *MatchmakingHandler) Enqueue(c *gin.Context) {
+	var req enqueueRequest
+	if err := bindJSON(c, &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	ticket, err := h.MatchmakingUseCase.Enqueue(c.Request.Context(), currentUserID(c), req.Mode, req.Party)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, domain.SuccessResponse{
+		Message: i18n.T(c.Request.Context(), "success.queued", nil),
+		Data:    toQueueTicketResponse(ticket),
+	})
+}
+
+func (h *MatchmakingHandler) Current(c *gin.Context) {
+	ticket, err := h.MatchmakingUseCase.Current(c.Request.Context(), currentUserID(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponse{
+		Message: i18n.T(c.Request.Context(), "success.queue_ticket_found", nil),
+		Data:    toQueueTicketResponse(ticket),
+	})
+}
+
+func (h *MatchmakingHandler) Cancel(c *gin.Context) {
+	if err := h.MatchmakingUseCase.Cancel(c.Request.Context(), currentUserID(c)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponse{
+		Message: i18n.T(c.Request.Context(), "success.queue_cancelled", nil),
+	})
+}
+
+func (h *MatchmakingHandler) Accept(c *gin.Context) {
+	proposal, err := h.MatchmakingUseCase.Accept(c.Request.Context(), currentUserID(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponse{
+		Message: i18n.T(c.Request.Context(), "success.match_accepted", nil),
+		Data:    toMatchProposalResponse(proposal),
+	})
+}
+
+func toQueuedPlayersResponse(players []matchmaking.Player) []queuedPlayerResponse {
+	res := make([]queuedPlayerResponse, len(players))
+	for i, p := range players {
+		res[i] = queuedPlayerResponse{UserID: p.UserID, Username: p.Username, DisplayName: p.DisplayName, Rating: p.Rating}
+	}
+	return res
+}
+
+func toQueueTicketResponse(ticket *matchmaking.Ticket) queueTicketResponse {
+	return queueTicketResponse{
+		ID:         ticket.ID,
+		Mode:       domain.GameMode(ticket.Mode),
+		Players:    toQueuedPlayersResponse(ticket.Players),
+		QueuedAt:   ticket.QueuedAt,
+		ProposalID: ticket.ProposalID,
+	}
+}
+
+func toMatchProposalResponse(proposal *matchmaking.Proposal) matchProposalResponse {
+	accepted := proposal.Accepted
+	if accepted == nil {
+		accepted = []string{}
+	}
+	return matchProposalResponse{
+		ID:       proposal.ID,
+		Mode:     domain.GameMode(proposal.Mode),
+		Players:  toQueuedPlayersResponse(proposal.Players()),
+		Accepted: accepted,
+		Deadline: proposal.Deadline,
+		MatchID:  proposal.MatchID,
+	}
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/i18n"
+	"github.com/Simpolette/HeartSteal/server/internal/openapi"
+	"github.com/Simpolette/HeartSteal/server/internal/realtime"
+)
+
+type realtimeTicketResponse struct {
+	Ticket    string    `json:"ticket"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type connectRealtimeQuery struct {
+	Ticket string `form:"ticket" binding:"required"`
+}
+
+var IssueRealtimeTicketOperation = openapi.Operation{
+	Summary:     "Issue a ticket for opening the realtime WebSocket",
+	Description: "The ticket can be used once, before expires_at, as the ticket query parameter of GET /api/v1/realtime. It keeps the access token out of URLs.",
+	Tags:        []string{"realtime"},
+	Responses:   []openapi.Response{{Status: http.StatusCreated, Body: domain.SuccessResponse{}, Data: realtimeTicketResponse{}}},
+	Errors:      []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests},
+}
+
+var ConnectRealtimeOperation = openapi.Operation{
+	Summary:     "Open the realtime WebSocket",
+	Description: "Upgrades to a WebSocket that exchanges JSON envelopes; see docs/api_spec.md for the messages.",
+	Tags:        []string{"realtime"},
+	Query:       connectRealtimeQuery{},
+	Responses:   []openapi.Response{{Status: http.StatusSwitchingProtocols, Description: "Switched to the WebSocket protocol"}},
+	Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusUpgradeRequired},
+}
+
+type RealtimeHandler struct {
+	RealtimeUseCase domain.RealtimeUsecase
+	Hub             *realtime.Hub
+}
+
+func NewRealtimeHandler(usecase domain.RealtimeUsecase, hub *realtime.Hub) *RealtimeHandler {
+	return &RealtimeHandler{
+		RealtimeUseCase: usecase,
+		Hub:             hub,
+	}
+}
+
+func (h *RealtimeHandler) IssueTicket(c *gin.Context) {
+	ticket, err := h.RealtimeUseCase.IssueTicket(c.Request.Context(), currentUserID(c))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, domain.SuccessResponse{
+		Message: i18n.T(c.Request.Context(), "success.realtime_ticket_issued", nil),
+		Data:    realtimeTicketResponse{Ticket: ticket.Value, ExpiresAt: ticket.ExpiresAt},
+	})
+}
+
+func (h *RealtimeHandler) Connect(c *gin.Context) {
+	var query connectRealtimeQuery
+	if err := bindQuery(c, &query); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	// Check before redeeming so a plain GET doesn't use up the ticket.
+	if !websocket.IsWebSocketUpgrade(c.Request) {
+		_ = c.Error(domain.ErrUpgradeRequired)
+		return
+	}
+
+	userID, err := h.RealtimeUseCase.RedeemTicket(c.Request.Context(), query.Ticket)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	h.Hub.Serve(c.Writer, c.Request, userID)
+}
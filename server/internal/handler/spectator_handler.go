@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/i18n"
+	"github.com/Simpolette/HeartSteal/server/internal/openapi"
+)
+
+type spectatorPolicyRequest struct {
+	Policy domain.SpectatorPolicy `json:"policy" binding:"required,oneof=off friends public"`
+}
+
+type spectatorPolicyResponse struct {
+	Policy domain.SpectatorPolicy `json:"policy"`
+}
+
+// spectatedMatchResponse's match.state is the public view, delay_seconds
+// behind the game, while the match runs.
+type spectatedMatchResponse struct {
+	Match        matchResponse `json:"match"`
+	Spectators   int           `json:"spectators"`
+	DelaySeconds int           `json:"delay_seconds"`
+}
+
+var SetSpectatorPolicyOperation = openapi.Operation{
+	Summary:     "Choose who may watch the caller's matches",
+	Description: "off lets nobody watch, friends only the caller's friends and public everyone. A spectator needs the permission of every player in the match. Spectators who lose it are unsubscribed from the matches the caller is playing.",
+	Tags:        []string{"players"},
+	Request:     spectatorPolicyRequest{},
+	Responses:   []openapi.Response{{Status: http.StatusOK, Body: domain.SuccessResponse{}, Data: spectatorPolicyResponse{}}},
+	Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound},
+}
+
+var SpectateMatchOperation = openapi.Operation{
+	Summary:     "Watch a match",
+	Description: "Returns the match with, while it runs, the state every player may see: hands are counts only. Ranked matches are shown delay_seconds late. Subscribe to spectate:<match_id> for the events that follow.",
+	Tags:        []string{"matches"},
+	Params:      matchURI{},
+	Responses:   []openapi.Response{{Status: http.StatusOK, Body: domain.SuccessResponse{}, Data: spectatedMatchResponse{}}},
+	Errors:      []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound},
+}
+
+type SpectatorHandler struct {
+	SpectatorUseCase domain.SpectatorUsecase
+}
+
+func NewSpectatorHandler(usecase domain.SpectatorUsecase) *SpectatorHandler {
+	return &SpectatorHandler{
+		SpectatorUseCase: usecase,
+	}
+}
+
+func (h *SpectatorHandler) SetPolicy(c *gin.Context) {
+	var req spectatorPolicyRequest
+	if err := bindJSON(c, &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err := h.SpectatorUseCase.SetPolicy(c.Request.Context(), currentUserID(c), req.Policy); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponse{
+		Message: i18n.T(c.Request.Context(), "success.spectator_policy_updated", nil),
+		Data:    spectatorPolicyResponse{Policy: req.Policy},
+	})
+}
+
+func (h *SpectatorHandler) Watch(c *gin.Context) {
+	var uri matchURI
+	if err := bindURI(c, &uri); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	spectated, err := h.SpectatorUseCase.Watch(c.Request.Context(), currentUserID(c), uri.ID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.SuccessResponse{
+		Message: i18n.T(c.Request.Context(), "success.match_spectated", nil),
+		Data: spectatedMatchResponse{
+			Match:        toMatchResponse(spectated.Match, spectated.State),
+			Spectators:   spectated.Spectators,
+			DelaySeconds: int(spectated.Delay.Seconds()),
+		},
+	})
+}
@@ -0,0 +1,50 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Simpolette/HeartSteal/server/internal/apitest"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/health"
+)
+
+func TestHealthHandler_Liveness(t *testing.T) {
+	srv := apitest.New(t)
+
+	res := srv.GET("/healthz")
+
+	assert.Equal(t, http.StatusOK, res.Code)
+}
+
+func TestHealthHandler_Readiness(t *testing.T) {
+	srv := apitest.New(t)
+	srv.App.Health.Register("mongo", time.Second, func(context.Context) error { return nil })
+
+	res := srv.GET("/readyz")
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	srv.App.Health.Register("redis", time.Second, func(context.Context) error { return errors.New("connection refused") })
+	res = srv.GET("/readyz")
+
+	var body health.Report
+	res.JSON(&body)
+	assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+	assert.Equal(t, health.StatusDown, body.Status)
+}
+
+func TestNotFound(t *testing.T) {
+	srv := apitest.New(t)
+
+	res := srv.GET("/api/v1/nope")
+
+	var body domain.ErrorResponse
+	res.JSON(&body)
+	assert.Equal(t, http.StatusNotFound, res.Code)
+	assert.Equal(t, domain.CodeNotFound, body.Code)
+}
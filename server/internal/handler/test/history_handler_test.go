@@ -0,0 +1,200 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Simpolette/HeartSteal/server/internal/apitest"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+)
+
+const usersPath = "/api/v1/users/"
+
+type historyBody struct {
+	Data struct {
+		Matches []struct {
+			ID              string  `json:"id"`
+			Mode            string  `json:"mode"`
+			Ranked          bool    `json:"ranked"`
+			Placement       int     `json:"placement"`
+			Result          string  `json:"result"`
+			RatingChange    float64 `json:"rating_change"`
+			DurationSeconds int     `json:"duration_seconds"`
+			Players         []struct {
+				Username  string `json:"username"`
+				Placement int    `json:"placement"`
+			} `json:"players"`
+		} `json:"matches"`
+		NextCursor string `json:"next_cursor"`
+	} `json:"data"`
+}
+
+type statsBody struct {
+	Data struct {
+		Games         int            `json:"games"`
+		Wins          int            `json:"wins"`
+		Losses        int            `json:"losses"`
+		WinRate       float64        `json:"win_rate"`
+		Streak        int            `json:"streak"`
+		BestWinStreak int            `json:"best_win_streak"`
+		FavoriteMode  string         `json:"favorite_mode"`
+		ModeGames     map[string]int `json:"mode_games"`
+	} `json:"data"`
+}
+
+func history(t *testing.T, srv *apitest.Server, path string, p player) historyBody {
+	res := srv.GET(usersPath+path, p.token)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	var body historyBody
+	res.JSON(&body)
+	return body
+}
+
+func matchIDs(body historyBody) []string {
+	ids := []string{}
+	for _, m := range body.Data.Matches {
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
+
+// finished stores a match the players finished in seat order, with the
+// given placements, minutes ago.
+func finished(t *testing.T, srv *apitest.Server, mode domain.GameMode, placements []int, minutes int, players ...player) string {
+	finishedAt := time.Now().UTC().Add(-time.Duration(minutes) * time.Minute)
+	match := &domain.Match{
+		Mode: mode, Ranked: mode != "", Status: domain.MatchFinished, Placements: placements,
+		CreatedAt: finishedAt.Add(-5 * time.Minute), FinishedAt: &finishedAt,
+	}
+	for seat, p := range players {
+		id, err := primitive.ObjectIDFromHex(p.id)
+		require.NoError(t, err)
+		match.Players = append(match.Players, domain.MatchPlayer{UserID: id, Seat: seat, Placement: placements[seat]})
+	}
+	require.NoError(t, srv.Repos.Match.Create(t.Context(), match))
+	return match.ID.Hex()
+}
+
+func TestHistoryHandler_Matches(t *testing.T) {
+	srv := apitest.New(t)
+	alice, bob, carol := newPlayer(t, srv, "alice"), newPlayer(t, srv, "bob"), newPlayer(t, srv, "carol")
+	duelWon := finished(t, srv, domain.ModeDuel, []int{1, 2}, 30, alice, bob)
+	tableLost := finished(t, srv, domain.ModeTable, []int{3, 1, 2}, 20, alice, bob, carol)
+	lobbyWon := finished(t, srv, "", []int{1, 2}, 10, alice, carol)
+
+	t.Run("Pages", func(t *testing.T) {
+		first := history(t, srv, "alice/matches?limit=2", bob)
+		assert.Equal(t, []string{lobbyWon, tableLost}, matchIDs(first))
+		require.NotEmpty(t, first.Data.NextCursor)
+
+		second := history(t, srv, "alice/matches?limit=2&cursor="+first.Data.NextCursor, bob)
+		assert.Equal(t, []string{duelWon}, matchIDs(second))
+		assert.Empty(t, second.Data.NextCursor)
+	})
+
+	t.Run("FromThePlayersView", func(t *testing.T) {
+		body := history(t, srv, "alice/matches?mode=table", bob)
+
+		require.Len(t, body.Data.Matches, 1)
+		m := body.Data.Matches[0]
+		assert.Equal(t, 3, m.Placement)
+		assert.Equal(t, string(domain.MatchLost), m.Result)
+		assert.Equal(t, 300, m.DurationSeconds)
+		assert.Equal(t, 1, m.Players[1].Placement)
+	})
+
+	t.Run("Filters", func(t *testing.T) {
+		assert.Equal(t, []string{lobbyWon, duelWon}, matchIDs(history(t, srv, "alice/matches?result=win", alice)))
+		assert.Equal(t, []string{tableLost}, matchIDs(history(t, srv, "alice/matches?result=loss", alice)))
+		assert.Equal(t, []string{lobbyWon}, matchIDs(history(t, srv, "alice/matches?ranked=false", alice)))
+		assert.Equal(t, []string{tableLost}, matchIDs(history(t, srv, "bob/matches?result=win&ranked=true", alice)))
+	})
+
+	t.Run("OnlyFinished", func(t *testing.T) {
+		id, err := primitive.ObjectIDFromHex(carol.id)
+		require.NoError(t, err)
+		require.NoError(t, srv.Repos.Match.Create(t.Context(), &domain.Match{
+			Status: domain.MatchActive, Players: []domain.MatchPlayer{{UserID: id}}, CreatedAt: time.Now().UTC(),
+		}))
+
+		assert.Equal(t, []string{lobbyWon, tableLost}, matchIDs(history(t, srv, "carol/matches", alice)))
+	})
+
+	t.Run("UnknownUser", func(t *testing.T) {
+		res := srv.GET(usersPath+"nobody/matches", alice.token)
+
+		assert.Equal(t, http.StatusNotFound, res.Code)
+		assert.Equal(t, domain.CodeUserNotFound, errorCode(res))
+	})
+
+	t.Run("UnknownResult", func(t *testing.T) {
+		res := srv.GET(usersPath+"alice/matches?result=draw", alice.token)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, domain.CodeInvalidRequest, errorCode(res))
+	})
+
+	t.Run("InvalidCursor", func(t *testing.T) {
+		res := srv.GET(usersPath+"alice/matches?cursor=nope", alice.token)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, domain.CodeInvalidCursor, errorCode(res))
+	})
+}
+
+func TestHistoryHandler_PlayedMatch(t *testing.T) {
+	srv := apitest.New(t)
+	alice, bob := newPlayer(t, srv, "alice"), newPlayer(t, srv, "bob")
+
+	res := srv.GET(usersPath+"alice/stats", bob.token)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	var stats statsBody
+	res.JSON(&stats)
+	assert.Zero(t, stats.Data.Games)
+	assert.Empty(t, stats.Data.FavoriteMode)
+
+	enqueue(t, srv, alice, map[string]any{"mode": "duel"})
+	enqueue(t, srv, bob, map[string]any{"mode": "duel"})
+	require.Equal(t, http.StatusOK, srv.POST(acceptPath, nil, alice.token).Code)
+	res = srv.POST(acceptPath, nil, bob.token)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	var proposal proposalBody
+	res.JSON(&proposal)
+	id := proposal.Data.MatchID
+	require.NotEmpty(t, id)
+
+	playOutMatch(t, srv, id, seated(t, srv, id, alice, bob)...)
+
+	require.Eventually(t, func() bool {
+		return len(history(t, srv, "alice/matches", alice).Data.Matches) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	for _, p := range []struct {
+		username string
+		player   player
+	}{{"alice", alice}, {"bob", bob}} {
+		m := history(t, srv, p.username+"/matches", alice).Data.Matches[0]
+		assert.Equal(t, id, m.ID)
+		assert.Equal(t, string(domain.ModeDuel), m.Mode)
+		assert.True(t, m.Ranked)
+		assert.Equal(t, string(domain.ResultOf(m.Placement)), m.Result)
+
+		srv.GET(usersPath+p.username+"/stats", alice.token).JSON(&stats)
+		assert.Equal(t, 1, stats.Data.Games)
+		assert.Equal(t, 1, stats.Data.Wins+stats.Data.Losses)
+		assert.Equal(t, string(domain.ModeDuel), stats.Data.FavoriteMode)
+		assert.Equal(t, map[string]int{"duel": 1}, stats.Data.ModeGames)
+		if m.Result == string(domain.MatchWon) {
+			assert.Equal(t, 1, stats.Data.Streak)
+			assert.Equal(t, 1.0, stats.Data.WinRate)
+		} else {
+			assert.Equal(t, -1, stats.Data.Streak)
+			assert.Zero(t, stats.Data.BestWinStreak)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Simpolette/HeartSteal/server/internal/apitest"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+)
+
+const leaderboardsPath = "/api/v1/leaderboards"
+
+type leaderboardBody struct {
+	Data struct {
+		Entries []struct {
+			Rank     int     `json:"rank"`
+			UserID   string  `json:"user_id"`
+			Username string  `json:"username"`
+			Rating   float64 `json:"rating"`
+			RD       float64 `json:"rd"`
+			Games    int     `json:"games"`
+		} `json:"entries"`
+		NextCursor string `json:"next_cursor"`
+	} `json:"data"`
+}
+
+func leaderboard(t *testing.T, srv *apitest.Server, path string, p player) leaderboardBody {
+	res := srv.GET(leaderboardsPath+path, p.token)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	var board leaderboardBody
+	res.JSON(&board)
+	return board
+}
+
+func usernames(board leaderboardBody) []string {
+	names := []string{}
+	for _, e := range board.Data.Entries {
+		names = append(names, e.Username)
+	}
+	return names
+}
+
+// rate stores p's duel rating directly, as if they had played.
+func rate(t *testing.T, srv *apitest.Server, p player, username string, value float64) {
+	id, err := primitive.ObjectIDFromHex(p.id)
+	require.NoError(t, err)
+	require.NoError(t, srv.Repos.Rating.Save(t.Context(), &domain.PlayerRating{
+		UserID: id, Username: username, Mode: domain.ModeDuel,
+		Rating: value, RD: 60, Volatility: 0.06, Games: 20, LastPlayedAt: time.Now().UTC(),
+	}))
+}
+
+// playOutMatch plays each player's first card at the other seat until the
+// match is over.
+func playOutMatch(t *testing.T, srv *apitest.Server, id string, players ...player) {
+	for {
+		state := getMatch(t, srv, id, players[0]).Data.State
+		if state == nil || state.Over {
+			return
+		}
+		mover := players[state.Turn]
+		view := getMatch(t, srv, id, mover).Data.State
+		require.NotNil(t, view)
+		body := map[string]any{"card": view.Players[view.Seat].Hand[0].ID, "target": 1 - view.Seat}
+		res := srv.POST(matchesPath+"/"+id+"/actions", body, mover.token)
+		require.Contains(t, []int{http.StatusOK, http.StatusConflict}, res.Code, res.Body.String())
+	}
+}
+
+func TestLeaderboardHandler_List(t *testing.T) {
+	srv := apitest.New(t)
+	alice, bob, carol := newPlayer(t, srv, "alice"), newPlayer(t, srv, "bob"), newPlayer(t, srv, "carol")
+	rate(t, srv, alice, "alice", 1700)
+	rate(t, srv, bob, "bob", 1500)
+	rate(t, srv, carol, "carol", 1600)
+
+	t.Run("Pages", func(t *testing.T) {
+		first := leaderboard(t, srv, "/duel?limit=2", bob)
+		assert.Equal(t, []string{"alice", "carol"}, usernames(first))
+		assert.Equal(t, 2, first.Data.Entries[1].Rank)
+		require.NotEmpty(t, first.Data.NextCursor)
+
+		second := leaderboard(t, srv, "/duel?limit=2&cursor="+first.Data.NextCursor, bob)
+		assert.Equal(t, []string{"bob"}, usernames(second))
+		assert.Equal(t, 3, second.Data.Entries[0].Rank)
+		assert.Empty(t, second.Data.NextCursor)
+	})
+
+	t.Run("Friends", func(t *testing.T) {
+		befriend(t, srv, bob, alice)
+
+		board := leaderboard(t, srv, "/duel?scope=friends", bob)
+
+		assert.Equal(t, []string{"alice", "bob"}, usernames(board))
+		assert.Equal(t, 2, board.Data.Entries[1].Rank)
+	})
+
+	t.Run("AroundMe", func(t *testing.T) {
+		board := leaderboard(t, srv, "/duel/around-me?limit=1", carol)
+
+		assert.Equal(t, []string{"alice", "carol", "bob"}, usernames(board))
+		assert.Equal(t, 2, board.Data.Entries[1].Rank)
+	})
+
+	t.Run("NotRanked", func(t *testing.T) {
+		res := srv.GET(leaderboardsPath+"/table/around-me", alice.token)
+
+		assert.Equal(t, http.StatusNotFound, res.Code)
+		assert.Equal(t, domain.CodeNotRanked, errorCode(res))
+	})
+
+	t.Run("UnknownMode", func(t *testing.T) {
+		res := srv.GET(leaderboardsPath+"/solo", alice.token)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, domain.CodeInvalidRequest, errorCode(res))
+	})
+
+	t.Run("InvalidCursor", func(t *testing.T) {
+		res := srv.GET(leaderboardsPath+"/duel?cursor=nope", alice.token)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, domain.CodeInvalidCursor, errorCode(res))
+	})
+}
+
+func TestLeaderboardHandler_RankedMatch(t *testing.T) {
+	srv := apitest.New(t)
+	alice, bob := newPlayer(t, srv, "alice"), newPlayer(t, srv, "bob")
+	enqueue(t, srv, alice, map[string]any{"mode": "duel"})
+	enqueue(t, srv, bob, map[string]any{"mode": "duel"})
+	require.Equal(t, http.StatusOK, srv.POST(acceptPath, nil, alice.token).Code)
+	res := srv.POST(acceptPath, nil, bob.token)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	var proposal proposalBody
+	res.JSON(&proposal)
+	id := proposal.Data.MatchID
+	require.NotEmpty(t, id)
+
+	playOutMatch(t, srv, id, seated(t, srv, id, alice, bob)...)
+
+	require.Eventually(t, func() bool {
+		return getMatch(t, srv, id, alice).Data.Status == string(domain.MatchFinished)
+	}, time.Second, 10*time.Millisecond)
+
+	var rated struct {
+		Data struct {
+			Ranked  bool `json:"ranked"`
+			Players []struct {
+				Seat         int     `json:"seat"`
+				RatingChange float64 `json:"rating_change"`
+			} `json:"players"`
+			Placements []int `json:"placements"`
+		} `json:"data"`
+	}
+	srv.GET(matchesPath+"/"+id, alice.token).JSON(&rated)
+	assert.True(t, rated.Data.Ranked)
+	require.Len(t, rated.Data.Placements, 2)
+	first, second := rated.Data.Players[0], rated.Data.Players[1]
+	switch {
+	case rated.Data.Placements[0] < rated.Data.Placements[1]:
+		assert.Positive(t, first.RatingChange)
+		assert.Negative(t, second.RatingChange)
+	case rated.Data.Placements[0] > rated.Data.Placements[1]:
+		assert.Negative(t, first.RatingChange)
+		assert.Positive(t, second.RatingChange)
+	default:
+		assert.InDelta(t, 0, first.RatingChange, 1e-6, "a draw between equals moves nothing")
+	}
+
+	board := leaderboard(t, srv, "/duel", alice)
+	require.Len(t, board.Data.Entries, 2)
+	for _, e := range board.Data.Entries {
+		assert.Equal(t, 1, e.Games)
+		assert.Less(t, e.RD, 350.0)
+	}
+	assert.GreaterOrEqual(t, board.Data.Entries[0].Rating, board.Data.Entries[1].Rating)
+}
+
+// seated orders players by their seat in match id.
+func seated(t *testing.T, srv *apitest.Server, id string, players ...player) []player {
+	match := getMatch(t, srv, id, players[0])
+	bySeat := make([]player, len(players))
+	for _, mp := range match.Data.Players {
+		for _, p := range players {
+			if p.id == mp.UserID {
+				bySeat[mp.Seat] = p
+			}
+		}
+	}
+	return bySeat
+}
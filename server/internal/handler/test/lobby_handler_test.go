@@ -0,0 +1,313 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Simpolette/HeartSteal/server/internal/apitest"
+	"github.com/Simpolette/HeartSteal/server/internal/bot"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/game"
+)
+
+const lobbiesPath = "/api/v1/lobbies"
+
+type lobbyBody struct {
+	Data struct {
+		ID         string             `json:"id"`
+		HostID     string             `json:"host_id"`
+		InviteCode string             `json:"invite_code"`
+		Status     domain.LobbyStatus `json:"status"`
+		MatchID    string             `json:"match_id"`
+		Members    []struct {
+			UserID   string    `json:"user_id"`
+			Username string    `json:"username"`
+			Bot      bot.Level `json:"bot"`
+		} `json:"members"`
+	} `json:"data"`
+}
+
+type player struct {
+	id    string
+	token apitest.RequestOption
+}
+
+func newPlayer(t *testing.T, srv *apitest.Server, username string) player {
+	user := &domain.User{Username: username, DisplayName: username, Email: username + "@example.com"}
+	require.NoError(t, srv.Repos.User.Create(t.Context(), user))
+	return player{id: user.ID.Hex(), token: apitest.WithToken(srv.AccessToken(user.ID.Hex()))}
+}
+
+func createLobby(t *testing.T, srv *apitest.Server, host player, body map[string]any) lobbyBody {
+	res := srv.POST(lobbiesPath, body, host.token)
+	require.Equal(t, http.StatusCreated, res.Code, res.Body.String())
+	var lobby lobbyBody
+	res.JSON(&lobby)
+	return lobby
+}
+
+func errorCode(res *apitest.Response) domain.ErrorCode {
+	var body domain.ErrorResponse
+	res.JSON(&body)
+	return body.Code
+}
+
+func TestLobbyHandler_Lifecycle(t *testing.T) {
+	srv := apitest.New(t)
+	host, guest, late := newPlayer(t, srv, "host"), newPlayer(t, srv, "guest"), newPlayer(t, srv, "late")
+
+	lobby := createLobby(t, srv, host, map[string]any{"name": "Friday night", "capacity": 2})
+	assert.Equal(t, host.id, lobby.Data.HostID)
+	assert.Len(t, lobby.Data.InviteCode, domain.InviteCodeLength)
+	lobbyPath := lobbiesPath + "/" + lobby.Data.ID
+
+	t.Run("OneLobbyAtATime", func(t *testing.T) {
+		res := srv.POST(lobbiesPath, map[string]any{"name": "Second", "capacity": 4}, host.token)
+
+		assert.Equal(t, http.StatusConflict, res.Code)
+		assert.Equal(t, domain.CodeAlreadyInLobby, errorCode(res))
+	})
+
+	t.Run("Join", func(t *testing.T) {
+		res := srv.POST(lobbyPath+"/join", nil, guest.token)
+
+		require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+		var joined lobbyBody
+		res.JSON(&joined)
+		assert.Len(t, joined.Data.Members, 2)
+	})
+
+	t.Run("FullLobbyIsInGame", func(t *testing.T) {
+		res := srv.POST(lobbyPath+"/join", nil, late.token)
+
+		assert.Equal(t, http.StatusConflict, res.Code)
+		assert.Equal(t, domain.CodeLobbyInGame, errorCode(res))
+	})
+
+	t.Run("Current", func(t *testing.T) {
+		res := srv.GET(lobbiesPath+"/current", guest.token)
+
+		require.Equal(t, http.StatusOK, res.Code)
+		var current lobbyBody
+		res.JSON(&current)
+		assert.Equal(t, lobby.Data.ID, current.Data.ID)
+	})
+
+	t.Run("OnlyHostKicks", func(t *testing.T) {
+		res := srv.DELETE(lobbyPath+"/members/"+host.id, guest.token)
+
+		assert.Equal(t, http.StatusForbidden, res.Code)
+		assert.Equal(t, domain.CodeNotLobbyHost, errorCode(res))
+	})
+
+	t.Run("PlayersStaySeated", func(t *testing.T) {
+		res := srv.POST(lobbyPath+"/leave", nil, host.token)
+
+		assert.Equal(t, http.StatusConflict, res.Code)
+		assert.Equal(t, domain.CodeLobbyInGame, errorCode(res))
+	})
+
+	// The first lobby plays its match; the rest happens in an open one.
+	other := newPlayer(t, srv, "other")
+	open := createLobby(t, srv, late, map[string]any{"name": "Saturday", "capacity": 3})
+	openPath := lobbiesPath + "/" + open.Data.ID
+	require.Equal(t, http.StatusOK, srv.POST(openPath+"/join", nil, other.token).Code)
+
+	t.Run("HostMigratesWhenHostLeaves", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, srv.POST(openPath+"/leave", nil, late.token).Code)
+
+		res := srv.GET(openPath, other.token)
+		require.Equal(t, http.StatusOK, res.Code)
+		var after lobbyBody
+		res.JSON(&after)
+		assert.Equal(t, other.id, after.Data.HostID)
+	})
+
+	t.Run("LastMemberClosesLobby", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, srv.POST(openPath+"/leave", nil, other.token).Code)
+
+		res := srv.GET(openPath, other.token)
+		assert.Equal(t, http.StatusNotFound, res.Code)
+		assert.Equal(t, domain.CodeLobbyNotFound, errorCode(res))
+	})
+}
+
+func TestLobbyHandler_Private(t *testing.T) {
+	srv := apitest.New(t)
+	host, friend, stranger := newPlayer(t, srv, "host"), newPlayer(t, srv, "friend"), newPlayer(t, srv, "stranger")
+
+	lobby := createLobby(t, srv, host, map[string]any{"name": "Friends only", "capacity": 4, "visibility": "private"})
+	lobbyPath := lobbiesPath + "/" + lobby.Data.ID
+
+	assert.Equal(t, http.StatusNotFound, srv.GET(lobbyPath, stranger.token).Code)
+	assert.Equal(t, http.StatusNotFound, srv.POST(lobbyPath+"/join", nil, stranger.token).Code)
+
+	res := srv.POST(lobbiesPath+"/join", map[string]any{"invite_code": lobby.Data.InviteCode}, friend.token)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+
+	t.Run("NotListed", func(t *testing.T) {
+		var list struct {
+			Data struct {
+				Lobbies []lobbyBody `json:"lobbies"`
+			} `json:"data"`
+		}
+		srv.GET(lobbiesPath, stranger.token).JSON(&list)
+		assert.Empty(t, list.Data.Lobbies)
+	})
+
+	t.Run("Kick", func(t *testing.T) {
+		res := srv.DELETE(lobbyPath+"/members/"+friend.id, host.token)
+
+		require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+		var after lobbyBody
+		res.JSON(&after)
+		assert.Len(t, after.Data.Members, 1)
+	})
+}
+
+func addBot(t *testing.T, srv *apitest.Server, lobbyID string, host player, level bot.Level) lobbyBody {
+	res := srv.POST(lobbiesPath+"/"+lobbyID+"/bots", map[string]any{"level": level}, host.token)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	var lobby lobbyBody
+	res.JSON(&lobby)
+	return lobby
+}
+
+func TestLobbyHandler_Bots(t *testing.T) {
+	srv := apitest.New(t)
+	host, guest := newPlayer(t, srv, "host"), newPlayer(t, srv, "guest")
+
+	lobby := createLobby(t, srv, host, map[string]any{"name": "Filler", "capacity": 4})
+	lobbyPath := lobbiesPath + "/" + lobby.Data.ID
+	require.Equal(t, http.StatusOK, srv.POST(lobbyPath+"/join", nil, guest.token).Code)
+
+	t.Run("OnlyHostAddsBots", func(t *testing.T) {
+		res := srv.POST(lobbyPath+"/bots", map[string]any{"level": "easy"}, guest.token)
+
+		assert.Equal(t, http.StatusForbidden, res.Code)
+		assert.Equal(t, domain.CodeNotLobbyHost, errorCode(res))
+	})
+
+	t.Run("UnknownLevel", func(t *testing.T) {
+		res := srv.POST(lobbyPath+"/bots", map[string]any{"level": "expert"}, host.token)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, domain.CodeInvalidRequest, errorCode(res))
+	})
+
+	t.Run("AddAndKick", func(t *testing.T) {
+		added := addBot(t, srv, lobby.Data.ID, host, bot.Hard)
+
+		require.Len(t, added.Data.Members, 3)
+		member := added.Data.Members[2]
+		assert.Equal(t, bot.Hard, member.Bot)
+		assert.Equal(t, "bot1", member.Username)
+		assert.Empty(t, added.Data.Members[0].Bot)
+
+		res := srv.DELETE(lobbyPath+"/members/"+member.UserID, host.token)
+		require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+		var after lobbyBody
+		res.JSON(&after)
+		assert.Len(t, after.Data.Members, 2)
+	})
+
+	t.Run("FillingStartsMatch", func(t *testing.T) {
+		addBot(t, srv, lobby.Data.ID, host, bot.Easy)
+		full := addBot(t, srv, lobby.Data.ID, host, bot.Normal)
+
+		assert.Equal(t, domain.LobbyInGame, full.Data.Status)
+		require.NotEmpty(t, full.Data.MatchID)
+		res := srv.GET(matchesPath+"/"+full.Data.MatchID, host.token)
+		require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+		var match struct {
+			Data struct {
+				Players []struct {
+					Bot bot.Level `json:"bot"`
+				} `json:"players"`
+			} `json:"data"`
+		}
+		res.JSON(&match)
+		require.Len(t, match.Data.Players, 4)
+		assert.Equal(t, bot.Easy, match.Data.Players[2].Bot)
+		assert.Equal(t, bot.Normal, match.Data.Players[3].Bot)
+	})
+}
+
+func TestLobbyHandler_BotsPlay(t *testing.T) {
+	srv := apitest.New(t)
+	solo := newPlayer(t, srv, "solo")
+	lobby := createLobby(t, srv, solo, map[string]any{"name": "Practice", "capacity": 2})
+	id := addBot(t, srv, lobby.Data.ID, solo, bot.Hard).Data.MatchID
+	require.NotEmpty(t, id)
+
+	// solo plays their first card whenever it's their turn, which only comes
+	// back once the bot has played.
+	for turns := 0; turns < 3; turns++ {
+		var state *game.View
+		require.Eventually(t, func() bool {
+			state = getMatch(t, srv, id, solo).Data.State
+			return state == nil || state.Turn == 0
+		}, time.Second, 10*time.Millisecond, "the bot plays its turn")
+		if state == nil {
+			return
+		}
+		res := srv.POST(matchesPath+"/"+id+"/actions", map[string]any{"card": state.Players[0].Hand[0].ID, "target": 1}, solo.token)
+		require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	}
+}
+
+func TestLobbyHandler_List(t *testing.T) {
+	srv := apitest.New(t)
+	viewer := newPlayer(t, srv, "viewer")
+	for _, name := range []string{"alpha", "bravo", "charlie"} {
+		createLobby(t, srv, newPlayer(t, srv, name), map[string]any{"name": name, "capacity": 4})
+	}
+
+	type page struct {
+		Data struct {
+			Lobbies []struct {
+				Name       string `json:"name"`
+				InviteCode string `json:"invite_code"`
+			} `json:"lobbies"`
+			NextCursor string `json:"next_cursor"`
+		} `json:"data"`
+	}
+
+	var first page
+	res := srv.GET(lobbiesPath+"?limit=2", viewer.token)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	res.JSON(&first)
+	require.Len(t, first.Data.Lobbies, 2)
+	require.NotEmpty(t, first.Data.NextCursor)
+	assert.Empty(t, first.Data.Lobbies[0].InviteCode, "invite codes are only shown to members")
+
+	var second page
+	srv.GET(lobbiesPath+"?limit=2&cursor="+first.Data.NextCursor, viewer.token).JSON(&second)
+	require.Len(t, second.Data.Lobbies, 1)
+	assert.Empty(t, second.Data.NextCursor)
+
+	seen := map[string]bool{}
+	for _, l := range append(first.Data.Lobbies, second.Data.Lobbies...) {
+		seen[l.Name] = true
+	}
+	assert.Len(t, seen, 3)
+
+	t.Run("InvalidCursor", func(t *testing.T) {
+		res := srv.GET(lobbiesPath+"?cursor=nope", viewer.token)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, domain.CodeInvalidCursor, errorCode(res))
+	})
+}
+
+func TestLobbyHandler_RequiresAuth(t *testing.T) {
+	srv := apitest.New(t)
+
+	res := srv.GET(lobbiesPath)
+
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+}
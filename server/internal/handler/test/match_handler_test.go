@@ -0,0 +1,386 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Simpolette/HeartSteal/server/internal/apitest"
+	"github.com/Simpolette/HeartSteal/server/internal/bootstrap"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/game"
+	"github.com/Simpolette/HeartSteal/server/internal/realtime"
+)
+
+const matchesPath = "/api/v1/matches"
+
+type matchBody struct {
+	Data struct {
+		ID      string `json:"id"`
+		LobbyID string `json:"lobby_id"`
+		Status  string `json:"status"`
+		Players []struct {
+			UserID string `json:"user_id"`
+			Seat   int    `json:"seat"`
+		} `json:"players"`
+		State *game.View `json:"state"`
+	} `json:"data"`
+}
+
+// startLobbyMatch fills a two-seat lobby and returns its match ID.
+func startLobbyMatch(t *testing.T, srv *apitest.Server, host, guest player) string {
+	lobby := createLobby(t, srv, host, map[string]any{"name": "Duel", "capacity": 2})
+	res := srv.POST(lobbiesPath+"/"+lobby.Data.ID+"/join", nil, guest.token)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+
+	var joined struct {
+		Data struct {
+			Status  domain.LobbyStatus `json:"status"`
+			MatchID string             `json:"match_id"`
+		} `json:"data"`
+	}
+	res.JSON(&joined)
+	require.Equal(t, domain.LobbyInGame, joined.Data.Status)
+	require.NotEmpty(t, joined.Data.MatchID)
+	return joined.Data.MatchID
+}
+
+func getMatch(t *testing.T, srv *apitest.Server, id string, p player) matchBody {
+	res := srv.GET(matchesPath+"/"+id, p.token)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	var match matchBody
+	res.JSON(&match)
+	return match
+}
+
+func TestMatchHandler_Get(t *testing.T) {
+	srv := apitest.New(t)
+	host, guest, stranger := newPlayer(t, srv, "host"), newPlayer(t, srv, "guest"), newPlayer(t, srv, "stranger")
+	id := startLobbyMatch(t, srv, host, guest)
+
+	t.Run("PlayerSeesOwnHand", func(t *testing.T) {
+		match := getMatch(t, srv, id, guest)
+
+		assert.Equal(t, string(domain.MatchActive), match.Data.Status)
+		require.Len(t, match.Data.Players, 2)
+		require.NotNil(t, match.Data.State)
+		assert.Equal(t, 1, match.Data.State.Seat)
+		assert.Len(t, match.Data.State.Players[1].Hand, game.DefaultHandSize)
+		assert.Empty(t, match.Data.State.Players[0].Hand)
+		assert.Equal(t, game.DefaultHandSize, match.Data.State.Players[0].HandCount)
+	})
+
+	t.Run("StrangerCantSee", func(t *testing.T) {
+		res := srv.GET(matchesPath+"/"+id, stranger.token)
+
+		assert.Equal(t, http.StatusNotFound, res.Code)
+		assert.Equal(t, domain.CodeMatchNotFound, errorCode(res))
+	})
+}
+
+func TestMatchHandler_Play(t *testing.T) {
+	srv := apitest.New(t)
+	host, guest := newPlayer(t, srv, "host"), newPlayer(t, srv, "guest")
+	id := startLobbyMatch(t, srv, host, guest)
+	actionsPath := matchesPath + "/" + id + "/actions"
+
+	seats := []player{host, guest}
+	state := getMatch(t, srv, id, host).Data.State
+	mover, waiting := seats[state.Turn], seats[1-state.Turn]
+	hand := getMatch(t, srv, id, mover).Data.State.Players[state.Turn].Hand
+	card := hand[0]
+
+	t.Run("NotYourTurn", func(t *testing.T) {
+		res := srv.POST(actionsPath, map[string]any{"card": card.ID, "target": state.Turn}, waiting.token)
+
+		assert.Equal(t, http.StatusConflict, res.Code)
+		assert.Equal(t, domain.CodeNotYourTurn, errorCode(res))
+	})
+
+	t.Run("CardNotInHand", func(t *testing.T) {
+		res := srv.POST(actionsPath, map[string]any{"card": 999, "target": 1 - state.Turn}, mover.token)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, domain.CodeInvalidMove, errorCode(res))
+	})
+
+	t.Run("MissingCard", func(t *testing.T) {
+		res := srv.POST(actionsPath, map[string]any{"target": 1}, mover.token)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, domain.CodeInvalidRequest, errorCode(res))
+	})
+
+	t.Run("PlaysAndPushes", func(t *testing.T) {
+		ws, _, err := connect(t, srv, issueTicket(t, srv, waiting), nil)
+		require.NoError(t, err)
+		topic := domain.MatchTopic(id)
+		require.NoError(t, ws.WriteJSON(realtime.Envelope{Type: realtime.TypeSubscribe, ID: "1", Topic: topic}))
+		require.Equal(t, realtime.TypeSubscribed, readEnvelope(t, ws).Type)
+
+		res := srv.POST(actionsPath, map[string]any{"card": card.ID, "target": 1 - state.Turn}, mover.token)
+
+		require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+		var body struct {
+			Data game.View `json:"data"`
+		}
+		res.JSON(&body)
+		assert.Equal(t, 2, body.Data.TurnNumber)
+		assert.Equal(t, 1-state.Turn, body.Data.Turn)
+
+		event := readEnvelope(t, ws)
+		assert.Equal(t, topic, event.Topic)
+		assert.Equal(t, domain.EventMatchUpdated, event.Event)
+		var update domain.MatchUpdate
+		require.NoError(t, json.Unmarshal(event.Data, &update))
+		assert.Equal(t, game.EventCardPlayed, update.Events[0].Type)
+		for _, e := range update.Events {
+			if e.Type == game.EventCardDrawn {
+				assert.Nil(t, e.Card, "other players don't see drawn cards")
+			}
+		}
+		assert.NotNil(t, update.TurnDeadline)
+	})
+
+	t.Run("StrangerCantSubscribe", func(t *testing.T) {
+		stranger := newPlayer(t, srv, "stranger")
+		ws, _, err := connect(t, srv, issueTicket(t, srv, stranger), nil)
+		require.NoError(t, err)
+
+		require.NoError(t, ws.WriteJSON(realtime.Envelope{Type: realtime.TypeSubscribe, ID: "1", Topic: domain.MatchTopic(id)}))
+
+		reply := readEnvelope(t, ws)
+		assert.Equal(t, realtime.TypeError, reply.Type)
+		assert.Equal(t, domain.CodeTopicForbidden, reply.Error.Code)
+	})
+}
+
+type resumeBody struct {
+	Data struct {
+		Match        matchBodyData         `json:"match"`
+		Events       []game.Event          `json:"events"`
+		TurnDeadline *time.Time            `json:"turn_deadline"`
+		Presence     []domain.SeatPresence `json:"presence"`
+	} `json:"data"`
+}
+
+func resume(t *testing.T, srv *apitest.Server, id string, p player, after int) resumeBody {
+	res := srv.GET(matchesPath+"/"+id+"/resume?after="+strconv.Itoa(after), p.token)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	var body resumeBody
+	res.JSON(&body)
+	return body
+}
+
+// follow subscribes p to match id on a new connection.
+func follow(t *testing.T, srv *apitest.Server, id string, p player) *websocket.Conn {
+	ws, _, err := connect(t, srv, issueTicket(t, srv, p), nil)
+	require.NoError(t, err)
+	require.NoError(t, ws.WriteJSON(realtime.Envelope{Type: realtime.TypeSubscribe, ID: "1", Topic: domain.MatchTopic(id)}))
+	require.Equal(t, realtime.TypeSubscribed, readEnvelope(t, ws).Type)
+	return ws
+}
+
+func readPresence(t *testing.T, ws *websocket.Conn) domain.MatchPresence {
+	event := readEnvelope(t, ws)
+	require.Equal(t, domain.EventMatchPresence, event.Event)
+	var presence domain.MatchPresence
+	require.NoError(t, json.Unmarshal(event.Data, &presence))
+	return presence
+}
+
+func TestMatchHandler_Resume(t *testing.T) {
+	srv := apitest.New(t)
+	host, guest, stranger := newPlayer(t, srv, "host"), newPlayer(t, srv, "guest"), newPlayer(t, srv, "stranger")
+	id := startLobbyMatch(t, srv, host, guest)
+	state := getMatch(t, srv, id, host).Data.State
+	mover := []player{host, guest}[state.Turn]
+
+	t.Run("CatchesUp", func(t *testing.T) {
+		before := resume(t, srv, id, mover, 0)
+		require.NotEmpty(t, before.Data.Events)
+		assert.Equal(t, 1, before.Data.Events[0].Seq)
+		last := before.Data.Events[len(before.Data.Events)-1].Seq
+		card := getMatch(t, srv, id, mover).Data.State.Players[state.Turn].Hand[0]
+		res := srv.POST(matchesPath+"/"+id+"/actions", map[string]any{"card": card.ID, "target": 1 - state.Turn}, mover.token)
+		require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+
+		body := resume(t, srv, id, mover, last)
+
+		require.NotEmpty(t, body.Data.Events)
+		assert.Equal(t, last+1, body.Data.Events[0].Seq)
+		assert.Equal(t, game.EventCardPlayed, body.Data.Events[0].Type)
+		require.NotNil(t, body.Data.Match.State)
+		assert.Equal(t, state.Turn, body.Data.Match.State.Seat)
+		assert.NotNil(t, body.Data.TurnDeadline)
+		assert.Equal(t, []domain.SeatPresence{{Seat: 0, Connected: true}, {Seat: 1, Connected: true}}, body.Data.Presence)
+	})
+
+	t.Run("DroppedPlayerKeepsSeat", func(t *testing.T) {
+		watcher := follow(t, srv, id, guest)
+		dropped := follow(t, srv, id, host)
+
+		require.NoError(t, dropped.Close())
+
+		presence := readPresence(t, watcher)
+		assert.Equal(t, 0, presence.Seat)
+		assert.False(t, presence.Connected)
+		require.NotNil(t, presence.GraceDeadline)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), *presence.GraceDeadline, 5*time.Second)
+		assert.False(t, resume(t, srv, id, guest, 0).Data.Presence[0].Connected)
+
+		follow(t, srv, id, host)
+
+		presence = readPresence(t, watcher)
+		assert.True(t, presence.Connected)
+		assert.Nil(t, presence.GraceDeadline)
+	})
+
+	t.Run("InvalidAfter", func(t *testing.T) {
+		res := srv.GET(matchesPath+"/"+id+"/resume?after=-1", host.token)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, domain.CodeInvalidRequest, errorCode(res))
+	})
+
+	t.Run("StrangerCantResume", func(t *testing.T) {
+		res := srv.GET(matchesPath+"/"+id+"/resume", stranger.token)
+
+		assert.Equal(t, http.StatusNotFound, res.Code)
+		assert.Equal(t, domain.CodeMatchNotFound, errorCode(res))
+	})
+}
+
+func TestMatchHandler_ForfeitOnDisconnect(t *testing.T) {
+	if testing.Short() {
+		t.Skip("waits for the reconnect grace period")
+	}
+	srv := apitest.New(t, apitest.WithEnv(func(env *bootstrap.Env) { env.ReconnectGraceSeconds = 1 }))
+	host, guest := newPlayer(t, srv, "host"), newPlayer(t, srv, "guest")
+	id := startLobbyMatch(t, srv, host, guest)
+	watcher := follow(t, srv, id, guest)
+	dropped := follow(t, srv, id, host)
+
+	require.NoError(t, dropped.Close())
+	require.False(t, readPresence(t, watcher).Connected)
+
+	assert.False(t, readPresence(t, watcher).Connected, "the grace period ran out")
+	event := readEnvelope(t, watcher)
+	require.Equal(t, domain.EventMatchUpdated, event.Event)
+	var update domain.MatchUpdate
+	require.NoError(t, json.Unmarshal(event.Data, &update))
+	assert.Equal(t, game.EventPlayerOut, update.Events[0].Type)
+	assert.True(t, update.Events[0].Forfeit)
+	assert.Equal(t, game.EventGameOver, update.Events[len(update.Events)-1].Type)
+	assert.Equal(t, []int{2, 1}, update.Events[len(update.Events)-1].Placements)
+}
+
+type replayBody struct {
+	Data struct {
+		Match struct {
+			ID         string `json:"id"`
+			Status     string `json:"status"`
+			Placements []int  `json:"placements"`
+		} `json:"match"`
+		Seed    string        `json:"seed"`
+		Actions []game.Action `json:"actions"`
+		Steps   int           `json:"steps"`
+		Step    int           `json:"step"`
+		State   game.View     `json:"state"`
+		Events  []game.Event  `json:"events"`
+	} `json:"data"`
+}
+
+func getReplay(t *testing.T, srv *apitest.Server, path string, p player) replayBody {
+	res := srv.GET(matchesPath+"/"+path, p.token)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	var body replayBody
+	res.JSON(&body)
+	return body
+}
+
+func TestMatchHandler_Replay(t *testing.T) {
+	srv := apitest.New(t)
+	host, guest, stranger := newPlayer(t, srv, "host"), newPlayer(t, srv, "guest"), newPlayer(t, srv, "stranger")
+	id := startLobbyMatch(t, srv, host, guest)
+
+	t.Run("NotWhileRunning", func(t *testing.T) {
+		res := srv.GET(matchesPath+"/"+id+"/replay", host.token)
+
+		assert.Equal(t, http.StatusNotFound, res.Code)
+		assert.Equal(t, domain.CodeReplayNotFound, errorCode(res))
+	})
+
+	playOutMatch(t, srv, id, seated(t, srv, id, host, guest)...)
+	require.Eventually(t, func() bool {
+		return getMatch(t, srv, id, host).Data.Status == string(domain.MatchFinished)
+	}, time.Second, 10*time.Millisecond)
+
+	t.Run("AnyoneSeesTheEnd", func(t *testing.T) {
+		body := getReplay(t, srv, id+"/replay", stranger)
+
+		assert.Equal(t, body.Data.Steps, body.Data.Step)
+		assert.Len(t, body.Data.Actions, body.Data.Steps)
+		assert.True(t, body.Data.State.Over)
+		assert.Equal(t, body.Data.Match.Placements, body.Data.State.Placements)
+		require.NotEmpty(t, body.Data.Events)
+		assert.Equal(t, game.EventGameOver, body.Data.Events[len(body.Data.Events)-1].Type)
+	})
+
+	t.Run("Deal", func(t *testing.T) {
+		body := getReplay(t, srv, id+"/replay?step=0", host)
+
+		assert.Equal(t, 0, body.Data.Step)
+		assert.Equal(t, 1, body.Data.State.TurnNumber)
+		for _, p := range body.Data.State.Players {
+			assert.Len(t, p.Hand, game.DefaultHandSize, "every hand is shown")
+		}
+		assert.Equal(t, game.EventStarted, body.Data.Events[0].Type)
+	})
+
+	t.Run("PastTheEnd", func(t *testing.T) {
+		body := getReplay(t, srv, id+"/replay?step=1000", host)
+
+		assert.Equal(t, body.Data.Steps, body.Data.Step)
+	})
+
+	t.Run("NegativeStep", func(t *testing.T) {
+		res := srv.GET(matchesPath+"/"+id+"/replay?step=-1", host.token)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, domain.CodeInvalidRequest, errorCode(res))
+	})
+
+	t.Run("UnknownMatch", func(t *testing.T) {
+		res := srv.GET(matchesPath+"/"+primitive.NewObjectID().Hex()+"/replay", host.token)
+
+		assert.Equal(t, http.StatusNotFound, res.Code)
+		assert.Equal(t, domain.CodeMatchNotFound, errorCode(res))
+	})
+
+	t.Run("EveryRecordedMatchReproducesItsResult", func(t *testing.T) {
+		second := startLobbyMatch(t, srv, guest, stranger)
+		playOutMatch(t, srv, second, seated(t, srv, second, guest, stranger)...)
+		var matches []domain.Match
+		require.Eventually(t, func() bool {
+			var err error
+			matches, err = srv.Repos.Match.ListByStatus(t.Context(), domain.MatchFinished)
+			return err == nil && len(matches) == 2
+		}, time.Second, 10*time.Millisecond)
+
+		for _, m := range matches {
+			r, err := m.Replay()
+			require.NoError(t, err)
+			final, err := r.Final()
+			require.NoError(t, err)
+			assert.Equal(t, m.Placements, final.Placements, "match %s", m.ID.Hex())
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Simpolette/HeartSteal/server/internal/apitest"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/realtime"
+)
+
+const (
+	queuePath  = "/api/v1/matchmaking/queue"
+	acceptPath = "/api/v1/matchmaking/accept"
+)
+
+type queueTicketBody struct {
+	Data struct {
+		ID      string `json:"id"`
+		Mode    string `json:"mode"`
+		Players []struct {
+			UserID string  `json:"user_id"`
+			Rating float64 `json:"rating"`
+		} `json:"players"`
+		ProposalID string `json:"proposal_id"`
+	} `json:"data"`
+}
+
+type proposalBody struct {
+	Data struct {
+		ID       string   `json:"id"`
+		Accepted []string `json:"accepted"`
+		MatchID  string   `json:"match_id"`
+	} `json:"data"`
+}
+
+func enqueue(t *testing.T, srv *apitest.Server, p player, body map[string]any) queueTicketBody {
+	res := srv.POST(queuePath, body, p.token)
+	require.Equal(t, http.StatusCreated, res.Code, res.Body.String())
+	var ticket queueTicketBody
+	res.JSON(&ticket)
+	return ticket
+}
+
+// befriend adds friend to p's friends list. A party needs it both ways.
+func befriend(t *testing.T, srv *apitest.Server, p player, friend player) {
+	user, err := srv.Repos.User.GetByID(t.Context(), p.id)
+	require.NoError(t, err)
+	friendID, err := primitive.ObjectIDFromHex(friend.id)
+	require.NoError(t, err)
+	user.FriendsList = append(user.FriendsList, friendID)
+	require.NoError(t, srv.Repos.User.Update(t.Context(), user))
+}
+
+func TestMatchmakingHandler_Queue(t *testing.T) {
+	srv := apitest.New(t)
+	alice, bob := newPlayer(t, srv, "alice"), newPlayer(t, srv, "bob")
+
+	ws, _, err := connect(t, srv, issueTicket(t, srv, alice), nil)
+	require.NoError(t, err)
+	require.NoError(t, ws.WriteJSON(realtime.Envelope{Type: realtime.TypeSubscribe, ID: "1", Topic: domain.UserTopic(alice.id)}))
+	require.Equal(t, realtime.TypeSubscribed, readEnvelope(t, ws).Type)
+
+	ticket := enqueue(t, srv, alice, map[string]any{"mode": "duel"})
+	require.Len(t, ticket.Data.Players, 1)
+	assert.Empty(t, ticket.Data.ProposalID)
+	assert.Equal(t, domain.EventMatchmakingQueued, readEnvelope(t, ws).Event)
+
+	t.Run("Current", func(t *testing.T) {
+		res := srv.GET(queuePath, alice.token)
+
+		require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+		var current queueTicketBody
+		res.JSON(&current)
+		assert.Equal(t, ticket.Data.ID, current.Data.ID)
+	})
+
+	t.Run("AlreadyQueued", func(t *testing.T) {
+		res := srv.POST(queuePath, map[string]any{"mode": "table"}, alice.token)
+
+		assert.Equal(t, http.StatusConflict, res.Code)
+		assert.Equal(t, domain.CodeAlreadyQueued, errorCode(res))
+	})
+
+	t.Run("UnknownMode", func(t *testing.T) {
+		res := srv.POST(queuePath, map[string]any{"mode": "solo"}, bob.token)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, domain.CodeInvalidRequest, errorCode(res))
+	})
+
+	t.Run("MatchFoundAndAccepted", func(t *testing.T) {
+		second := enqueue(t, srv, bob, map[string]any{"mode": "duel"})
+		require.NotEmpty(t, second.Data.ProposalID)
+
+		found := readEnvelope(t, ws)
+		assert.Equal(t, domain.EventMatchmakingFound, found.Event)
+		var event domain.MatchmakingEvent
+		require.NoError(t, json.Unmarshal(found.Data, &event))
+		assert.Equal(t, second.Data.ProposalID, event.ProposalID)
+		assert.NotNil(t, event.Deadline)
+
+		res := srv.POST(acceptPath, nil, alice.token)
+		require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+		var proposal proposalBody
+		res.JSON(&proposal)
+		assert.Equal(t, []string{alice.id}, proposal.Data.Accepted)
+		assert.Empty(t, proposal.Data.MatchID)
+
+		res = srv.POST(acceptPath, nil, bob.token)
+		require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+		res.JSON(&proposal)
+		require.NotEmpty(t, proposal.Data.MatchID)
+
+		match := getMatch(t, srv, proposal.Data.MatchID, alice)
+		assert.Empty(t, match.Data.LobbyID)
+		assert.Len(t, match.Data.Players, 2)
+
+		res = srv.GET(queuePath, alice.token)
+		assert.Equal(t, http.StatusNotFound, res.Code)
+		assert.Equal(t, domain.CodeNotQueued, errorCode(res))
+	})
+}
+
+func TestMatchmakingHandler_Party(t *testing.T) {
+	srv := apitest.New(t)
+	alice, bob := newPlayer(t, srv, "alice"), newPlayer(t, srv, "bob")
+
+	t.Run("OnlyFriends", func(t *testing.T) {
+		res := srv.POST(queuePath, map[string]any{"mode": "table", "party": []string{bob.id}}, alice.token)
+
+		assert.Equal(t, http.StatusForbidden, res.Code)
+		assert.Equal(t, domain.CodePartyNotFriends, errorCode(res))
+	})
+
+	t.Run("OnlyMutualFriends", func(t *testing.T) {
+		befriend(t, srv, alice, bob)
+
+		res := srv.POST(queuePath, map[string]any{"mode": "table", "party": []string{bob.id}}, alice.token)
+
+		assert.Equal(t, http.StatusForbidden, res.Code, "bob hasn't befriended alice back")
+		assert.Equal(t, domain.CodePartyNotFriends, errorCode(res))
+	})
+
+	t.Run("QueueAndCancelTogether", func(t *testing.T) {
+		befriend(t, srv, bob, alice)
+
+		ticket := enqueue(t, srv, alice, map[string]any{"mode": "table", "party": []string{bob.id}})
+		require.Len(t, ticket.Data.Players, 2)
+
+		res := srv.DELETE(queuePath, bob.token)
+		require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+
+		res = srv.GET(queuePath, alice.token)
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+
+	t.Run("TooLarge", func(t *testing.T) {
+		carol := newPlayer(t, srv, "carol")
+		befriend(t, srv, alice, carol)
+		befriend(t, srv, carol, alice)
+
+		res := srv.POST(queuePath, map[string]any{"mode": "duel", "party": []string{bob.id, carol.id}}, alice.token)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, domain.CodePartyTooLarge, errorCode(res))
+	})
+}
+
+func TestMatchmakingHandler_Accept(t *testing.T) {
+	srv := apitest.New(t)
+	alice := newPlayer(t, srv, "alice")
+	enqueue(t, srv, alice, map[string]any{"mode": "duel"})
+
+	res := srv.POST(acceptPath, nil, alice.token)
+
+	assert.Equal(t, http.StatusNotFound, res.Code)
+	assert.Equal(t, domain.CodeNoPendingMatch, errorCode(res))
+}
@@ -0,0 +1,158 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Simpolette/HeartSteal/server/internal/apitest"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/realtime"
+)
+
+const (
+	realtimePath = "/api/v1/realtime"
+	ticketsPath  = realtimePath + "/tickets"
+)
+
+func issueTicket(t *testing.T, srv *apitest.Server, p player) string {
+	res := srv.POST(ticketsPath, nil, p.token)
+	require.Equal(t, http.StatusCreated, res.Code, res.Body.String())
+	var body struct {
+		Data struct {
+			Ticket    string    `json:"ticket"`
+			ExpiresAt time.Time `json:"expires_at"`
+		} `json:"data"`
+	}
+	res.JSON(&body)
+	require.NotEmpty(t, body.Data.Ticket)
+	return body.Data.Ticket
+}
+
+// connect opens the WebSocket on a real listener, since the upgrade needs to
+// hijack the connection.
+func connect(t *testing.T, srv *apitest.Server, ticket string, header http.Header) (*websocket.Conn, *http.Response, error) {
+	httpSrv := httptest.NewServer(srv.Engine)
+	t.Cleanup(httpSrv.Close)
+	url := "ws" + strings.TrimPrefix(httpSrv.URL, "http") + realtimePath + "?ticket=" + ticket
+	ws, res, err := websocket.DefaultDialer.Dial(url, header)
+	if err == nil {
+		t.Cleanup(func() { _ = ws.Close() })
+	}
+	return ws, res, err
+}
+
+func readEnvelope(t *testing.T, ws *websocket.Conn) realtime.Envelope {
+	require.NoError(t, ws.SetReadDeadline(time.Now().Add(2*time.Second)))
+	var env realtime.Envelope
+	require.NoError(t, ws.ReadJSON(&env))
+	return env
+}
+
+func TestRealtimeHandler_IssueTicket(t *testing.T) {
+	srv := apitest.New(t)
+
+	t.Run("RequiresToken", func(t *testing.T) {
+		res := srv.POST(ticketsPath, nil)
+
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+	})
+
+	t.Run("BannedUser", func(t *testing.T) {
+		bannedAt := time.Now()
+		user := &domain.User{Username: "banned", Email: "banned@example.com", BannedAt: &bannedAt}
+		require.NoError(t, srv.Repos.User.Create(t.Context(), user))
+
+		res := srv.POST(ticketsPath, nil, apitest.WithToken(srv.AccessToken(user.ID.Hex())))
+
+		assert.Equal(t, http.StatusForbidden, res.Code)
+		assert.Equal(t, domain.CodeUserBanned, errorCode(res))
+	})
+}
+
+func TestRealtimeHandler_Connect(t *testing.T) {
+	srv := apitest.New(t)
+	alice := newPlayer(t, srv, "alice")
+
+	t.Run("RequiresUpgrade", func(t *testing.T) {
+		res := srv.GET(realtimePath + "?ticket=" + issueTicket(t, srv, alice))
+
+		assert.Equal(t, http.StatusUpgradeRequired, res.Code)
+		assert.Equal(t, domain.CodeUpgradeRequired, errorCode(res))
+	})
+
+	t.Run("InvalidTicket", func(t *testing.T) {
+		_, res, err := connect(t, srv, "forged", nil)
+
+		require.ErrorIs(t, err, websocket.ErrBadHandshake)
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	})
+
+	t.Run("TicketIsSingleUse", func(t *testing.T) {
+		ticket := issueTicket(t, srv, alice)
+
+		_, _, err := connect(t, srv, ticket, nil)
+		require.NoError(t, err)
+		_, res, err := connect(t, srv, ticket, nil)
+
+		require.ErrorIs(t, err, websocket.ErrBadHandshake)
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	})
+
+	t.Run("DisallowedOrigin", func(t *testing.T) {
+		header := http.Header{"Origin": {"https://evil.example"}}
+
+		_, res, err := connect(t, srv, issueTicket(t, srv, alice), header)
+
+		require.ErrorIs(t, err, websocket.ErrBadHandshake)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+	})
+
+	t.Run("AllowedOrigin", func(t *testing.T) {
+		header := http.Header{"Origin": {"http://localhost:3000"}}
+
+		_, _, err := connect(t, srv, issueTicket(t, srv, alice), header)
+
+		require.NoError(t, err)
+	})
+}
+
+func TestRealtimeHandler_LobbyEvents(t *testing.T) {
+	srv := apitest.New(t)
+	host, guest, stranger := newPlayer(t, srv, "host"), newPlayer(t, srv, "guest"), newPlayer(t, srv, "stranger")
+	lobby := createLobby(t, srv, host, map[string]any{"name": "Friday night", "capacity": 4})
+	topic := domain.LobbyTopic(lobby.Data.ID)
+
+	hostWS, _, err := connect(t, srv, issueTicket(t, srv, host), nil)
+	require.NoError(t, err)
+	require.NoError(t, hostWS.WriteJSON(realtime.Envelope{Type: realtime.TypeSubscribe, ID: "1", Topic: topic}))
+	require.Equal(t, realtime.TypeSubscribed, readEnvelope(t, hostWS).Type)
+
+	t.Run("MembersOnly", func(t *testing.T) {
+		ws, _, err := connect(t, srv, issueTicket(t, srv, stranger), nil)
+		require.NoError(t, err)
+
+		require.NoError(t, ws.WriteJSON(realtime.Envelope{Type: realtime.TypeSubscribe, ID: "1", Topic: topic}))
+
+		reply := readEnvelope(t, ws)
+		assert.Equal(t, realtime.TypeError, reply.Type)
+		assert.Equal(t, domain.CodeTopicForbidden, reply.Error.Code)
+	})
+
+	t.Run("JoinIsPushed", func(t *testing.T) {
+		res := srv.POST(lobbiesPath+"/"+lobby.Data.ID+"/join", nil, guest.token)
+		require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+
+		event := readEnvelope(t, hostWS)
+		assert.Equal(t, realtime.TypeEvent, event.Type)
+		assert.Equal(t, topic, event.Topic)
+		assert.Equal(t, domain.EventLobbyUpdated, event.Event)
+		assert.Contains(t, string(event.Data), guest.id)
+	})
+}
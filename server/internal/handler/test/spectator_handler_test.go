@@ -0,0 +1,165 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Simpolette/HeartSteal/server/internal/apitest"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/game"
+	"github.com/Simpolette/HeartSteal/server/internal/realtime"
+)
+
+const spectatorPolicyPath = "/api/v1/users/me/spectator-policy"
+
+type spectatedBody struct {
+	Data struct {
+		Match        matchBodyData `json:"match"`
+		Spectators   int           `json:"spectators"`
+		DelaySeconds int           `json:"delay_seconds"`
+	} `json:"data"`
+}
+
+type matchBodyData struct {
+	ID     string     `json:"id"`
+	Status string     `json:"status"`
+	State  *game.View `json:"state"`
+}
+
+func setSpectatorPolicy(t *testing.T, srv *apitest.Server, p player, policy domain.SpectatorPolicy) {
+	res := srv.PUT(spectatorPolicyPath, map[string]any{"policy": policy}, p.token)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	var body struct {
+		Data struct {
+			Policy domain.SpectatorPolicy `json:"policy"`
+		} `json:"data"`
+	}
+	res.JSON(&body)
+	require.Equal(t, policy, body.Data.Policy)
+}
+
+func spectate(t *testing.T, srv *apitest.Server, id string, p player) spectatedBody {
+	res := srv.GET(matchesPath+"/"+id+"/spectate", p.token)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	var body spectatedBody
+	res.JSON(&body)
+	return body
+}
+
+func TestSpectatorHandler_Watch(t *testing.T) {
+	srv := apitest.New(t)
+	host, guest, fan := newPlayer(t, srv, "host"), newPlayer(t, srv, "guest"), newPlayer(t, srv, "fan")
+	id := startLobbyMatch(t, srv, host, guest)
+	spectatePath := matchesPath + "/" + id + "/spectate"
+
+	t.Run("FriendsOnlyByDefault", func(t *testing.T) {
+		res := srv.GET(spectatePath, fan.token)
+
+		assert.Equal(t, http.StatusForbidden, res.Code)
+		assert.Equal(t, domain.CodeSpectatingForbidden, errorCode(res))
+	})
+
+	setSpectatorPolicy(t, srv, host, domain.SpectatePublic)
+	setSpectatorPolicy(t, srv, guest, domain.SpectatePublic)
+	ws, _, err := connect(t, srv, issueTicket(t, srv, fan), nil)
+	require.NoError(t, err)
+	topic := domain.SpectateTopic(id)
+
+	t.Run("HandsStayHidden", func(t *testing.T) {
+		body := spectate(t, srv, id, fan)
+
+		assert.Equal(t, id, body.Data.Match.ID)
+		assert.Zero(t, body.Data.DelaySeconds, "lobby matches aren't ranked")
+		require.NotNil(t, body.Data.Match.State)
+		assert.Equal(t, -1, body.Data.Match.State.Seat)
+		for _, p := range body.Data.Match.State.Players {
+			assert.Empty(t, p.Hand)
+			assert.Equal(t, game.DefaultHandSize, p.HandCount)
+		}
+	})
+
+	t.Run("FollowsPublicEvents", func(t *testing.T) {
+		require.NoError(t, ws.WriteJSON(realtime.Envelope{Type: realtime.TypeSubscribe, ID: "1", Topic: topic}))
+		require.Equal(t, realtime.TypeSubscribed, readEnvelope(t, ws).Type)
+		assert.Equal(t, 1, spectate(t, srv, id, fan).Data.Spectators)
+
+		state := getMatch(t, srv, id, host).Data.State
+		mover := []player{host, guest}[state.Turn]
+		card := getMatch(t, srv, id, mover).Data.State.Players[state.Turn].Hand[0]
+		res := srv.POST(matchesPath+"/"+id+"/actions", map[string]any{"card": card.ID, "target": 1 - state.Turn}, mover.token)
+		require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+
+		event := readEnvelope(t, ws)
+		assert.Equal(t, topic, event.Topic)
+		assert.Equal(t, domain.EventMatchUpdated, event.Event)
+		var update domain.MatchUpdate
+		require.NoError(t, json.Unmarshal(event.Data, &update))
+		assert.Equal(t, game.EventCardPlayed, update.Events[0].Type)
+		assert.Equal(t, 1, update.Spectators)
+		for _, e := range update.Events {
+			if e.Type == game.EventCardDrawn {
+				assert.Nil(t, e.Card, "spectators don't see drawn cards")
+			}
+		}
+	})
+
+	t.Run("PlayerTurningItOffDropsSpectators", func(t *testing.T) {
+		setSpectatorPolicy(t, srv, guest, domain.SpectateOff)
+
+		reply := readEnvelope(t, ws)
+		assert.Equal(t, realtime.TypeUnsubscribed, reply.Type)
+		assert.Equal(t, topic, reply.Topic)
+
+		res := srv.GET(spectatePath, fan.token)
+		assert.Equal(t, http.StatusForbidden, res.Code)
+		assert.Equal(t, domain.CodeSpectatingForbidden, errorCode(res))
+
+		require.NoError(t, ws.WriteJSON(realtime.Envelope{Type: realtime.TypeSubscribe, ID: "2", Topic: topic}))
+		resubscribe := readEnvelope(t, ws)
+		assert.Equal(t, realtime.TypeError, resubscribe.Type)
+		assert.Equal(t, domain.CodeTopicForbidden, resubscribe.Error.Code)
+	})
+
+	t.Run("PlayersMayWatchTheirOwn", func(t *testing.T) {
+		body := spectate(t, srv, id, guest)
+
+		assert.Equal(t, -1, body.Data.Match.State.Seat)
+	})
+
+	t.Run("UnknownMatch", func(t *testing.T) {
+		res := srv.GET(matchesPath+"/"+"000000000000000000000000/spectate", fan.token)
+
+		assert.Equal(t, http.StatusNotFound, res.Code)
+		assert.Equal(t, domain.CodeMatchNotFound, errorCode(res))
+	})
+}
+
+func TestSpectatorHandler_SetPolicy(t *testing.T) {
+	srv := apitest.New(t)
+	alice := newPlayer(t, srv, "alice")
+
+	t.Run("Saved", func(t *testing.T) {
+		setSpectatorPolicy(t, srv, alice, domain.SpectatePublic)
+
+		user, err := srv.Repos.User.GetByID(t.Context(), alice.id)
+		require.NoError(t, err)
+		assert.Equal(t, domain.SpectatePublic, user.SpectatorPolicy)
+	})
+
+	t.Run("UnknownPolicy", func(t *testing.T) {
+		res := srv.PUT(spectatorPolicyPath, map[string]any{"policy": "everyone"}, alice.token)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+		assert.Equal(t, domain.CodeInvalidRequest, errorCode(res))
+	})
+
+	t.Run("RequiresAuth", func(t *testing.T) {
+		res := srv.PUT(spectatorPolicyPath, map[string]any{"policy": "off"})
+
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+	})
+}
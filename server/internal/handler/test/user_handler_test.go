@@ -0,0 +1,189 @@
+package handler_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Simpolette/HeartSteal/server/internal/apitest"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/usecase"
+	tokenutil "github.com/Simpolette/HeartSteal/server/utils"
+)
+
+const (
+	signupPath = "/api/v1/auth/signup"
+	loginPath  = "/api/v1/auth/login"
+)
+
+func signupBody(username string) map[string]any {
+	return map[string]any{
+		"username": username,
+		"email":    username + "@example.com",
+		"password": "strongPassword123",
+	}
+}
+
+func TestUserHandler_Signup(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		srv := apitest.New(t)
+
+		res := srv.POST(signupPath, map[string]any{
+			"username":     "johndoe",
+			"display_name": "John Doe",
+			"locale":       "vi-VN",
+			"email":        "john@example.com",
+			"password":     "strongPassword123",
+		})
+
+		require.Equal(t, http.StatusCreated, res.Code, res.Body.String())
+		user, err := srv.Repos.User.GetByUsername(t.Context(), "johndoe")
+		require.NoError(t, err)
+		assert.Equal(t, "John Doe", user.DisplayName)
+		assert.Equal(t, "vi", user.Locale)
+		assert.NotEqual(t, "strongPassword123", user.Password, "passwords are stored hashed")
+	})
+
+	t.Run("ValidationErrors", func(t *testing.T) {
+		srv := apitest.New(t)
+
+		res := srv.POST(signupPath, map[string]any{"username": "admin", "email": "nope", "password": "short"})
+
+		require.Equal(t, http.StatusBadRequest, res.Code)
+		var body domain.ErrorResponse
+		res.JSON(&body)
+		assert.Equal(t, domain.CodeInvalidRequest, body.Code)
+		fields := map[string]string{}
+		for _, d := range body.Details {
+			fields[d.Field] = d.Rule
+		}
+		assert.Equal(t, map[string]string{"username": "username", "email": "email", "password": "min"}, fields)
+	})
+
+	t.Run("MalformedJSON", func(t *testing.T) {
+		srv := apitest.New(t)
+
+		res := srv.POST(signupPath, `{"username":`)
+
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+
+	// Bind and validation failures are the client's fault, so they must not
+	// be counted as internal errors.
+	t.Run("MetricsLabels", func(t *testing.T) {
+		srv := apitest.New(t)
+
+		srv.POST(signupPath, signupBody("johndoe"))
+		srv.POST(signupPath, map[string]any{"username": "admin", "email": "nope", "password": "short"})
+		srv.POST(signupPath, `{"username":`)
+
+		expected := `
+# HELP heartsteal_auth_signups_total Number of signup attempts by result.
+# TYPE heartsteal_auth_signups_total counter
+heartsteal_auth_signups_total{result="invalid_request"} 2
+heartsteal_auth_signups_total{result="success"} 1
+`
+		assert.NoError(t, testutil.CollectAndCompare(srv.App.Metrics.Registry, strings.NewReader(expected), "heartsteal_auth_signups_total"))
+	})
+
+	t.Run("Duplicates", func(t *testing.T) {
+		srv := apitest.New(t)
+		require.Equal(t, http.StatusCreated, srv.POST(signupPath, signupBody("johndoe")).Code)
+
+		sameEmail := signupBody("janedoe")
+		sameEmail["email"] = "johndoe@example.com"
+		res := srv.POST(signupPath, sameEmail)
+		var body domain.ErrorResponse
+		res.JSON(&body)
+		assert.Equal(t, http.StatusConflict, res.Code)
+		assert.Equal(t, domain.CodeEmailExists, body.Code)
+
+		sameUsername := signupBody("johndoe")
+		sameUsername["email"] = "other@example.com"
+		res = srv.POST(signupPath, sameUsername)
+		res.JSON(&body)
+		assert.Equal(t, http.StatusConflict, res.Code)
+		assert.Equal(t, domain.CodeUsernameExists, body.Code)
+	})
+
+	t.Run("RateLimited", func(t *testing.T) {
+		srv := apitest.New(t)
+
+		var res *apitest.Response
+		for i := 0; i < 6; i++ {
+			res = srv.POST(signupPath, map[string]any{})
+		}
+
+		assert.Equal(t, http.StatusTooManyRequests, res.Code)
+		assert.NotEmpty(t, res.Header().Get("Retry-After"))
+	})
+}
+
+func TestUserHandler_Login(t *testing.T) {
+	srv := apitest.New(t, apitest.WithoutRateLimit())
+	require.Equal(t, http.StatusCreated, srv.POST(signupPath, signupBody("johndoe")).Code)
+
+	t.Run("Success", func(t *testing.T) {
+		res := srv.POST(loginPath, map[string]any{"username": "johndoe", "password": "strongPassword123"})
+
+		require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+		var body struct {
+			Data struct {
+				AccessToken string `json:"accessToken"`
+			} `json:"data"`
+		}
+		res.JSON(&body)
+		user, err := srv.Repos.User.GetByUsername(t.Context(), "johndoe")
+		require.NoError(t, err)
+		id, err := tokenutil.ExtractIDFromToken(body.Data.AccessToken, apitest.TokenSecret)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID.Hex(), id)
+
+		sessions, err := srv.Repos.Session.ListActiveByUser(t.Context(), user.ID, time.Now())
+		require.NoError(t, err)
+		assert.NotEmpty(t, sessions)
+	})
+
+	t.Run("WrongPassword", func(t *testing.T) {
+		res := srv.POST(loginPath, map[string]any{"username": "johndoe", "password": "wrongPassword1"})
+
+		var body domain.ErrorResponse
+		res.JSON(&body)
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+		assert.Equal(t, domain.CodeInvalidCredentials, body.Code)
+	})
+
+	t.Run("UnknownUser", func(t *testing.T) {
+		res := srv.POST(loginPath, map[string]any{"username": "nobody", "password": "strongPassword123"})
+
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+	})
+
+	t.Run("Banned", func(t *testing.T) {
+		require.Equal(t, http.StatusCreated, srv.POST(signupPath, signupBody("cheater")).Code)
+		admin := usecase.NewAdminUseCase(srv.Repos.User, srv.Repos.Session, time.Second)
+		_, err := admin.Ban(t.Context(), "cheater", "cheating")
+		require.NoError(t, err)
+
+		res := srv.POST(loginPath, map[string]any{"username": "cheater", "password": "strongPassword123"})
+
+		var body domain.ErrorResponse
+		res.JSON(&body)
+		assert.Equal(t, http.StatusForbidden, res.Code)
+		assert.Equal(t, domain.CodeUserBanned, body.Code)
+	})
+
+	t.Run("LegacyAlias", func(t *testing.T) {
+		res := srv.POST("/api/login", map[string]any{"username": "johndoe", "password": "strongPassword123"})
+
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.NotEmpty(t, res.Header().Get("Deprecation"))
+		assert.NotEmpty(t, res.Header().Get("Sunset"))
+		assert.Equal(t, `</api/v1/auth/login>; rel="successor-version"`, res.Header().Get("Link"))
+	})
+}
@@ -5,12 +5,17 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/i18n"
+	"github.com/Simpolette/HeartSteal/server/internal/metrics"
+	"github.com/Simpolette/HeartSteal/server/internal/openapi"
 )
 
 type signupRequest struct {
-	Username    string `json:"username"     binding:"required"`
+	Username    string `json:"username"     binding:"required,username"`
+	DisplayName string `json:"display_name" binding:"omitempty,displayname"`
+	Locale      string `json:"locale"       binding:"omitempty,locale"`
 	Email    	string `json:"email"        binding:"required,email"`
-	Password 	string `json:"password"     binding:"required,min=8"` // #nosec G117
+	Password 	string `json:"password"     binding:"required,min=8,max=72"` // #nosec G117
 }
 
 type loginRequest struct {
@@ -18,65 +23,88 @@ type loginRequest struct {
 	Password 	string `json:"password"     binding:"required"` // #nosec G117
 }
 
+type loginResponse struct {
+	AccessToken string `json:"accessToken"`
+}
+
+var SignupOperation = openapi.Operation{
+	Summary:   "Register a user",
+	Tags:      []string{"auth"},
+	Request:   signupRequest{},
+	Responses: []openapi.Response{{Status: http.StatusCreated, Body: domain.SuccessResponse{}}},
+	Errors:    []int{http.StatusBadRequest, http.StatusConflict, http.StatusTooManyRequests},
+}
+
+var LoginOperation = openapi.Operation{
+	Summary:   "Exchange credentials for an access token",
+	Tags:      []string{"auth"},
+	Request:   loginRequest{},
+	Responses: []openapi.Response{{Status: http.StatusOK, Body: domain.SuccessResponse{}, Data: loginResponse{}}},
+	Errors:    []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests},
+}
+
 type UserHandler struct {
 	UserUseCase domain.UserUsecase
+	Metrics     *metrics.Metrics
 }
 
-func NewUserHandler(usecase domain.UserUsecase) *UserHandler {
+func NewUserHandler(usecase domain.UserUsecase, m *metrics.Metrics) *UserHandler {
 	return &UserHandler{
 		UserUseCase: usecase,
+		Metrics:     m,
 	}
 }
 
 func (h *UserHandler) Signup(c *gin.Context) {
 	var req signupRequest
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+	if err := bindJSON(c, &req); err != nil {
+		h.Metrics.Signup(resultLabel(err))
+		_ = c.Error(err)
 		return
 	}
 
+	if req.DisplayName == "" {
+		req.DisplayName = req.Username
+	}
+	locale, _ := i18n.Default().Supported(req.Locale)
+
 	user := &domain.User{
 		Username:     	req.Username,
+		DisplayName:  	req.DisplayName,
+		Locale:       	locale,
 		Email:    		req.Email,
 		Password: 		req.Password,
 	}
 
 	err := h.UserUseCase.Register(c.Request.Context(), user)
+	h.Metrics.Signup(resultLabel(err))
 	if err != nil {
-		if err == domain.ErrEmailExists {
-			c.JSON(http.StatusConflict, domain.ErrorResponse{Message: "Email already existed"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, domain.SuccessResponse{Message: "User registered successfully"})
+	c.JSON(http.StatusCreated, domain.SuccessResponse{Message: i18n.T(c.Request.Context(), "success.user_registered", nil)})
 }
 
 func (h *UserHandler) Login(c *gin.Context) {
 	var req loginRequest
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+	if err := bindJSON(c, &req); err != nil {
+		h.Metrics.Login(resultLabel(err))
+		_ = c.Error(err)
 		return
 	}
 
 	accessToken, err := h.UserUseCase.Login(c.Request.Context(), req.Username, req.Password)
+	h.Metrics.Login(resultLabel(err))
 	if err != nil {
-		if err == domain.ErrInvalidCredentials {
-			c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Message: "Invalid email or password"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, domain.SuccessResponse{
-		Message: "Login successfully",
-		Data: gin.H{
-			"accessToken": accessToken,
-		},
+		Message: i18n.T(c.Request.Context(), "success.logged_in", nil),
+		Data:    loginResponse{AccessToken: accessToken},
 	})
-}
\ No newline at end of file
+}
@@ -0,0 +1,192 @@
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+const (
+	DefaultCheckTimeout = 2 * time.Second
+	DefaultCacheTTL     = 5 * time.Second
+)
+
+// CheckFunc reports whether a dependency is usable. A nil error means healthy.
+type CheckFunc func(ctx context.Context) error
+
+type Result struct {
+	Name      string    `json:"name"`
+	Status    Status    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Duration  string    `json:"duration"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+type Report struct {
+	Status Status   `json:"status"`
+	Checks []Result `json:"checks,omitempty"`
+}
+
+type check struct {
+	name    string
+	timeout time.Duration
+	fn      CheckFunc
+
+	mu      sync.Mutex
+	last    Result
+	expires time.Time
+}
+
+// Registry holds the readiness checks of every subsystem. Results are cached
+// for cacheTTL so that aggressive probing does not hammer the dependencies.
+type Registry struct {
+	mu           sync.RWMutex
+	checks       map[string]*check
+	cacheTTL     atomic.Int64
+	shuttingDown atomic.Bool
+	now          func() time.Time
+}
+
+func NewRegistry(cacheTTL time.Duration) *Registry {
+	r := &Registry{
+		checks: make(map[string]*check),
+		now:    time.Now,
+	}
+	r.SetCacheTTL(cacheTTL)
+	return r
+}
+
+// SetCacheTTL changes how long check results are reused. Safe to call while
+// probes are being served.
+func (r *Registry) SetCacheTTL(ttl time.Duration) {
+	if ttl < 0 {
+		ttl = 0
+	}
+	r.cacheTTL.Store(int64(ttl))
+}
+
+// Register adds (or replaces) a named readiness check. A non-positive timeout
+// falls back to DefaultCheckTimeout.
+func (r *Registry) Register(name string, timeout time.Duration, fn CheckFunc) {
+	if timeout <= 0 {
+		timeout = DefaultCheckTimeout
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks[name] = &check{name: name, timeout: timeout, fn: fn}
+}
+
+// SetShuttingDown makes every subsequent readiness report fail so that load
+// balancers stop routing traffic while in-flight requests drain.
+func (r *Registry) SetShuttingDown() {
+	r.shuttingDown.Store(true)
+}
+
+func (r *Registry) IsShuttingDown() bool {
+	return r.shuttingDown.Load()
+}
+
+// Liveness only tells whether the process is able to serve HTTP at all.
+func (r *Registry) Liveness() Report {
+	return Report{Status: StatusUp}
+}
+
+// Readiness runs every registered check concurrently and aggregates them.
+func (r *Registry) Readiness(ctx context.Context) Report {
+	r.mu.RLock()
+	checks := make([]*check, 0, len(r.checks))
+	for _, c := range r.checks {
+		checks = append(checks, c)
+	}
+	r.mu.RUnlock()
+
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c *check) {
+			defer wg.Done()
+			results[i] = r.run(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+
+	report := Report{Status: StatusUp, Checks: results}
+	for _, res := range results {
+		if res.Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+
+	if r.IsShuttingDown() {
+		report.Status = StatusDown
+		report.Checks = append(report.Checks, Result{
+			Name:      "shutdown",
+			Status:    StatusDown,
+			Error:     "server is shutting down",
+			Duration:  "0s",
+			CheckedAt: r.now(),
+		})
+	}
+
+	return report
+}
+
+func (r *Registry) run(ctx context.Context, c *check) Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := r.now()
+	ttl := time.Duration(r.cacheTTL.Load())
+	if ttl > 0 && now.Before(c.expires) {
+		return c.last
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := runWithTimeout(ctx, c.fn)
+
+	res := Result{
+		Name:      c.name,
+		Status:    StatusUp,
+		Duration:  time.Since(start).String(),
+		CheckedAt: now,
+	}
+	if err != nil {
+		res.Status = StatusDown
+		res.Error = err.Error()
+	}
+
+	c.last = res
+	c.expires = now.Add(ttl)
+	return res
+}
+
+// runWithTimeout guards against checks that ignore their context.
+func runWithTimeout(ctx context.Context, fn CheckFunc) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,76 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Simpolette/HeartSteal/server/internal/health"
+)
+
+func TestRegistry_Readiness(t *testing.T) {
+	t.Run("AllUp", func(t *testing.T) {
+		r := health.NewRegistry(0)
+		r.Register("mongo", time.Second, func(ctx context.Context) error { return nil })
+
+		report := r.Readiness(context.Background())
+
+		assert.Equal(t, health.StatusUp, report.Status)
+		assert.Len(t, report.Checks, 1)
+		assert.Equal(t, "mongo", report.Checks[0].Name)
+	})
+
+	t.Run("FailingCheck", func(t *testing.T) {
+		r := health.NewRegistry(0)
+		r.Register("mongo", time.Second, func(ctx context.Context) error { return nil })
+		r.Register("cache", time.Second, func(ctx context.Context) error { return errors.New("unreachable") })
+
+		report := r.Readiness(context.Background())
+
+		assert.Equal(t, health.StatusDown, report.Status)
+		assert.Equal(t, "cache", report.Checks[0].Name)
+		assert.Equal(t, "unreachable", report.Checks[0].Error)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		r := health.NewRegistry(0)
+		r.Register("slow", 10*time.Millisecond, func(ctx context.Context) error {
+			time.Sleep(time.Second)
+			return nil
+		})
+
+		start := time.Now()
+		report := r.Readiness(context.Background())
+
+		assert.Equal(t, health.StatusDown, report.Status)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+
+	t.Run("CachedResult", func(t *testing.T) {
+		r := health.NewRegistry(time.Minute)
+		calls := 0
+		r.Register("mongo", time.Second, func(ctx context.Context) error {
+			calls++
+			return nil
+		})
+
+		r.Readiness(context.Background())
+		r.Readiness(context.Background())
+
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("ShuttingDown", func(t *testing.T) {
+		r := health.NewRegistry(0)
+		r.Register("mongo", time.Second, func(ctx context.Context) error { return nil })
+
+		r.SetShuttingDown()
+		report := r.Readiness(context.Background())
+
+		assert.Equal(t, health.StatusDown, report.Status)
+		assert.Equal(t, health.StatusUp, r.Liveness().Status)
+	})
+}
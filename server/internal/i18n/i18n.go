@@ -0,0 +1,242 @@
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+// DefaultLocale is used when negotiation fails and as the fallback catalog for
+// keys missing from another locale.
+const DefaultLocale = "en"
+
+//go:embed locales/*.json
+var localeFS embed.FS
+
+// Params are substituted into {name} placeholders. The "count" param also
+// selects the plural form.
+type Params map[string]any
+
+// message is either a plain string or a set of CLDR plural forms
+// ("zero", "one", "two", "few", "many", "other").
+type message struct {
+	text   string
+	plural map[string]string
+}
+
+func (m *message) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, &m.text); err == nil {
+		return nil
+	}
+	if err := json.Unmarshal(b, &m.plural); err != nil {
+		return err
+	}
+	if _, ok := m.plural["other"]; !ok {
+		return fmt.Errorf("plural message is missing the \"other\" form")
+	}
+	return nil
+}
+
+type Bundle struct {
+	catalogs map[string]map[string]message
+	tags     []language.Tag
+	matcher  language.Matcher
+}
+
+var (
+	defaultBundle *Bundle
+	loadOnce      sync.Once
+)
+
+// Default returns the bundle built from the embedded catalogs.
+func Default() *Bundle {
+	loadOnce.Do(func() {
+		b, err := Load()
+		if err != nil {
+			panic(fmt.Sprintf("i18n: embedded catalogs are invalid: %v", err))
+		}
+		defaultBundle = b
+	})
+	return defaultBundle
+}
+
+// Load parses every embedded locales/<locale>.json catalog.
+func Load() (*Bundle, error) {
+	entries, err := localeFS.ReadDir("locales")
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Bundle{catalogs: make(map[string]map[string]message)}
+	for _, e := range entries {
+		locale := strings.TrimSuffix(e.Name(), ".json")
+		raw, err := localeFS.ReadFile(path.Join("locales", e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		catalog := make(map[string]message)
+		if err := json.Unmarshal(raw, &catalog); err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Name(), err)
+		}
+		b.catalogs[locale] = catalog
+	}
+
+	if _, ok := b.catalogs[DefaultLocale]; !ok {
+		return nil, fmt.Errorf("default locale %q has no catalog", DefaultLocale)
+	}
+
+	// The default locale must come first: the matcher falls back to tags[0].
+	b.tags = []language.Tag{language.Make(DefaultLocale)}
+	for _, locale := range b.Locales() {
+		if locale != DefaultLocale {
+			b.tags = append(b.tags, language.Make(locale))
+		}
+	}
+	b.matcher = language.NewMatcher(b.tags)
+
+	return b, nil
+}
+
+// Locales lists the shipped locales in a stable order.
+func (b *Bundle) Locales() []string {
+	locales := make([]string, 0, len(b.catalogs))
+	for locale := range b.catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Keys lists the message keys of a locale's catalog.
+func (b *Bundle) Keys(locale string) []string {
+	keys := make([]string, 0, len(b.catalogs[locale]))
+	for key := range b.catalogs[locale] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Has reports whether locale defines key without falling back.
+func (b *Bundle) Has(locale, key string) bool {
+	_, ok := b.catalogs[locale][key]
+	return ok
+}
+
+// PluralForms returns the plural categories defined for key in locale, or nil
+// for plain messages.
+func (b *Bundle) PluralForms(locale, key string) []string {
+	msg, ok := b.catalogs[locale][key]
+	if !ok || msg.plural == nil {
+		return nil
+	}
+	forms := make([]string, 0, len(msg.plural))
+	for form := range msg.plural {
+		forms = append(forms, form)
+	}
+	sort.Strings(forms)
+	return forms
+}
+
+// Supported returns the shipped locale matching locale exactly, if any.
+func (b *Bundle) Supported(locale string) (string, bool) {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return "", false
+	}
+	base, _ := tag.Base()
+	if _, ok := b.catalogs[base.String()]; ok {
+		return base.String(), true
+	}
+	return "", false
+}
+
+// Negotiate picks the best shipped locale for an Accept-Language header.
+func (b *Bundle) Negotiate(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return DefaultLocale
+	}
+	_, idx, confidence := b.matcher.Match(tags...)
+	if confidence == language.No {
+		return DefaultLocale
+	}
+	base, _ := b.tags[idx].Base()
+	return base.String()
+}
+
+// Translate renders key in locale, falling back to the default locale and
+// finally to the key itself so a missing entry never produces an empty string.
+func (b *Bundle) Translate(locale, key string, params Params) string {
+	msg, ok := b.catalogs[locale][key]
+	if !ok {
+		locale = DefaultLocale
+		msg, ok = b.catalogs[DefaultLocale][key]
+		if !ok {
+			return key
+		}
+	}
+
+	text := msg.text
+	if msg.plural != nil {
+		form := PluralCategory(locale, count(params))
+		if text, ok = msg.plural[form]; !ok {
+			text = msg.plural["other"]
+		}
+	}
+
+	return interpolate(text, params)
+}
+
+func count(params Params) int {
+	switch v := params["count"].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case string:
+		n, _ := strconv.Atoi(v)
+		return n
+	default:
+		return 0
+	}
+}
+
+func interpolate(text string, params Params) string {
+	if len(params) == 0 {
+		return text
+	}
+	pairs := make([]string, 0, len(params)*2)
+	for k, v := range params {
+		pairs = append(pairs, "{"+k+"}", fmt.Sprint(v))
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+type ctxKey struct{}
+
+// WithLocale stores the negotiated locale for the rest of the request.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, locale)
+}
+
+func LocaleFromContext(ctx context.Context) string {
+	if locale, ok := ctx.Value(ctxKey{}).(string); ok {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// T translates key into the request's locale using the default bundle.
+func T(ctx context.Context, key string, params Params) string {
+	return Default().Translate(LocaleFromContext(ctx), key, params)
+}
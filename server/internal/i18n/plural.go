@@ -0,0 +1,36 @@
+package i18n
+
+// PluralCategory returns the CLDR plural category of n for the shipped
+// locales. Only integer counts are needed by our messages.
+func PluralCategory(locale string, n int) string {
+	if n < 0 {
+		n = -n
+	}
+
+	switch locale {
+	case "vi", "ja", "ko", "zh", "th", "id":
+		// No grammatical plural.
+		return "other"
+	case "fr", "pt":
+		if n == 0 || n == 1 {
+			return "one"
+		}
+		return "other"
+	default:
+		// English and most Germanic/Romance languages.
+		if n == 1 {
+			return "one"
+		}
+		return "other"
+	}
+}
+
+// RequiredPluralForms lists the categories a catalog must define for locale.
+func RequiredPluralForms(locale string) []string {
+	switch locale {
+	case "vi", "ja", "ko", "zh", "th", "id":
+		return []string{"other"}
+	default:
+		return []string{"one", "other"}
+	}
+}
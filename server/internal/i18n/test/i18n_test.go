@@ -0,0 +1,80 @@
+package i18n_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/i18n"
+)
+
+// TestCatalogs_Complete fails when any shipped catalog lacks a key defined by
+// the default catalog, an API error code, or a plural form its language needs.
+func TestCatalogs_Complete(t *testing.T) {
+	bundle, err := i18n.Load()
+	require.NoError(t, err)
+
+	required := bundle.Keys(i18n.DefaultLocale)
+	for _, code := range domain.ErrorCodes {
+		required = append(required, "error."+string(code))
+	}
+
+	for _, locale := range bundle.Locales() {
+		t.Run(locale, func(t *testing.T) {
+			for _, key := range required {
+				assert.Truef(t, bundle.Has(locale, key), "catalog %q is missing %q", locale, key)
+
+				if bundle.PluralForms(i18n.DefaultLocale, key) == nil {
+					continue
+				}
+				forms := bundle.PluralForms(locale, key)
+				for _, form := range i18n.RequiredPluralForms(locale) {
+					assert.Containsf(t, forms, form, "catalog %q is missing plural form %q of %q", locale, form, key)
+				}
+			}
+
+			for _, key := range bundle.Keys(locale) {
+				assert.Truef(t, bundle.Has(i18n.DefaultLocale, key), "catalog %q defines unknown key %q", locale, key)
+			}
+		})
+	}
+}
+
+func TestBundle_Negotiate(t *testing.T) {
+	bundle := i18n.Default()
+
+	cases := map[string]string{
+		"":                          "en",
+		"vi-VN,vi;q=0.9,en;q=0.8":   "vi",
+		"fr-CA":                     "fr",
+		"de-DE,fr;q=0.5":            "fr",
+		"ja":                        "en",
+		"en-GB;q=0.2,vi;q=0.7":      "vi",
+		"not a valid header ;;; q=": "en",
+	}
+
+	for header, expected := range cases {
+		assert.Equalf(t, expected, bundle.Negotiate(header), "Accept-Language: %q", header)
+	}
+}
+
+func TestBundle_Translate(t *testing.T) {
+	bundle := i18n.Default()
+
+	t.Run("Plural", func(t *testing.T) {
+		assert.Equal(t, "must be at least 1 character long", bundle.Translate("en", "validation.min.string", i18n.Params{"count": 1}))
+		assert.Equal(t, "must be at least 8 characters long", bundle.Translate("en", "validation.min.string", i18n.Params{"count": 8}))
+		assert.Equal(t, "doit contenir au moins 0 caractère", bundle.Translate("fr", "validation.min.string", i18n.Params{"count": 0}))
+		assert.Equal(t, "phải có ít nhất 8 ký tự", bundle.Translate("vi", "validation.min.string", i18n.Params{"count": 8}))
+	})
+
+	t.Run("FallbackToDefaultLocale", func(t *testing.T) {
+		assert.Equal(t, "Email already existed", bundle.Translate("xx", "error.EMAIL_EXISTS", nil))
+	})
+
+	t.Run("FallbackToKey", func(t *testing.T) {
+		assert.Equal(t, "no.such.key", bundle.Translate("en", "no.such.key", nil))
+	})
+}
@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are attribute keys whose values never reach the log output,
+// regardless of which group they are nested in.
+var sensitiveKeys = map[string]struct{}{
+	"authorization": {},
+	"password":      {},
+	"accesstoken":   {},
+	"access_token":  {},
+	"refreshtoken":  {},
+	"refresh_token": {},
+	"cookie":        {},
+	"set-cookie":    {},
+}
+
+type ctxKey struct{}
+
+// New builds a logger writing to w. Unknown levels fall back to info and
+// unknown formats fall back to JSON.
+func New(level string, format string, w io.Writer) *slog.Logger {
+	lvl := new(slog.LevelVar)
+	lvl.Set(ParseLevel(level))
+	return NewWithLevel(lvl, format, w)
+}
+
+// NewWithLevel is like New but reads the minimum level from lvl, so it can be
+// changed while the process runs.
+func NewWithLevel(lvl *slog.LevelVar, format string, w io.Writer) *slog.Logger {
+	opts := &slog.HandlerOptions{
+		Level:       lvl,
+		ReplaceAttr: redact,
+	}
+
+	var handler slog.Handler
+	if strings.EqualFold(format, FormatText) {
+		handler = slog.NewTextHandler(w, opts)
+	} else {
+		handler = slog.NewJSONHandler(w, opts)
+	}
+
+	return slog.New(handler)
+}
+
+func ParseLevel(level string) slog.Level {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return slog.LevelInfo
+	}
+	return l
+}
+
+func IsSensitive(key string) bool {
+	_, ok := sensitiveKeys[strings.ToLower(key)]
+	return ok
+}
+
+func redact(_ []string, a slog.Attr) slog.Attr {
+	if IsSensitive(a.Key) {
+		return slog.String(a.Key, redacted)
+	}
+	return a
+}
+
+// WithContext stores l in ctx so downstream layers log with the same
+// request-scoped attributes.
+func WithContext(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext returns the request-scoped logger, or the default logger when
+// none was attached.
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
+
+// Fatal logs at error level on the default logger and exits, replacing the
+// log.Fatal calls used during startup.
+func Fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
@@ -0,0 +1,66 @@
+package logger_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Simpolette/HeartSteal/server/internal/logger"
+	"github.com/Simpolette/HeartSteal/server/internal/middleware"
+)
+
+func TestLogger_Redaction(t *testing.T) {
+	var buf bytes.Buffer
+	l := logger.New("debug", logger.FormatJSON, &buf)
+
+	l.Info("signup", "username", "alice", "password", "hunter22", "Authorization", "Bearer abc")
+
+	assert.Contains(t, buf.String(), `"username":"alice"`)
+	assert.NotContains(t, buf.String(), "hunter22")
+	assert.NotContains(t, buf.String(), "Bearer abc")
+}
+
+func TestLogger_RequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	setup := func(buf *bytes.Buffer) *gin.Engine {
+		r := gin.New()
+		r.Use(middleware.RequestIDMiddleware(logger.New("debug", logger.FormatJSON, buf)), middleware.AccessLogMiddleware())
+		r.GET("/ping", func(c *gin.Context) {
+			logger.FromContext(c.Request.Context()).Info("handled")
+			c.Status(http.StatusOK)
+		})
+		return r
+	}
+
+	t.Run("Propagated", func(t *testing.T) {
+		var buf bytes.Buffer
+		r := setup(&buf)
+
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.Header.Set(middleware.RequestIDHeader, "abc-123")
+		req.Header.Set("Authorization", "Bearer secret-token")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, "abc-123", w.Header().Get(middleware.RequestIDHeader))
+		assert.Equal(t, 2, bytes.Count(buf.Bytes(), []byte(`"request_id":"abc-123"`)))
+		assert.NotContains(t, buf.String(), "secret-token")
+	})
+
+	t.Run("Generated", func(t *testing.T) {
+		var buf bytes.Buffer
+		r := setup(&buf)
+
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.Header.Set(middleware.RequestIDHeader, "bad id\nwith newline")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Len(t, w.Header().Get(middleware.RequestIDHeader), 32)
+	})
+}
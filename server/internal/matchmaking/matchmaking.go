@@ -0,0 +1,172 @@
+// Package matchmaking groups players waiting in a queue into matches of
+// similar strength.
+//
+// A player queues alone or with their party as one Ticket. Tickets of the
+// same mode are grouped when every rating in the group is within the search
+// window of each ticket in it; the window starts narrow and widens the longer
+// a ticket waits. A group becomes a Proposal that every player must accept
+// before AcceptTimeout, after which the caller starts the match.
+//
+// Queue holds the tickets and proposals. MemoryQueue keeps them in process;
+// a shared store (e.g. Redis) can implement the same interface so several
+// instances match from one queue.
+package matchmaking
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+)
+
+var (
+	ErrAlreadyQueued    = errors.New("player is already queued")
+	ErrNotQueued        = errors.New("player is not queued")
+	ErrProposalNotFound = errors.New("no match is waiting to be accepted")
+	// ErrTicketTaken means a ticket changed under the caller: it was
+	// proposed, cancelled or resolved concurrently.
+	ErrTicketTaken = errors.New("ticket is no longer waiting")
+)
+
+// DefaultRating is the rating of players who have none yet.
+const DefaultRating = 1500
+
+type Config struct {
+	// InitialWindow is how many rating points apart players may be when
+	// they first queue.
+	InitialWindow float64
+	// WindowGrowth widens the window by this many points per second waited.
+	WindowGrowth float64
+	// MaxWindow caps the window; 0 lets it grow without bound.
+	MaxWindow float64
+	// AcceptTimeout is how long players have to accept a match found.
+	AcceptTimeout time.Duration
+	// Interval is how often waiting tickets are matched again as their
+	// windows widen and proposals expire.
+	Interval time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		InitialWindow: 100,
+		WindowGrowth:  10,
+		MaxWindow:     1000,
+		AcceptTimeout: 15 * time.Second,
+		Interval:      time.Second,
+	}
+}
+
+// Window returns how far from its own rating t accepts opponents at now.
+func (c Config) Window(t Ticket, now time.Time) float64 {
+	w := c.InitialWindow + c.WindowGrowth*max(now.Sub(t.QueuedAt).Seconds(), 0)
+	if c.MaxWindow > 0 {
+		w = min(w, c.MaxWindow)
+	}
+	return w
+}
+
+type Player struct {
+	UserID      string
+	Username    string
+	DisplayName string
+	Rating      float64
+}
+
+// Ticket is a party waiting for a match. Players[0] queued it.
+type Ticket struct {
+	ID       string
+	Mode     string
+	Players  []Player
+	QueuedAt time.Time
+	// ProposalID is set while a proposal holds the ticket.
+	ProposalID string
+}
+
+// Rating is the mean rating of the party.
+func (t Ticket) Rating() float64 {
+	if len(t.Players) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, p := range t.Players {
+		sum += p.Rating
+	}
+	return sum / float64(len(t.Players))
+}
+
+func (t Ticket) Has(userID string) bool {
+	return slices.ContainsFunc(t.Players, func(p Player) bool { return p.UserID == userID })
+}
+
+func (t Ticket) clone() Ticket {
+	t.Players = slices.Clone(t.Players)
+	return t
+}
+
+// Proposal is a match found for a group of tickets, waiting for every
+// player to accept it.
+type Proposal struct {
+	ID       string
+	Mode     string
+	Tickets  []Ticket
+	Accepted []string
+	Deadline time.Time
+	// MatchID is set once everyone accepted and the match started.
+	MatchID string
+}
+
+// Players returns the players of every ticket, in ticket order.
+func (p Proposal) Players() []Player {
+	var players []Player
+	for _, t := range p.Tickets {
+		players = append(players, t.Players...)
+	}
+	return players
+}
+
+func (p Proposal) Has(userID string) bool {
+	return slices.ContainsFunc(p.Tickets, func(t Ticket) bool { return t.Has(userID) })
+}
+
+func (p Proposal) HasAccepted(userID string) bool {
+	return slices.Contains(p.Accepted, userID)
+}
+
+func (p Proposal) AllAccepted() bool {
+	return len(p.Accepted) == len(p.Players())
+}
+
+func (p Proposal) clone() Proposal {
+	p.Tickets = slices.Clone(p.Tickets)
+	for i := range p.Tickets {
+		p.Tickets[i] = p.Tickets[i].clone()
+	}
+	p.Accepted = slices.Clone(p.Accepted)
+	return p
+}
+
+// Queue stores tickets and proposals. A player holds at most one ticket.
+// Implementations must be safe for concurrent use, and Propose and Resolve
+// must be atomic so two matchmakers can't take the same ticket.
+type Queue interface {
+	// Add queues ticket, failing with ErrAlreadyQueued if one of its
+	// players holds a ticket.
+	Add(ctx context.Context, ticket Ticket) error
+	// Get returns the ticket holding userID, or ErrNotQueued.
+	Get(ctx context.Context, userID string) (*Ticket, error)
+	// Remove drops a waiting ticket. A ticket held by a proposal is
+	// ErrTicketTaken; it leaves through Resolve.
+	Remove(ctx context.Context, ticketID string) error
+	// Waiting returns the tickets of mode no proposal holds, oldest first.
+	Waiting(ctx context.Context, mode string) ([]Ticket, error)
+	// Propose stores proposal and marks its tickets as held by it. It fails
+	// with ErrTicketTaken, changing nothing, unless every ticket is waiting.
+	Propose(ctx context.Context, proposal Proposal) error
+	// Accept records that userID accepted the proposal and returns it.
+	Accept(ctx context.Context, proposalID string, userID string) (*Proposal, error)
+	// Resolve removes a proposal and its tickets and returns it. Only one
+	// caller gets it; later ones get ErrProposalNotFound.
+	Resolve(ctx context.Context, proposalID string) (*Proposal, error)
+	// Expired returns the proposals whose deadline is not after now.
+	Expired(ctx context.Context, now time.Time) ([]Proposal, error)
+}
@@ -0,0 +1,165 @@
+package matchmaking
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryQueue keeps the queue in process. It is suitable for a single
+// instance; players queued on different instances never meet.
+type MemoryQueue struct {
+	mu        sync.Mutex
+	tickets   map[string]*Ticket
+	users     map[string]string
+	proposals map[string]*Proposal
+}
+
+var _ Queue = &MemoryQueue{}
+
+func NewMemoryQueue() *MemoryQueue {
+	return &MemoryQueue{
+		tickets:   make(map[string]*Ticket),
+		users:     make(map[string]string),
+		proposals: make(map[string]*Proposal),
+	}
+}
+
+func (q *MemoryQueue) Add(_ context.Context, ticket Ticket) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, p := range ticket.Players {
+		if _, ok := q.users[p.UserID]; ok {
+			return ErrAlreadyQueued
+		}
+	}
+	ticket = ticket.clone()
+	ticket.ProposalID = ""
+	q.tickets[ticket.ID] = &ticket
+	for _, p := range ticket.Players {
+		q.users[p.UserID] = ticket.ID
+	}
+	return nil
+}
+
+func (q *MemoryQueue) Get(_ context.Context, userID string) (*Ticket, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	id, ok := q.users[userID]
+	if !ok {
+		return nil, ErrNotQueued
+	}
+	ticket := q.tickets[id].clone()
+	return &ticket, nil
+}
+
+func (q *MemoryQueue) Remove(_ context.Context, ticketID string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	ticket, ok := q.tickets[ticketID]
+	if !ok {
+		return ErrNotQueued
+	}
+	if ticket.ProposalID != "" {
+		return ErrTicketTaken
+	}
+	q.remove(ticket)
+	return nil
+}
+
+func (q *MemoryQueue) Waiting(_ context.Context, mode string) ([]Ticket, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var waiting []Ticket
+	for _, t := range q.tickets {
+		if t.Mode == mode && t.ProposalID == "" {
+			waiting = append(waiting, t.clone())
+		}
+	}
+	slices.SortFunc(waiting, func(a, b Ticket) int {
+		if c := a.QueuedAt.Compare(b.QueuedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return waiting, nil
+}
+
+func (q *MemoryQueue) Propose(_ context.Context, proposal Proposal) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, t := range proposal.Tickets {
+		stored, ok := q.tickets[t.ID]
+		if !ok || stored.ProposalID != "" {
+			return ErrTicketTaken
+		}
+	}
+	proposal = proposal.clone()
+	for i, t := range proposal.Tickets {
+		stored := q.tickets[t.ID]
+		stored.ProposalID = proposal.ID
+		proposal.Tickets[i] = stored.clone()
+	}
+	q.proposals[proposal.ID] = &proposal
+	return nil
+}
+
+func (q *MemoryQueue) Accept(_ context.Context, proposalID string, userID string) (*Proposal, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	proposal, ok := q.proposals[proposalID]
+	if !ok || !proposal.Has(userID) {
+		return nil, ErrProposalNotFound
+	}
+	if !proposal.HasAccepted(userID) {
+		proposal.Accepted = append(proposal.Accepted, userID)
+	}
+	p := proposal.clone()
+	return &p, nil
+}
+
+func (q *MemoryQueue) Resolve(_ context.Context, proposalID string) (*Proposal, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	proposal, ok := q.proposals[proposalID]
+	if !ok {
+		return nil, ErrProposalNotFound
+	}
+	delete(q.proposals, proposalID)
+	for _, t := range proposal.Tickets {
+		if stored, ok := q.tickets[t.ID]; ok {
+			q.remove(stored)
+		}
+	}
+	return proposal, nil
+}
+
+func (q *MemoryQueue) Expired(_ context.Context, now time.Time) ([]Proposal, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var expired []Proposal
+	for _, p := range q.proposals {
+		if !p.Deadline.After(now) {
+			expired = append(expired, p.clone())
+		}
+	}
+	slices.SortFunc(expired, func(a, b Proposal) int { return a.Deadline.Compare(b.Deadline) })
+	return expired, nil
+}
+
+func (q *MemoryQueue) remove(ticket *Ticket) {
+	delete(q.tickets, ticket.ID)
+	for _, p := range ticket.Players {
+		delete(q.users, p.UserID)
+	}
+}
@@ -0,0 +1,69 @@
+package matchmaking
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"time"
+)
+
+// Find groups waiting tickets of one mode into matches of size players.
+// The longest waiting ticket is matched first, with the closest rated
+// tickets that fit. A group is only formed when the spread of its ratings is
+// within the window of every ticket in it, so a new player isn't matched
+// against someone far off just because that player has waited long.
+func Find(tickets []Ticket, size int, now time.Time, cfg Config) [][]Ticket {
+	queue := slices.Clone(tickets)
+	slices.SortStableFunc(queue, func(a, b Ticket) int { return a.QueuedAt.Compare(b.QueuedAt) })
+
+	used := make([]bool, len(queue))
+	var groups [][]Ticket
+	for i, anchor := range queue {
+		if used[i] || len(anchor.Players) > size {
+			continue
+		}
+
+		candidates := make([]int, 0, len(queue))
+		for j := range queue {
+			if j != i && !used[j] {
+				candidates = append(candidates, j)
+			}
+		}
+		slices.SortStableFunc(candidates, func(a, b int) int {
+			return cmp.Compare(math.Abs(queue[a].Rating()-anchor.Rating()), math.Abs(queue[b].Rating()-anchor.Rating()))
+		})
+
+		group := []int{i}
+		players := len(anchor.Players)
+		low, high := anchor.Rating(), anchor.Rating()
+		window := cfg.Window(anchor, now)
+		for _, j := range candidates {
+			if players == size {
+				break
+			}
+			t := queue[j]
+			if players+len(t.Players) > size {
+				continue
+			}
+			r := t.Rating()
+			w := min(window, cfg.Window(t, now))
+			if max(high, r)-min(low, r) > w {
+				continue
+			}
+			group = append(group, j)
+			players += len(t.Players)
+			low, high, window = min(low, r), max(high, r), w
+		}
+		if players < size {
+			continue
+		}
+
+		matched := make([]Ticket, len(group))
+		for k, j := range group {
+			used[j] = true
+			matched[k] = queue[j]
+		}
+		groups = append(groups, matched)
+	}
+	return groups
+}
@@ -0,0 +1,198 @@
+package matchmaking_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Simpolette/HeartSteal/server/internal/matchmaking"
+)
+
+var start = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// ticket queues one player per rating, waited seconds before start.
+func ticket(id string, waited int, ratings ...float64) matchmaking.Ticket {
+	t := matchmaking.Ticket{ID: id, Mode: "duel", QueuedAt: start.Add(-time.Duration(waited) * time.Second)}
+	for i, r := range ratings {
+		t.Players = append(t.Players, matchmaking.Player{UserID: fmt.Sprintf("%s-%d", id, i), Rating: r})
+	}
+	return t
+}
+
+func ids(groups [][]matchmaking.Ticket) [][]string {
+	res := [][]string{}
+	for _, g := range groups {
+		var group []string
+		for _, t := range g {
+			group = append(group, t.ID)
+		}
+		res = append(res, group)
+	}
+	return res
+}
+
+func TestConfig_Window(t *testing.T) {
+	cfg := matchmaking.Config{InitialWindow: 100, WindowGrowth: 10, MaxWindow: 300}
+
+	assert.Equal(t, 100.0, cfg.Window(ticket("a", 0, 1500), start))
+	assert.Equal(t, 150.0, cfg.Window(ticket("a", 5, 1500), start))
+	assert.Equal(t, 300.0, cfg.Window(ticket("a", 600, 1500), start), "capped")
+
+	cfg.MaxWindow = 0
+	assert.Equal(t, 6100.0, cfg.Window(ticket("a", 600, 1500), start), "uncapped")
+}
+
+func TestFind(t *testing.T) {
+	cfg := matchmaking.Config{InitialWindow: 100, WindowGrowth: 10, MaxWindow: 1000}
+
+	t.Run("PairsClosestRatings", func(t *testing.T) {
+		tickets := []matchmaking.Ticket{
+			ticket("a", 30, 1500),
+			ticket("b", 20, 1580),
+			ticket("c", 10, 1510),
+			ticket("d", 0, 1590),
+		}
+
+		assert.Equal(t, [][]string{{"a", "c"}, {"b", "d"}}, ids(matchmaking.Find(tickets, 2, start, cfg)))
+	})
+
+	t.Run("WindowWidensWithWaiting", func(t *testing.T) {
+		tickets := []matchmaking.Ticket{ticket("a", 0, 1500), ticket("b", 0, 1800)}
+		assert.Empty(t, matchmaking.Find(tickets, 2, start, cfg))
+
+		later := start.Add(25 * time.Second)
+		assert.Equal(t, [][]string{{"a", "b"}}, ids(matchmaking.Find(tickets, 2, later, cfg)))
+	})
+
+	t.Run("NewcomerKeepsNarrowWindow", func(t *testing.T) {
+		tickets := []matchmaking.Ticket{ticket("old", 120, 1500), ticket("new", 0, 1800)}
+
+		assert.Empty(t, matchmaking.Find(tickets, 2, start, cfg), "both windows must cover the gap")
+	})
+
+	t.Run("OldestFirst", func(t *testing.T) {
+		tickets := []matchmaking.Ticket{ticket("young", 0, 1505), ticket("mid", 5, 1520), ticket("old", 10, 1510)}
+
+		assert.Equal(t, [][]string{{"old", "young"}}, ids(matchmaking.Find(tickets, 2, start, cfg)))
+	})
+
+	t.Run("PartiesFillSeats", func(t *testing.T) {
+		tickets := []matchmaking.Ticket{
+			ticket("trio", 10, 1500, 1500, 1500),
+			ticket("pair", 5, 1500, 1500),
+			ticket("solo", 0, 1550),
+		}
+
+		assert.Equal(t, [][]string{{"trio", "solo"}}, ids(matchmaking.Find(tickets, 4, start, cfg)))
+	})
+
+	t.Run("PartyRatedByMean", func(t *testing.T) {
+		party := ticket("party", 0, 1400, 1600)
+		assert.Equal(t, 1500.0, party.Rating())
+
+		tickets := []matchmaking.Ticket{party, ticket("x", 0, 1450), ticket("y", 0, 1550)}
+		assert.Equal(t, [][]string{{"party", "x", "y"}}, ids(matchmaking.Find(tickets, 4, start, cfg)))
+	})
+
+	t.Run("TooLargeForMode", func(t *testing.T) {
+		tickets := []matchmaking.Ticket{ticket("big", 0, 1500, 1500, 1500), ticket("solo", 0, 1500)}
+
+		assert.Empty(t, matchmaking.Find(tickets, 2, start, cfg))
+	})
+}
+
+func TestMemoryQueue(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("OneTicketPerPlayer", func(t *testing.T) {
+		q := matchmaking.NewMemoryQueue()
+		require.NoError(t, q.Add(ctx, ticket("a", 0, 1500, 1500)))
+
+		other := ticket("b", 0, 1500)
+		other.Players[0].UserID = "a-1"
+		assert.ErrorIs(t, q.Add(ctx, other), matchmaking.ErrAlreadyQueued)
+
+		got, err := q.Get(ctx, "a-1")
+		require.NoError(t, err)
+		assert.Equal(t, "a", got.ID)
+
+		require.NoError(t, q.Remove(ctx, "a"))
+		_, err = q.Get(ctx, "a-0")
+		assert.ErrorIs(t, err, matchmaking.ErrNotQueued)
+		assert.ErrorIs(t, q.Remove(ctx, "a"), matchmaking.ErrNotQueued)
+	})
+
+	t.Run("ProposalHoldsTickets", func(t *testing.T) {
+		q := matchmaking.NewMemoryQueue()
+		a, b, c := ticket("a", 2, 1500), ticket("b", 1, 1500), ticket("c", 0, 1500)
+		for _, tk := range []matchmaking.Ticket{a, b, c} {
+			require.NoError(t, q.Add(ctx, tk))
+		}
+
+		require.NoError(t, q.Propose(ctx, matchmaking.Proposal{ID: "p", Mode: "duel", Tickets: []matchmaking.Ticket{a, b}, Deadline: start}))
+
+		waiting, err := q.Waiting(ctx, "duel")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"c"}, ids([][]matchmaking.Ticket{waiting})[0])
+		got, err := q.Get(ctx, "a-0")
+		require.NoError(t, err)
+		assert.Equal(t, "p", got.ProposalID)
+		assert.ErrorIs(t, q.Remove(ctx, "a"), matchmaking.ErrTicketTaken)
+
+		err = q.Propose(ctx, matchmaking.Proposal{ID: "q", Mode: "duel", Tickets: []matchmaking.Ticket{c, a}})
+		assert.ErrorIs(t, err, matchmaking.ErrTicketTaken)
+		got, err = q.Get(ctx, "c-0")
+		require.NoError(t, err)
+		assert.Empty(t, got.ProposalID, "a failed proposal changes nothing")
+	})
+
+	t.Run("AcceptAndResolve", func(t *testing.T) {
+		q := matchmaking.NewMemoryQueue()
+		a, b := ticket("a", 0, 1500), ticket("b", 0, 1500)
+		require.NoError(t, q.Add(ctx, a))
+		require.NoError(t, q.Add(ctx, b))
+		require.NoError(t, q.Propose(ctx, matchmaking.Proposal{ID: "p", Mode: "duel", Tickets: []matchmaking.Ticket{a, b}, Deadline: start}))
+
+		p, err := q.Accept(ctx, "p", "a-0")
+		require.NoError(t, err)
+		p, err = q.Accept(ctx, "p", "a-0")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a-0"}, p.Accepted, "accepting twice counts once")
+		assert.False(t, p.AllAccepted())
+		_, err = q.Accept(ctx, "p", "stranger")
+		assert.ErrorIs(t, err, matchmaking.ErrProposalNotFound)
+
+		p, err = q.Accept(ctx, "p", "b-0")
+		require.NoError(t, err)
+		assert.True(t, p.AllAccepted())
+
+		resolved, err := q.Resolve(ctx, "p")
+		require.NoError(t, err)
+		assert.Len(t, resolved.Players(), 2)
+		_, err = q.Resolve(ctx, "p")
+		assert.ErrorIs(t, err, matchmaking.ErrProposalNotFound, "only one caller resolves")
+		_, err = q.Get(ctx, "a-0")
+		assert.ErrorIs(t, err, matchmaking.ErrNotQueued)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		q := matchmaking.NewMemoryQueue()
+		a, b := ticket("a", 0, 1500), ticket("b", 0, 1500)
+		require.NoError(t, q.Add(ctx, a))
+		require.NoError(t, q.Add(ctx, b))
+		require.NoError(t, q.Propose(ctx, matchmaking.Proposal{ID: "p", Tickets: []matchmaking.Ticket{a, b}, Deadline: start}))
+
+		expired, err := q.Expired(ctx, start.Add(-time.Second))
+		require.NoError(t, err)
+		assert.Empty(t, expired)
+
+		expired, err = q.Expired(ctx, start)
+		require.NoError(t, err)
+		require.Len(t, expired, 1)
+		assert.Equal(t, "p", expired[0].ID)
+	})
+}
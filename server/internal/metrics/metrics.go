@@ -0,0 +1,218 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+const namespace = "heartsteal"
+
+// Metrics owns a dedicated Prometheus registry so tests and multiple app
+// instances never collide on the global default registry. All recording
+// methods are safe to call on a nil *Metrics.
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	httpRequests     *prometheus.CounterVec
+	httpDuration     *prometheus.HistogramVec
+	httpRequestSize  *prometheus.HistogramVec
+	httpResponseSize *prometheus.HistogramVec
+
+	signups         *prometheus.CounterVec
+	logins          *prometheus.CounterVec
+	tokenRejections *prometheus.CounterVec
+
+	mongoDuration *prometheus.HistogramVec
+
+	realtimeConnections  prometheus.Gauge
+	realtimeDisconnects  *prometheus.CounterVec
+	realtimeMessagesSent *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	sizeBuckets := prometheus.ExponentialBuckets(64, 4, 8)
+
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Number of HTTP requests by route template, method and status.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by route template, method and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		httpRequestSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_size_bytes",
+			Help:      "HTTP request body size by route template and method.",
+			Buckets:   sizeBuckets,
+		}, []string{"method", "route"}),
+		httpResponseSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "response_size_bytes",
+			Help:      "HTTP response body size by route template, method and status.",
+			Buckets:   sizeBuckets,
+		}, []string{"method", "route", "status"}),
+
+		signups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "auth",
+			Name:      "signups_total",
+			Help:      "Number of signup attempts by result.",
+		}, []string{"result"}),
+		logins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "auth",
+			Name:      "logins_total",
+			Help:      "Number of login attempts by result.",
+		}, []string{"result"}),
+		tokenRejections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "auth",
+			Name:      "token_rejections_total",
+			Help:      "Number of requests rejected by the JWT middleware by reason.",
+		}, []string{"reason"}),
+
+		mongoDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "mongo",
+			Name:      "command_duration_seconds",
+			Help:      "MongoDB command latency by command name and outcome.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"command", "status"}),
+
+		realtimeConnections: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "realtime",
+			Name:      "connections",
+			Help:      "Number of open WebSocket connections.",
+		}),
+		realtimeDisconnects: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "realtime",
+			Name:      "disconnects_total",
+			Help:      "Number of closed WebSocket connections by reason.",
+		}, []string{"reason"}),
+		realtimeMessagesSent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "realtime",
+			Name:      "messages_sent_total",
+			Help:      "Number of WebSocket messages queued for clients by envelope type.",
+		}, []string{"type"}),
+	}
+
+	m.Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.httpRequestSize,
+		m.httpResponseSize,
+		m.signups,
+		m.logins,
+		m.tokenRejections,
+		m.mongoDuration,
+		m.realtimeConnections,
+		m.realtimeDisconnects,
+		m.realtimeMessagesSent,
+	)
+
+	return m
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{Registry: m.Registry})
+}
+
+func (m *Metrics) ObserveHTTP(method, route, status string, duration time.Duration, reqSize, respSize int) {
+	if m == nil {
+		return
+	}
+	m.httpRequests.WithLabelValues(method, route, status).Inc()
+	m.httpDuration.WithLabelValues(method, route, status).Observe(duration.Seconds())
+	if reqSize >= 0 {
+		m.httpRequestSize.WithLabelValues(method, route).Observe(float64(reqSize))
+	}
+	if respSize >= 0 {
+		m.httpResponseSize.WithLabelValues(method, route, status).Observe(float64(respSize))
+	}
+}
+
+func (m *Metrics) Signup(result string) {
+	if m == nil {
+		return
+	}
+	m.signups.WithLabelValues(result).Inc()
+}
+
+func (m *Metrics) Login(result string) {
+	if m == nil {
+		return
+	}
+	m.logins.WithLabelValues(result).Inc()
+}
+
+func (m *Metrics) TokenRejected(reason string) {
+	if m == nil {
+		return
+	}
+	m.tokenRejections.WithLabelValues(reason).Inc()
+}
+
+// MongoMonitor returns a driver command monitor that records per-command
+// latency as reported by the driver.
+func (m *Metrics) MongoMonitor() *event.CommandMonitor {
+	if m == nil {
+		return nil
+	}
+	return &event.CommandMonitor{
+		Succeeded: func(_ context.Context, evt *event.CommandSucceededEvent) {
+			m.observeMongo(evt.CommandName, "success", evt.Duration)
+		},
+		Failed: func(_ context.Context, evt *event.CommandFailedEvent) {
+			m.observeMongo(evt.CommandName, "failure", evt.Duration)
+		},
+	}
+}
+
+func (m *Metrics) observeMongo(command, status string, duration time.Duration) {
+	m.mongoDuration.WithLabelValues(strings.ToLower(command), status).Observe(duration.Seconds())
+}
+
+func (m *Metrics) RealtimeConnected() {
+	if m == nil {
+		return
+	}
+	m.realtimeConnections.Inc()
+}
+
+func (m *Metrics) RealtimeDisconnected(reason string) {
+	if m == nil {
+		return
+	}
+	m.realtimeConnections.Dec()
+	m.realtimeDisconnects.WithLabelValues(reason).Inc()
+}
+
+func (m *Metrics) RealtimeMessageSent(messageType string) {
+	if m == nil {
+		return
+	}
+	m.realtimeMessagesSent.WithLabelValues(messageType).Inc()
+}
@@ -0,0 +1,48 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Simpolette/HeartSteal/server/internal/metrics"
+	"github.com/Simpolette/HeartSteal/server/internal/middleware"
+)
+
+func TestMetrics_ObserveHTTP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("LabelsByRouteTemplate", func(t *testing.T) {
+		m := metrics.New()
+		r := gin.New()
+		r.Use(middleware.MetricsMiddleware(m))
+		r.GET("/api/users/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		for _, id := range []string{"a", "b", "c"} {
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/users/"+id, nil))
+		}
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/nope", nil))
+
+		expected := `
+# HELP heartsteal_http_requests_total Number of HTTP requests by route template, method and status.
+# TYPE heartsteal_http_requests_total counter
+heartsteal_http_requests_total{method="GET",route="/api/users/:id",status="200"} 3
+heartsteal_http_requests_total{method="GET",route="unmatched",status="404"} 1
+`
+		assert.NoError(t, testutil.CollectAndCompare(m.Registry, strings.NewReader(expected), "heartsteal_http_requests_total"))
+	})
+
+	t.Run("NilSafe", func(t *testing.T) {
+		var m *metrics.Metrics
+		assert.NotPanics(t, func() {
+			m.Login("success")
+			m.Signup("success")
+			m.TokenRejected("missing_token")
+		})
+	})
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/origin"
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+type CORSConfig struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// CORSMiddleware allows the configured origins. An origin pattern may use a
+// leading wildcard label, e.g. https://*.heartsteal.gg, which matches any
+// subdomain (but not the apex) with the same scheme and port.
+func CORSMiddleware(cfg CORSConfig) (gin.HandlerFunc, error) {
+	matcher, err := origin.NewMatcher(cfg.AllowedOrigins)
+	if err != nil {
+		return nil, fmt.Errorf("CORS: %w", err)
+	}
+	if cfg.AllowCredentials && matcher.Any() {
+		return nil, fmt.Errorf("CORS: credentials can't be allowed for the \"*\" origin")
+	}
+
+	return cors.New(cors.Config{
+		AllowOriginFunc:  matcher.Match,
+		AllowMethods:     cfg.AllowedMethods,
+		AllowHeaders:     cfg.AllowedHeaders,
+		ExposeHeaders:    cfg.ExposedHeaders,
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           cfg.MaxAge,
+	}), nil
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecation describes a retired route that is still served during a
+// migration window.
+type Deprecation struct {
+	// Since is when the route was deprecated.
+	Since time.Time
+	// Sunset is when the route stops being served. Zero omits the header.
+	Sunset time.Time
+	// Successor maps the matched route (c.FullPath) to the path that
+	// replaces it. Nil omits the Link header.
+	Successor func(fullPath string) string
+}
+
+// DeprecationMiddleware marks responses with the Deprecation (RFC 9745),
+// Sunset (RFC 8594) and successor-version Link headers so clients can find
+// and migrate off legacy routes before they are removed.
+func DeprecationMiddleware(d Deprecation) gin.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(d.Since.Unix(), 10)
+	sunset := ""
+	if !d.Sunset.IsZero() {
+		sunset = d.Sunset.UTC().Format(http.TimeFormat)
+	}
+
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		if sunset != "" {
+			c.Header("Sunset", sunset)
+		}
+		if d.Successor != nil {
+			if path := d.Successor(c.FullPath()); path != "" {
+				c.Header("Link", "<"+path+`>; rel="successor-version"`)
+			}
+		}
+
+		c.Next()
+	}
+}
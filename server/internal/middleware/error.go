@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/Simpolette/HeartSteal/server/internal/apierror"
+	"github.com/Simpolette/HeartSteal/server/internal/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorHandlerMiddleware renders the last error attached with c.Error as an
+// ErrorResponse. Handlers only need to call c.Error(err) and return.
+func ErrorHandlerMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		appErr := apierror.Resolve(c.Errors.Last().Err)
+
+		level := slog.LevelDebug
+		if appErr.Status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		logger.FromContext(c.Request.Context()).Log(c.Request.Context(), level, "request failed",
+			slog.String("code", string(appErr.Code)),
+			slog.Int("status", appErr.Status),
+			slog.Any("error", appErr.Err),
+		)
+
+		c.AbortWithStatusJSON(appErr.Status, apierror.Localize(c.Request.Context(), appErr))
+	}
+}
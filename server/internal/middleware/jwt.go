@@ -1,15 +1,25 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
 	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/logger"
+	"github.com/Simpolette/HeartSteal/server/internal/metrics"
 	"github.com/Simpolette/HeartSteal/server/utils"
 	"github.com/gin-gonic/gin"
 )
 
-func JwtAuthMiddleware(secret string) gin.HandlerFunc {
+// TokenCheckFunc decides whether a validly signed token of userID, issued at
+// tokenVersion, is still accepted, e.g. that the user isn't banned and hasn't
+// had their tokens revoked since.
+type TokenCheckFunc func(ctx context.Context, userID string, tokenVersion int) error
+
+// JwtAuthMiddleware authenticates the bearer token and, when check is set,
+// asks it whether the token is still accepted.
+func JwtAuthMiddleware(secret string, m *metrics.Metrics, check TokenCheckFunc) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.Request.Header.Get("Authorization")
 		t := strings.Split(authHeader, " ")
@@ -17,21 +27,38 @@ func JwtAuthMiddleware(secret string) gin.HandlerFunc {
 			authToken := t[1]
 			authorized, err := tokenutil.IsAuthorized(authToken, secret)
 			if authorized {
-				userID, err := tokenutil.ExtractIDFromToken(authToken, secret)
+				claims, err := tokenutil.ExtractClaims(authToken, secret)
 				if err != nil {
-					c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Message: err.Error()})
-					c.Abort()
+					m.TokenRejected("invalid_claims")
+					abortWithError(c, domain.NewAppError(domain.CodeInvalidToken, http.StatusUnauthorized, "Invalid access token", err))
 					return
 				}
+				userID := claims.Subject
+				if check != nil {
+					if err := check(c.Request.Context(), userID, claims.TokenVersion); err != nil {
+						m.TokenRejected("revoked")
+						abortWithError(c, err)
+						return
+					}
+				}
 				c.Set("x-user-id", userID)
+				ctx := c.Request.Context()
+				l := logger.FromContext(ctx).With("user_id", userID)
+				c.Request = c.Request.WithContext(logger.WithContext(ctx, l))
 				c.Next()
 				return
 			}
-			c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Message: err.Error()})
-			c.Abort()
+			m.TokenRejected("invalid_token")
+			abortWithError(c, domain.NewAppError(domain.CodeInvalidToken, http.StatusUnauthorized, "Invalid access token", err))
 			return
 		}
-		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Message: "Not authorized"})
-		c.Abort()
+		m.TokenRejected("missing_token")
+		abortWithError(c, domain.ErrUnauthorized)
 	}
-}
\ No newline at end of file
+}
+
+// abortWithError stops the chain and leaves rendering to ErrorHandlerMiddleware.
+func abortWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
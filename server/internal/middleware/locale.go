@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/Simpolette/HeartSteal/server/internal/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// LocaleMiddleware negotiates the response language from Accept-Language.
+func LocaleMiddleware(bundle *i18n.Bundle) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		setLocale(c, bundle.Negotiate(c.GetHeader("Accept-Language")))
+		c.Next()
+	}
+}
+
+// UserLocaleFunc returns the locale saved on a user's profile, or "" if none.
+type UserLocaleFunc func(ctx context.Context, userID string) string
+
+// UserLocaleMiddleware lets an authenticated user's saved locale override the
+// negotiated one. It must run after JwtAuthMiddleware.
+func UserLocaleMiddleware(bundle *i18n.Bundle, lookup UserLocaleFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if userID := c.GetString("x-user-id"); userID != "" {
+			if locale, ok := bundle.Supported(lookup(c.Request.Context(), userID)); ok {
+				setLocale(c, locale)
+			}
+		}
+		c.Next()
+	}
+}
+
+func setLocale(c *gin.Context, locale string) {
+	c.Request = c.Request.WithContext(i18n.WithLocale(c.Request.Context(), locale))
+	c.Header("Content-Language", locale)
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/logger"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+	RequestIDKey    = "x-request-id"
+)
+
+// Accept only short, printable client-supplied IDs so they can't be used to
+// inject content into log lines.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestIDMiddleware propagates the caller's X-Request-ID (or assigns one),
+// echoes it on the response and attaches a request-scoped logger to the
+// request context.
+func RequestIDMiddleware(base *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = newRequestID()
+		}
+
+		c.Set(RequestIDKey, requestID)
+		c.Header(RequestIDHeader, requestID)
+
+		l := base.With(slog.String("request_id", requestID))
+		c.Request = c.Request.WithContext(logger.WithContext(c.Request.Context(), l))
+
+		c.Next()
+	}
+}
+
+// AccessLogMiddleware writes one line per request once the handler chain has
+// completed. It must run after RequestIDMiddleware.
+func AccessLogMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		l := logger.FromContext(c.Request.Context())
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.String("route", c.FullPath()),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("client_ip", c.ClientIP()),
+			slog.Int("size", c.Writer.Size()),
+			slog.String("user_agent", c.Request.UserAgent()),
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("errors", c.Errors.String()))
+		}
+		if l.Enabled(c.Request.Context(), slog.LevelDebug) {
+			attrs = append(attrs, headerAttrs(c.Request.Header))
+		}
+
+		l.LogAttrs(c.Request.Context(), level, "request completed", attrs...)
+	}
+}
+
+// headerAttrs logs request headers; sensitive ones such as Authorization are
+// replaced by the logger's redaction hook.
+func headerAttrs(h http.Header) slog.Attr {
+	attrs := make([]any, 0, len(h))
+	for key, values := range h {
+		attrs = append(attrs, slog.Any(key, values))
+	}
+	return slog.Group("headers", attrs...)
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
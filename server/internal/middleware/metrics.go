@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsMiddleware records request count, latency and sizes labelled by the
+// route template (e.g. /api/users/:id) so label cardinality stays bounded.
+func MetricsMiddleware(m *metrics.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		m.ObserveHTTP(
+			c.Request.Method,
+			route,
+			strconv.Itoa(c.Writer.Status()),
+			time.Since(start),
+			int(c.Request.ContentLength),
+			c.Writer.Size(),
+		)
+	}
+}
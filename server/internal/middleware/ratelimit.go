@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/logger"
+	"github.com/Simpolette/HeartSteal/server/internal/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitMiddleware enforces policy using store and reports the state with
+// the RateLimit-* headers (IETF draft) plus Retry-After when rejecting. A nil
+// store disables limiting; store failures fail open so an outage of the
+// limiter backend never takes the API down with it.
+func RateLimitMiddleware(store ratelimit.Store, policy ratelimit.Policy) gin.HandlerFunc {
+	keyFunc := policy.Key
+	if keyFunc == nil {
+		keyFunc = ratelimit.ByIP
+	}
+
+	return func(c *gin.Context) {
+		if store == nil {
+			c.Next()
+			return
+		}
+
+		key, ok := keyFunc(c)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		res, err := store.Allow(c.Request.Context(), key, policy)
+		if err != nil {
+			logger.FromContext(c.Request.Context()).Warn("rate limiter unavailable", "policy", policy.Name, "error", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Policy", policy.Header())
+		c.Header("RateLimit-Limit", strconv.Itoa(res.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("RateLimit-Reset", seconds(res.ResetAfter))
+
+		if !res.Allowed {
+			c.Header("Retry-After", seconds(res.RetryAfter))
+			abortWithError(c, domain.ErrRateLimited)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// seconds rounds up so clients never retry before the limit has reset.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
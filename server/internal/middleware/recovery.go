@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// RecoveryMiddleware turns a panic into a logged error and the same JSON
+// ErrorResponse shape as any other internal error.
+func RecoveryMiddleware() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(c *gin.Context, recovered any) {
+		logger.FromContext(c.Request.Context()).Error("panic recovered", "panic", recovered)
+
+		appErr := domain.InternalError(fmt.Errorf("panic: %v", recovered))
+		c.AbortWithStatusJSON(appErr.Status, domain.ErrorResponse{
+			Code:    appErr.Code,
+			Message: appErr.Message,
+		})
+	})
+}
@@ -0,0 +1,50 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Simpolette/HeartSteal/server/internal/middleware"
+)
+
+func TestDeprecationMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	serve := func(d middleware.Deprecation) http.Header {
+		r := gin.New()
+		r.Use(middleware.DeprecationMiddleware(d))
+		r.POST("/api/login", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/login", nil))
+		return w.Header()
+	}
+
+	t.Run("AllHeaders", func(t *testing.T) {
+		h := serve(middleware.Deprecation{
+			Since:  time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC),
+			Sunset: time.Date(2027, time.April, 19, 0, 0, 0, 0, time.UTC),
+			Successor: func(fullPath string) string {
+				return "/api/v1/auth" + strings.TrimPrefix(fullPath, "/api")
+			},
+		})
+
+		assert.Equal(t, "@1792368000", h.Get("Deprecation"))
+		assert.Equal(t, "Mon, 19 Apr 2027 00:00:00 GMT", h.Get("Sunset"))
+		assert.Equal(t, `</api/v1/auth/login>; rel="successor-version"`, h.Get("Link"))
+	})
+
+	t.Run("DeprecationOnly", func(t *testing.T) {
+		h := serve(middleware.Deprecation{Since: time.Unix(0, 0)})
+
+		assert.Equal(t, "@0", h.Get("Deprecation"))
+		assert.Empty(t, h.Get("Sunset"))
+		assert.Empty(t, h.Get("Link"))
+	})
+}
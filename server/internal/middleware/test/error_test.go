@@ -0,0 +1,98 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Simpolette/HeartSteal/server/internal/apierror"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/i18n"
+	"github.com/Simpolette/HeartSteal/server/internal/middleware"
+)
+
+func TestErrorHandlerMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	errTeapot := errors.New("teapot")
+	apierror.Register(errTeapot, http.StatusTeapot, "TEAPOT", "I'm a teapot")
+
+	serve := func(handler gin.HandlerFunc) (*httptest.ResponseRecorder, domain.ErrorResponse) {
+		r := gin.New()
+		r.Use(middleware.RecoveryMiddleware(), middleware.ErrorHandlerMiddleware())
+		r.GET("/", handler)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		var body domain.ErrorResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &body)
+		return w, body
+	}
+
+	t.Run("WrappedSentinel", func(t *testing.T) {
+		w, body := serve(func(c *gin.Context) {
+			_ = c.Error(fmt.Errorf("brewing: %w", errTeapot))
+		})
+
+		assert.Equal(t, http.StatusTeapot, w.Code)
+		assert.Equal(t, domain.ErrorCode("TEAPOT"), body.Code)
+		assert.Equal(t, "I'm a teapot", body.Message)
+	})
+
+	t.Run("AppError", func(t *testing.T) {
+		w, body := serve(func(c *gin.Context) {
+			_ = c.Error(domain.NewAppError(domain.CodeInvalidRequest, http.StatusBadRequest, "Invalid request", errors.New("detail")))
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, domain.CodeInvalidRequest, body.Code)
+		assert.Equal(t, "Request validation failed", body.Message)
+	})
+
+	t.Run("Localized", func(t *testing.T) {
+		r := gin.New()
+		r.Use(middleware.LocaleMiddleware(i18n.Default()), middleware.ErrorHandlerMiddleware())
+		r.GET("/", func(c *gin.Context) {
+			_ = c.Error(domain.ErrUnauthorized)
+		})
+		apierror.Register(domain.ErrUnauthorized, http.StatusUnauthorized, domain.CodeUnauthorized, "Not authorized")
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Language", "vi-VN,vi;q=0.9")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		var body domain.ErrorResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &body)
+		assert.Equal(t, "vi", w.Header().Get("Content-Language"))
+		assert.Equal(t, domain.CodeUnauthorized, body.Code)
+		assert.Equal(t, "Chưa được xác thực", body.Message)
+	})
+
+	t.Run("UnknownErrorDoesNotLeak", func(t *testing.T) {
+		w, body := serve(func(c *gin.Context) {
+			_ = c.Error(errors.New("mongo: connection refused to 10.0.0.1"))
+		})
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, domain.CodeInternal, body.Code)
+		assert.NotContains(t, w.Body.String(), "10.0.0.1")
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		w, body := serve(func(c *gin.Context) {
+			panic("boom")
+		})
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, domain.CodeInternal, body.Code)
+		assert.NotContains(t, w.Body.String(), "boom")
+	})
+}
@@ -0,0 +1,92 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Simpolette/HeartSteal/server/internal/middleware"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("RejectsWildcardWithCredentials", func(t *testing.T) {
+		_, err := middleware.CORSMiddleware(middleware.CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Preflight", func(t *testing.T) {
+		handler, err := middleware.CORSMiddleware(middleware.CORSConfig{
+			AllowedOrigins:   []string{"https://*.heartsteal.gg"},
+			AllowedMethods:   []string{"GET", "POST"},
+			AllowedHeaders:   []string{"Content-Type"},
+			AllowCredentials: true,
+		})
+		require.NoError(t, err)
+		r := gin.New()
+		r.Use(handler)
+		r.POST("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		preflight := func(origin string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodOptions, "/", nil)
+			req.Header.Set("Origin", origin)
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			return w
+		}
+
+		w := preflight("https://play.heartsteal.gg")
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "https://play.heartsteal.gg", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+
+		w = preflight("https://evil.example")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
+
+func TestSecurityHeadersMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	serve := func(cfg middleware.SecurityHeadersConfig) http.Header {
+		r := gin.New()
+		r.Use(middleware.SecurityHeadersMiddleware(cfg))
+		r.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		return w.Header()
+	}
+
+	t.Run("Defaults", func(t *testing.T) {
+		h := serve(middleware.SecurityHeadersConfig{})
+
+		assert.Empty(t, h.Get("Strict-Transport-Security"))
+		assert.Equal(t, "nosniff", h.Get("X-Content-Type-Options"))
+		assert.Equal(t, "frame-ancestors 'none'", h.Get("Content-Security-Policy"))
+		assert.Equal(t, "DENY", h.Get("X-Frame-Options"))
+		assert.Equal(t, "strict-origin-when-cross-origin", h.Get("Referrer-Policy"))
+	})
+
+	t.Run("Configured", func(t *testing.T) {
+		h := serve(middleware.SecurityHeadersConfig{
+			HSTSMaxAge:            31536000,
+			HSTSIncludeSubdomains: true,
+			FrameAncestors:        "https://*.heartsteal.gg",
+			ReferrerPolicy:        "no-referrer",
+		})
+
+		assert.Equal(t, "max-age=31536000; includeSubDomains", h.Get("Strict-Transport-Security"))
+		assert.Equal(t, "frame-ancestors https://*.heartsteal.gg", h.Get("Content-Security-Policy"))
+		assert.Empty(t, h.Get("X-Frame-Options"))
+		assert.Equal(t, "no-referrer", h.Get("Referrer-Policy"))
+	})
+}
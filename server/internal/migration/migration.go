@@ -0,0 +1,137 @@
+// Package migration applies versioned changes to the MongoDB schema, such as
+// indexes, and records which ones have run in the schema_migrations
+// collection.
+package migration
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const CollectionMigrations = "schema_migrations"
+
+// Migration is one schema change. Versions must be unique and increase in
+// the order migrations are listed; an applied migration must never change.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(ctx context.Context, db *mongo.Database) error
+}
+
+// Status reports whether a migration has been applied and when.
+type Status struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+type record struct {
+	Version   int       `bson:"_id"`
+	Name      string    `bson:"name"`
+	AppliedAt time.Time `bson:"applied_at"`
+}
+
+// Validate checks that versions are positive and strictly increasing.
+func Validate(migrations []Migration) error {
+	last := 0
+	for _, m := range migrations {
+		if m.Version <= last {
+			return fmt.Errorf("migration %d (%s) must have a version greater than %d", m.Version, m.Name, last)
+		}
+		if m.Up == nil {
+			return fmt.Errorf("migration %d (%s) has no Up function", m.Version, m.Name)
+		}
+		last = m.Version
+	}
+	return nil
+}
+
+// Pending returns the migrations whose version is not in applied, in order.
+func Pending(migrations []Migration, applied map[int]time.Time) []Migration {
+	var pending []Migration
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; !ok {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
+type Migrator struct {
+	db         *mongo.Database
+	migrations []Migration
+}
+
+func NewMigrator(db *mongo.Database, migrations []Migration) (*Migrator, error) {
+	if err := Validate(migrations); err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Status lists every known migration with the time it was applied, if any.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		status := Status{Version: mig.Version, Name: mig.Name}
+		if at, ok := applied[mig.Version]; ok {
+			status.AppliedAt = &at
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Up applies pending migrations in order and stops at the first failure. With
+// dryRun it only returns what would be applied.
+func (m *Migrator) Up(ctx context.Context, dryRun bool) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := Pending(m.migrations, applied)
+	if dryRun {
+		return pending, nil
+	}
+
+	collection := m.db.Collection(CollectionMigrations)
+	for i, mig := range pending {
+		if err := mig.Up(ctx, m.db); err != nil {
+			return pending[:i], fmt.Errorf("migration %d (%s): %w", mig.Version, mig.Name, err)
+		}
+		rec := record{Version: mig.Version, Name: mig.Name, AppliedAt: time.Now().UTC()}
+		if _, err := collection.InsertOne(ctx, rec); err != nil {
+			return pending[:i], fmt.Errorf("migration %d (%s) ran but can't be recorded: %w", mig.Version, mig.Name, err)
+		}
+	}
+	return pending, nil
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int]time.Time, error) {
+	cursor, err := m.db.Collection(CollectionMigrations).Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	var records []record
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]time.Time, len(records))
+	for _, r := range records {
+		applied[r.Version] = r.AppliedAt
+	}
+	return applied, nil
+}
@@ -0,0 +1,134 @@
+package migration
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// All lists every migration in the order it is applied. Append new ones at
+// the end with the next version number.
+var All = []Migration{
+	{
+		Version: 1,
+		Name:    "users unique email and username",
+		Up: createIndexes(domain.CollectionUser,
+			mongo.IndexModel{Keys: bson.D{{Key: "email", Value: 1}}, Options: options.Index().SetUnique(true)},
+			mongo.IndexModel{Keys: bson.D{{Key: "username", Value: 1}}, Options: options.Index().SetUnique(true)},
+		),
+	},
+	{
+		Version: 2,
+		Name:    "sessions by user and expiry",
+		Up: createIndexes(domain.CollectionSession,
+			mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+			// Let MongoDB delete sessions once they expire.
+			mongo.IndexModel{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		),
+	},
+	{
+		Version: 3,
+		Name:    "lobbies by invite code, member and open listing",
+		Up: createIndexes(domain.CollectionLobby,
+			mongo.IndexModel{Keys: bson.D{{Key: "invite_code", Value: 1}}, Options: options.Index().SetUnique(true)},
+			mongo.IndexModel{Keys: bson.D{{Key: "members.user_id", Value: 1}}},
+			mongo.IndexModel{Keys: bson.D{
+				{Key: "visibility", Value: 1},
+				{Key: "status", Value: 1},
+				{Key: "created_at", Value: -1},
+				{Key: "_id", Value: -1},
+			}},
+		),
+	},
+	{
+		Version: 4,
+		Name:    "realtime tickets expiry",
+		Up: createIndexes(domain.CollectionTicket,
+			mongo.IndexModel{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		),
+	},
+	{
+		Version: 5,
+		Name:    "matches by status",
+		Up: createIndexes(domain.CollectionMatch,
+			mongo.IndexModel{Keys: bson.D{{Key: "status", Value: 1}}},
+		),
+	},
+	{
+		Version: 6,
+		Name:    "ratings by user and leaderboard order",
+		Up: createIndexes(domain.CollectionRating,
+			mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "mode", Value: 1}}, Options: options.Index().SetUnique(true)},
+			mongo.IndexModel{Keys: bson.D{{Key: "mode", Value: 1}, {Key: "rating", Value: -1}, {Key: "user_id", Value: 1}}},
+		),
+	},
+	{
+		Version: 7,
+		Name:    "matches by player history",
+		Up: createIndexes(domain.CollectionMatch,
+			mongo.IndexModel{Keys: bson.D{
+				{Key: "players.user_id", Value: 1},
+				{Key: "status", Value: 1},
+				{Key: "finished_at", Value: -1},
+				{Key: "_id", Value: -1},
+			}},
+		),
+	},
+	{
+		Version: 8,
+		Name:    "matches by lease expiry",
+		Up: createIndexes(domain.CollectionMatch,
+			mongo.IndexModel{Keys: bson.D{{Key: "status", Value: 1}, {Key: "lease_expires_at", Value: 1}}},
+		),
+	},
+	{
+		Version: 9,
+		Name:    "lobbies unique member",
+		Up: replaceIndex(domain.CollectionLobby, "members.user_id_1",
+			// Lobbies without members would all index as null, so only
+			// index the ones that have some.
+			mongo.IndexModel{
+				Keys: bson.D{{Key: "members.user_id", Value: 1}},
+				Options: options.Index().
+					SetName(domain.IndexLobbyMember).
+					SetUnique(true).
+					SetPartialFilterExpression(bson.M{"members.user_id": bson.M{"$exists": true}}),
+			},
+		),
+	},
+}
+
+func createIndexes(collection string, indexes ...mongo.IndexModel) func(context.Context, *mongo.Database) error {
+	return func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection(collection).Indexes().CreateMany(ctx, indexes)
+		return err
+	}
+}
+
+// replaceIndex drops the index named old, if it exists, and creates index in
+// its place.
+func replaceIndex(collection, old string, index mongo.IndexModel) func(context.Context, *mongo.Database) error {
+	return func(ctx context.Context, db *mongo.Database) error {
+		indexes := db.Collection(collection).Indexes()
+		if _, err := indexes.DropOne(ctx, old); err != nil && !isIndexNotFound(err) {
+			return err
+		}
+		_, err := indexes.CreateOne(ctx, index)
+		return err
+	}
+}
+
+// MongoDB error codes for a missing collection and a missing index.
+const (
+	codeNamespaceNotFound = 26
+	codeIndexNotFound     = 27
+)
+
+func isIndexNotFound(err error) bool {
+	var serverErr mongo.ServerError
+	return errors.As(err, &serverErr) && (serverErr.HasErrorCode(codeIndexNotFound) || serverErr.HasErrorCode(codeNamespaceNotFound))
+}
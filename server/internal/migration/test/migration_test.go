@@ -0,0 +1,55 @@
+package migration_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Simpolette/HeartSteal/server/internal/migration"
+)
+
+func noop(context.Context, *mongo.Database) error { return nil }
+
+func TestMigration_All(t *testing.T) {
+	assert.NoError(t, migration.Validate(migration.All))
+}
+
+func TestMigration_Validate(t *testing.T) {
+	t.Run("OutOfOrder", func(t *testing.T) {
+		err := migration.Validate([]migration.Migration{
+			{Version: 2, Name: "b", Up: noop},
+			{Version: 1, Name: "a", Up: noop},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("Duplicate", func(t *testing.T) {
+		err := migration.Validate([]migration.Migration{
+			{Version: 1, Name: "a", Up: noop},
+			{Version: 1, Name: "b", Up: noop},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("MissingUp", func(t *testing.T) {
+		err := migration.Validate([]migration.Migration{{Version: 1, Name: "a"}})
+		assert.Error(t, err)
+	})
+}
+
+func TestMigration_Pending(t *testing.T) {
+	migrations := []migration.Migration{
+		{Version: 1, Name: "a", Up: noop},
+		{Version: 2, Name: "b", Up: noop},
+		{Version: 3, Name: "c", Up: noop},
+	}
+
+	pending := migration.Pending(migrations, map[int]time.Time{2: time.Now()})
+
+	assert.Len(t, pending, 2)
+	assert.Equal(t, 1, pending[0].Version)
+	assert.Equal(t, 3, pending[1].Version)
+}
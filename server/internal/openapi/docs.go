@@ -0,0 +1,78 @@
+package openapi
+
+import (
+	"embed"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed docs.html
+var docsPage string
+
+// swaggerUI holds the vendored swagger-ui-dist files; see swaggerui/README.md.
+//
+//go:embed swaggerui
+var swaggerUI embed.FS
+
+// docsAssets are the vendored files the docs page loads.
+var docsAssets = []string{"swagger-ui.css", "swagger-ui-bundle.js"}
+
+// DocsParams selects one of the docs page assets.
+type DocsParams struct {
+	File string `uri:"file" binding:"required"`
+}
+
+// DocsHandler serves an interactive explorer (Swagger UI) for the document
+// published at specURL, loading its assets from assetsURL rather than a CDN.
+// Builds without the vendored assets serve 503 and say how to add them.
+func DocsHandler(specURL, assetsURL string) gin.HandlerFunc {
+	page := []byte(strings.NewReplacer("{{SPEC_URL}}", specURL, "{{ASSETS_URL}}", assetsURL).Replace(docsPage))
+	vendored := DocsVendored()
+	return func(c *gin.Context) {
+		if !vendored {
+			c.String(http.StatusServiceUnavailable, "Swagger UI isn't vendored in this build; run go run ./scripts/vendor_swagger_ui")
+			return
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", page)
+	}
+}
+
+// DocsAssetsHandler serves the vendored Swagger UI files named by the :file
+// path parameter.
+func DocsAssetsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("file")
+		content, err := docsAsset(name)
+		if err != nil {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		c.Header("Cache-Control", "public, max-age=86400")
+		c.Data(http.StatusOK, mime.TypeByExtension(path.Ext(name)), content)
+	}
+}
+
+// DocsVendored reports whether the Swagger UI assets were vendored before the
+// build.
+func DocsVendored() bool {
+	for _, name := range docsAssets {
+		if _, err := docsAsset(name); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func docsAsset(name string) ([]byte, error) {
+	for _, asset := range docsAssets {
+		if name == asset {
+			return fs.ReadFile(swaggerUI, "swaggerui/"+name)
+		}
+	}
+	return nil, fs.ErrNotExist
+}
@@ -0,0 +1,92 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Router registers routes on a gin group and documents them in the spec in
+// the same call. Route files use it instead of the gin group directly.
+type Router struct {
+	spec       *Spec
+	group      *gin.RouterGroup
+	auth       bool
+	deprecated bool
+}
+
+func (s *Spec) Router(group *gin.RouterGroup) *Router {
+	return &Router{spec: s, group: group}
+}
+
+// Group mirrors gin's RouterGroup.Group.
+func (r *Router) Group(relativePath string, handlers ...gin.HandlerFunc) *Router {
+	sub := *r
+	sub.group = r.group.Group(relativePath, handlers...)
+	return &sub
+}
+
+func (r *Router) Use(middleware ...gin.HandlerFunc) *Router {
+	r.group.Use(middleware...)
+	return r
+}
+
+// Authenticated returns a sub-router whose routes are documented as requiring
+// a bearer token. The caller still installs the auth middleware.
+func (r *Router) Authenticated() *Router {
+	sub := *r
+	sub.auth = true
+	return &sub
+}
+
+// Deprecated returns a sub-router whose routes are documented as deprecated.
+func (r *Router) Deprecated() *Router {
+	sub := *r
+	sub.deprecated = true
+	return &sub
+}
+
+func (r *Router) BasePath() string {
+	return r.group.BasePath()
+}
+
+func (r *Router) Handle(method, relativePath string, op Operation, handlers ...gin.HandlerFunc) {
+	r.group.Handle(method, relativePath, handlers...)
+	r.spec.Add(method, joinPaths(r.group.BasePath(), relativePath), op, r.auth, r.deprecated)
+}
+
+func (r *Router) GET(relativePath string, op Operation, handlers ...gin.HandlerFunc) {
+	r.Handle(http.MethodGet, relativePath, op, handlers...)
+}
+
+func (r *Router) POST(relativePath string, op Operation, handlers ...gin.HandlerFunc) {
+	r.Handle(http.MethodPost, relativePath, op, handlers...)
+}
+
+func (r *Router) PUT(relativePath string, op Operation, handlers ...gin.HandlerFunc) {
+	r.Handle(http.MethodPut, relativePath, op, handlers...)
+}
+
+func (r *Router) PATCH(relativePath string, op Operation, handlers ...gin.HandlerFunc) {
+	r.Handle(http.MethodPatch, relativePath, op, handlers...)
+}
+
+func (r *Router) DELETE(relativePath string, op Operation, handlers ...gin.HandlerFunc) {
+	r.Handle(http.MethodDelete, relativePath, op, handlers...)
+}
+
+func joinPaths(base, relative string) string {
+	if relative == "" || relative == "/" {
+		if base == "" {
+			return "/"
+		}
+		return base
+	}
+	if base == "/" {
+		base = ""
+	}
+	if relative[0] != '/' {
+		relative = "/" + relative
+	}
+	return base + relative
+}
@@ -0,0 +1,227 @@
+package openapi
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3gen"
+)
+
+const componentPrefix = "#/components/schemas/"
+
+// schema reflects value into a component schema and returns a reference to
+// it. The reference keeps its resolved value so the document can be
+// validated and used for request/response checks without a loader pass.
+func (s *Spec) schema(value any) (*openapi3.SchemaRef, error) {
+	gen := openapi3gen.NewGenerator(
+		openapi3gen.SchemaCustomizer(s.customize),
+		openapi3gen.CreateTypeNameGenerator(typeName),
+		openapi3gen.CreateComponentSchemas(openapi3gen.ExportComponentSchemasOptions{
+			ExportComponentSchemas: true,
+			ExportTopLevelSchema:   true,
+		}),
+	)
+	ref, err := gen.NewSchemaRefForValue(value, s.doc.Components.Schemas)
+	if err != nil {
+		return nil, err
+	}
+
+	for r := range gen.SchemaRefs {
+		s.resolve(r)
+	}
+	s.resolve(ref)
+	return ref, nil
+}
+
+// fieldSchema describes a single struct field, for parameters.
+func (s *Spec) fieldSchema(f reflect.StructField) (*openapi3.SchemaRef, error) {
+	gen := openapi3gen.NewGenerator(openapi3gen.SchemaCustomizer(s.customize))
+	ref, err := gen.GenerateSchemaRef(f.Type)
+	if err != nil {
+		return nil, err
+	}
+	s.applyRules(f.Type, f.Tag, ref.Value)
+	ref.Ref = ""
+	return ref, nil
+}
+
+func (s *Spec) resolve(ref *openapi3.SchemaRef) {
+	if ref == nil || !strings.HasPrefix(ref.Ref, componentPrefix) {
+		return
+	}
+	if component := s.doc.Components.Schemas[strings.TrimPrefix(ref.Ref, componentPrefix)]; component != nil {
+		ref.Value = component.Value
+	}
+}
+
+// customize is called for every generated schema: name is the field's JSON
+// name ("_root" for the top level), tag its struct tag.
+func (s *Spec) customize(_ string, t reflect.Type, tag reflect.StructTag, schema *openapi3.Schema) error {
+	if values, ok := s.enums[t]; ok {
+		schema.Enum = values
+	}
+	if t.Kind() == reflect.Struct {
+		schema.Required = requiredFields(t)
+	}
+	s.applyRules(t, tag, schema)
+	return nil
+}
+
+// applyRules translates the validator rules in the binding tag. Rules the
+// spec can't express are left to the description of the custom rule. Rules
+// after "dive" apply to the items of a slice.
+func (s *Spec) applyRules(t reflect.Type, tag reflect.StructTag, schema *openapi3.Schema) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	rules := strings.Split(tag.Get("binding"), ",")
+	var itemRules []string
+	if i := slices.Index(rules, "dive"); i >= 0 {
+		rules, itemRules = rules[:i], rules[i+1:]
+	}
+	s.applyRuleList(t, rules, schema)
+
+	// The generator customizes items with their slice's tag before the
+	// slice itself, so they were given the slice's rules; swap them for the
+	// item rules.
+	if (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && schema.Items != nil && schema.Items.Value != nil {
+		item, elem := schema.Items.Value, t.Elem()
+		item.MinLength, item.MaxLength, item.MinItems, item.MaxItems = 0, nil, 0, nil
+		item.Min, item.Max, item.ExclusiveMin, item.ExclusiveMax = nil, nil, false, false
+		item.Format, item.Enum = "", s.enums[elem]
+		for elem.Kind() == reflect.Pointer {
+			elem = elem.Elem()
+		}
+		s.applyRuleList(elem, itemRules, item)
+	}
+}
+
+func (s *Spec) applyRuleList(t reflect.Type, rules []string, schema *openapi3.Schema) {
+	for _, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "", "required", "omitempty":
+		case "email":
+			schema.Format = "email"
+		case "url":
+			schema.Format = "uri"
+		case "uuid":
+			schema.Format = "uuid"
+		case "oneof":
+			values := strings.Fields(param)
+			schema.Enum = make([]any, 0, len(values))
+			for _, v := range values {
+				schema.Enum = append(schema.Enum, enumValue(t, v))
+			}
+		case "min", "max", "len", "gte", "lte", "gt", "lt":
+			bound(t, schema, name, param)
+		default:
+			if describe, ok := s.rules[name]; ok {
+				describe(schema)
+			}
+		}
+	}
+}
+
+func bound(t reflect.Type, schema *openapi3.Schema, rule, param string) {
+	switch t.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		n, err := strconv.ParseUint(param, 10, 64)
+		if err != nil {
+			return
+		}
+		minimum, maximum := &schema.MinLength, &schema.MaxLength
+		if t.Kind() != reflect.String {
+			minimum, maximum = &schema.MinItems, &schema.MaxItems
+		}
+		switch rule {
+		case "min", "gte":
+			*minimum = n
+		case "max", "lte":
+			*maximum = &n
+		case "len":
+			*minimum, *maximum = n, &n
+		}
+	default:
+		n, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return
+		}
+		switch rule {
+		case "min", "gte":
+			schema.Min = &n
+		case "max", "lte":
+			schema.Max = &n
+		case "gt":
+			schema.Min, schema.ExclusiveMin = &n, true
+		case "lt":
+			schema.Max, schema.ExclusiveMax = &n, true
+		case "len":
+			schema.Min, schema.Max = &n, &n
+		}
+	}
+}
+
+func enumValue(t reflect.Type, v string) any {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n
+		}
+	case reflect.Float32, reflect.Float64:
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return n
+		}
+	}
+	return v
+}
+
+// requiredFields lists the JSON properties that are always present: request
+// fields bound with "required", and response fields that are neither
+// omitempty, omitzero nor validated (and so never optional).
+func requiredFields(t reflect.Type) []string {
+	var required []string
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || name == "" {
+			continue
+		}
+
+		if _, bound := f.Tag.Lookup("binding"); bound {
+			if hasRule(f.Tag, "required") {
+				required = append(required, name)
+			}
+			continue
+		}
+		if !strings.Contains(opts, "omitempty") && !strings.Contains(opts, "omitzero") && f.Type.Kind() != reflect.Pointer {
+			required = append(required, name)
+		}
+	}
+	return required
+}
+
+func hasRule(tag reflect.StructTag, rule string) bool {
+	for _, r := range strings.Split(tag.Get("binding"), ",") {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// typeName exports component names, so unexported request types such as
+// signupRequest become SignupRequest.
+func typeName(t reflect.Type) string {
+	name := t.Name()
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(r)) + name[size:]
+}
@@ -0,0 +1,284 @@
+// Package openapi builds the OpenAPI 3 description of the API from the route
+// registrations themselves, so a route can't be added without documenting it.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+)
+
+const bearerAuth = "bearerAuth"
+
+// Operation documents one route. Request, Params and Query are zero values of
+// the types the handler binds; they are reflected into schemas using their
+// json, uri and form tags and their binding rules.
+type Operation struct {
+	Summary     string
+	Description string
+	Tags        []string
+	Request     any
+	Params      any
+	Query       any
+	Responses   []Response
+	// Errors lists the error statuses worth documenting. Every operation also
+	// gets a default ErrorResponse, since any route can fail.
+	Errors []int
+}
+
+// Response documents one status. When Data is set, Body must be
+// domain.SuccessResponse and its data field is described by Data.
+type Response struct {
+	Status      int
+	Description string
+	Body        any
+	Data        any
+	// ContentType defaults to application/json.
+	ContentType string
+}
+
+type Spec struct {
+	mu    sync.Mutex
+	doc   *openapi3.T
+	enums map[reflect.Type][]any
+	rules map[string]func(*openapi3.Schema)
+
+	encodeOnce sync.Once
+	encoded    []byte
+	encodeErr  error
+}
+
+func NewSpec(title, version, description string) *Spec {
+	s := &Spec{
+		doc: &openapi3.T{
+			OpenAPI: "3.0.3",
+			Info: &openapi3.Info{
+				Title:       title,
+				Version:     version,
+				Description: description,
+			},
+			Paths: openapi3.NewPaths(),
+			Components: &openapi3.Components{
+				Schemas: openapi3.Schemas{},
+				SecuritySchemes: openapi3.SecuritySchemes{
+					bearerAuth: &openapi3.SecuritySchemeRef{Value: openapi3.NewJWTSecurityScheme()},
+				},
+			},
+		},
+		enums: map[reflect.Type][]any{},
+		rules: map[string]func(*openapi3.Schema){},
+	}
+
+	codes := make([]any, len(domain.ErrorCodes))
+	for i, code := range domain.ErrorCodes {
+		codes[i] = string(code)
+	}
+	s.enums[reflect.TypeOf(domain.ErrorCode(""))] = codes
+	return s
+}
+
+// Enum documents the allowed values of a named type, e.g. a string status.
+func (s *Spec) Enum(values ...any) {
+	if len(values) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]any, len(values))
+	for i, v := range values {
+		out[i] = enumValue(reflect.TypeOf(v), fmt.Sprint(v))
+	}
+	s.enums[reflect.TypeOf(values[0])] = out
+}
+
+// Rule documents a custom binding rule (e.g. "username") by adjusting the
+// schema of every field that uses it.
+func (s *Spec) Rule(name string, describe func(*openapi3.Schema)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rules[name] = describe
+}
+
+// Add documents a route. path uses gin syntax (/users/:id). It panics on a
+// type that can't be described, like gin does for an invalid route.
+func (s *Spec) Add(method, path string, op Operation, auth, deprecated bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, err := s.operation(op, auth, deprecated)
+	if err != nil {
+		panic(fmt.Sprintf("openapi: %s %s: %v", method, path, err))
+	}
+
+	path = Path(path)
+	item := s.doc.Paths.Value(path)
+	if item == nil {
+		item = &openapi3.PathItem{}
+		s.doc.Paths.Set(path, item)
+	}
+	item.SetOperation(method, o)
+}
+
+// Document returns the description built so far.
+func (s *Spec) Document() *openapi3.T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.doc
+}
+
+// Handler serves the document as JSON. Routes are all registered before the
+// server starts, so it is encoded once.
+func (s *Spec) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		s.encodeOnce.Do(func() {
+			s.encoded, s.encodeErr = json.Marshal(s.Document())
+		})
+		if s.encodeErr != nil {
+			_ = c.Error(s.encodeErr)
+			return
+		}
+		c.Data(http.StatusOK, "application/json; charset=utf-8", s.encoded)
+	}
+}
+
+// Path converts gin path parameters (:id, *rest) to OpenAPI templates.
+func Path(ginPath string) string {
+	segments := strings.Split(ginPath, "/")
+	for i, seg := range segments {
+		if strings.HasPrefix(seg, ":") || strings.HasPrefix(seg, "*") {
+			segments[i] = "{" + seg[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func (s *Spec) operation(op Operation, auth, deprecated bool) (*openapi3.Operation, error) {
+	o := openapi3.NewOperation()
+	o.Summary = op.Summary
+	o.Description = op.Description
+	o.Tags = op.Tags
+	o.Deprecated = deprecated
+	if auth {
+		o.Security = openapi3.NewSecurityRequirements().With(openapi3.NewSecurityRequirement().Authenticate(bearerAuth))
+	}
+
+	for _, p := range []struct {
+		value any
+		in    string
+		tag   string
+	}{{op.Params, openapi3.ParameterInPath, "uri"}, {op.Query, openapi3.ParameterInQuery, "form"}} {
+		if p.value == nil {
+			continue
+		}
+		params, err := s.parameters(p.value, p.in, p.tag)
+		if err != nil {
+			return nil, err
+		}
+		o.Parameters = append(o.Parameters, params...)
+	}
+
+	if op.Request != nil {
+		ref, err := s.schema(op.Request)
+		if err != nil {
+			return nil, err
+		}
+		o.RequestBody = &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().
+			WithRequired(true).
+			WithJSONSchemaRef(ref)}
+	}
+
+	o.Responses = openapi3.NewResponses(openapi3.WithName("default", s.errorResponse("Unexpected error")))
+	for _, r := range op.Responses {
+		resp, err := s.response(r)
+		if err != nil {
+			return nil, err
+		}
+		o.AddResponse(r.Status, resp)
+	}
+	for _, status := range op.Errors {
+		o.AddResponse(status, s.errorResponse(http.StatusText(status)))
+	}
+	return o, nil
+}
+
+func (s *Spec) response(r Response) (*openapi3.Response, error) {
+	description := r.Description
+	if description == "" {
+		description = http.StatusText(r.Status)
+	}
+	resp := openapi3.NewResponse().WithDescription(description)
+	if r.Body == nil {
+		return resp, nil
+	}
+
+	ref, err := s.schema(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	if r.Data != nil {
+		data, err := s.schema(r.Data)
+		if err != nil {
+			return nil, err
+		}
+		envelope := openapi3.NewObjectSchema().WithPropertyRef("data", data)
+		envelope.Required = []string{"data"}
+		allOf := openapi3.NewSchema()
+		allOf.AllOf = openapi3.SchemaRefs{ref, openapi3.NewSchemaRef("", envelope)}
+		ref = openapi3.NewSchemaRef("", allOf)
+	}
+
+	contentType := r.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	resp.Content = openapi3.NewContentWithSchemaRef(ref, []string{contentType})
+	return resp, nil
+}
+
+func (s *Spec) errorResponse(description string) *openapi3.Response {
+	ref, err := s.schema(domain.ErrorResponse{})
+	if err != nil {
+		panic(fmt.Sprintf("openapi: ErrorResponse: %v", err))
+	}
+	return openapi3.NewResponse().WithDescription(description).WithJSONSchemaRef(ref)
+}
+
+func (s *Spec) parameters(value any, in, tagName string) (openapi3.Parameters, error) {
+	t := reflect.TypeOf(value)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s parameters must be a struct, got %s", in, t)
+	}
+
+	var params openapi3.Parameters
+	for _, f := range reflect.VisibleFields(t) {
+		name := strings.SplitN(f.Tag.Get(tagName), ",", 2)[0]
+		if name == "" || name == "-" || !f.IsExported() {
+			continue
+		}
+
+		ref, err := s.fieldSchema(f)
+		if err != nil {
+			return nil, err
+		}
+		p := &openapi3.Parameter{
+			Name:     name,
+			In:       in,
+			Required: in == openapi3.ParameterInPath || hasRule(f.Tag, "required"),
+			Schema:   ref,
+		}
+		params = append(params, &openapi3.ParameterRef{Value: p})
+	}
+	return params, nil
+}
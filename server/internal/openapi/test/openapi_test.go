@@ -0,0 +1,130 @@
+package openapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Simpolette/HeartSteal/server/internal/openapi"
+)
+
+type createRoomRequest struct {
+	Name     string   `json:"name"     binding:"required,min=3,max=32"`
+	Capacity int      `json:"capacity" binding:"required,gte=2,lte=8"`
+	Mode     string   `json:"mode"     binding:"omitempty,oneof=casual ranked"`
+	Tags     []string `json:"tags"     binding:"omitempty,max=5"`
+}
+
+type roomParams struct {
+	ID string `uri:"id" binding:"required"`
+}
+
+type listQuery struct {
+	Limit  int    `form:"limit"  binding:"omitempty,min=1,max=100"`
+	Cursor string `form:"cursor"`
+}
+
+type room struct {
+	ID      string  `json:"id"`
+	Invite  *string `json:"invite"`
+	Private bool    `json:"private,omitempty"`
+}
+
+func TestPath(t *testing.T) {
+	assert.Equal(t, "/api/v1/rooms/{id}/players/{player}", openapi.Path("/api/v1/rooms/:id/players/:player"))
+	assert.Equal(t, "/files/{path}", openapi.Path("/files/*path"))
+	assert.Equal(t, "/healthz", openapi.Path("/healthz"))
+}
+
+func TestRouter_Handle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	spec := openapi.NewSpec("test", "1.0.0", "")
+	rooms := spec.Router(engine.Group("/api/v1")).Group("/rooms").Authenticated()
+
+	rooms.POST("", openapi.Operation{Request: createRoomRequest{}, Responses: []openapi.Response{{Status: http.StatusCreated, Body: room{}}}}, func(c *gin.Context) {})
+	rooms.GET("/:id", openapi.Operation{Params: roomParams{}, Query: listQuery{}}, func(c *gin.Context) {})
+	rooms.Deprecated().DELETE("/:id", openapi.Operation{Params: roomParams{}}, func(c *gin.Context) {})
+
+	doc := spec.Document()
+	require.NoError(t, doc.Validate(context.Background()))
+	assert.Len(t, engine.Routes(), 3)
+
+	create := doc.Paths.Value("/api/v1/rooms").Post
+	require.NotNil(t, create)
+	assert.NotEmpty(t, create.Security, "authenticated routes require the bearer scheme")
+	assert.False(t, create.Deprecated)
+	assert.NotNil(t, create.Responses.Default(), "every operation documents the error body")
+
+	request := doc.Components.Schemas["CreateRoomRequest"].Value
+	assert.ElementsMatch(t, []string{"name", "capacity"}, request.Required)
+	assert.Equal(t, uint64(3), request.Properties["name"].Value.MinLength)
+	assert.Equal(t, uint64(32), *request.Properties["name"].Value.MaxLength)
+	assert.Equal(t, 2.0, *request.Properties["capacity"].Value.Min)
+	assert.Equal(t, 8.0, *request.Properties["capacity"].Value.Max)
+	assert.Equal(t, []any{"casual", "ranked"}, request.Properties["mode"].Value.Enum)
+	assert.Equal(t, uint64(5), *request.Properties["tags"].Value.MaxItems)
+
+	response := doc.Components.Schemas["Room"].Value
+	assert.Equal(t, []string{"id"}, response.Required, "pointers and omitempty fields are optional")
+
+	get := doc.Paths.Value("/api/v1/rooms/{id}").Get
+	require.NotNil(t, get)
+	require.Len(t, get.Parameters, 3)
+	assert.Equal(t, "id", get.Parameters[0].Value.Name)
+	assert.True(t, get.Parameters[0].Value.Required)
+	assert.Equal(t, "limit", get.Parameters[1].Value.Name)
+	assert.False(t, get.Parameters[1].Value.Required)
+	assert.Equal(t, 100.0, *get.Parameters[1].Value.Schema.Value.Max)
+
+	assert.True(t, doc.Paths.Value("/api/v1/rooms/{id}").Delete.Deprecated)
+}
+
+func TestDocsHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/docs", openapi.DocsHandler("/openapi.json", "/docs/assets"))
+	engine.GET("/docs/assets/:file", openapi.DocsAssetsHandler())
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	t.Run("Page", func(t *testing.T) {
+		w := get("/docs")
+
+		if !openapi.DocsVendored() {
+			assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+			assert.Contains(t, w.Body.String(), "vendor_swagger_ui")
+			return
+		}
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `href="/docs/assets/swagger-ui.css"`)
+		assert.Contains(t, w.Body.String(), `src="/docs/assets/swagger-ui-bundle.js"`)
+		assert.Contains(t, w.Body.String(), `url: "/openapi.json"`)
+		assert.NotContains(t, w.Body.String(), "https://", "nothing is loaded from a CDN")
+	})
+
+	t.Run("Assets", func(t *testing.T) {
+		if !openapi.DocsVendored() {
+			t.Skip("Swagger UI isn't vendored")
+		}
+		w := get("/docs/assets/swagger-ui-bundle.js")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "javascript")
+		assert.NotEmpty(t, w.Body.Bytes())
+	})
+
+	t.Run("OnlyAssets", func(t *testing.T) {
+		for _, path := range []string{"/docs/assets/README.md", "/docs/assets/docs.html", "/docs/assets/..%2Fdocs.html"} {
+			assert.Equal(t, http.StatusNotFound, get(path).Code, path)
+		}
+	})
+}
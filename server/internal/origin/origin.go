@@ -0,0 +1,91 @@
+// Package origin matches request Origin headers against the configured
+// allowlist. CORS, WebSocket upgrades and config validation share it.
+package origin
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+type originPattern struct {
+	scheme string
+	host   string
+	port   string
+	suffix bool
+}
+
+// Matcher reports whether an Origin header is in an allowlist of origins.
+// Entries are exact origins, "*" for any, or "scheme://*.host[:port]" for
+// every subdomain of host.
+type Matcher struct {
+	any      bool
+	patterns []originPattern
+}
+
+func NewMatcher(origins []string) (*Matcher, error) {
+	m := &Matcher{}
+	for _, origin := range origins {
+		origin = strings.TrimSpace(origin)
+		if origin == "" {
+			continue
+		}
+		if origin == "*" {
+			m.any = true
+			continue
+		}
+
+		suffix := false
+		raw := origin
+		if i := strings.Index(raw, "://*."); i >= 0 {
+			suffix = true
+			raw = raw[:i+3] + raw[i+5:]
+		}
+
+		u, err := url.Parse(raw)
+		if err != nil || u.Scheme == "" || u.Host == "" || strings.Contains(raw, "*") || (u.Path != "" && u.Path != "/") {
+			return nil, fmt.Errorf("invalid origin %q", origin)
+		}
+
+		m.patterns = append(m.patterns, originPattern{
+			scheme: strings.ToLower(u.Scheme),
+			host:   strings.ToLower(u.Hostname()),
+			port:   u.Port(),
+			suffix: suffix,
+		})
+	}
+	return m, nil
+}
+
+// Any reports whether every origin matches, i.e. the list contains "*".
+func (m *Matcher) Any() bool {
+	return m.any
+}
+
+func (m *Matcher) Match(origin string) bool {
+	if m.any {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	scheme, host, port := strings.ToLower(u.Scheme), strings.ToLower(u.Hostname()), u.Port()
+
+	for _, p := range m.patterns {
+		if p.scheme != scheme || p.port != port {
+			continue
+		}
+		if p.suffix {
+			if strings.HasSuffix(host, "."+p.host) {
+				return true
+			}
+			continue
+		}
+		if p.host == host {
+			return true
+		}
+	}
+	return false
+}
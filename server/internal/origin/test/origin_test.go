@@ -0,0 +1,44 @@
+package origin_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Simpolette/HeartSteal/server/internal/origin"
+)
+
+func TestMatcher_Match(t *testing.T) {
+	m, err := origin.NewMatcher([]string{"http://localhost:3000", "https://*.heartsteal.gg"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		origin  string
+		allowed bool
+	}{
+		{"http://localhost:3000", true},
+		{"http://localhost:3001", false},
+		{"https://localhost:3000", false},
+		{"https://play.heartsteal.gg", true},
+		{"https://eu.play.heartsteal.gg", true},
+		{"https://heartsteal.gg", false},
+		{"http://play.heartsteal.gg", false},
+		{"https://evilheartsteal.gg", false},
+		{"https://heartsteal.gg.evil.com", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.allowed, m.Match(tt.origin), tt.origin)
+	}
+
+	_, err = origin.NewMatcher([]string{"https://api.*.heartsteal.gg"})
+	assert.Error(t, err)
+}
+
+func TestMatcher_Any(t *testing.T) {
+	m, err := origin.NewMatcher([]string{"http://localhost:3000", "*"})
+	require.NoError(t, err)
+
+	assert.True(t, m.Any())
+	assert.True(t, m.Match("https://anything.example"))
+}
@@ -0,0 +1,33 @@
+package ratelimit
+
+import "github.com/gin-gonic/gin"
+
+// KeyFunc extracts the identity a policy limits. Returning false skips the
+// limit for that request (e.g. ByUserID on an anonymous request).
+type KeyFunc func(c *gin.Context) (string, bool)
+
+// ByIP limits by client IP as resolved through the trusted proxies.
+func ByIP(c *gin.Context) (string, bool) {
+	return "ip:" + c.ClientIP(), true
+}
+
+// ByUserID limits authenticated users; it must run after JwtAuthMiddleware.
+func ByUserID(c *gin.Context) (string, bool) {
+	userID := c.GetString("x-user-id")
+	if userID == "" {
+		return "", false
+	}
+	return "user:" + userID, true
+}
+
+// FirstOf tries each KeyFunc in order, e.g. FirstOf(ByUserID, ByIP).
+func FirstOf(funcs ...KeyFunc) KeyFunc {
+	return func(c *gin.Context) (string, bool) {
+		for _, f := range funcs {
+			if key, ok := f(c); ok {
+				return key, true
+			}
+		}
+		return "", false
+	}
+}
@@ -0,0 +1,147 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	// token bucket
+	tokens float64
+	last   time.Time
+
+	// sliding window
+	windowStart time.Time
+	current     int
+	previous    int
+
+	expires time.Time
+}
+
+// MemoryStore keeps limiter state in process. It is suitable for a single
+// instance; use RedisStore when several replicas must share limits.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+	sweeps  int
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// SetClock replaces the time source, for tests.
+func (s *MemoryStore) SetClock(now func() time.Time) {
+	s.now = now
+}
+
+func (s *MemoryStore) Allow(_ context.Context, key string, policy Policy) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	k := storageKey(policy, key)
+	b, ok := s.buckets[k]
+	if !ok {
+		b = &bucket{tokens: float64(policy.capacity()), last: now, windowStart: now}
+		s.buckets[k] = b
+	}
+	b.expires = now.Add(2 * policy.Window)
+
+	if policy.Algorithm == TokenBucket {
+		return allowTokenBucket(b, policy, now), nil
+	}
+	return allowSlidingWindow(b, policy, now), nil
+}
+
+// sweep drops idle buckets every few hundred calls so memory stays bounded
+// without a background goroutine.
+func (s *MemoryStore) sweep(now time.Time) {
+	s.sweeps++
+	if s.sweeps < 256 {
+		return
+	}
+	s.sweeps = 0
+	for k, b := range s.buckets {
+		if now.After(b.expires) {
+			delete(s.buckets, k)
+		}
+	}
+}
+
+func allowTokenBucket(b *bucket, policy Policy, now time.Time) Result {
+	capacity := float64(policy.capacity())
+	rate := float64(policy.Limit) / policy.Window.Seconds()
+
+	elapsed := now.Sub(b.last).Seconds()
+	b.tokens = math.Min(capacity, b.tokens+elapsed*rate)
+	b.last = now
+
+	res := Result{Limit: policy.capacity()}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = secondsToDuration((1 - b.tokens) / rate)
+	}
+	res.Remaining = int(math.Floor(b.tokens))
+	res.ResetAfter = secondsToDuration((capacity - b.tokens) / rate)
+	return res
+}
+
+func allowSlidingWindow(b *bucket, policy Policy, now time.Time) Result {
+	elapsed := now.Sub(b.windowStart)
+	if elapsed >= policy.Window {
+		windows := int(elapsed / policy.Window)
+		if windows == 1 {
+			b.previous = b.current
+		} else {
+			b.previous = 0
+		}
+		b.current = 0
+		b.windowStart = b.windowStart.Add(time.Duration(windows) * policy.Window)
+		elapsed = now.Sub(b.windowStart)
+	}
+
+	weight := 1 - float64(elapsed)/float64(policy.Window)
+	estimated := float64(b.previous)*weight + float64(b.current)
+
+	res := Result{Limit: policy.Limit, ResetAfter: policy.Window - elapsed}
+	if estimated+1 <= float64(policy.Limit) {
+		b.current++
+		res.Allowed = true
+		estimated++
+	} else {
+		res.RetryAfter = slidingRetryAfter(b.previous, b.current, policy, elapsed)
+	}
+	res.Remaining = int(math.Max(0, math.Floor(float64(policy.Limit)-estimated)))
+	return res
+}
+
+// slidingRetryAfter estimates when the weighted count drops enough to admit
+// one more request: either the previous window decays or a new window starts.
+func slidingRetryAfter(previous, current int, policy Policy, elapsed time.Duration) time.Duration {
+	untilNext := policy.Window - elapsed
+	if previous == 0 || current+1 > policy.Limit {
+		return untilNext
+	}
+	// previous * (1 - t/W) + current + 1 <= limit  =>  t >= W * (1 - (limit-current-1)/previous)
+	needed := 1 - float64(policy.Limit-current-1)/float64(previous)
+	wait := time.Duration(needed*float64(policy.Window)) - elapsed
+	if wait <= 0 || wait > untilNext {
+		return untilNext
+	}
+	return wait
+}
+
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type Algorithm string
+
+const (
+	// TokenBucket refills Limit tokens per Window continuously and allows
+	// bursts of up to Burst requests.
+	TokenBucket Algorithm = "token_bucket"
+	// SlidingWindow allows at most Limit requests in any Window, approximated
+	// by weighting the previous fixed window.
+	SlidingWindow Algorithm = "sliding_window"
+)
+
+// Policy describes one limit. Name namespaces the keys so two policies
+// keyed by the same client never share a bucket.
+type Policy struct {
+	Name      string
+	Algorithm Algorithm
+	Limit     int
+	Window    time.Duration
+	Burst     int
+	Key       KeyFunc
+}
+
+func (p Policy) capacity() int {
+	if p.Algorithm == TokenBucket && p.Burst > 0 {
+		return p.Burst
+	}
+	return p.Limit
+}
+
+// Header renders the policy for the RateLimit-Policy header, e.g. "10;w=60".
+// Its quota is the capacity stores report as Result.Limit, the burst of a
+// token bucket, so it agrees with the RateLimit-Limit header.
+func (p Policy) Header() string {
+	return fmt.Sprintf("%d;w=%d", p.capacity(), int(p.Window.Seconds()))
+}
+
+type Result struct {
+	Allowed bool
+	// Limit is the policy's capacity: Burst for a token bucket that sets
+	// one, Limit otherwise.
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration
+	RetryAfter time.Duration
+}
+
+// Store is the state backend. Implementations must be safe for concurrent use
+// and apply the decision for one key atomically.
+type Store interface {
+	Allow(ctx context.Context, key string, policy Policy) (Result, error)
+}
+
+func storageKey(policy Policy, key string) string {
+	return "ratelimit:" + policy.Name + ":" + key
+}
@@ -0,0 +1,154 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Both scripts run atomically on the server so concurrent replicas never
+// double-spend a token. Floats are returned as strings because Redis
+// truncates Lua numbers to integers.
+var tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local ttl = tonumber(ARGV[4])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'last')
+local tokens = tonumber(state[1]) or capacity
+local last = tonumber(state[2]) or now
+
+tokens = math.min(capacity, tokens + math.max(0, now - last) * rate)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'last', tostring(now))
+redis.call('PEXPIRE', KEYS[1], ttl)
+
+return {allowed, tostring(tokens)}
+`)
+
+var slidingWindowScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local elapsed = tonumber(ARGV[3])
+
+local current = tonumber(redis.call('GET', KEYS[1]) or '0')
+local previous = tonumber(redis.call('GET', KEYS[2]) or '0')
+local estimated = previous * (1 - elapsed / window) + current
+
+local allowed = 0
+if estimated + 1 <= limit then
+	current = redis.call('INCR', KEYS[1])
+	redis.call('PEXPIRE', KEYS[1], window * 2)
+	estimated = estimated + 1
+	allowed = 1
+end
+
+return {allowed, tostring(estimated), current, previous}
+`)
+
+// RedisStore shares limiter state between replicas through any server that
+// speaks the Redis protocol and supports EVAL.
+type RedisStore struct {
+	client redis.Scripter
+	now    func() time.Time
+}
+
+func NewRedisStore(client redis.Scripter) *RedisStore {
+	return &RedisStore{
+		client: client,
+		now:    time.Now,
+	}
+}
+
+// SetClock replaces the time source, for tests.
+func (s *RedisStore) SetClock(now func() time.Time) {
+	s.now = now
+}
+
+func (s *RedisStore) Allow(ctx context.Context, key string, policy Policy) (Result, error) {
+	if policy.Algorithm == TokenBucket {
+		return s.allowTokenBucket(ctx, storageKey(policy, key), policy)
+	}
+	return s.allowSlidingWindow(ctx, storageKey(policy, key), policy)
+}
+
+func (s *RedisStore) allowTokenBucket(ctx context.Context, key string, policy Policy) (Result, error) {
+	capacity := policy.capacity()
+	ratePerMs := float64(policy.Limit) / float64(policy.Window.Milliseconds())
+
+	vals, err := tokenBucketScript.Run(ctx, s.client, []string{key},
+		capacity,
+		strconv.FormatFloat(ratePerMs, 'f', -1, 64),
+		s.now().UnixMilli(),
+		(2 * policy.Window).Milliseconds(),
+	).Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	allowed := vals[0].(int64) == 1
+	tokens, err := strconv.ParseFloat(vals[1].(string), 64)
+	if err != nil {
+		return Result{}, err
+	}
+
+	rate := ratePerMs * 1000
+	res := Result{
+		Allowed:    allowed,
+		Limit:      capacity,
+		Remaining:  int(math.Floor(tokens)),
+		ResetAfter: secondsToDuration((float64(capacity) - tokens) / rate),
+	}
+	if !allowed {
+		res.RetryAfter = secondsToDuration((1 - tokens) / rate)
+	}
+	return res, nil
+}
+
+func (s *RedisStore) allowSlidingWindow(ctx context.Context, key string, policy Policy) (Result, error) {
+	now := s.now()
+	window := policy.Window.Milliseconds()
+	index := now.UnixMilli() / window
+	elapsed := time.Duration(now.UnixMilli()-index*window) * time.Millisecond
+
+	vals, err := slidingWindowScript.Run(ctx, s.client,
+		[]string{
+			key + ":" + strconv.FormatInt(index, 10),
+			key + ":" + strconv.FormatInt(index-1, 10),
+		},
+		policy.Limit,
+		window,
+		elapsed.Milliseconds(),
+	).Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	allowed := vals[0].(int64) == 1
+	estimated, err := strconv.ParseFloat(vals[1].(string), 64)
+	if err != nil {
+		return Result{}, err
+	}
+	current, previous := int(vals[2].(int64)), int(vals[3].(int64))
+
+	res := Result{
+		Allowed:    allowed,
+		Limit:      policy.Limit,
+		Remaining:  int(math.Max(0, math.Floor(float64(policy.Limit)-estimated))),
+		ResetAfter: policy.Window - elapsed,
+	}
+	if !allowed {
+		res.RetryAfter = slidingRetryAfter(previous, current, policy, elapsed)
+	}
+	return res, nil
+}
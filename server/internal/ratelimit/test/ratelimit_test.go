@@ -0,0 +1,154 @@
+package ratelimit_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Simpolette/HeartSteal/server/internal/apierror"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/middleware"
+	"github.com/Simpolette/HeartSteal/server/internal/ratelimit"
+)
+
+type clockedStore interface {
+	ratelimit.Store
+	SetClock(func() time.Time)
+}
+
+// stores runs every test against both backends; Redis is replaced by an
+// in-process miniredis server.
+func stores(t *testing.T) map[string]func() (clockedStore, *time.Time) {
+	return map[string]func() (clockedStore, *time.Time){
+		"Memory": func() (clockedStore, *time.Time) {
+			now := time.Unix(1_700_000_000, 0)
+			s := ratelimit.NewMemoryStore()
+			s.SetClock(func() time.Time { return now })
+			return s, &now
+		},
+		"Redis": func() (clockedStore, *time.Time) {
+			srv := miniredis.RunT(t)
+			client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+			t.Cleanup(func() { _ = client.Close() })
+
+			now := time.Unix(1_700_000_000, 0)
+			s := ratelimit.NewRedisStore(client)
+			s.SetClock(func() time.Time { return now })
+			return s, &now
+		},
+	}
+}
+
+func TestStore_TokenBucket(t *testing.T) {
+	policy := ratelimit.Policy{Name: "login", Algorithm: ratelimit.TokenBucket, Limit: 60, Window: time.Minute, Burst: 3}
+
+	for name, newStore := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			s, now := newStore()
+			ctx := context.Background()
+
+			for i := 0; i < 3; i++ {
+				res, err := s.Allow(ctx, "ip:1", policy)
+				require.NoError(t, err)
+				assert.True(t, res.Allowed)
+				assert.Equal(t, 2-i, res.Remaining)
+			}
+
+			res, err := s.Allow(ctx, "ip:1", policy)
+			require.NoError(t, err)
+			assert.False(t, res.Allowed)
+			assert.Equal(t, time.Second, res.RetryAfter)
+
+			// Another client has its own bucket.
+			res, _ = s.Allow(ctx, "ip:2", policy)
+			assert.True(t, res.Allowed)
+
+			// One token refills per second.
+			*now = now.Add(time.Second)
+			res, _ = s.Allow(ctx, "ip:1", policy)
+			assert.True(t, res.Allowed)
+		})
+	}
+}
+
+func TestStore_SlidingWindow(t *testing.T) {
+	policy := ratelimit.Policy{Name: "signup", Algorithm: ratelimit.SlidingWindow, Limit: 4, Window: time.Minute}
+
+	for name, newStore := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			s, now := newStore()
+			ctx := context.Background()
+
+			for i := 0; i < 4; i++ {
+				res, err := s.Allow(ctx, "ip:1", policy)
+				require.NoError(t, err)
+				assert.True(t, res.Allowed)
+			}
+
+			res, err := s.Allow(ctx, "ip:1", policy)
+			require.NoError(t, err)
+			assert.False(t, res.Allowed)
+			assert.Equal(t, 0, res.Remaining)
+			assert.Greater(t, res.RetryAfter, time.Duration(0))
+
+			// Two windows later the previous count no longer weighs in.
+			*now = now.Add(2 * time.Minute)
+			res, _ = s.Allow(ctx, "ip:1", policy)
+			assert.True(t, res.Allowed)
+		})
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	apierror.Register(domain.ErrRateLimited, http.StatusTooManyRequests, domain.CodeRateLimited, "Too many requests")
+
+	// serve returns a function making one request through policy.
+	serve := func(policy ratelimit.Policy) func() *httptest.ResponseRecorder {
+		r := gin.New()
+		r.Use(middleware.ErrorHandlerMiddleware())
+		r.GET("/", middleware.RateLimitMiddleware(ratelimit.NewMemoryStore(), policy), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+		return func() *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			return w
+		}
+	}
+
+	t.Run("SlidingWindow", func(t *testing.T) {
+		request := serve(ratelimit.Policy{Name: "test", Algorithm: ratelimit.SlidingWindow, Limit: 1, Window: time.Minute, Key: ratelimit.ByIP})
+
+		first := request()
+		assert.Equal(t, http.StatusOK, first.Code)
+		assert.Equal(t, "1", first.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "0", first.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "1;w=60", first.Header().Get("RateLimit-Policy"))
+
+		second := request()
+		assert.Equal(t, http.StatusTooManyRequests, second.Code)
+		assert.NotEmpty(t, second.Header().Get("Retry-After"))
+		assert.Contains(t, second.Body.String(), `"code":"RATE_LIMITED"`)
+	})
+
+	// A bucket's burst is the quota both headers announce, not its refill.
+	t.Run("TokenBucketBurst", func(t *testing.T) {
+		request := serve(ratelimit.Policy{Name: "test", Algorithm: ratelimit.TokenBucket, Limit: 1, Burst: 3, Window: time.Minute, Key: ratelimit.ByIP})
+
+		first := request()
+		assert.Equal(t, http.StatusOK, first.Code)
+		assert.Equal(t, "3", first.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "2", first.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "3;w=60", first.Header().Get("RateLimit-Policy"))
+	})
+}
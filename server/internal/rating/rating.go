@@ -0,0 +1,146 @@
+// Package rating implements Glicko-2 (http://www.glicko.net/glicko/glicko2.pdf).
+// Like game and matchmaking it does no I/O; callers load and store ratings
+// and decide how long a rating period is.
+package rating
+
+import "math"
+
+const (
+	DefaultRating     = 1500
+	DefaultRD         = 350
+	DefaultVolatility = 0.06
+	// DefaultTau constrains how fast volatility changes. Glickman suggests
+	// 0.3 to 1.2; lower suits games with fewer upsets.
+	DefaultTau = 0.5
+)
+
+// scale converts between the Glicko and Glicko-2 scales.
+const scale = 173.7178
+
+// convergence is the tolerance of the volatility iteration.
+const convergence = 0.000001
+
+// Rating is a player's strength: RD is how uncertain it is and Volatility
+// how erratic their results are.
+type Rating struct {
+	Rating     float64
+	RD         float64
+	Volatility float64
+}
+
+// Default is the rating of a player who never played.
+func Default() Rating {
+	return Rating{Rating: DefaultRating, RD: DefaultRD, Volatility: DefaultVolatility}
+}
+
+// Result is one game against an opponent. Score is 1 for a win, 0.5 for a
+// draw and 0 for a loss.
+type Result struct {
+	Opponent Rating
+	Score    float64
+}
+
+// Decay grows RD for periods rating periods without games, up to DefaultRD.
+// Fractional periods are allowed.
+func (r Rating) Decay(periods float64) Rating {
+	if periods <= 0 {
+		return r
+	}
+	phi := r.RD / scale
+	phi = math.Sqrt(phi*phi + periods*r.Volatility*r.Volatility)
+	r.RD = min(phi*scale, DefaultRD)
+	return r
+}
+
+// Update rates the results of one rating period. A period without results
+// only grows RD.
+func Update(r Rating, results []Result, tau float64) Rating {
+	if len(results) == 0 {
+		return r.Decay(1)
+	}
+
+	mu, phi := (r.Rating-DefaultRating)/scale, r.RD/scale
+
+	var invV, sum float64
+	for _, res := range results {
+		muJ, phiJ := (res.Opponent.Rating-DefaultRating)/scale, res.Opponent.RD/scale
+		g := 1 / math.Sqrt(1+3*phiJ*phiJ/(math.Pi*math.Pi))
+		e := 1 / (1 + math.Exp(-g*(mu-muJ)))
+		invV += g * g * e * (1 - e)
+		sum += g * (res.Score - e)
+	}
+	v := 1 / invV
+	delta := v * sum
+
+	sigma := volatility(phi, r.Volatility, v, delta, tau)
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	phi = 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	mu += phi * phi * sum
+
+	return Rating{
+		Rating:     mu*scale + DefaultRating,
+		RD:         min(phi*scale, DefaultRD),
+		Volatility: sigma,
+	}
+}
+
+// volatility is step 5 of the paper: the Illinois algorithm finds the new
+// volatility.
+func volatility(phi, sigma, v, delta, tau float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/(tau*tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		B = a - k*tau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > convergence {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+	return math.Exp(A / 2)
+}
+
+// Placements rates a game of several players as a round robin: each player
+// beat everyone placed below them and drew with those placed the same.
+// placements[i] is player i's rank, 1 being first. All players are rated
+// against the others' ratings from before the game.
+func Placements(ratings []Rating, placements []int, tau float64) []Rating {
+	updated := make([]Rating, len(ratings))
+	for i := range ratings {
+		results := make([]Result, 0, len(ratings)-1)
+		for j := range ratings {
+			if i == j {
+				continue
+			}
+			score := 0.5
+			if placements[i] < placements[j] {
+				score = 1
+			} else if placements[i] > placements[j] {
+				score = 0
+			}
+			results = append(results, Result{Opponent: ratings[j], Score: score})
+		}
+		updated[i] = Update(ratings[i], results, tau)
+	}
+	return updated
+}
@@ -0,0 +1,74 @@
+package rating_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Simpolette/HeartSteal/server/internal/rating"
+)
+
+func TestUpdate(t *testing.T) {
+	t.Run("GlickmanExample", func(t *testing.T) {
+		// The worked example from the Glicko-2 paper.
+		player := rating.Rating{Rating: 1500, RD: 200, Volatility: 0.06}
+		results := []rating.Result{
+			{Opponent: rating.Rating{Rating: 1400, RD: 30}, Score: 1},
+			{Opponent: rating.Rating{Rating: 1550, RD: 100}, Score: 0},
+			{Opponent: rating.Rating{Rating: 1700, RD: 300}, Score: 0},
+		}
+
+		got := rating.Update(player, results, 0.5)
+
+		assert.InDelta(t, 1464.06, got.Rating, 0.01)
+		assert.InDelta(t, 151.52, got.RD, 0.01)
+		assert.InDelta(t, 0.05999, got.Volatility, 0.00001)
+	})
+
+	t.Run("NoGamesOnlyGrowsRD", func(t *testing.T) {
+		player := rating.Rating{Rating: 1600, RD: 50, Volatility: 0.06}
+
+		got := rating.Update(player, nil, 0.5)
+
+		assert.Equal(t, 1600.0, got.Rating)
+		assert.InDelta(t, 51.07, got.RD, 0.01)
+		assert.Equal(t, 0.06, got.Volatility)
+	})
+
+	t.Run("UpsetMovesMore", func(t *testing.T) {
+		underdog := rating.Rating{Rating: 1300, RD: 100, Volatility: 0.06}
+		favourite := rating.Rating{Rating: 1700, RD: 100, Volatility: 0.06}
+		peer := rating.Rating{Rating: 1300, RD: 100, Volatility: 0.06}
+
+		upset := rating.Update(underdog, []rating.Result{{Opponent: favourite, Score: 1}}, 0.5)
+		expected := rating.Update(underdog, []rating.Result{{Opponent: peer, Score: 1}}, 0.5)
+
+		assert.Greater(t, upset.Rating-underdog.Rating, expected.Rating-underdog.Rating)
+	})
+}
+
+func TestRating_Decay(t *testing.T) {
+	r := rating.Rating{Rating: 1500, RD: 50, Volatility: 0.06}
+
+	assert.Equal(t, r, r.Decay(0))
+	assert.Equal(t, rating.Update(r, nil, 0.5).RD, r.Decay(1).RD)
+	assert.Greater(t, r.Decay(30).RD, r.Decay(1).RD)
+	assert.Equal(t, 1500.0, r.Decay(30).Rating, "only the deviation decays")
+	assert.Equal(t, float64(rating.DefaultRD), r.Decay(1e6).RD, "capped at a newcomer's deviation")
+}
+
+func TestPlacements(t *testing.T) {
+	players := []rating.Rating{rating.Default(), rating.Default(), rating.Default(), rating.Default()}
+
+	got := rating.Placements(players, []int{2, 1, 3, 3}, rating.DefaultTau)
+
+	require.Len(t, got, 4)
+	assert.Greater(t, got[1].Rating, got[0].Rating, "first beats second")
+	assert.Greater(t, got[0].Rating, rating.DefaultRating+0.0)
+	assert.InDelta(t, got[2].Rating, got[3].Rating, 1e-9, "a tie rates both the same")
+	assert.Less(t, got[2].Rating, rating.DefaultRating+0.0)
+	for _, r := range got {
+		assert.Less(t, r.RD, float64(rating.DefaultRD))
+	}
+}
@@ -0,0 +1,193 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/Simpolette/HeartSteal/server/internal/apierror"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/logger"
+)
+
+// Reasons a connection closed, as reported in metrics and logs.
+const (
+	reasonClientClosed    = "client_closed"
+	reasonTimeout         = "timeout"
+	reasonMessageTooLarge = "message_too_large"
+	reasonReadError       = "read_error"
+	reasonWriteError      = "write_error"
+	reasonSlowConsumer    = "slow_consumer"
+	reasonShutdown        = "shutdown"
+)
+
+var errUnknownMessageType = domain.NewAppError(domain.CodeInvalidRequest, http.StatusBadRequest, "Unknown message type", nil)
+
+type conn struct {
+	hub    *Hub
+	ws     *websocket.Conn
+	userID string
+
+	send    chan []byte
+	done    chan struct{}
+	written chan struct{}
+
+	closeOnce sync.Once
+	closeCode int
+	reason    string
+
+	// topics is guarded by hub.mu.
+	topics map[string]struct{}
+}
+
+func newConn(h *Hub, ws *websocket.Conn, userID string) *conn {
+	return &conn{
+		hub:     h,
+		ws:      ws,
+		userID:  userID,
+		send:    make(chan []byte, h.cfg.SendQueueSize),
+		done:    make(chan struct{}),
+		written: make(chan struct{}),
+		topics:  make(map[string]struct{}),
+	}
+}
+
+// close asks the write loop to send a close frame and hang up. Only the
+// first reason is kept.
+func (c *conn) close(code int, reason string) {
+	c.closeOnce.Do(func() {
+		c.closeCode, c.reason = code, reason
+		close(c.done)
+	})
+}
+
+// enqueue never blocks: a client whose queue is full is disconnected.
+func (c *conn) enqueue(msg []byte, typ MessageType) {
+	select {
+	case <-c.done:
+		return
+	default:
+	}
+	select {
+	case c.send <- msg:
+		c.hub.metrics.RealtimeMessageSent(string(typ))
+	default:
+		c.close(websocket.CloseTryAgainLater, reasonSlowConsumer)
+	}
+}
+
+func (c *conn) reply(env Envelope) {
+	msg, err := json.Marshal(env)
+	if err != nil {
+		return
+	}
+	c.enqueue(msg, env.Type)
+}
+
+func (c *conn) replyError(ctx context.Context, in Envelope, err error) {
+	appErr := apierror.Resolve(err)
+	if appErr.Status >= http.StatusInternalServerError {
+		logger.FromContext(ctx).Error("realtime message failed", "type", in.Type, "topic", in.Topic, "error", err)
+	}
+	resp := apierror.Localize(ctx, appErr)
+	c.reply(Envelope{Type: TypeError, ID: in.ID, Topic: in.Topic, Error: &resp})
+}
+
+func (c *conn) readLoop(ctx context.Context) {
+	pongWait := 2 * c.hub.cfg.PingInterval
+	c.ws.SetReadLimit(c.hub.cfg.MaxMessageBytes)
+	_ = c.ws.SetReadDeadline(time.Now().Add(pongWait))
+	c.ws.SetPongHandler(func(string) error {
+		return c.ws.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := c.ws.ReadMessage()
+		if err != nil {
+			c.close(websocket.CloseNormalClosure, readErrorReason(err))
+			return
+		}
+		_ = c.ws.SetReadDeadline(time.Now().Add(pongWait))
+		c.handle(ctx, data)
+	}
+}
+
+func (c *conn) handle(ctx context.Context, data []byte) {
+	var in Envelope
+	if err := json.Unmarshal(data, &in); err != nil {
+		c.replyError(ctx, in, domain.NewAppError(domain.CodeInvalidRequest, http.StatusBadRequest, "Malformed message", err))
+		return
+	}
+
+	switch in.Type {
+	case TypePing:
+		c.reply(Envelope{Type: TypePong, ID: in.ID})
+	case TypeSubscribe:
+		changes, err := c.hub.subscribe(ctx, c, in.Topic)
+		if err != nil {
+			c.replyError(ctx, in, err)
+			return
+		}
+		c.reply(Envelope{Type: TypeSubscribed, ID: in.ID, Topic: in.Topic})
+		notify(changes)
+	case TypeUnsubscribe:
+		changes := c.hub.unsubscribe(c, in.Topic)
+		c.reply(Envelope{Type: TypeUnsubscribed, ID: in.ID, Topic: in.Topic})
+		notify(changes)
+	default:
+		c.replyError(ctx, in, errUnknownMessageType)
+	}
+}
+
+// writeLoop is the only writer of data frames, as gorilla/websocket requires.
+// It also sends the pings and, once the connection is closing, the close
+// frame.
+func (c *conn) writeLoop() {
+	ticker := time.NewTicker(c.hub.cfg.PingInterval)
+	defer func() {
+		ticker.Stop()
+		_ = c.ws.Close()
+		close(c.written)
+	}()
+
+	wait := c.hub.cfg.WriteWait
+	for {
+		select {
+		case msg := <-c.send:
+			_ = c.ws.SetWriteDeadline(time.Now().Add(wait))
+			if err := c.ws.WriteMessage(websocket.TextMessage, msg); err != nil {
+				c.close(websocket.CloseAbnormalClosure, reasonWriteError)
+				return
+			}
+		case <-ticker.C:
+			if err := c.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(wait)); err != nil {
+				c.close(websocket.CloseAbnormalClosure, reasonWriteError)
+				return
+			}
+		case <-c.done:
+			_ = c.ws.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(c.closeCode, c.reason), time.Now().Add(wait))
+			return
+		}
+	}
+}
+
+func readErrorReason(err error) string {
+	var netErr net.Error
+	switch {
+	case websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway, websocket.CloseNoStatusReceived):
+		return reasonClientClosed
+	case errors.Is(err, websocket.ErrReadLimit):
+		return reasonMessageTooLarge
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return reasonTimeout
+	default:
+		return reasonReadError
+	}
+}
@@ -0,0 +1,39 @@
+// Package realtime is the WebSocket gateway. Clients subscribe to topics and
+// the server pushes events to them, all wrapped in the same JSON Envelope.
+package realtime
+
+import (
+	"encoding/json"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+)
+
+type MessageType string
+
+// Sent by clients.
+const (
+	TypeSubscribe   MessageType = "subscribe"
+	TypeUnsubscribe MessageType = "unsubscribe"
+	TypePing        MessageType = "ping"
+)
+
+// Sent by the server.
+const (
+	TypeSubscribed   MessageType = "subscribed"
+	TypeUnsubscribed MessageType = "unsubscribed"
+	TypePong         MessageType = "pong"
+	TypeEvent        MessageType = "event"
+	TypeError        MessageType = "error"
+)
+
+// Envelope is every message on the socket, in both directions. ID is chosen
+// by the client and echoed on the reply to that message, so requests and
+// replies can be matched; server-initiated messages have no ID.
+type Envelope struct {
+	Type  MessageType           `json:"type"`
+	ID    string                `json:"id,omitempty"`
+	Topic string                `json:"topic,omitempty"`
+	Event string                `json:"event,omitempty"`
+	Data  json.RawMessage       `json:"data,omitempty"`
+	Error *domain.ErrorResponse `json:"error,omitempty"`
+}
@@ -0,0 +1,336 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/logger"
+	"github.com/Simpolette/HeartSteal/server/internal/metrics"
+)
+
+var _ domain.Publisher = &Hub{}
+
+type Config struct {
+	// SendQueueSize bounds the messages waiting to be written to one
+	// connection. A client that falls further behind is disconnected rather
+	// than slowing down publishers or growing memory.
+	SendQueueSize    int
+	MaxSubscriptions int
+	MaxMessageBytes  int64
+	// PingInterval is how often the server pings. A client that sends
+	// nothing, not even a pong, for two intervals is disconnected.
+	PingInterval time.Duration
+	WriteWait    time.Duration
+	// CheckOrigin accepts or rejects the Origin of an upgrade request. Nil
+	// only accepts same-origin requests.
+	CheckOrigin func(r *http.Request) bool
+}
+
+func DefaultConfig() Config {
+	return Config{
+		SendQueueSize:    64,
+		MaxSubscriptions: 32,
+		MaxMessageBytes:  4096,
+		PingInterval:     25 * time.Second,
+		WriteWait:        10 * time.Second,
+	}
+}
+
+// Authorizer decides whether userID may subscribe to the topic "<kind>:<id>".
+// Returning domain.ErrTopicForbidden rejects the subscription.
+type Authorizer func(ctx context.Context, userID, id string) error
+
+// Presence is told when userID starts following the topic "<kind>:<id>",
+// with their first subscription to it, and when they stop, once their last
+// one ends by unsubscribing or disconnecting. It is called outside the hub's
+// lock, on the goroutine that made the change, after the client was told.
+type Presence func(userID, id string, present bool)
+
+// Hub tracks the open connections and the topics they subscribed to. It is
+// in-process: an event only reaches clients connected to the same instance.
+type Hub struct {
+	cfg      Config
+	metrics  *metrics.Metrics
+	upgrader websocket.Upgrader
+
+	mu          sync.RWMutex
+	authorizers map[string]Authorizer
+	presence    map[string]Presence
+	conns       map[*conn]struct{}
+	topics      map[string]map[*conn]struct{}
+	closed      bool
+	active      sync.WaitGroup
+}
+
+// NewHub returns a hub that already serves the user topic, which each user
+// may only subscribe to for themselves.
+func NewHub(cfg Config, m *metrics.Metrics) *Hub {
+	h := &Hub{
+		cfg:         cfg,
+		metrics:     m,
+		upgrader:    websocket.Upgrader{CheckOrigin: cfg.CheckOrigin},
+		authorizers: make(map[string]Authorizer),
+		presence:    make(map[string]Presence),
+		conns:       make(map[*conn]struct{}),
+		topics:      make(map[string]map[*conn]struct{}),
+	}
+	h.Authorize(domain.TopicUser, func(_ context.Context, userID, id string) error {
+		if id != userID {
+			return domain.ErrTopicForbidden
+		}
+		return nil
+	})
+	return h
+}
+
+// Authorize makes topics of the given kind available for subscription.
+func (h *Hub) Authorize(kind string, authorize Authorizer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.authorizers[kind] = authorize
+}
+
+// OnPresence has fn told who follows topics of the given kind.
+func (h *Hub) OnPresence(kind string, fn Presence) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.presence[kind] = fn
+}
+
+// Connections returns the number of open connections.
+func (h *Hub) Connections() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.conns)
+}
+
+// Serve upgrades the request and handles the connection for userID until it
+// closes. The caller must have authenticated userID; a request that isn't a
+// WebSocket upgrade should be rejected before calling Serve.
+func (h *Hub) Serve(w http.ResponseWriter, r *http.Request, userID string) {
+	ws, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has already answered the request.
+		logger.FromContext(r.Context()).Debug("WebSocket upgrade failed", "error", err)
+		return
+	}
+
+	c := newConn(h, ws, userID)
+	if !h.register(c) {
+		c.close(websocket.CloseGoingAway, reasonShutdown)
+	}
+	defer h.active.Done()
+
+	go c.writeLoop()
+	c.readLoop(r.Context())
+	<-c.written
+
+	h.unregister(c)
+	logger.FromContext(r.Context()).Debug("WebSocket closed", "user_id", userID, "reason", c.reason)
+}
+
+// Publish sends an event to every connection subscribed to topic. data is
+// encoded once and shared by all of them.
+func (h *Hub) Publish(topic, event string, data any) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		slog.Error("Realtime event can't be encoded", "topic", topic, "event", event, "error", err)
+		return
+	}
+	msg, err := json.Marshal(Envelope{Type: TypeEvent, Topic: topic, Event: event, Data: raw})
+	if err != nil {
+		slog.Error("Realtime event can't be encoded", "topic", topic, "event", event, "error", err)
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for c := range h.topics[topic] {
+		c.enqueue(msg, TypeEvent)
+	}
+}
+
+func (h *Hub) Unsubscribe(topic, userID string) {
+	h.mu.Lock()
+	var dropped []*conn
+	var changes []func()
+	for c := range h.topics[topic] {
+		if c.userID == userID {
+			changes = append(changes, h.removeTopic(c, topic)...)
+			dropped = append(dropped, c)
+		}
+	}
+	h.mu.Unlock()
+
+	for _, c := range dropped {
+		c.reply(Envelope{Type: TypeUnsubscribed, Topic: topic})
+	}
+	notify(changes)
+}
+
+func (h *Hub) Subscribers(topic string) []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	users := []string{}
+	for c := range h.topics[topic] {
+		if !slices.Contains(users, c.userID) {
+			users = append(users, c.userID)
+		}
+	}
+	return users
+}
+
+// Shutdown closes every connection with "going away" and waits for them to
+// finish, or for ctx to end. New connections are refused from then on.
+func (h *Hub) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	h.closed = true
+	for c := range h.conns {
+		c.close(websocket.CloseGoingAway, reasonShutdown)
+	}
+	h.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		h.active.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (h *Hub) register(c *conn) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.active.Add(1)
+	if h.closed {
+		return false
+	}
+	h.conns[c] = struct{}{}
+	h.metrics.RealtimeConnected()
+	return true
+}
+
+func (h *Hub) unregister(c *conn) {
+	h.mu.Lock()
+	if _, ok := h.conns[c]; !ok {
+		h.mu.Unlock()
+		return
+	}
+	var changes []func()
+	for topic := range c.topics {
+		changes = append(changes, h.removeTopic(c, topic)...)
+	}
+	delete(h.conns, c)
+	h.metrics.RealtimeDisconnected(c.reason)
+	h.mu.Unlock()
+
+	notify(changes)
+}
+
+// subscribe returns the presence change to report once the client has its
+// reply.
+func (h *Hub) subscribe(ctx context.Context, c *conn, topic string) ([]func(), error) {
+	kind, id, ok := strings.Cut(topic, ":")
+
+	h.mu.RLock()
+	authorize := h.authorizers[kind]
+	_, subscribed := c.topics[topic]
+	count := len(c.topics)
+	h.mu.RUnlock()
+
+	if !ok || id == "" || authorize == nil {
+		return nil, domain.ErrUnknownTopic
+	}
+	if subscribed {
+		return nil, nil
+	}
+	if count >= h.cfg.MaxSubscriptions {
+		return nil, domain.ErrTooManySubscriptions
+	}
+	if err := authorize(ctx, c.userID, id); err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(c.topics) >= h.cfg.MaxSubscriptions {
+		return nil, domain.ErrTooManySubscriptions
+	}
+	var changes []func()
+	if !h.follows(c.userID, topic) {
+		changes = h.presenceChange(topic, c.userID, true)
+	}
+	c.topics[topic] = struct{}{}
+	if h.topics[topic] == nil {
+		h.topics[topic] = make(map[*conn]struct{})
+	}
+	h.topics[topic][c] = struct{}{}
+	return changes, nil
+}
+
+func (h *Hub) unsubscribe(c *conn, topic string) []func() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.removeTopic(c, topic)
+}
+
+// removeTopic must be called with h.mu held. It returns the presence change
+// to report once the lock is released, if c was userID's last subscription.
+func (h *Hub) removeTopic(c *conn, topic string) []func() {
+	if _, ok := c.topics[topic]; !ok {
+		return nil
+	}
+	delete(c.topics, topic)
+	subscribers := h.topics[topic]
+	delete(subscribers, c)
+	if len(subscribers) == 0 {
+		delete(h.topics, topic)
+	}
+	if h.follows(c.userID, topic) {
+		return nil
+	}
+	return h.presenceChange(topic, c.userID, false)
+}
+
+// follows reports whether any of userID's connections subscribed to topic.
+// It must be called with h.mu held.
+func (h *Hub) follows(userID, topic string) bool {
+	for c := range h.topics[topic] {
+		if c.userID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// presenceChange returns the call telling the presence handler of topic's
+// kind about userID, if it has one. It must be called with h.mu held.
+func (h *Hub) presenceChange(topic, userID string, present bool) []func() {
+	kind, id, _ := strings.Cut(topic, ":")
+	fn := h.presence[kind]
+	if fn == nil {
+		return nil
+	}
+	return []func(){func() { fn(userID, id, present) }}
+}
+
+func notify(changes []func()) {
+	for _, change := range changes {
+		change()
+	}
+}
@@ -0,0 +1,327 @@
+package realtime_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Simpolette/HeartSteal/server/internal/apierror"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/realtime"
+)
+
+// Error envelopes resolve codes through the registry route.Setup fills.
+func TestMain(m *testing.M) {
+	apierror.Register(domain.ErrUnknownTopic, http.StatusBadRequest, domain.CodeUnknownTopic, "Unknown topic")
+	apierror.Register(domain.ErrTopicForbidden, http.StatusForbidden, domain.CodeTopicForbidden, "You can't subscribe to this topic")
+	apierror.Register(domain.ErrTooManySubscriptions, http.StatusBadRequest, domain.CodeTooManySubscriptions, "Too many subscriptions on this connection")
+	os.Exit(m.Run())
+}
+
+// newHub serves the hub over HTTP; the user ID comes from the query string
+// instead of a ticket.
+func newHub(t *testing.T, configure func(*realtime.Config)) (*realtime.Hub, string) {
+	t.Helper()
+	cfg := realtime.DefaultConfig()
+	if configure != nil {
+		configure(&cfg)
+	}
+	hub := realtime.NewHub(cfg, nil)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hub.Serve(w, r, r.URL.Query().Get("user"))
+	}))
+	t.Cleanup(func() {
+		_ = hub.Shutdown(context.Background())
+		srv.Close()
+	})
+	return hub, "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func dial(t *testing.T, url, userID string) *websocket.Conn {
+	t.Helper()
+	ws, _, err := websocket.DefaultDialer.Dial(url+"?user="+userID, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ws.Close() })
+	return ws
+}
+
+func send(t *testing.T, ws *websocket.Conn, env realtime.Envelope) {
+	t.Helper()
+	require.NoError(t, ws.WriteJSON(env))
+}
+
+func receive(t *testing.T, ws *websocket.Conn) realtime.Envelope {
+	t.Helper()
+	require.NoError(t, ws.SetReadDeadline(time.Now().Add(2*time.Second)))
+	var env realtime.Envelope
+	require.NoError(t, ws.ReadJSON(&env))
+	return env
+}
+
+func subscribe(t *testing.T, ws *websocket.Conn, topic string) {
+	t.Helper()
+	send(t, ws, realtime.Envelope{Type: realtime.TypeSubscribe, ID: "sub", Topic: topic})
+	reply := receive(t, ws)
+	require.Equal(t, realtime.TypeSubscribed, reply.Type, "error: %+v", reply.Error)
+}
+
+func TestHub_Serve(t *testing.T) {
+	t.Run("PingPong", func(t *testing.T) {
+		_, url := newHub(t, nil)
+		ws := dial(t, url, "alice")
+
+		send(t, ws, realtime.Envelope{Type: realtime.TypePing, ID: "1"})
+
+		reply := receive(t, ws)
+		assert.Equal(t, realtime.TypePong, reply.Type)
+		assert.Equal(t, "1", reply.ID)
+	})
+
+	t.Run("MalformedMessage", func(t *testing.T) {
+		_, url := newHub(t, nil)
+		ws := dial(t, url, "alice")
+
+		require.NoError(t, ws.WriteMessage(websocket.TextMessage, []byte("{")))
+
+		reply := receive(t, ws)
+		assert.Equal(t, realtime.TypeError, reply.Type)
+		assert.Equal(t, domain.CodeInvalidRequest, reply.Error.Code)
+	})
+
+	t.Run("UnknownMessageType", func(t *testing.T) {
+		_, url := newHub(t, nil)
+		ws := dial(t, url, "alice")
+
+		send(t, ws, realtime.Envelope{Type: "shout", ID: "7"})
+
+		reply := receive(t, ws)
+		assert.Equal(t, realtime.TypeError, reply.Type)
+		assert.Equal(t, "7", reply.ID)
+		assert.Equal(t, domain.CodeInvalidRequest, reply.Error.Code)
+	})
+
+	t.Run("MessageTooLarge", func(t *testing.T) {
+		hub, url := newHub(t, func(cfg *realtime.Config) { cfg.MaxMessageBytes = 64 })
+		ws := dial(t, url, "alice")
+
+		send(t, ws, realtime.Envelope{Type: realtime.TypePing, ID: strings.Repeat("x", 100)})
+
+		_, _, err := ws.ReadMessage()
+		assert.True(t, websocket.IsCloseError(err, websocket.CloseMessageTooBig), "got %v", err)
+		assert.Eventually(t, func() bool { return hub.Connections() == 0 }, 2*time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestHub_Authorize(t *testing.T) {
+	t.Run("OwnUserTopic", func(t *testing.T) {
+		_, url := newHub(t, nil)
+		ws := dial(t, url, "alice")
+
+		subscribe(t, ws, domain.UserTopic("alice"))
+	})
+
+	t.Run("OtherUserTopicForbidden", func(t *testing.T) {
+		_, url := newHub(t, nil)
+		ws := dial(t, url, "alice")
+
+		send(t, ws, realtime.Envelope{Type: realtime.TypeSubscribe, ID: "1", Topic: domain.UserTopic("bob")})
+
+		reply := receive(t, ws)
+		assert.Equal(t, realtime.TypeError, reply.Type)
+		assert.Equal(t, domain.CodeTopicForbidden, reply.Error.Code)
+		assert.Equal(t, domain.UserTopic("bob"), reply.Topic)
+	})
+
+	t.Run("UnknownTopic", func(t *testing.T) {
+		_, url := newHub(t, nil)
+		ws := dial(t, url, "alice")
+
+		for _, topic := range []string{"weather:today", "lobby", "user:"} {
+			send(t, ws, realtime.Envelope{Type: realtime.TypeSubscribe, Topic: topic})
+
+			reply := receive(t, ws)
+			assert.Equal(t, domain.CodeUnknownTopic, reply.Error.Code, topic)
+		}
+	})
+
+	t.Run("CustomKind", func(t *testing.T) {
+		hub, url := newHub(t, nil)
+		hub.Authorize("room", func(_ context.Context, userID, id string) error {
+			if id == "open" {
+				return nil
+			}
+			return domain.ErrTopicForbidden
+		})
+		ws := dial(t, url, "alice")
+
+		subscribe(t, ws, "room:open")
+		send(t, ws, realtime.Envelope{Type: realtime.TypeSubscribe, Topic: "room:closed"})
+		assert.Equal(t, domain.CodeTopicForbidden, receive(t, ws).Error.Code)
+	})
+
+	t.Run("TooManySubscriptions", func(t *testing.T) {
+		hub, url := newHub(t, func(cfg *realtime.Config) { cfg.MaxSubscriptions = 2 })
+		hub.Authorize("room", func(context.Context, string, string) error { return nil })
+		ws := dial(t, url, "alice")
+
+		subscribe(t, ws, "room:1")
+		subscribe(t, ws, "room:2")
+		subscribe(t, ws, "room:2")
+		send(t, ws, realtime.Envelope{Type: realtime.TypeSubscribe, Topic: "room:3"})
+
+		assert.Equal(t, domain.CodeTooManySubscriptions, receive(t, ws).Error.Code)
+	})
+}
+
+func TestHub_Publish(t *testing.T) {
+	t.Run("DeliversToSubscribers", func(t *testing.T) {
+		hub, url := newHub(t, nil)
+		alice := dial(t, url, "alice")
+		bob := dial(t, url, "bob")
+		subscribe(t, alice, domain.UserTopic("alice"))
+		subscribe(t, bob, domain.UserTopic("bob"))
+
+		hub.Publish(domain.UserTopic("alice"), "greeting", map[string]string{"text": "hi"})
+		hub.Publish(domain.UserTopic("bob"), "greeting", map[string]string{"text": "hello"})
+
+		got := receive(t, alice)
+		assert.Equal(t, realtime.TypeEvent, got.Type)
+		assert.Equal(t, domain.UserTopic("alice"), got.Topic)
+		assert.Equal(t, "greeting", got.Event)
+		assert.JSONEq(t, `{"text":"hi"}`, string(got.Data))
+		assert.JSONEq(t, `{"text":"hello"}`, string(receive(t, bob).Data))
+	})
+
+	t.Run("StopsAfterUnsubscribe", func(t *testing.T) {
+		hub, url := newHub(t, nil)
+		ws := dial(t, url, "alice")
+		topic := domain.UserTopic("alice")
+		subscribe(t, ws, topic)
+
+		send(t, ws, realtime.Envelope{Type: realtime.TypeUnsubscribe, ID: "2", Topic: topic})
+		assert.Equal(t, realtime.TypeUnsubscribed, receive(t, ws).Type)
+
+		hub.Publish(topic, "ignored", nil)
+		send(t, ws, realtime.Envelope{Type: realtime.TypePing, ID: "3"})
+		assert.Equal(t, realtime.TypePong, receive(t, ws).Type)
+	})
+
+	t.Run("ServerUnsubscribe", func(t *testing.T) {
+		hub, url := newHub(t, nil)
+		hub.Authorize("room", func(context.Context, string, string) error { return nil })
+		alice := dial(t, url, "alice")
+		bob := dial(t, url, "bob")
+		subscribe(t, alice, "room:1")
+		subscribe(t, bob, "room:1")
+
+		hub.Unsubscribe("room:1", "alice")
+		hub.Publish("room:1", "news", nil)
+
+		got := receive(t, alice)
+		assert.Equal(t, realtime.TypeUnsubscribed, got.Type)
+		assert.Equal(t, "room:1", got.Topic)
+		assert.Equal(t, "news", receive(t, bob).Event)
+	})
+
+	t.Run("DisconnectsSlowConsumer", func(t *testing.T) {
+		hub, url := newHub(t, func(cfg *realtime.Config) {
+			cfg.SendQueueSize = 1
+			cfg.WriteWait = 100 * time.Millisecond
+		})
+		ws := dial(t, url, "alice")
+		subscribe(t, ws, domain.UserTopic("alice"))
+
+		// The client never reads, so once the socket buffers fill the queue
+		// does too.
+		payload := json.RawMessage(`"` + strings.Repeat("x", 64<<10) + `"`)
+		for i := 0; i < 1000 && hub.Connections() > 0; i++ {
+			hub.Publish(domain.UserTopic("alice"), "flood", payload)
+		}
+
+		assert.Eventually(t, func() bool { return hub.Connections() == 0 }, 5*time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestHub_Subscribers(t *testing.T) {
+	hub, url := newHub(t, nil)
+	hub.Authorize("room", func(context.Context, string, string) error { return nil })
+	assert.Empty(t, hub.Subscribers("room:1"))
+
+	subscribe(t, dial(t, url, "alice"), "room:1")
+	subscribe(t, dial(t, url, "alice"), "room:1")
+	bob := dial(t, url, "bob")
+	subscribe(t, bob, "room:1")
+	subscribe(t, dial(t, url, "carol"), "room:2")
+
+	assert.ElementsMatch(t, []string{"alice", "bob"}, hub.Subscribers("room:1"), "each user once")
+
+	require.NoError(t, bob.Close())
+	assert.Eventually(t, func() bool {
+		return len(hub.Subscribers("room:1")) == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestHub_OnPresence(t *testing.T) {
+	type change struct {
+		userID, id string
+		present    bool
+	}
+	hub, url := newHub(t, nil)
+	hub.Authorize("room", func(context.Context, string, string) error { return nil })
+	changes := make(chan change, 8)
+	hub.OnPresence("room", func(userID, id string, present bool) {
+		changes <- change{userID, id, present}
+	})
+	next := func() change {
+		t.Helper()
+		select {
+		case c := <-changes:
+			return c
+		case <-time.After(2 * time.Second):
+			t.Fatal("no presence change")
+			return change{}
+		}
+	}
+
+	phone, laptop := dial(t, url, "alice"), dial(t, url, "alice")
+	subscribe(t, phone, "room:1")
+	assert.Equal(t, change{"alice", "1", true}, next())
+	subscribe(t, laptop, "room:1")
+	subscribe(t, phone, domain.UserTopic("alice"))
+
+	send(t, phone, realtime.Envelope{Type: realtime.TypeUnsubscribe, ID: "2", Topic: "room:1"})
+	require.Equal(t, realtime.TypeUnsubscribed, receive(t, phone).Type)
+	require.NoError(t, laptop.Close())
+	assert.Equal(t, change{"alice", "1", false}, next(), "only once the last subscription ends")
+
+	bob := dial(t, url, "bob")
+	subscribe(t, bob, "room:2")
+	assert.Equal(t, change{"bob", "2", true}, next())
+	hub.Unsubscribe("room:2", "bob")
+	assert.Equal(t, change{"bob", "2", false}, next())
+	assert.Empty(t, changes, "other kinds aren't reported")
+}
+
+func TestHub_Shutdown(t *testing.T) {
+	hub, url := newHub(t, nil)
+	ws := dial(t, url, "alice")
+	send(t, ws, realtime.Envelope{Type: realtime.TypePing})
+	receive(t, ws)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	require.NoError(t, hub.Shutdown(ctx))
+
+	_, _, err := ws.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), "got %v", err)
+	assert.Equal(t, 0, hub.Connections())
+}
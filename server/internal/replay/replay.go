@@ -0,0 +1,133 @@
+// Package replay plays recorded games again. Like game it does no I/O: a
+// game is its seed plus the actions taken, and the engine being
+// deterministic, applying them again rebuilds every state it went through.
+package replay
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/Simpolette/HeartSteal/server/internal/game"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported replay format")
+	ErrCorrupt           = errors.New("corrupt replay")
+	ErrUnencodable       = errors.New("action doesn't fit the replay format")
+	// ErrDiverged means a recorded action doesn't apply to the replayed
+	// game: the log or the rules changed since it was recorded.
+	ErrDiverged       = errors.New("replay diverged from the recorded game")
+	ErrStepOutOfRange = errors.New("replay step out of range")
+)
+
+// Encoded logs start with the format version, then take two little-endian
+// bytes per action: the card in bits 0-5, the target in bits 6-8, the seat
+// in bits 9-11, the auto flag in bit 12 and, since version 2, the forfeit
+// flag in bit 13.
+const (
+	formatV1   byte = 1
+	formatV2   byte = 2
+	actionSize      = 2
+
+	cardBits  = 6
+	seatBits  = 3
+	seatMask  = 1<<seatBits - 1
+	cardMask  = 1<<cardBits - 1
+	targetAt  = cardBits
+	seatAt    = targetAt + seatBits
+	autoAt    = seatAt + seatBits
+	forfeitAt = autoAt + 1
+)
+
+// Replay is everything needed to play a game again. Config must be the one
+// the game was started with.
+type Replay struct {
+	Config  game.Config
+	Seed    int64
+	Actions []game.Action
+}
+
+// Steps is the number of actions, so At(Steps()) is the end of the game.
+func (r Replay) Steps() int {
+	return len(r.Actions)
+}
+
+// At rebuilds the game after its first step actions and returns it with
+// the events of the last of them, or the setup events for step 0.
+func (r Replay) At(step int) (*game.State, []game.Event, error) {
+	if step < 0 || step > len(r.Actions) {
+		return nil, nil, ErrStepOutOfRange
+	}
+	s, events, err := game.New(r.Config, r.Seed)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, action := range r.Actions[:step] {
+		if events, err = game.Apply(s, action); err != nil {
+			return nil, nil, fmt.Errorf("%w: action %d: %w", ErrDiverged, i, err)
+		}
+	}
+	return s, events, nil
+}
+
+// Final rebuilds the game after every action.
+func (r Replay) Final() (*game.State, error) {
+	s, _, err := r.At(len(r.Actions))
+	return s, err
+}
+
+// Encode packs actions into the compact stored form.
+func Encode(actions []game.Action) ([]byte, error) {
+	data := make([]byte, 1, 1+actionSize*len(actions))
+	data[0] = formatV2
+	for i, a := range actions {
+		if a.Card < 0 || a.Card > cardMask || a.Seat < 0 || a.Seat > seatMask || a.Target < 0 || a.Target > seatMask {
+			return nil, fmt.Errorf("%w: action %d", ErrUnencodable, i)
+		}
+		v := uint16(a.Card) | uint16(a.Target)<<targetAt | uint16(a.Seat)<<seatAt
+		if a.Auto {
+			v |= 1 << autoAt
+		}
+		if a.Forfeit {
+			v |= 1 << forfeitAt
+		}
+		data = binary.LittleEndian.AppendUint16(data, v)
+	}
+	return data, nil
+}
+
+// Decode unpacks actions stored by Encode, including logs recorded before
+// forfeits existed.
+func Decode(data []byte) ([]game.Action, error) {
+	if len(data) == 0 {
+		return nil, ErrCorrupt
+	}
+	last := autoAt
+	switch data[0] {
+	case formatV1:
+	case formatV2:
+		last = forfeitAt
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	data = data[1:]
+	if len(data)%actionSize != 0 {
+		return nil, ErrCorrupt
+	}
+	actions := make([]game.Action, 0, len(data)/actionSize)
+	for i := 0; i < len(data); i += actionSize {
+		v := binary.LittleEndian.Uint16(data[i:])
+		if v>>(last+1) != 0 {
+			return nil, ErrCorrupt
+		}
+		actions = append(actions, game.Action{
+			Card:    int(v & cardMask),
+			Target:  int(v >> targetAt & seatMask),
+			Seat:    int(v >> seatAt & seatMask),
+			Auto:    v>>autoAt&1 == 1,
+			Forfeit: v>>forfeitAt&1 == 1,
+		})
+	}
+	return actions, nil
+}
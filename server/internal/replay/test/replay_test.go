@@ -0,0 +1,152 @@
+package replay_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Simpolette/HeartSteal/server/internal/game"
+	"github.com/Simpolette/HeartSteal/server/internal/replay"
+)
+
+// record plays a random game to the end, timing out and forfeiting now and
+// then, and returns its replay with the final state.
+func record(t *testing.T, rng *rand.Rand, players int) (replay.Replay, *game.State) {
+	t.Helper()
+	r := replay.Replay{Config: game.Config{Players: players}, Seed: rng.Int64()}
+	s, _, err := game.New(r.Config, r.Seed)
+	require.NoError(t, err)
+	for !s.Over {
+		action := game.AutoAction(s)
+		switch n := rng.IntN(40); {
+		case n == 0:
+			action = game.Action{Seat: rng.IntN(players), Forfeit: true}
+			if s.Players[action.Seat].Out {
+				continue
+			}
+		case n > 10:
+			actions := game.LegalActions(s)
+			action = actions[rng.IntN(len(actions))]
+		}
+		_, err := game.Apply(s, action)
+		require.NoError(t, err)
+		r.Actions = append(r.Actions, action)
+	}
+	return r, s
+}
+
+func TestReplay_At(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+
+	t.Run("ReproducesRecordedGames", func(t *testing.T) {
+		for i := range 200 {
+			players := game.MinPlayers + i%(game.MaxPlayers-game.MinPlayers+1)
+			r, want := record(t, rng, players)
+
+			data, err := replay.Encode(r.Actions)
+			require.NoError(t, err)
+			actions, err := replay.Decode(data)
+			require.NoError(t, err)
+			assert.Equal(t, r.Actions, actions)
+
+			got, err := replay.Replay{Config: r.Config, Seed: r.Seed, Actions: actions}.Final()
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		}
+	})
+
+	t.Run("IntermediateSteps", func(t *testing.T) {
+		r, _ := record(t, rng, 3)
+		s, events, err := game.New(r.Config, r.Seed)
+		require.NoError(t, err)
+
+		for step := 0; step <= r.Steps(); step++ {
+			got, gotEvents, err := r.At(step)
+			require.NoError(t, err)
+			assert.Equal(t, s, got)
+			assert.Equal(t, events, gotEvents)
+			if step < r.Steps() {
+				events, err = game.Apply(s, r.Actions[step])
+				require.NoError(t, err)
+			}
+		}
+	})
+
+	t.Run("ErrorDiverged", func(t *testing.T) {
+		r, _ := record(t, rng, 2)
+		r.Seed++
+
+		_, _, err := r.At(r.Steps())
+
+		assert.ErrorIs(t, err, replay.ErrDiverged)
+	})
+
+	t.Run("ErrorStepOutOfRange", func(t *testing.T) {
+		r, _ := record(t, rng, 2)
+
+		_, _, err := r.At(r.Steps() + 1)
+
+		assert.ErrorIs(t, err, replay.ErrStepOutOfRange)
+	})
+}
+
+func TestReplay_Encode(t *testing.T) {
+	t.Run("Compact", func(t *testing.T) {
+		actions := []game.Action{
+			{Seat: 0, Card: 63, Target: 7},
+			{Seat: 7, Card: 0, Target: 0, Auto: true},
+			{Seat: 3, Forfeit: true},
+		}
+
+		data, err := replay.Encode(actions)
+
+		require.NoError(t, err)
+		assert.Len(t, data, 1+2*len(actions))
+		decoded, err := replay.Decode(data)
+		require.NoError(t, err)
+		assert.Equal(t, actions, decoded)
+	})
+
+	t.Run("ErrorUnencodable", func(t *testing.T) {
+		_, err := replay.Encode([]game.Action{{Seat: 0, Card: 64}})
+
+		assert.ErrorIs(t, err, replay.ErrUnencodable)
+	})
+}
+
+func TestReplay_Decode(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{"Empty", nil, replay.ErrCorrupt},
+		{"UnknownVersion", []byte{3, 0, 0}, replay.ErrUnsupportedFormat},
+		{"Truncated", []byte{2, 0}, replay.ErrCorrupt},
+		{"UnusedBits", []byte{2, 0, 0x40}, replay.ErrCorrupt},
+		{"ForfeitInV1", []byte{1, 0, 0x20}, replay.ErrCorrupt},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := replay.Decode(tt.data)
+
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	t.Run("V1", func(t *testing.T) {
+		actions, err := replay.Decode([]byte{1, 0x05, 0x12})
+
+		require.NoError(t, err)
+		assert.Equal(t, []game.Action{{Seat: 1, Card: 5, Target: 0, Auto: true}}, actions)
+	})
+
+	t.Run("NoActions", func(t *testing.T) {
+		actions, err := replay.Decode([]byte{1})
+
+		require.NoError(t, err)
+		assert.Empty(t, actions)
+	})
+}
@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DryRun wraps repos so reads hit storage while writes are only logged. The
+// usecases run unchanged on top of it, so a dry run performs every lookup and
+// check a real run would.
+//
+// Each wrapper embeds only the read methods of its repository and implements
+// every write itself, so a write method added to a domain interface fails to
+// compile here instead of reaching the database.
+func DryRun(repos Repositories, log *slog.Logger) Repositories {
+	return Repositories{
+		User:    &dryRunUserRepository{userReader: repos.User, log: log},
+		Session: &dryRunSessionRepository{sessionReader: repos.Session, log: log},
+		Lobby:   &dryRunLobbyRepository{lobbyReader: repos.Lobby, log: log},
+		Ticket:  &dryRunTicketRepository{log: log},
+		Match:   &dryRunMatchRepository{matchReader: repos.Match, log: log},
+		Rating:  &dryRunRatingRepository{ratingReader: repos.Rating, log: log},
+		Stats:   &dryRunStatsRepository{statsReader: repos.Stats, log: log},
+		Tx:      repos.Tx,
+	}
+}
+
+type userReader interface {
+	GetByUsername(c context.Context, username string) (*domain.User, error)
+	GetByEmail(c context.Context, email string) (*domain.User, error)
+	GetByID(c context.Context, id string) (*domain.User, error)
+}
+
+type sessionReader interface {
+	ListActiveByUser(c context.Context, userID primitive.ObjectID, now time.Time) ([]domain.Session, error)
+}
+
+type lobbyReader interface {
+	GetByID(c context.Context, id string) (*domain.Lobby, error)
+	GetByInviteCode(c context.Context, code string) (*domain.Lobby, error)
+	GetByMember(c context.Context, userID primitive.ObjectID) (*domain.Lobby, error)
+	ListOpenPublic(c context.Context, limit int, after *domain.Cursor) ([]domain.Lobby, error)
+}
+
+type matchReader interface {
+	GetByID(c context.Context, id string) (*domain.Match, error)
+	ListByStatus(c context.Context, status domain.MatchStatus) ([]domain.Match, error)
+	ListActiveByPlayer(c context.Context, userID primitive.ObjectID) ([]domain.Match, error)
+	ListFinishedByPlayer(c context.Context, userID primitive.ObjectID, filter domain.MatchFilter, limit int, after *domain.Cursor) ([]domain.Match, error)
+	ListLeaseExpired(c context.Context, now time.Time) ([]domain.Match, error)
+}
+
+type ratingReader interface {
+	Get(c context.Context, userID primitive.ObjectID, mode domain.GameMode) (*domain.PlayerRating, error)
+	ListByUsers(c context.Context, mode domain.GameMode, userIDs []primitive.ObjectID) ([]domain.PlayerRating, error)
+	ListRanked(c context.Context, mode domain.GameMode, userIDs []primitive.ObjectID, after *domain.RankCursor, limit int) ([]domain.PlayerRating, error)
+	ListAhead(c context.Context, mode domain.GameMode, userIDs []primitive.ObjectID, pos domain.RankCursor, limit int) ([]domain.PlayerRating, error)
+	CountAhead(c context.Context, mode domain.GameMode, userIDs []primitive.ObjectID, pos domain.RankCursor) (int, error)
+}
+
+type statsReader interface {
+	Get(c context.Context, userID primitive.ObjectID) (*domain.PlayerStats, error)
+}
+
+type dryRunUserRepository struct {
+	userReader
+	log *slog.Logger
+}
+
+func (r *dryRunUserRepository) Create(_ context.Context, user *domain.User) error {
+	if user.ID.IsZero() {
+		user.ID = primitive.NewObjectID()
+	}
+	r.log.Info("dry run: would create user", "username", user.Username)
+	return nil
+}
+
+func (r *dryRunUserRepository) Update(_ context.Context, user *domain.User) error {
+	r.log.Info("dry run: would update user", "username", user.Username)
+	return nil
+}
+
+func (r *dryRunUserRepository) Delete(c context.Context, id primitive.ObjectID) error {
+	user, err := r.userReader.GetByID(c, id.Hex())
+	if err != nil {
+		return err
+	}
+	r.log.Info("dry run: would delete user", "username", user.Username)
+	return nil
+}
+
+type dryRunSessionRepository struct {
+	sessionReader
+	log *slog.Logger
+}
+
+func (r *dryRunSessionRepository) Create(_ context.Context, session *domain.Session) error {
+	if session.ID.IsZero() {
+		session.ID = primitive.NewObjectID()
+	}
+	r.log.Info("dry run: would create session", "user_id", session.UserID.Hex())
+	return nil
+}
+
+func (r *dryRunSessionRepository) DeleteByUser(c context.Context, userID primitive.ObjectID) (int64, error) {
+	sessions, err := r.sessionReader.ListActiveByUser(c, userID, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	r.log.Info("dry run: would delete sessions", "user_id", userID.Hex(), "count", len(sessions))
+	return int64(len(sessions)), nil
+}
+
+type dryRunLobbyRepository struct {
+	lobbyReader
+	log *slog.Logger
+}
+
+func (r *dryRunLobbyRepository) Create(_ context.Context, lobby *domain.Lobby) error {
+	if lobby.ID.IsZero() {
+		lobby.ID = primitive.NewObjectID()
+	}
+	r.log.Info("dry run: would create lobby", "name", lobby.Name)
+	return nil
+}
+
+func (r *dryRunLobbyRepository) Update(_ context.Context, lobby *domain.Lobby) error {
+	r.log.Info("dry run: would update lobby", "lobby_id", lobby.ID.Hex())
+	return nil
+}
+
+func (r *dryRunLobbyRepository) Delete(_ context.Context, lobby *domain.Lobby) error {
+	r.log.Info("dry run: would delete lobby", "lobby_id", lobby.ID.Hex())
+	return nil
+}
+
+// dryRunTicketRepository has no reads: Take consumes the ticket it reads.
+type dryRunTicketRepository struct {
+	log *slog.Logger
+}
+
+func (r *dryRunTicketRepository) Create(_ context.Context, ticket *domain.Ticket) error {
+	r.log.Info("dry run: would create realtime ticket", "user_id", ticket.UserID.Hex())
+	return nil
+}
+
+// Take reports every ticket as invalid, since it can't be read without
+// deleting it.
+func (r *dryRunTicketRepository) Take(context.Context, string, time.Time) (*domain.Ticket, error) {
+	r.log.Info("dry run: would take realtime ticket")
+	return nil, domain.ErrInvalidTicket
+}
+
+type dryRunMatchRepository struct {
+	matchReader
+	log *slog.Logger
+}
+
+func (r *dryRunMatchRepository) Create(_ context.Context, match *domain.Match) error {
+	if match.ID.IsZero() {
+		match.ID = primitive.NewObjectID()
+	}
+	r.log.Info("dry run: would create match", "match_id", match.ID.Hex())
+	return nil
+}
+
+func (r *dryRunMatchRepository) Update(_ context.Context, match *domain.Match) error {
+	r.log.Info("dry run: would update match", "match_id", match.ID.Hex(), "status", match.Status)
+	return nil
+}
+
+func (r *dryRunMatchRepository) Delete(c context.Context, id primitive.ObjectID) error {
+	if _, err := r.matchReader.GetByID(c, id.Hex()); err != nil {
+		return err
+	}
+	r.log.Info("dry run: would delete match", "match_id", id.Hex())
+	return nil
+}
+
+func (r *dryRunMatchRepository) RenewLease(_ context.Context, id primitive.ObjectID, owner string, _ time.Time) error {
+	r.log.Info("dry run: would renew match lease", "match_id", id.Hex(), "owner", owner)
+	return nil
+}
+
+func (r *dryRunMatchRepository) Finish(_ context.Context, match *domain.Match, owner string) error {
+	r.log.Info("dry run: would finish match", "match_id", match.ID.Hex(), "owner", owner)
+	return nil
+}
+
+func (r *dryRunMatchRepository) AbandonLeaseExpired(_ context.Context, id primitive.ObjectID, _ time.Time) (bool, error) {
+	r.log.Info("dry run: would abandon match", "match_id", id.Hex())
+	return true, nil
+}
+
+type dryRunRatingRepository struct {
+	ratingReader
+	log *slog.Logger
+}
+
+func (r *dryRunRatingRepository) Save(_ context.Context, rating *domain.PlayerRating) error {
+	r.log.Info("dry run: would save rating", "user_id", rating.UserID.Hex(), "mode", rating.Mode, "rating", rating.Rating)
+	return nil
+}
+
+type dryRunStatsRepository struct {
+	statsReader
+	log *slog.Logger
+}
+
+func (r *dryRunStatsRepository) Save(_ context.Context, stats *domain.PlayerStats) error {
+	r.log.Info("dry run: would save player stats", "user_id", stats.UserID.Hex(), "games", stats.Games)
+	return nil
+}
+
+func (r *dryRunStatsRepository) Delete(_ context.Context, userID primitive.ObjectID) error {
+	r.log.Info("dry run: would delete player stats", "user_id", userID.Hex())
+	return nil
+}
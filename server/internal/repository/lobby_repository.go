@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// duplicateKeyCode is the MongoDB error code for a unique index violation.
+const duplicateKeyCode = 11000
+
+type lobbyRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewLobbyRepository(db *mongo.Database, collection string) domain.LobbyRepository {
+	return &lobbyRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (r *lobbyRepository) Create(c context.Context, lobby *domain.Lobby) (err error) {
+	c, span := startSpan(c, "lobbyRepository.Create", r.collection)
+	defer func() { tracing.End(span, err) }()
+
+	result, err := r.database.Collection(r.collection).InsertOne(c, lobby)
+	if err != nil {
+		return lobbyWriteError(err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		lobby.ID = oid
+	}
+
+	return nil
+}
+
+func (r *lobbyRepository) Update(c context.Context, lobby *domain.Lobby) (err error) {
+	c, span := startSpan(c, "lobbyRepository.Update", r.collection)
+	defer func() { tracing.End(span, err) }()
+
+	read := lobby.Version
+	lobby.Version++
+
+	result, err := r.database.Collection(r.collection).ReplaceOne(c, bson.M{"_id": lobby.ID, "version": read}, lobby)
+	if err == nil && result.MatchedCount == 0 {
+		err = domain.ErrLobbyConflict
+	}
+	if err != nil {
+		lobby.Version = read
+		return lobbyWriteError(err)
+	}
+
+	return nil
+}
+
+func (r *lobbyRepository) Delete(c context.Context, lobby *domain.Lobby) (err error) {
+	c, span := startSpan(c, "lobbyRepository.Delete", r.collection)
+	defer func() { tracing.End(span, err) }()
+
+	result, err := r.database.Collection(r.collection).DeleteOne(c, bson.M{"_id": lobby.ID, "version": lobby.Version})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrLobbyConflict
+	}
+
+	return nil
+}
+
+func (r *lobbyRepository) GetByID(c context.Context, id string) (_ *domain.Lobby, err error) {
+	c, span := startSpan(c, "lobbyRepository.GetByID", r.collection)
+	defer func() { tracing.End(span, ignoreNotFound(err)) }()
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.ErrLobbyNotFound
+	}
+
+	return r.findOne(c, bson.M{"_id": objID})
+}
+
+func (r *lobbyRepository) GetByInviteCode(c context.Context, code string) (_ *domain.Lobby, err error) {
+	c, span := startSpan(c, "lobbyRepository.GetByInviteCode", r.collection)
+	defer func() { tracing.End(span, ignoreNotFound(err)) }()
+
+	return r.findOne(c, bson.M{"invite_code": code})
+}
+
+func (r *lobbyRepository) GetByMember(c context.Context, userID primitive.ObjectID) (_ *domain.Lobby, err error) {
+	c, span := startSpan(c, "lobbyRepository.GetByMember", r.collection)
+	defer func() { tracing.End(span, ignoreNotFound(err)) }()
+
+	return r.findOne(c, bson.M{"members.user_id": userID})
+}
+
+func (r *lobbyRepository) ListOpenPublic(c context.Context, limit int, after *domain.Cursor) (_ []domain.Lobby, err error) {
+	c, span := startSpan(c, "lobbyRepository.ListOpenPublic", r.collection)
+	defer func() { tracing.End(span, err) }()
+
+	filter := bson.M{
+		"visibility": domain.LobbyPublic,
+		"status":     domain.LobbyOpen,
+		"$expr":      bson.M{"$lt": bson.A{bson.M{"$size": "$members"}, "$capacity"}},
+	}
+	if after != nil {
+		filter["$or"] = bson.A{
+			bson.M{"created_at": bson.M{"$lt": after.Time}},
+			bson.M{"created_at": after.Time, "_id": bson.M{"$lt": after.ID}},
+		}
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.database.Collection(r.collection).Find(c, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	lobbies := []domain.Lobby{}
+	if err := cursor.All(c, &lobbies); err != nil {
+		return nil, err
+	}
+
+	return lobbies, nil
+}
+
+func (r *lobbyRepository) findOne(c context.Context, filter bson.M) (*domain.Lobby, error) {
+	var lobby domain.Lobby
+	err := r.database.Collection(r.collection).FindOne(c, filter).Decode(&lobby)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrLobbyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &lobby, nil
+}
+
+// lobbyWriteError tells the unique indexes apart: a member already in another
+// lobby trips the member index, anything else is an invite code clash.
+func lobbyWriteError(err error) error {
+	if !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) && serverErr.HasErrorCodeWithMessage(duplicateKeyCode, "index: "+domain.IndexLobbyMember+" ") {
+		return domain.ErrAlreadyInLobby
+	}
+	return domain.ErrInviteCodeTaken
+}
@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type matchRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewMatchRepository(db *mongo.Database, collection string) domain.MatchRepository {
+	return &matchRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (r *matchRepository) Create(c context.Context, match *domain.Match) (err error) {
+	c, span := startSpan(c, "matchRepository.Create", r.collection)
+	defer func() { tracing.End(span, err) }()
+
+	result, err := r.database.Collection(r.collection).InsertOne(c, match)
+	if err != nil {
+		return err
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		match.ID = oid
+	}
+
+	return nil
+}
+
+func (r *matchRepository) Update(c context.Context, match *domain.Match) (err error) {
+	c, span := startSpan(c, "matchRepository.Update", r.collection)
+	defer func() { tracing.End(span, err) }()
+
+	result, err := r.database.Collection(r.collection).ReplaceOne(c, bson.M{"_id": match.ID}, match)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrMatchNotFound
+	}
+
+	return nil
+}
+
+func (r *matchRepository) GetByID(c context.Context, id string) (_ *domain.Match, err error) {
+	c, span := startSpan(c, "matchRepository.GetByID", r.collection)
+	defer func() { tracing.End(span, ignoreNotFound(err)) }()
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.ErrMatchNotFound
+	}
+
+	var match domain.Match
+	err = r.database.Collection(r.collection).FindOne(c, bson.M{"_id": objID}).Decode(&match)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrMatchNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &match, nil
+}
+
+func (r *matchRepository) Delete(c context.Context, id primitive.ObjectID) (err error) {
+	c, span := startSpan(c, "matchRepository.Delete", r.collection)
+	defer func() { tracing.End(span, err) }()
+
+	result, err := r.database.Collection(r.collection).DeleteOne(c, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrMatchNotFound
+	}
+
+	return nil
+}
+
+func (r *matchRepository) ListByStatus(c context.Context, status domain.MatchStatus) (_ []domain.Match, err error) {
+	c, span := startSpan(c, "matchRepository.ListByStatus", r.collection)
+	defer func() { tracing.End(span, err) }()
+
+	cursor, err := r.database.Collection(r.collection).Find(c, bson.M{"status": status})
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []domain.Match{}
+	if err := cursor.All(c, &matches); err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+// ListActiveByPlayer is served by the players.user_id and status prefix of
+// the player history index.
+func (r *matchRepository) ListActiveByPlayer(c context.Context, userID primitive.ObjectID) (_ []domain.Match, err error) {
+	c, span := startSpan(c, "matchRepository.ListActiveByPlayer", r.collection)
+	defer func() { tracing.End(span, err) }()
+
+	cursor, err := r.database.Collection(r.collection).Find(c, bson.M{"players.user_id": userID, "status": domain.MatchActive})
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []domain.Match{}
+	if err := cursor.All(c, &matches); err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+func (r *matchRepository) ListFinishedByPlayer(c context.Context, userID primitive.ObjectID, filter domain.MatchFilter, limit int, after *domain.Cursor) (_ []domain.Match, err error) {
+	c, span := startSpan(c, "matchRepository.ListFinishedByPlayer", r.collection)
+	defer func() { tracing.End(span, err) }()
+
+	// Match the player's own entry, so results are theirs and not any
+	// other player's.
+	player := bson.M{"user_id": userID}
+	switch filter.Result {
+	case domain.MatchWon:
+		player["placement"] = 1
+	case domain.MatchLost:
+		player["placement"] = bson.M{"$gt": 1}
+	}
+	query := bson.M{
+		"status":  domain.MatchFinished,
+		"players": bson.M{"$elemMatch": player},
+	}
+	if filter.Mode != "" {
+		query["mode"] = filter.Mode
+	}
+	if filter.Ranked != nil {
+		query["ranked"] = *filter.Ranked
+	}
+	if after != nil {
+		query["$or"] = bson.A{
+			bson.M{"finished_at": bson.M{"$lt": after.Time}},
+			bson.M{"finished_at": after.Time, "_id": bson.M{"$lt": after.ID}},
+		}
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "finished_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.database.Collection(r.collection).Find(c, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []domain.Match{}
+	if err := cursor.All(c, &matches); err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+func (r *matchRepository) RenewLease(c context.Context, id primitive.ObjectID, owner string, expiresAt time.Time) (err error) {
+	c, span := startSpan(c, "matchRepository.RenewLease", r.collection)
+	defer func() { tracing.End(span, ignoreNotFound(err)) }()
+
+	result, err := r.database.Collection(r.collection).UpdateOne(c,
+		bson.M{"_id": id, "status": domain.MatchActive, "owner": owner},
+		bson.M{"$set": bson.M{"lease_expires_at": expiresAt}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrMatchNotFound
+	}
+
+	return nil
+}
+
+func (r *matchRepository) Finish(c context.Context, match *domain.Match, owner string) (err error) {
+	c, span := startSpan(c, "matchRepository.Finish", r.collection)
+	defer func() { tracing.End(span, ignoreNotFound(err)) }()
+
+	result, err := r.database.Collection(r.collection).ReplaceOne(c,
+		bson.M{"_id": match.ID, "status": domain.MatchActive, "owner": owner},
+		match,
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrMatchNotFound
+	}
+
+	return nil
+}
+
+// leaseExpired matches active matches whose lease lapsed before now,
+// including ones stored before matches had leases.
+func leaseExpired(now time.Time) bson.M {
+	return bson.M{"status": domain.MatchActive, "lease_expires_at": bson.M{"$not": bson.M{"$gte": now}}}
+}
+
+func (r *matchRepository) ListLeaseExpired(c context.Context, now time.Time) (_ []domain.Match, err error) {
+	c, span := startSpan(c, "matchRepository.ListLeaseExpired", r.collection)
+	defer func() { tracing.End(span, err) }()
+
+	cursor, err := r.database.Collection(r.collection).Find(c, leaseExpired(now))
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []domain.Match{}
+	if err := cursor.All(c, &matches); err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+func (r *matchRepository) AbandonLeaseExpired(c context.Context, id primitive.ObjectID, now time.Time) (_ bool, err error) {
+	c, span := startSpan(c, "matchRepository.AbandonLeaseExpired", r.collection)
+	defer func() { tracing.End(span, err) }()
+
+	filter := leaseExpired(now)
+	filter["_id"] = id
+	result, err := r.database.Collection(r.collection).UpdateOne(c, filter,
+		bson.M{"$set": bson.M{"status": domain.MatchAbandoned, "finished_at": now}},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount > 0, nil
+}
@@ -0,0 +1,152 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type lobbyRepository struct {
+	mu      sync.RWMutex
+	lobbies map[primitive.ObjectID]domain.Lobby
+}
+
+func NewLobbyRepository() domain.LobbyRepository {
+	return &lobbyRepository{
+		lobbies: make(map[primitive.ObjectID]domain.Lobby),
+	}
+}
+
+func (r *lobbyRepository) Create(_ context.Context, lobby *domain.Lobby) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Mirror the unique index on invite_code.
+	for _, l := range r.lobbies {
+		if l.InviteCode == lobby.InviteCode {
+			return domain.ErrInviteCodeTaken
+		}
+	}
+	if err := r.ensureMembersFree(lobby); err != nil {
+		return err
+	}
+
+	if lobby.ID.IsZero() {
+		lobby.ID = primitive.NewObjectID()
+	}
+	r.lobbies[lobby.ID] = cloneLobby(lobby)
+	return nil
+}
+
+func (r *lobbyRepository) Update(_ context.Context, lobby *domain.Lobby) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.lobbies[lobby.ID]
+	if !ok || stored.Version != lobby.Version {
+		return domain.ErrLobbyConflict
+	}
+	if err := r.ensureMembersFree(lobby); err != nil {
+		return err
+	}
+	lobby.Version++
+	r.lobbies[lobby.ID] = cloneLobby(lobby)
+	return nil
+}
+
+func (r *lobbyRepository) Delete(_ context.Context, lobby *domain.Lobby) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.lobbies[lobby.ID]
+	if !ok || stored.Version != lobby.Version {
+		return domain.ErrLobbyConflict
+	}
+	delete(r.lobbies, lobby.ID)
+	return nil
+}
+
+func (r *lobbyRepository) GetByID(_ context.Context, id string) (*domain.Lobby, error) {
+	objID, err := primitive.ObjectIDFromHex(strings.TrimSpace(id))
+	if err != nil {
+		return nil, domain.ErrLobbyNotFound
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	lobby, ok := r.lobbies[objID]
+	if !ok {
+		return nil, domain.ErrLobbyNotFound
+	}
+	out := cloneLobby(&lobby)
+	return &out, nil
+}
+
+func (r *lobbyRepository) GetByInviteCode(_ context.Context, code string) (*domain.Lobby, error) {
+	return r.find(func(l *domain.Lobby) bool { return l.InviteCode == code })
+}
+
+func (r *lobbyRepository) GetByMember(_ context.Context, userID primitive.ObjectID) (*domain.Lobby, error) {
+	return r.find(func(l *domain.Lobby) bool { return l.IsMember(userID) })
+}
+
+func (r *lobbyRepository) ListOpenPublic(_ context.Context, limit int, after *domain.Cursor) ([]domain.Lobby, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	lobbies := []domain.Lobby{}
+	for _, l := range r.lobbies {
+		if l.Visibility == domain.LobbyPublic && l.Status == domain.LobbyOpen && !l.IsFull() && after.Before(l.CreatedAt, l.ID) {
+			lobbies = append(lobbies, cloneLobby(&l))
+		}
+	}
+	slices.SortFunc(lobbies, func(a, b domain.Lobby) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(b.ID.Hex(), a.ID.Hex())
+	})
+	if len(lobbies) > limit {
+		lobbies = lobbies[:limit]
+	}
+	return lobbies, nil
+}
+
+func (r *lobbyRepository) find(match func(*domain.Lobby) bool) (*domain.Lobby, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, l := range r.lobbies {
+		if match(&l) {
+			out := cloneLobby(&l)
+			return &out, nil
+		}
+	}
+	return nil, domain.ErrLobbyNotFound
+}
+
+// ensureMembersFree mirrors the unique index on members.user_id.
+func (r *lobbyRepository) ensureMembersFree(lobby *domain.Lobby) error {
+	for id, l := range r.lobbies {
+		if id == lobby.ID {
+			continue
+		}
+		for _, m := range lobby.Members {
+			if l.IsMember(m.UserID) {
+				return domain.ErrAlreadyInLobby
+			}
+		}
+	}
+	return nil
+}
+
+func cloneLobby(lobby *domain.Lobby) domain.Lobby {
+	out := *lobby
+	out.Members = append([]domain.LobbyMember(nil), lobby.Members...)
+	return out
+}
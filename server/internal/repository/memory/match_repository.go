@@ -0,0 +1,196 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type matchRepository struct {
+	mu      sync.RWMutex
+	matches map[primitive.ObjectID]domain.Match
+}
+
+func NewMatchRepository() domain.MatchRepository {
+	return &matchRepository{
+		matches: make(map[primitive.ObjectID]domain.Match),
+	}
+}
+
+func (r *matchRepository) Create(_ context.Context, match *domain.Match) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if match.ID.IsZero() {
+		match.ID = primitive.NewObjectID()
+	}
+	r.matches[match.ID] = cloneMatch(match)
+	return nil
+}
+
+func (r *matchRepository) Update(_ context.Context, match *domain.Match) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.matches[match.ID]; !ok {
+		return domain.ErrMatchNotFound
+	}
+	r.matches[match.ID] = cloneMatch(match)
+	return nil
+}
+
+func (r *matchRepository) Delete(_ context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.matches[id]; !ok {
+		return domain.ErrMatchNotFound
+	}
+	delete(r.matches, id)
+	return nil
+}
+
+func (r *matchRepository) GetByID(_ context.Context, id string) (*domain.Match, error) {
+	objID, err := primitive.ObjectIDFromHex(strings.TrimSpace(id))
+	if err != nil {
+		return nil, domain.ErrMatchNotFound
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	match, ok := r.matches[objID]
+	if !ok {
+		return nil, domain.ErrMatchNotFound
+	}
+	out := cloneMatch(&match)
+	return &out, nil
+}
+
+func (r *matchRepository) ListByStatus(_ context.Context, status domain.MatchStatus) ([]domain.Match, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matches := []domain.Match{}
+	for _, m := range r.matches {
+		if m.Status == status {
+			matches = append(matches, cloneMatch(&m))
+		}
+	}
+	return matches, nil
+}
+
+func (r *matchRepository) ListActiveByPlayer(_ context.Context, userID primitive.ObjectID) ([]domain.Match, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matches := []domain.Match{}
+	for _, m := range r.matches {
+		if m.Status == domain.MatchActive && m.Player(userID) != nil {
+			matches = append(matches, cloneMatch(&m))
+		}
+	}
+	return matches, nil
+}
+
+func (r *matchRepository) ListFinishedByPlayer(_ context.Context, userID primitive.ObjectID, filter domain.MatchFilter, limit int, after *domain.Cursor) ([]domain.Match, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matches := []domain.Match{}
+	for _, m := range r.matches {
+		p := m.Player(userID)
+		if p == nil || m.Status != domain.MatchFinished || !after.Before(*m.FinishedAt, m.ID) {
+			continue
+		}
+		if (filter.Mode != "" && m.Mode != filter.Mode) ||
+			(filter.Ranked != nil && m.Ranked != *filter.Ranked) ||
+			(filter.Result != "" && (p.Placement == 0 || domain.ResultOf(p.Placement) != filter.Result)) {
+			continue
+		}
+		matches = append(matches, cloneMatch(&m))
+	}
+	slices.SortFunc(matches, func(a, b domain.Match) int {
+		if c := b.FinishedAt.Compare(*a.FinishedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(b.ID.Hex(), a.ID.Hex())
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+func (r *matchRepository) RenewLease(_ context.Context, id primitive.ObjectID, owner string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, ok := r.matches[id]
+	if !ok || m.Status != domain.MatchActive || m.Owner != owner {
+		return domain.ErrMatchNotFound
+	}
+	m.LeaseExpiresAt = expiresAt
+	r.matches[id] = m
+	return nil
+}
+
+func (r *matchRepository) Finish(_ context.Context, match *domain.Match, owner string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, ok := r.matches[match.ID]
+	if !ok || m.Status != domain.MatchActive || m.Owner != owner {
+		return domain.ErrMatchNotFound
+	}
+	r.matches[match.ID] = cloneMatch(match)
+	return nil
+}
+
+func (r *matchRepository) ListLeaseExpired(_ context.Context, now time.Time) ([]domain.Match, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matches := []domain.Match{}
+	for _, m := range r.matches {
+		if leaseExpired(&m, now) {
+			matches = append(matches, cloneMatch(&m))
+		}
+	}
+	return matches, nil
+}
+
+func (r *matchRepository) AbandonLeaseExpired(_ context.Context, id primitive.ObjectID, now time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, ok := r.matches[id]
+	if !ok || !leaseExpired(&m, now) {
+		return false, nil
+	}
+	m.Status = domain.MatchAbandoned
+	m.FinishedAt = &now
+	r.matches[id] = m
+	return true, nil
+}
+
+func leaseExpired(m *domain.Match, now time.Time) bool {
+	return m.Status == domain.MatchActive && m.LeaseExpiresAt.Before(now)
+}
+
+func cloneMatch(match *domain.Match) domain.Match {
+	out := *match
+	out.Players = slices.Clone(match.Players)
+	out.Placements = slices.Clone(match.Placements)
+	out.Actions = slices.Clone(match.Actions)
+	if match.FinishedAt != nil {
+		finished := *match.FinishedAt
+		out.FinishedAt = &finished
+	}
+	return out
+}
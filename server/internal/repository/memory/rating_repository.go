@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ratingKey struct {
+	userID primitive.ObjectID
+	mode   domain.GameMode
+}
+
+type ratingRepository struct {
+	mu      sync.RWMutex
+	ratings map[ratingKey]domain.PlayerRating
+}
+
+func NewRatingRepository() domain.RatingRepository {
+	return &ratingRepository{
+		ratings: make(map[ratingKey]domain.PlayerRating),
+	}
+}
+
+func (r *ratingRepository) Get(_ context.Context, userID primitive.ObjectID, mode domain.GameMode) (*domain.PlayerRating, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rating, ok := r.ratings[ratingKey{userID, mode}]
+	if !ok {
+		return nil, domain.ErrNotRanked
+	}
+	return &rating, nil
+}
+
+func (r *ratingRepository) ListByUsers(_ context.Context, mode domain.GameMode, userIDs []primitive.ObjectID) ([]domain.PlayerRating, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ratings := []domain.PlayerRating{}
+	for _, id := range userIDs {
+		if rating, ok := r.ratings[ratingKey{id, mode}]; ok {
+			ratings = append(ratings, rating)
+		}
+	}
+	return ratings, nil
+}
+
+func (r *ratingRepository) Save(_ context.Context, rating *domain.PlayerRating) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := ratingKey{rating.UserID, rating.Mode}
+	if stored, ok := r.ratings[key]; ok {
+		rating.ID = stored.ID
+	} else if rating.ID.IsZero() {
+		rating.ID = primitive.NewObjectID()
+	}
+	r.ratings[key] = *rating
+	return nil
+}
+
+func (r *ratingRepository) ListRanked(_ context.Context, mode domain.GameMode, userIDs []primitive.ObjectID, after *domain.RankCursor, limit int) ([]domain.PlayerRating, error) {
+	board := r.board(mode, userIDs, func(p *domain.PlayerRating) bool {
+		return after == nil || behind(*after, p)
+	})
+	return board[:min(limit, len(board))], nil
+}
+
+func (r *ratingRepository) ListAhead(_ context.Context, mode domain.GameMode, userIDs []primitive.ObjectID, pos domain.RankCursor, limit int) ([]domain.PlayerRating, error) {
+	board := r.board(mode, userIDs, func(p *domain.PlayerRating) bool {
+		return pos.Ahead(p.Rating, p.UserID)
+	})
+	return board[max(0, len(board)-limit):], nil
+}
+
+func (r *ratingRepository) CountAhead(_ context.Context, mode domain.GameMode, userIDs []primitive.ObjectID, pos domain.RankCursor) (int, error) {
+	board := r.board(mode, userIDs, func(p *domain.PlayerRating) bool {
+		return pos.Ahead(p.Rating, p.UserID)
+	})
+	return len(board), nil
+}
+
+// board returns mode's ratings that pass keep, in board order. A non-nil
+// userIDs limits it to those users.
+func (r *ratingRepository) board(mode domain.GameMode, userIDs []primitive.ObjectID, keep func(*domain.PlayerRating) bool) []domain.PlayerRating {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	board := []domain.PlayerRating{}
+	for key, p := range r.ratings {
+		if key.mode == mode && (userIDs == nil || slices.Contains(userIDs, key.userID)) && keep(&p) {
+			board = append(board, p)
+		}
+	}
+	slices.SortFunc(board, func(a, b domain.PlayerRating) int {
+		if a.Rating != b.Rating {
+			if a.Rating > b.Rating {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.UserID.Hex(), b.UserID.Hex())
+	})
+	return board
+}
+
+// behind reports whether p is ranked after pos.
+func behind(pos domain.RankCursor, p *domain.PlayerRating) bool {
+	return !pos.Ahead(p.Rating, p.UserID) && (p.Rating != pos.Rating || p.UserID != pos.UserID)
+}
@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type sessionRepository struct {
+	mu       sync.RWMutex
+	sessions map[primitive.ObjectID]domain.Session
+}
+
+func NewSessionRepository() domain.SessionRepository {
+	return &sessionRepository{
+		sessions: make(map[primitive.ObjectID]domain.Session),
+	}
+}
+
+func (r *sessionRepository) Create(_ context.Context, session *domain.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if session.ID.IsZero() {
+		session.ID = primitive.NewObjectID()
+	}
+	r.sessions[session.ID] = *session
+	return nil
+}
+
+func (r *sessionRepository) ListActiveByUser(_ context.Context, userID primitive.ObjectID, now time.Time) ([]domain.Session, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sessions := []domain.Session{}
+	for _, s := range r.sessions {
+		if s.UserID == userID && s.ExpiresAt.After(now) {
+			sessions = append(sessions, s)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].CreatedAt.After(sessions[j].CreatedAt) })
+	return sessions, nil
+}
+
+func (r *sessionRepository) DeleteByUser(_ context.Context, userID primitive.ObjectID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for id, s := range r.sessions {
+		if s.UserID == userID {
+			delete(r.sessions, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"sync"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type statsRepository struct {
+	mu    sync.RWMutex
+	stats map[primitive.ObjectID]domain.PlayerStats
+}
+
+func NewStatsRepository() domain.StatsRepository {
+	return &statsRepository{
+		stats: make(map[primitive.ObjectID]domain.PlayerStats),
+	}
+}
+
+func (r *statsRepository) Get(_ context.Context, userID primitive.ObjectID) (*domain.PlayerStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stats, ok := r.stats[userID]
+	if !ok {
+		return &domain.PlayerStats{UserID: userID}, nil
+	}
+	out := cloneStats(&stats)
+	return &out, nil
+}
+
+func (r *statsRepository) Save(_ context.Context, stats *domain.PlayerStats) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.stats[stats.UserID] = cloneStats(stats)
+	return nil
+}
+
+func (r *statsRepository) Delete(_ context.Context, userID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.stats, userID)
+	return nil
+}
+
+func cloneStats(stats *domain.PlayerStats) domain.PlayerStats {
+	out := *stats
+	out.ModeGames = maps.Clone(stats.ModeGames)
+	if stats.LastPlayedAt != nil {
+		played := *stats.LastPlayedAt
+		out.LastPlayedAt = &played
+	}
+	return out
+}
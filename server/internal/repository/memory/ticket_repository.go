@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+)
+
+type ticketRepository struct {
+	mu      sync.Mutex
+	tickets map[string]domain.Ticket
+}
+
+func NewTicketRepository() domain.TicketRepository {
+	return &ticketRepository{
+		tickets: make(map[string]domain.Ticket),
+	}
+}
+
+func (r *ticketRepository) Create(_ context.Context, ticket *domain.Ticket) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tickets[ticket.Value] = *ticket
+	return nil
+}
+
+func (r *ticketRepository) Take(_ context.Context, value string, now time.Time) (*domain.Ticket, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ticket, ok := r.tickets[value]
+	if !ok {
+		return nil, domain.ErrInvalidTicket
+	}
+	delete(r.tickets, value)
+	if !ticket.ExpiresAt.After(now) {
+		return nil, domain.ErrInvalidTicket
+	}
+	return &ticket, nil
+}
@@ -0,0 +1,26 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+)
+
+// transactor runs transactions one at a time, so they are isolated from
+// each other. Nothing is rolled back when fn fails; tests that need that
+// must use MongoDB.
+type transactor struct {
+	mu sync.Mutex
+}
+
+func NewTransactor() domain.Transactor {
+	return &transactor{}
+}
+
+func (t *transactor) WithTransaction(c context.Context, fn func(ctx context.Context) error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return fn(c)
+}
@@ -0,0 +1,116 @@
+// Package memory implements the domain repositories in process memory. It
+// backs the HTTP test harness and local tooling; data is lost on exit.
+package memory
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type userRepository struct {
+	mu    sync.RWMutex
+	users map[primitive.ObjectID]domain.User
+}
+
+func NewUserRepository() domain.UserRepository {
+	return &userRepository{
+		users: make(map[primitive.ObjectID]domain.User),
+	}
+}
+
+func (r *userRepository) Create(_ context.Context, user *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Mirror the unique indexes of the users collection.
+	for _, u := range r.users {
+		if u.Email == user.Email {
+			return domain.ErrEmailExists
+		}
+		if u.Username == user.Username {
+			return domain.ErrUsernameExists
+		}
+	}
+
+	if user.ID.IsZero() {
+		user.ID = primitive.NewObjectID()
+	}
+	r.users[user.ID] = clone(user)
+	return nil
+}
+
+func (r *userRepository) Update(_ context.Context, user *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[user.ID]; !ok {
+		return domain.ErrUserNotFound
+	}
+	r.users[user.ID] = clone(user)
+	return nil
+}
+
+func (r *userRepository) Delete(_ context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[id]; !ok {
+		return domain.ErrUserNotFound
+	}
+	delete(r.users, id)
+	return nil
+}
+
+func (r *userRepository) GetByEmail(_ context.Context, email string) (*domain.User, error) {
+	return r.find(func(u *domain.User) bool { return u.Email == email })
+}
+
+func (r *userRepository) GetByUsername(_ context.Context, username string) (*domain.User, error) {
+	return r.find(func(u *domain.User) bool { return u.Username == username })
+}
+
+func (r *userRepository) GetByID(_ context.Context, id string) (*domain.User, error) {
+	objID, err := primitive.ObjectIDFromHex(strings.TrimSpace(id))
+	if err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[objID]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	out := clone(&user)
+	return &out, nil
+}
+
+func (r *userRepository) find(match func(*domain.User) bool) (*domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, u := range r.users {
+		if match(&u) {
+			out := clone(&u)
+			return &out, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+// clone copies a user so callers can't mutate stored state through slices.
+func clone(user *domain.User) domain.User {
+	out := *user
+	out.FriendsList = append([]primitive.ObjectID(nil), user.FriendsList...)
+	out.Roles = append([]domain.Role(nil), user.Roles...)
+	if user.BannedAt != nil {
+		bannedAt := *user.BannedAt
+		out.BannedAt = &bannedAt
+	}
+	return out
+}
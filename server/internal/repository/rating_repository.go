@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// boardOrder sorts a leaderboard; the (mode, rating, user_id) index serves
+// it and its reverse, so a page reads only its own entries.
+var boardOrder = bson.D{{Key: "rating", Value: -1}, {Key: "user_id", Value: 1}}
+
+type ratingRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewRatingRepository(db *mongo.Database, collection string) domain.RatingRepository {
+	return &ratingRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (r *ratingRepository) Get(c context.Context, userID primitive.ObjectID, mode domain.GameMode) (_ *domain.PlayerRating, err error) {
+	c, span := startSpan(c, "ratingRepository.Get", r.collection)
+	defer func() { tracing.End(span, ignoreNotFound(err)) }()
+
+	var rating domain.PlayerRating
+	err = r.database.Collection(r.collection).FindOne(c, bson.M{"user_id": userID, "mode": mode}).Decode(&rating)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrNotRanked
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rating, nil
+}
+
+func (r *ratingRepository) ListByUsers(c context.Context, mode domain.GameMode, userIDs []primitive.ObjectID) (_ []domain.PlayerRating, err error) {
+	c, span := startSpan(c, "ratingRepository.ListByUsers", r.collection)
+	defer func() { tracing.End(span, err) }()
+
+	return r.find(c, bson.M{"mode": mode, "user_id": bson.M{"$in": userIDs}}, options.Find())
+}
+
+func (r *ratingRepository) Save(c context.Context, rating *domain.PlayerRating) (err error) {
+	c, span := startSpan(c, "ratingRepository.Save", r.collection)
+	defer func() { tracing.End(span, err) }()
+
+	// Upsert on the unique (user_id, mode) key, leaving _id to the stored
+	// document.
+	doc := *rating
+	doc.ID = primitive.NilObjectID
+	opts := options.Replace().SetUpsert(true)
+	result, err := r.database.Collection(r.collection).ReplaceOne(c, bson.M{"user_id": rating.UserID, "mode": rating.Mode}, doc, opts)
+	if err != nil {
+		return err
+	}
+
+	if oid, ok := result.UpsertedID.(primitive.ObjectID); ok {
+		rating.ID = oid
+	}
+
+	return nil
+}
+
+func (r *ratingRepository) ListRanked(c context.Context, mode domain.GameMode, userIDs []primitive.ObjectID, after *domain.RankCursor, limit int) (_ []domain.PlayerRating, err error) {
+	c, span := startSpan(c, "ratingRepository.ListRanked", r.collection)
+	defer func() { tracing.End(span, err) }()
+
+	filter := boardFilter(mode, userIDs)
+	if after != nil {
+		filter["$or"] = bson.A{
+			bson.M{"rating": bson.M{"$lt": after.Rating}},
+			bson.M{"rating": after.Rating, "user_id": bson.M{"$gt": after.UserID}},
+		}
+	}
+	return r.find(c, filter, options.Find().SetSort(boardOrder).SetLimit(int64(limit)))
+}
+
+func (r *ratingRepository) ListAhead(c context.Context, mode domain.GameMode, userIDs []primitive.ObjectID, pos domain.RankCursor, limit int) (_ []domain.PlayerRating, err error) {
+	c, span := startSpan(c, "ratingRepository.ListAhead", r.collection)
+	defer func() { tracing.End(span, err) }()
+
+	// Walk the board upwards from pos, then put the entries back in order.
+	reverse := bson.D{{Key: "rating", Value: 1}, {Key: "user_id", Value: -1}}
+	ratings, err := r.find(c, aheadFilter(mode, userIDs, pos), options.Find().SetSort(reverse).SetLimit(int64(limit)))
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(ratings)-1; i < j; i, j = i+1, j-1 {
+		ratings[i], ratings[j] = ratings[j], ratings[i]
+	}
+	return ratings, nil
+}
+
+func (r *ratingRepository) CountAhead(c context.Context, mode domain.GameMode, userIDs []primitive.ObjectID, pos domain.RankCursor) (_ int, err error) {
+	c, span := startSpan(c, "ratingRepository.CountAhead", r.collection)
+	defer func() { tracing.End(span, err) }()
+
+	count, err := r.database.Collection(r.collection).CountDocuments(c, aheadFilter(mode, userIDs, pos))
+	return int(count), err
+}
+
+func (r *ratingRepository) find(c context.Context, filter bson.M, opts *options.FindOptions) ([]domain.PlayerRating, error) {
+	cursor, err := r.database.Collection(r.collection).Find(c, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ratings := []domain.PlayerRating{}
+	if err := cursor.All(c, &ratings); err != nil {
+		return nil, err
+	}
+
+	return ratings, nil
+}
+
+func boardFilter(mode domain.GameMode, userIDs []primitive.ObjectID) bson.M {
+	filter := bson.M{"mode": mode}
+	if userIDs != nil {
+		filter["user_id"] = bson.M{"$in": userIDs}
+	}
+	return filter
+}
+
+func aheadFilter(mode domain.GameMode, userIDs []primitive.ObjectID, pos domain.RankCursor) bson.M {
+	filter := boardFilter(mode, userIDs)
+	filter["$or"] = bson.A{
+		bson.M{"rating": bson.M{"$gt": pos.Rating}},
+		bson.M{"rating": pos.Rating, "user_id": bson.M{"$lt": pos.UserID}},
+	}
+	return filter
+}
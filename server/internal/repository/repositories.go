@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Repositories groups the storage the usecases are built from, so the router
+// runs on MongoDB in production and on the in-memory implementations in tests.
+type Repositories struct {
+	User    domain.UserRepository
+	Session domain.SessionRepository
+	Lobby   domain.LobbyRepository
+	Ticket  domain.TicketRepository
+	Match   domain.MatchRepository
+	Rating  domain.RatingRepository
+	Stats   domain.StatsRepository
+	// Tx runs transactions across the repositories above.
+	Tx domain.Transactor
+}
+
+func NewMongoRepositories(db *mongo.Database) Repositories {
+	return Repositories{
+		User:    NewUserRepository(db, domain.CollectionUser),
+		Session: NewSessionRepository(db, domain.CollectionSession),
+		Lobby:   NewLobbyRepository(db, domain.CollectionLobby),
+		Ticket:  NewTicketRepository(db, domain.CollectionTicket),
+		Match:   NewMatchRepository(db, domain.CollectionMatch),
+		Rating:  NewRatingRepository(db, domain.CollectionRating),
+		Stats:   NewStatsRepository(db, domain.CollectionStats),
+		Tx:      NewTransactor(db.Client()),
+	}
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type sessionRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewSessionRepository(db *mongo.Database, collection string) domain.SessionRepository {
+	return &sessionRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (r *sessionRepository) Create(c context.Context, session *domain.Session) (err error) {
+	c, span := startSpan(c, "sessionRepository.Create", r.collection)
+	defer func() { tracing.End(span, err) }()
+
+	result, err := r.database.Collection(r.collection).InsertOne(c, session)
+	if err != nil {
+		return err
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		session.ID = oid
+	}
+
+	return nil
+}
+
+func (r *sessionRepository) ListActiveByUser(c context.Context, userID primitive.ObjectID, now time.Time) (_ []domain.Session, err error) {
+	c, span := startSpan(c, "sessionRepository.ListActiveByUser", r.collection)
+	defer func() { tracing.End(span, err) }()
+
+	filter := bson.M{"user_id": userID, "expires_at": bson.M{"$gt": now}}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := r.database.Collection(r.collection).Find(c, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := []domain.Session{}
+	if err := cursor.All(c, &sessions); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (r *sessionRepository) DeleteByUser(c context.Context, userID primitive.ObjectID) (_ int64, err error) {
+	c, span := startSpan(c, "sessionRepository.DeleteByUser", r.collection)
+	defer func() { tracing.End(span, err) }()
+
+	result, err := r.database.Collection(r.collection).DeleteMany(c, bson.M{"user_id": userID})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type statsRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewStatsRepository(db *mongo.Database, collection string) domain.StatsRepository {
+	return &statsRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (r *statsRepository) Get(c context.Context, userID primitive.ObjectID) (_ *domain.PlayerStats, err error) {
+	c, span := startSpan(c, "statsRepository.Get", r.collection)
+	defer func() { tracing.End(span, err) }()
+
+	var stats domain.PlayerStats
+	err = r.database.Collection(r.collection).FindOne(c, bson.M{"_id": userID}).Decode(&stats)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return &domain.PlayerStats{UserID: userID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+func (r *statsRepository) Save(c context.Context, stats *domain.PlayerStats) (err error) {
+	c, span := startSpan(c, "statsRepository.Save", r.collection)
+	defer func() { tracing.End(span, err) }()
+
+	opts := options.Replace().SetUpsert(true)
+	_, err = r.database.Collection(r.collection).ReplaceOne(c, bson.M{"_id": stats.UserID}, stats, opts)
+	return err
+}
+
+func (r *statsRepository) Delete(c context.Context, userID primitive.ObjectID) (err error) {
+	c, span := startSpan(c, "statsRepository.Delete", r.collection)
+	defer func() { tracing.End(span, err) }()
+
+	_, err = r.database.Collection(r.collection).DeleteOne(c, bson.M{"_id": userID})
+	return err
+}
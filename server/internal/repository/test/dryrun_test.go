@@ -0,0 +1,97 @@
+package repository_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/repository"
+	"github.com/Simpolette/HeartSteal/server/internal/repository/memory"
+)
+
+func memoryRepositories() repository.Repositories {
+	return repository.Repositories{
+		User:    memory.NewUserRepository(),
+		Session: memory.NewSessionRepository(),
+		Lobby:   memory.NewLobbyRepository(),
+		Ticket:  memory.NewTicketRepository(),
+		Match:   memory.NewMatchRepository(),
+		Rating:  memory.NewRatingRepository(),
+		Stats:   memory.NewStatsRepository(),
+		Tx:      memory.NewTransactor(),
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	ctx := context.Background()
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	t.Run("ReadsHitStorage", func(t *testing.T) {
+		repos := memoryRepositories()
+		user := &domain.User{Username: "johndoe", Email: "john@example.com"}
+		require.NoError(t, repos.User.Create(ctx, user))
+		dry := repository.DryRun(repos, log)
+
+		found, err := dry.User.GetByUsername(ctx, "johndoe")
+
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, found.ID)
+	})
+
+	t.Run("WritesAreDropped", func(t *testing.T) {
+		repos := memoryRepositories()
+		existing := &domain.User{Username: "johndoe", Email: "john@example.com"}
+		require.NoError(t, repos.User.Create(ctx, existing))
+		require.NoError(t, repos.Session.Create(ctx, &domain.Session{UserID: existing.ID, ExpiresAt: time.Now().Add(time.Hour)}))
+		dry := repository.DryRun(repos, log)
+
+		user := &domain.User{Username: "janedoe", Email: "jane@example.com"}
+		require.NoError(t, dry.User.Create(ctx, user))
+		assert.False(t, user.ID.IsZero(), "created documents still get an ID")
+		renamed := *existing
+		renamed.DisplayName = "Renamed"
+		require.NoError(t, dry.User.Update(ctx, &renamed))
+		require.NoError(t, dry.User.Delete(ctx, existing.ID))
+		deleted, err := dry.Session.DeleteByUser(ctx, existing.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted, "reports what would be deleted")
+		require.NoError(t, dry.Stats.Save(ctx, &domain.PlayerStats{UserID: existing.ID, Games: 3}))
+		require.NoError(t, dry.Match.Create(ctx, &domain.Match{}))
+
+		_, err = repos.User.GetByUsername(ctx, "janedoe")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+		stored, err := repos.User.GetByID(ctx, existing.ID.Hex())
+		require.NoError(t, err)
+		assert.Empty(t, stored.DisplayName)
+		sessions, err := repos.Session.ListActiveByUser(ctx, existing.ID, time.Now())
+		require.NoError(t, err)
+		assert.Len(t, sessions, 1)
+		stats, err := repos.Stats.Get(ctx, existing.ID)
+		require.NoError(t, err)
+		assert.Zero(t, stats.Games)
+		active, err := repos.Match.ListByStatus(ctx, domain.MatchActive)
+		require.NoError(t, err)
+		assert.Empty(t, active)
+	})
+
+	t.Run("TicketsAreNotConsumed", func(t *testing.T) {
+		repos := memoryRepositories()
+		ticket := &domain.Ticket{Value: "ticket", UserID: primitive.NewObjectID(), ExpiresAt: time.Now().Add(time.Minute)}
+		require.NoError(t, repos.Ticket.Create(ctx, ticket))
+		dry := repository.DryRun(repos, log)
+
+		_, err := dry.Ticket.Take(ctx, ticket.Value, time.Now())
+		assert.ErrorIs(t, err, domain.ErrInvalidTicket)
+
+		taken, err := repos.Ticket.Take(ctx, ticket.Value, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, ticket.UserID, taken.UserID)
+	})
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ticketRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewTicketRepository(db *mongo.Database, collection string) domain.TicketRepository {
+	return &ticketRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (r *ticketRepository) Create(c context.Context, ticket *domain.Ticket) (err error) {
+	c, span := startSpan(c, "ticketRepository.Create", r.collection)
+	defer func() { tracing.End(span, err) }()
+
+	_, err = r.database.Collection(r.collection).InsertOne(c, ticket)
+	return err
+}
+
+func (r *ticketRepository) Take(c context.Context, value string, now time.Time) (_ *domain.Ticket, err error) {
+	c, span := startSpan(c, "ticketRepository.Take", r.collection)
+	defer func() { tracing.End(span, ignoreNotFound(err)) }()
+
+	// The TTL index only sweeps about once a minute, so an expired ticket
+	// may still be stored; it is deleted here either way.
+	ticket := &domain.Ticket{}
+	err = r.database.Collection(r.collection).FindOneAndDelete(c, bson.M{"_id": value}).Decode(ticket)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.ErrInvalidTicket
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !ticket.ExpiresAt.After(now) {
+		return nil, domain.ErrInvalidTicket
+	}
+
+	return ticket, nil
+}
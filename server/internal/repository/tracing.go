@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Simpolette/HeartSteal/server/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = tracing.Tracer("github.com/Simpolette/HeartSteal/server/internal/repository")
+
+func startSpan(c context.Context, name string, collection string) (context.Context, trace.Span) {
+	return tracer.Start(c, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "mongodb"),
+			attribute.String("db.collection.name", collection),
+		),
+	)
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// transactor runs MongoDB multi-document transactions, which need a replica
+// set; Atlas clusters always are one. Repositories join the transaction
+// through the session carried by the context.
+type transactor struct {
+	client *mongo.Client
+}
+
+func NewTransactor(client *mongo.Client) domain.Transactor {
+	return &transactor{client: client}
+}
+
+func (t *transactor) WithTransaction(c context.Context, fn func(ctx context.Context) error) error {
+	session, err := t.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(c)
+
+	// The driver retries fn on transient errors and the commit on unknown
+	// results.
+	_, err = session.WithTransaction(c, func(ctx mongo.SessionContext) (any, error) {
+		return nil, fn(ctx)
+	})
+	return err
+}
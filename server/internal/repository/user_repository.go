@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/tracing"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -21,7 +22,10 @@ func NewUserRepository(db *mongo.Database, collection string) domain.UserReposit
 	}
 }
 
-func (r *userRepository) Create(c context.Context, user *domain.User) error {
+func (r *userRepository) Create(c context.Context, user *domain.User) (err error) {
+	c, span := startSpan(c, "userRepository.Create", r.collection)
+	defer func() { tracing.End(span, err) }()
+
 	collection := r.database.Collection(r.collection)
 
 	result, err := collection.InsertOne(c, user)
@@ -36,14 +40,51 @@ func (r *userRepository) Create(c context.Context, user *domain.User) error {
 	return nil
 }
 
-func (r *userRepository) GetByEmail(c context.Context, email string) (*domain.User, error) {
+func (r *userRepository) Update(c context.Context, user *domain.User) (err error) {
+	c, span := startSpan(c, "userRepository.Update", r.collection)
+	defer func() { tracing.End(span, ignoreNotFound(err)) }()
+
+	collection := r.database.Collection(r.collection)
+
+	result, err := collection.ReplaceOne(c, bson.M{"_id": user.ID}, user)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *userRepository) Delete(c context.Context, id primitive.ObjectID) (err error) {
+	c, span := startSpan(c, "userRepository.Delete", r.collection)
+	defer func() { tracing.End(span, ignoreNotFound(err)) }()
+
+	collection := r.database.Collection(r.collection)
+
+	result, err := collection.DeleteOne(c, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *userRepository) GetByEmail(c context.Context, email string) (_ *domain.User, err error) {
+	c, span := startSpan(c, "userRepository.GetByEmail", r.collection)
+	defer func() { tracing.End(span, ignoreNotFound(err)) }()
+
 	collection := r.database.Collection(r.collection)
 
 	var user domain.User
 
 	filter := bson.M{"email": email}
 
-	err := collection.FindOne(c, filter).Decode(&user)
+	err = collection.FindOne(c, filter).Decode(&user)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, domain.ErrUserNotFound
@@ -55,14 +96,17 @@ func (r *userRepository) GetByEmail(c context.Context, email string) (*domain.Us
 }
 
 
-func (r *userRepository) GetByUsername(c context.Context, username string) (*domain.User, error) {
+func (r *userRepository) GetByUsername(c context.Context, username string) (_ *domain.User, err error) {
+	c, span := startSpan(c, "userRepository.GetByUsername", r.collection)
+	defer func() { tracing.End(span, ignoreNotFound(err)) }()
+
 	collection := r.database.Collection(r.collection)
 
 	var user domain.User
 
 	filter := bson.M{"username": username}
 
-	err := collection.FindOne(c, filter).Decode(&user)
+	err = collection.FindOne(c, filter).Decode(&user)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, domain.ErrUserNotFound
@@ -74,7 +118,10 @@ func (r *userRepository) GetByUsername(c context.Context, username string) (*dom
 }
 
 
-func (r *userRepository) GetByID(c context.Context, id string) (*domain.User, error) {
+func (r *userRepository) GetByID(c context.Context, id string) (_ *domain.User, err error) {
+	c, span := startSpan(c, "userRepository.GetByID", r.collection)
+	defer func() { tracing.End(span, ignoreNotFound(err)) }()
+
 	collection := r.database.Collection(r.collection)
 
 	objID, err := primitive.ObjectIDFromHex(id)
@@ -95,4 +142,13 @@ func (r *userRepository) GetByID(c context.Context, id string) (*domain.User, er
 	}
 
 	return &user, nil
-}
\ No newline at end of file
+}
+
+// ignoreNotFound keeps lookups that legitimately miss from being reported as
+// failed spans.
+func ignoreNotFound(err error) error {
+	if err == domain.ErrUserNotFound || err == domain.ErrLobbyNotFound || err == domain.ErrInvalidTicket || err == domain.ErrMatchNotFound {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,71 @@
+package route
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/Simpolette/HeartSteal/server/internal/apierror"
+	"github.com/Simpolette/HeartSteal/server/internal/bot"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/game"
+	"github.com/Simpolette/HeartSteal/server/internal/matchmaking"
+)
+
+var registerErrorsOnce sync.Once
+
+// registerErrors is the single place where domain errors are translated into
+// HTTP responses. Add new sentinels here rather than in handlers.
+func registerErrors() {
+	registerErrorsOnce.Do(func() {
+		apierror.Register(domain.ErrInternalServerError, http.StatusInternalServerError, domain.CodeInternal, "Internal server error")
+		apierror.Register(domain.ErrRateLimited, http.StatusTooManyRequests, domain.CodeRateLimited, "Too many requests")
+
+		apierror.Register(domain.ErrUnauthorized, http.StatusUnauthorized, domain.CodeUnauthorized, "Not authorized")
+		apierror.Register(domain.ErrInvalidToken, http.StatusUnauthorized, domain.CodeInvalidToken, "Invalid access token")
+		apierror.Register(domain.ErrInvalidCredentials, http.StatusUnauthorized, domain.CodeInvalidCredentials, "Invalid username or password")
+		apierror.Register(domain.ErrUserBanned, http.StatusForbidden, domain.CodeUserBanned, "This account has been banned")
+
+		apierror.Register(domain.ErrUserNotFound, http.StatusNotFound, domain.CodeUserNotFound, "User not found")
+		apierror.Register(domain.ErrEmailExists, http.StatusConflict, domain.CodeEmailExists, "Email already existed")
+		apierror.Register(domain.ErrUsernameExists, http.StatusConflict, domain.CodeUsernameExists, "Username already existed")
+
+		apierror.Register(domain.ErrInvalidCursor, http.StatusBadRequest, domain.CodeInvalidCursor, "Invalid pagination cursor")
+
+		apierror.Register(domain.ErrLobbyNotFound, http.StatusNotFound, domain.CodeLobbyNotFound, "Lobby not found")
+		apierror.Register(domain.ErrLobbyFull, http.StatusConflict, domain.CodeLobbyFull, "Lobby is full")
+		apierror.Register(domain.ErrAlreadyInLobby, http.StatusConflict, domain.CodeAlreadyInLobby, "You are already in another lobby")
+		apierror.Register(domain.ErrNotInLobby, http.StatusConflict, domain.CodeNotInLobby, "Player is not in this lobby")
+		apierror.Register(domain.ErrNotLobbyHost, http.StatusForbidden, domain.CodeNotLobbyHost, "Only the lobby host can do this")
+		apierror.Register(domain.ErrCannotKickSelf, http.StatusBadRequest, domain.CodeCannotKickSelf, "The host can't kick themselves; leave the lobby instead")
+		apierror.Register(domain.ErrLobbyInGame, http.StatusConflict, domain.CodeLobbyInGame, "This lobby is already playing a match")
+		apierror.Register(domain.ErrLobbyConflict, http.StatusConflict, domain.CodeLobbyConflict, "The lobby changed while updating it, please try again")
+		apierror.Register(bot.ErrUnknownLevel, http.StatusBadRequest, domain.CodeUnknownBotLevel, "Unknown bot level")
+
+		apierror.Register(domain.ErrInvalidTicket, http.StatusUnauthorized, domain.CodeInvalidTicket, "Realtime ticket is invalid or expired")
+		apierror.Register(domain.ErrUpgradeRequired, http.StatusUpgradeRequired, domain.CodeUpgradeRequired, "This endpoint only accepts WebSocket connections")
+		apierror.Register(domain.ErrUnknownTopic, http.StatusBadRequest, domain.CodeUnknownTopic, "Unknown topic")
+		apierror.Register(domain.ErrTopicForbidden, http.StatusForbidden, domain.CodeTopicForbidden, "You can't subscribe to this topic")
+		apierror.Register(domain.ErrTooManySubscriptions, http.StatusBadRequest, domain.CodeTooManySubscriptions, "Too many subscriptions on this connection")
+
+		apierror.Register(domain.ErrMatchNotFound, http.StatusNotFound, domain.CodeMatchNotFound, "Match not found")
+		apierror.Register(game.ErrGameOver, http.StatusConflict, domain.CodeMatchOver, "This match is over")
+		apierror.Register(game.ErrNotYourTurn, http.StatusConflict, domain.CodeNotYourTurn, "It isn't your turn")
+		apierror.Register(game.ErrCardNotInHand, http.StatusBadRequest, domain.CodeInvalidMove, "That move isn't allowed")
+		apierror.Register(game.ErrInvalidTarget, http.StatusBadRequest, domain.CodeInvalidMove, "That move isn't allowed")
+		apierror.Register(domain.ErrReplayNotFound, http.StatusNotFound, domain.CodeReplayNotFound, "This match has no replay")
+		apierror.Register(domain.ErrSpectatingForbidden, http.StatusForbidden, domain.CodeSpectatingForbidden, "The players don't let you watch this match")
+		apierror.Register(domain.ErrUnknownSpectatorPolicy, http.StatusBadRequest, domain.CodeUnknownPolicy, "Unknown spectator policy")
+
+		apierror.Register(matchmaking.ErrAlreadyQueued, http.StatusConflict, domain.CodeAlreadyQueued, "You are already queued for a match")
+		apierror.Register(matchmaking.ErrNotQueued, http.StatusNotFound, domain.CodeNotQueued, "You are not queued for a match")
+		apierror.Register(matchmaking.ErrProposalNotFound, http.StatusNotFound, domain.CodeNoPendingMatch, "No match is waiting for you to accept")
+		apierror.Register(matchmaking.ErrTicketTaken, http.StatusConflict, domain.CodeMatchmakingConflict, "The queue changed while updating it, please try again")
+		apierror.Register(domain.ErrUnknownGameMode, http.StatusBadRequest, domain.CodeUnknownGameMode, "Unknown game mode")
+		apierror.Register(domain.ErrPartyTooLarge, http.StatusBadRequest, domain.CodePartyTooLarge, "Your party has more players than this mode seats")
+		apierror.Register(domain.ErrPartyNotFriends, http.StatusForbidden, domain.CodePartyNotFriends, "You can only queue with your friends")
+		apierror.Register(domain.ErrAlreadyInMatch, http.StatusConflict, domain.CodeAlreadyInMatch, "You or a party member are already playing a match")
+
+		apierror.Register(domain.ErrNotRanked, http.StatusNotFound, domain.CodeNotRanked, "You haven't played this mode ranked yet")
+		apierror.Register(domain.ErrUnknownLeaderboardScope, http.StatusBadRequest, domain.CodeUnknownScope, "Unknown leaderboard scope")
+	})
+}
@@ -0,0 +1,27 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/Simpolette/HeartSteal/server/internal/handler"
+	"github.com/Simpolette/HeartSteal/server/internal/health"
+	"github.com/Simpolette/HeartSteal/server/internal/openapi"
+)
+
+// NewHealthRouter registers the probes at the root so orchestrators don't
+// depend on the API prefix.
+func NewHealthRouter(registry *health.Registry, router *openapi.Router) {
+	h := handler.NewHealthHandler(registry)
+
+	router.GET("/healthz", handler.LivenessOperation, h.Liveness)
+	router.GET("/readyz", handler.ReadinessOperation, h.Readiness)
+}
+
+// shouldTrace keeps probe and scrape traffic out of traces.
+func shouldTrace(r *http.Request) bool {
+	switch r.URL.Path {
+	case "/healthz", "/readyz", "/metrics":
+		return false
+	}
+	return true
+}
@@ -0,0 +1,18 @@
+package route
+
+import (
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/handler"
+	"github.com/Simpolette/HeartSteal/server/internal/openapi"
+)
+
+// NewHistoryRouter mounts players' match histories and stats on an
+// authenticated group. The history usecase is shared with matches, which
+// record results.
+func NewHistoryRouter(history domain.HistoryUsecase, protected *openapi.Router) {
+	h := handler.NewHistoryHandler(history)
+
+	group := protected.Group("/users/:username")
+	group.GET("/matches", handler.MatchHistoryOperation, h.Matches)
+	group.GET("/stats", handler.PlayerStatsOperation, h.Stats)
+}
@@ -0,0 +1,18 @@
+package route
+
+import (
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/handler"
+	"github.com/Simpolette/HeartSteal/server/internal/openapi"
+)
+
+// NewLeaderboardRouter mounts the leaderboards on an authenticated group.
+// The ratings usecase is shared with matches, which record results, and
+// matchmaking, which pairs by rating.
+func NewLeaderboardRouter(ratings domain.RatingUsecase, protected *openapi.Router) {
+	h := handler.NewLeaderboardHandler(ratings)
+
+	group := protected.Group("/leaderboards")
+	group.GET("/:mode", handler.LeaderboardOperation, h.List)
+	group.GET("/:mode/around-me", handler.AroundMeOperation, h.AroundMe)
+}
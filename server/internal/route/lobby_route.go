@@ -0,0 +1,57 @@
+package route
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/bootstrap"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/handler"
+	"github.com/Simpolette/HeartSteal/server/internal/middleware"
+	"github.com/Simpolette/HeartSteal/server/internal/openapi"
+	"github.com/Simpolette/HeartSteal/server/internal/ratelimit"
+	"github.com/Simpolette/HeartSteal/server/internal/realtime"
+	"github.com/Simpolette/HeartSteal/server/internal/repository"
+	"github.com/Simpolette/HeartSteal/server/internal/usecase"
+)
+
+// Creating a lobby allocates an invite code, so keep a player from churning
+// through rooms.
+var createLobbyPolicy = ratelimit.Policy{
+	Name:      "lobby_create",
+	Algorithm: ratelimit.SlidingWindow,
+	Limit:     10,
+	Window:    time.Minute,
+	Key:       ratelimit.ByUserID,
+}
+
+// NewLobbyRouter mounts the lobby endpoints on an authenticated group.
+func NewLobbyRouter(app *bootstrap.Application, timeout time.Duration, repos repository.Repositories, matches domain.MatchUsecase, protected *openapi.Router) {
+	uc := usecase.NewLobbyUseCase(repos.Lobby, repos.User, repos.Match, matches, app.Matchmaking, app.Realtime, timeout)
+	h := handler.NewLobbyHandler(uc)
+	app.Realtime.Authorize(domain.TopicLobby, lobbyTopicAuthorizer(uc))
+
+	group := protected.Group("/lobbies")
+	group.POST("", handler.CreateLobbyOperation, middleware.RateLimitMiddleware(app.RateLimiter, createLobbyPolicy), h.Create)
+	group.GET("", handler.ListLobbiesOperation, h.List)
+	group.GET("/current", handler.CurrentLobbyOperation, h.Current)
+	group.POST("/join", handler.JoinLobbyByCodeOperation, h.JoinByCode)
+	group.GET("/:id", handler.GetLobbyOperation, h.Get)
+	group.POST("/:id/join", handler.JoinLobbyOperation, h.Join)
+	group.POST("/:id/leave", handler.LeaveLobbyOperation, h.Leave)
+	group.DELETE("/:id/members/:user_id", handler.KickLobbyMemberOperation, h.Kick)
+	group.POST("/:id/bots", handler.AddLobbyBotOperation, h.AddBot)
+}
+
+// lobbyTopicAuthorizer only lets members follow a lobby, since its events
+// carry the invite code.
+func lobbyTopicAuthorizer(uc domain.LobbyUsecase) realtime.Authorizer {
+	return func(ctx context.Context, userID, lobbyID string) error {
+		lobby, err := uc.Current(ctx, userID)
+		if errors.Is(err, domain.ErrLobbyNotFound) || (err == nil && lobby.ID.Hex() != lobbyID) {
+			return domain.ErrTopicForbidden
+		}
+		return err
+	}
+}
@@ -0,0 +1,47 @@
+package route
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/Simpolette/HeartSteal/server/internal/bootstrap"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/handler"
+	"github.com/Simpolette/HeartSteal/server/internal/openapi"
+	"github.com/Simpolette/HeartSteal/server/internal/realtime"
+)
+
+// NewMatchRouter mounts the match endpoints on an authenticated group. The
+// matches usecase is shared with the lobbies, which start matches.
+func NewMatchRouter(app *bootstrap.Application, uc domain.MatchUsecase, protected *openapi.Router) {
+	h := handler.NewMatchHandler(uc)
+	app.Realtime.Authorize(domain.TopicMatch, matchTopicAuthorizer(uc))
+	app.Realtime.OnPresence(domain.TopicMatch, matchPresence(uc))
+
+	group := protected.Group("/matches")
+	group.GET("/:id", handler.GetMatchOperation, h.Get)
+	group.POST("/:id/actions", handler.PlayCardOperation, h.Play)
+	group.GET("/:id/resume", handler.ResumeMatchOperation, h.Resume)
+	group.GET("/:id/replay", handler.ReplayOperation, h.Replay)
+}
+
+// matchTopicAuthorizer only lets players follow a match.
+func matchTopicAuthorizer(uc domain.MatchUsecase) realtime.Authorizer {
+	return func(ctx context.Context, userID, matchID string) error {
+		_, _, err := uc.Get(ctx, userID, matchID)
+		if errors.Is(err, domain.ErrMatchNotFound) {
+			return domain.ErrTopicForbidden
+		}
+		return err
+	}
+}
+
+// matchPresence counts a player as connected while they follow their match.
+func matchPresence(uc domain.MatchUsecase) realtime.Presence {
+	return func(userID, matchID string, present bool) {
+		if err := uc.SetConnected(context.Background(), userID, matchID, present); err != nil {
+			slog.Warn("Match presence can't be recorded", "match_id", matchID, "user_id", userID, "error", err)
+		}
+	}
+}
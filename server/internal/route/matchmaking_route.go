@@ -0,0 +1,43 @@
+package route
+
+import (
+	"context"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/bootstrap"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/handler"
+	"github.com/Simpolette/HeartSteal/server/internal/matchmaking"
+	"github.com/Simpolette/HeartSteal/server/internal/middleware"
+	"github.com/Simpolette/HeartSteal/server/internal/openapi"
+	"github.com/Simpolette/HeartSteal/server/internal/ratelimit"
+	"github.com/Simpolette/HeartSteal/server/internal/repository"
+	"github.com/Simpolette/HeartSteal/server/internal/usecase"
+)
+
+// Every enqueue runs a matching pass, so keep a player from spinning the
+// queue.
+var enqueuePolicy = ratelimit.Policy{
+	Name:      "matchmaking_enqueue",
+	Algorithm: ratelimit.SlidingWindow,
+	Limit:     20,
+	Window:    time.Minute,
+	Key:       ratelimit.ByUserID,
+}
+
+// NewMatchmakingRouter mounts the queue endpoints on an authenticated group
+// and starts the matchmaker, which runs for the life of the process. Events
+// go to each player's user topic, so no topic kind is registered.
+func NewMatchmakingRouter(app *bootstrap.Application, timeout time.Duration, repos repository.Repositories, matches domain.MatchUsecase, ratings domain.RatingSource, protected *openapi.Router) {
+	cfg := matchmaking.DefaultConfig()
+	cfg.AcceptTimeout = time.Duration(app.Env.MatchAcceptSeconds) * time.Second
+	uc := usecase.NewMatchmakingUseCase(app.Matchmaking, repos.User, repos.Lobby, repos.Match, matches, ratings, app.Realtime, cfg, timeout)
+	h := handler.NewMatchmakingHandler(uc)
+	go uc.Run(context.Background())
+
+	group := protected.Group("/matchmaking")
+	group.POST("/queue", handler.EnqueueOperation, middleware.RateLimitMiddleware(app.RateLimiter, enqueuePolicy), h.Enqueue)
+	group.GET("/queue", handler.CurrentQueueTicketOperation, h.Current)
+	group.DELETE("/queue", handler.CancelQueueOperation, h.Cancel)
+	group.POST("/accept", handler.AcceptMatchOperation, h.Accept)
+}
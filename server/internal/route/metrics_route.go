@@ -0,0 +1,24 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/Simpolette/HeartSteal/server/internal/metrics"
+	"github.com/Simpolette/HeartSteal/server/internal/openapi"
+	"github.com/gin-gonic/gin"
+)
+
+var metricsOperation = openapi.Operation{
+	Summary: "Prometheus metrics",
+	Tags:    []string{"operations"},
+	Responses: []openapi.Response{{
+		Status:      http.StatusOK,
+		Description: "Metrics in the Prometheus text exposition format",
+		Body:        "",
+		ContentType: "text/plain",
+	}},
+}
+
+func NewMetricsRouter(m *metrics.Metrics, router *openapi.Router) {
+	router.GET("/metrics", metricsOperation, gin.WrapH(m.Handler()))
+}
@@ -0,0 +1,78 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/Simpolette/HeartSteal/server/internal/bot"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/game"
+	"github.com/Simpolette/HeartSteal/server/internal/health"
+	"github.com/Simpolette/HeartSteal/server/internal/openapi"
+	"github.com/Simpolette/HeartSteal/server/internal/validation"
+)
+
+const (
+	openAPIPath = apiPrefix + "/openapi.json"
+	docsPath    = apiPrefix + "/docs"
+	assetsPath  = docsPath + "/assets"
+)
+
+var openAPIOperation = openapi.Operation{
+	Summary:   "OpenAPI 3 description of this API",
+	Tags:      []string{"operations"},
+	Responses: []openapi.Response{{Status: http.StatusOK, Description: "OpenAPI document"}},
+}
+
+var docsOperation = openapi.Operation{
+	Summary: "Interactive API documentation",
+	Tags:    []string{"operations"},
+	Responses: []openapi.Response{{
+		Status:      http.StatusOK,
+		Description: "Swagger UI page",
+		Body:        "",
+		ContentType: "text/html",
+	}, {
+		Status:      http.StatusServiceUnavailable,
+		Description: "Swagger UI isn't vendored in this build",
+		Body:        "",
+		ContentType: "text/plain",
+	}},
+}
+
+var docsAssetOperation = openapi.Operation{
+	Summary: "Vendored Swagger UI file used by the documentation page",
+	Tags:    []string{"operations"},
+	Params:  openapi.DocsParams{},
+	Responses: []openapi.Response{{
+		Status:      http.StatusOK,
+		Description: "Stylesheet or script",
+		Body:        "",
+		ContentType: "text/javascript",
+	}, {
+		Status:      http.StatusNotFound,
+		Description: "No such file",
+	}},
+}
+
+func newSpec() *openapi.Spec {
+	spec := openapi.NewSpec("HeartSteal API", "1.0.0",
+		"Generated from the route registrations. Errors use the ErrorResponse schema; see docs/api_spec.md for the error codes.")
+	spec.Enum(health.StatusUp, health.StatusDown)
+	spec.Enum(domain.LobbyPublic, domain.LobbyPrivate)
+	spec.Enum(domain.LobbyOpen, domain.LobbyInGame)
+	spec.Enum(domain.MatchActive, domain.MatchFinished, domain.MatchAbandoned)
+	spec.Enum(game.CardSteal, game.CardShield)
+	spec.Enum(domain.ModeDuel, domain.ModeTable)
+	spec.Enum(domain.LeaderboardGlobal, domain.LeaderboardFriends)
+	spec.Enum(domain.MatchWon, domain.MatchLost)
+	spec.Enum(domain.SpectateOff, domain.SpectateFriends, domain.SpectatePublic)
+	spec.Enum(bot.Easy, bot.Normal, bot.Hard)
+	validation.Describe(spec)
+	return spec
+}
+
+func NewOpenAPIRouter(spec *openapi.Spec, router *openapi.Router) {
+	router.GET(openAPIPath, openAPIOperation, spec.Handler())
+	router.GET(docsPath, docsOperation, openapi.DocsHandler(openAPIPath, assetsPath))
+	router.GET(assetsPath+"/:file", docsAssetOperation, openapi.DocsAssetsHandler())
+}
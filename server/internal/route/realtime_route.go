@@ -0,0 +1,35 @@
+package route
+
+import (
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/bootstrap"
+	"github.com/Simpolette/HeartSteal/server/internal/handler"
+	"github.com/Simpolette/HeartSteal/server/internal/middleware"
+	"github.com/Simpolette/HeartSteal/server/internal/openapi"
+	"github.com/Simpolette/HeartSteal/server/internal/ratelimit"
+	"github.com/Simpolette/HeartSteal/server/internal/repository"
+	"github.com/Simpolette/HeartSteal/server/internal/usecase"
+)
+
+// A client needs one ticket per connection attempt; this leaves room for
+// reconnect loops without letting tickets pile up.
+var realtimeTicketPolicy = ratelimit.Policy{
+	Name:      "realtime_ticket",
+	Algorithm: ratelimit.TokenBucket,
+	Limit:     30,
+	Window:    time.Minute,
+	Burst:     10,
+	Key:       ratelimit.ByUserID,
+}
+
+// NewRealtimeRouter mounts the ticket endpoint on the authenticated group and
+// the WebSocket itself on the public one, since it authenticates by ticket.
+func NewRealtimeRouter(app *bootstrap.Application, timeout time.Duration, repos repository.Repositories, versions apiVersions, protected *openapi.Router) {
+	ttl := time.Duration(app.Env.WSTicketTTLSeconds) * time.Second
+	uc := usecase.NewRealtimeUseCase(repos.Ticket, repos.User, ttl, timeout)
+	h := handler.NewRealtimeHandler(uc, app.Realtime)
+
+	protected.POST("/realtime/tickets", handler.IssueRealtimeTicketOperation, middleware.RateLimitMiddleware(app.RateLimiter, realtimeTicketPolicy), h.IssueTicket)
+	versions.V1.GET("/realtime", handler.ConnectRealtimeOperation, h.Connect)
+}
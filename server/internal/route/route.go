@@ -1,35 +1,168 @@
 package route
 
 import (
+	"context"
+	"errors"
 	"time"
-	"log"
+
 	"github.com/Simpolette/HeartSteal/server/internal/bootstrap"
+	"github.com/Simpolette/HeartSteal/server/internal/bot"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/handler"
+	"github.com/Simpolette/HeartSteal/server/internal/i18n"
+	"github.com/Simpolette/HeartSteal/server/internal/logger"
 	"github.com/Simpolette/HeartSteal/server/internal/middleware"
+	"github.com/Simpolette/HeartSteal/server/internal/repository"
+	"github.com/Simpolette/HeartSteal/server/internal/tracing"
+	"github.com/Simpolette/HeartSteal/server/internal/ttlcache"
+	"github.com/Simpolette/HeartSteal/server/internal/usecase"
+	"github.com/Simpolette/HeartSteal/server/internal/validation"
 
-	"go.mongodb.org/mongo-driver/mongo"
 	"github.com/gin-gonic/gin"
-	"github.com/gin-contrib/cors"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
-func Setup(env *bootstrap.Env, timeout time.Duration, db *mongo.Database, gin *gin.Engine) {
-	if err := gin.SetTrustedProxies(nil); err != nil {
-    	log.Fatal("Could not configure trusted proxies: ", err)
-	}
-
-	gin.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:3000"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true,
-		MaxAge:           12 * time.Hour,
-	}))
-	
-	publicRouter := gin.Group("/api")
+// Setup registers every route on gin. It returns the match usecase running
+// this process's matches, which the caller also sweeps for expired leases.
+func Setup(app *bootstrap.Application, timeout time.Duration, repos repository.Repositories, gin *gin.Engine) domain.MatchUsecase {
+	env := app.Env
+
+	// Only trust X-Forwarded-For from our load balancers; nil trusts nobody.
+	if err := gin.SetTrustedProxies(env.TrustedProxies); err != nil {
+    	logger.Fatal("Could not configure trusted proxies", "error", err)
+	}
+
+	registerErrors()
+	validation.Register()
+
+	gin.Use(
+		middleware.RequestIDMiddleware(app.Logger),
+		middleware.LocaleMiddleware(i18n.Default()),
+		middleware.AccessLogMiddleware(),
+		middleware.RecoveryMiddleware(),
+		middleware.ErrorHandlerMiddleware(),
+	)
+	gin.NoRoute(handler.NotFound)
+
+	corsMiddleware, err := middleware.CORSMiddleware(middleware.CORSConfig{
+		AllowedOrigins:   env.CORSAllowedOrigins,
+		AllowedMethods:   env.CORSAllowedMethods,
+		AllowedHeaders:   env.CORSAllowedHeaders,
+		ExposedHeaders:   []string{"Content-Length", "Content-Language", middleware.RequestIDHeader, "RateLimit-Policy", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After", "Deprecation", "Sunset", "Link"},
+		AllowCredentials: env.CORSAllowCredentials,
+		MaxAge:           time.Duration(env.CORSMaxAgeSeconds) * time.Second,
+	})
+	if err != nil {
+		logger.Fatal("Could not configure CORS", "error", err)
+	}
+	gin.Use(
+		middleware.SecurityHeadersMiddleware(middleware.SecurityHeadersConfig{
+			HSTSMaxAge:            env.HSTSMaxAgeSeconds,
+			HSTSIncludeSubdomains: true,
+			FrameAncestors:        env.FrameAncestors,
+			ReferrerPolicy:        env.ReferrerPolicy,
+		}),
+		corsMiddleware,
+	)
+
+	gin.Use(otelgin.Middleware(tracing.ServiceName, otelgin.WithFilter(shouldTrace)))
+	gin.Use(middleware.MetricsMiddleware(app.Metrics))
+
+	spec := newSpec()
+	root := spec.Router(&gin.RouterGroup)
+
+	NewHealthRouter(app.Health, root)
+
+	// Serve metrics on the API listener only when no dedicated address is set.
+	if env.MetricsAddress == "" || env.MetricsAddress == env.ServerAddress {
+		NewMetricsRouter(app.Metrics, root)
+	}
+
+	NewOpenAPIRouter(spec, root)
+
+	versions := newAPIVersions(root)
 	// All Public APIs
-	NewUserRouter(env, timeout, db, publicRouter)
+	NewUserRouter(app, timeout, repos, versions)
 
-	protectedRouter := gin.Group("/api")
-	protectedRouter.Use(middleware.JwtAuthMiddleware(env.AccessTokenSecret))
+	users := newUserStates(repos.User, time.Duration(env.UserCacheSeconds)*time.Second)
+	protectedRouter := versions.V1.Group("").Authenticated()
+	protectedRouter.Use(
+		middleware.JwtAuthMiddleware(env.AccessTokenSecret, app.Metrics, users.checkToken),
+		middleware.UserLocaleMiddleware(i18n.Default(), users.locale),
+	)
 	// All Private APIs
-}
\ No newline at end of file
+	ratings := usecase.NewRatingUseCase(repos.Rating, repos.User, timeout)
+	history := usecase.NewHistoryUseCase(repos.Match, repos.Stats, repos.User, timeout)
+	matches := usecase.NewMatchUseCase(repos.Match, repos.Lobby, ratings, history, repos.Tx, app.Realtime, usecase.MatchConfig{
+		SpectatorDelay:   time.Duration(env.SpectatorDelaySeconds) * time.Second,
+		ReconnectGrace:   time.Duration(env.ReconnectGraceSeconds) * time.Second,
+		DisconnectPolicy: domain.DisconnectPolicy(env.DisconnectPolicy),
+		DisconnectBot:    bot.Level(env.DisconnectBotLevel),
+		BotMove:          time.Duration(env.BotMoveSeconds) * time.Second,
+		Lease:            time.Duration(env.MatchLeaseSeconds) * time.Second,
+	}, timeout)
+	NewLobbyRouter(app, timeout, repos, matches, protectedRouter)
+	NewMatchRouter(app, matches, protectedRouter)
+	NewSpectatorRouter(app, timeout, repos, matches, protectedRouter)
+	NewMatchmakingRouter(app, timeout, repos, matches, ratings, protectedRouter)
+	NewLeaderboardRouter(ratings, protectedRouter)
+	NewHistoryRouter(history, protectedRouter)
+	NewRealtimeRouter(app, timeout, repos, versions, protectedRouter)
+	return matches
+}
+
+// userState is what protected requests read about their user every time.
+type userState struct {
+	locale       string
+	tokenVersion int
+	banned       bool
+}
+
+// userStates reads userState through a per-user cache, so authenticated
+// requests don't each cost a user lookup. Bans and revocations made by
+// another process apply once the cached state expires.
+type userStates struct {
+	users domain.UserRepository
+	cache *ttlcache.Cache[string, userState]
+}
+
+func newUserStates(ur domain.UserRepository, ttl time.Duration) *userStates {
+	return &userStates{users: ur, cache: ttlcache.New[string, userState](ttl, 0)}
+}
+
+func (s *userStates) get(ctx context.Context, userID string) (userState, error) {
+	return s.cache.Get(userID, func() (userState, error) {
+		user, err := s.users.GetByID(ctx, userID)
+		if err != nil {
+			return userState{}, err
+		}
+		return userState{locale: user.Locale, tokenVersion: user.TokenVersion, banned: user.IsBanned()}, nil
+	})
+}
+
+// checkToken rejects tokens of banned or deleted users and tokens issued
+// before the user's tokens were last revoked.
+func (s *userStates) checkToken(ctx context.Context, userID string, tokenVersion int) error {
+	state, err := s.get(ctx, userID)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return domain.ErrInvalidToken
+	}
+	if err != nil {
+		return err
+	}
+	if state.banned {
+		return domain.ErrUserBanned
+	}
+	if tokenVersion != state.tokenVersion {
+		return domain.ErrInvalidToken
+	}
+	return nil
+}
+
+func (s *userStates) locale(ctx context.Context, userID string) string {
+	state, err := s.get(ctx, userID)
+	if err != nil {
+		return ""
+	}
+	return state.locale
+}
@@ -0,0 +1,37 @@
+package route
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/bootstrap"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/handler"
+	"github.com/Simpolette/HeartSteal/server/internal/openapi"
+	"github.com/Simpolette/HeartSteal/server/internal/realtime"
+	"github.com/Simpolette/HeartSteal/server/internal/repository"
+	"github.com/Simpolette/HeartSteal/server/internal/usecase"
+)
+
+// NewSpectatorRouter mounts spectating on an authenticated group. The
+// matches usecase builds what spectators see.
+func NewSpectatorRouter(app *bootstrap.Application, timeout time.Duration, repos repository.Repositories, matches domain.MatchUsecase, protected *openapi.Router) {
+	uc := usecase.NewSpectatorUseCase(repos.Match, repos.User, matches, app.Realtime, timeout)
+	h := handler.NewSpectatorHandler(uc)
+	app.Realtime.Authorize(domain.TopicSpectate, spectateTopicAuthorizer(uc))
+
+	protected.PUT("/users/me/spectator-policy", handler.SetSpectatorPolicyOperation, h.SetPolicy)
+	protected.GET("/matches/:id/spectate", handler.SpectateMatchOperation, h.Watch)
+}
+
+// spectateTopicAuthorizer lets users the players allow follow a match.
+func spectateTopicAuthorizer(uc domain.SpectatorUsecase) realtime.Authorizer {
+	return func(ctx context.Context, userID, matchID string) error {
+		err := uc.CanWatch(ctx, userID, matchID)
+		if errors.Is(err, domain.ErrMatchNotFound) || errors.Is(err, domain.ErrSpectatingForbidden) {
+			return domain.ErrTopicForbidden
+		}
+		return err
+	}
+}
@@ -0,0 +1,100 @@
+package route_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Simpolette/HeartSteal/server/internal/apitest"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/usecase"
+)
+
+const protectedPath = "/api/v1/users/johndoe/stats"
+
+// login signs johndoe up and in through the API and returns the token.
+func login(t *testing.T, srv *apitest.Server, password string) apitest.RequestOption {
+	res := srv.POST("/api/v1/auth/login", map[string]any{"username": "johndoe", "password": password})
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	var body struct {
+		Data struct {
+			AccessToken string `json:"accessToken"`
+		} `json:"data"`
+	}
+	res.JSON(&body)
+	return apitest.WithToken(body.Data.AccessToken)
+}
+
+func signup(t *testing.T, srv *apitest.Server) {
+	res := srv.POST("/api/v1/auth/signup", map[string]any{"username": "johndoe", "email": "john@example.com", "password": "strongPassword123"})
+	require.Equal(t, http.StatusCreated, res.Code, res.Body.String())
+}
+
+func errorCode(res *apitest.Response) domain.ErrorCode {
+	var body domain.ErrorResponse
+	res.JSON(&body)
+	return body.Code
+}
+
+func TestJwtAuth_Revocation(t *testing.T) {
+	t.Run("Ban", func(t *testing.T) {
+		srv := apitest.New(t)
+		signup(t, srv)
+		token := login(t, srv, "strongPassword123")
+		require.Equal(t, http.StatusOK, srv.GET(protectedPath, token).Code)
+
+		_, err := usecase.NewAdminUseCase(srv.Repos.User, srv.Repos.Session, time.Second).Ban(t.Context(), "johndoe", "cheating")
+		require.NoError(t, err)
+		res := srv.GET(protectedPath, token)
+
+		assert.Equal(t, http.StatusForbidden, res.Code)
+		assert.Equal(t, domain.CodeUserBanned, errorCode(res))
+	})
+
+	t.Run("TokensStayRevokedAfterUnban", func(t *testing.T) {
+		srv := apitest.New(t)
+		signup(t, srv)
+		token := login(t, srv, "strongPassword123")
+		admin := usecase.NewAdminUseCase(srv.Repos.User, srv.Repos.Session, time.Second)
+		_, err := admin.Ban(t.Context(), "johndoe", "cheating")
+		require.NoError(t, err)
+		_, err = admin.Unban(t.Context(), "johndoe")
+		require.NoError(t, err)
+
+		res := srv.GET(protectedPath, token)
+
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+		assert.Equal(t, domain.CodeInvalidToken, errorCode(res))
+		assert.Equal(t, http.StatusOK, srv.GET(protectedPath, login(t, srv, "strongPassword123")).Code)
+	})
+
+	t.Run("ResetPassword", func(t *testing.T) {
+		srv := apitest.New(t)
+		signup(t, srv)
+		token := login(t, srv, "strongPassword123")
+
+		_, err := usecase.NewAdminUseCase(srv.Repos.User, srv.Repos.Session, time.Second).ResetPassword(t.Context(), "johndoe", "anotherPassword456")
+		require.NoError(t, err)
+		res := srv.GET(protectedPath, token)
+
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+		assert.Equal(t, domain.CodeInvalidToken, errorCode(res))
+		assert.Equal(t, http.StatusOK, srv.GET(protectedPath, login(t, srv, "anotherPassword456")).Code)
+	})
+
+	t.Run("DeletedUser", func(t *testing.T) {
+		srv := apitest.New(t)
+		signup(t, srv)
+		token := login(t, srv, "strongPassword123")
+		user, err := srv.Repos.User.GetByUsername(t.Context(), "johndoe")
+		require.NoError(t, err)
+
+		require.NoError(t, srv.Repos.User.Delete(t.Context(), user.ID))
+		res := srv.GET(protectedPath, token)
+
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+	})
+}
@@ -0,0 +1,53 @@
+package route_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Simpolette/HeartSteal/server/internal/apitest"
+	"github.com/Simpolette/HeartSteal/server/internal/bootstrap"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+)
+
+func TestUserLocale(t *testing.T) {
+	newUser := func(t *testing.T, srv *apitest.Server) (*domain.User, apitest.RequestOption) {
+		user := &domain.User{Username: "johndoe", DisplayName: "John", Email: "john@example.com", Locale: "vi"}
+		require.NoError(t, srv.Repos.User.Create(t.Context(), user))
+		return user, apitest.WithToken(srv.AccessToken(user.ID.Hex()))
+	}
+
+	t.Run("SavedLocaleWins", func(t *testing.T) {
+		srv := apitest.New(t)
+		_, token := newUser(t, srv)
+
+		res := srv.GET("/api/v1/lobbies/current", token, apitest.WithHeader("Accept-Language", "fr"))
+
+		assert.Equal(t, "vi", res.Header().Get("Content-Language"))
+	})
+
+	t.Run("Cached", func(t *testing.T) {
+		srv := apitest.New(t, apitest.WithEnv(func(env *bootstrap.Env) { env.UserCacheSeconds = 60 }))
+		user, token := newUser(t, srv)
+		require.Equal(t, "vi", srv.GET("/api/v1/lobbies/current", token).Header().Get("Content-Language"))
+
+		user.Locale = "fr"
+		require.NoError(t, srv.Repos.User.Update(t.Context(), user))
+		res := srv.GET("/api/v1/lobbies/current", token)
+
+		assert.Equal(t, "vi", res.Header().Get("Content-Language"), "the locale is reused until the cache expires")
+	})
+
+	t.Run("NotCached", func(t *testing.T) {
+		srv := apitest.New(t)
+		user, token := newUser(t, srv)
+		require.Equal(t, "vi", srv.GET("/api/v1/lobbies/current", token).Header().Get("Content-Language"))
+
+		user.Locale = "fr"
+		require.NoError(t, srv.Repos.User.Update(t.Context(), user))
+		res := srv.GET("/api/v1/lobbies/current", token)
+
+		assert.Equal(t, "fr", res.Header().Get("Content-Language"))
+	})
+}
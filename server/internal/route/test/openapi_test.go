@@ -0,0 +1,38 @@
+package route_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Simpolette/HeartSteal/server/internal/apitest"
+	"github.com/Simpolette/HeartSteal/server/internal/openapi"
+)
+
+func TestOpenAPI_CoversEveryRoute(t *testing.T) {
+	srv := apitest.New(t)
+	doc := srv.Document()
+
+	for _, r := range srv.Engine.Routes() {
+		item := doc.Paths.Value(openapi.Path(r.Path))
+		if assert.NotNil(t, item, "%s %s has no spec entry", r.Method, r.Path) {
+			assert.NotNil(t, item.GetOperation(r.Method), "%s %s has no spec entry", r.Method, r.Path)
+		}
+	}
+}
+
+func TestOpenAPI_DocsUI(t *testing.T) {
+	srv := apitest.New(t)
+
+	res := srv.GET("/api/docs")
+
+	if !openapi.DocsVendored() {
+		assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+		return
+	}
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Contains(t, res.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, res.Body.String(), `url: "/api/openapi.json"`)
+	assert.Equal(t, http.StatusOK, srv.GET("/api/docs/assets/swagger-ui-bundle.js").Code)
+}
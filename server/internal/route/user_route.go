@@ -3,29 +3,57 @@ package route
 import (
 	"time"
 
-	"github.com/gin-gonic/gin"
 	"github.com/Simpolette/HeartSteal/server/internal/bootstrap"
-	"github.com/Simpolette/HeartSteal/server/internal/domain"
 	"github.com/Simpolette/HeartSteal/server/internal/handler"
-	// "github.com/Simpolette/HeartSteal/server/internal/middleware"
+	"github.com/Simpolette/HeartSteal/server/internal/middleware"
+	"github.com/Simpolette/HeartSteal/server/internal/openapi"
+	"github.com/Simpolette/HeartSteal/server/internal/ratelimit"
 	"github.com/Simpolette/HeartSteal/server/internal/repository"
 	"github.com/Simpolette/HeartSteal/server/internal/usecase"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func NewUserRouter(env *bootstrap.Env, timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
-	ur := repository.NewUserRepository(db, domain.CollectionUser)
-	uc := usecase.NewUserUseCase(ur, timeout, env.AccessTokenSecret, env.AccessTokenExpiryHour)
-	h := handler.NewUserHandler(uc)
+var (
+	// Signups are rare per client, so a strict window stops account farming.
+	signupPolicy = ratelimit.Policy{
+		Name:      "signup",
+		Algorithm: ratelimit.SlidingWindow,
+		Limit:     5,
+		Window:    time.Hour,
+		Key:       ratelimit.ByIP,
+	}
+	// Logins allow a short burst for typos but throttle credential stuffing.
+	loginPolicy = ratelimit.Policy{
+		Name:      "login",
+		Algorithm: ratelimit.TokenBucket,
+		Limit:     10,
+		Window:    time.Minute,
+		Burst:     5,
+		Key:       ratelimit.ByIP,
+	}
+)
+
+func NewUserRouter(app *bootstrap.Application, timeout time.Duration, repos repository.Repositories, versions apiVersions) {
+	env := app.Env
+
+	uc := usecase.NewUserUseCase(repos.User, repos.Session, timeout, env.AccessTokenSecret, env.AccessTokenExpiryHour)
+	h := handler.NewUserHandler(uc, app.Metrics)
 
 	// Public Routes
-	group.POST("/signup", h.Signup)
-	group.POST("/login", h.Login)
+	newAuthRoutes(app, h, versions.V1.Group("/auth"))
+	// Deprecated: /api/signup and /api/login
+	newAuthRoutes(app, h, versions.legacyAlias(v1Prefix+"/auth"))
 
 	// Private Routes
 	// protected := group.Group("/users")
 	
-	// protected.Use(middleware.JwtAuthMiddleware(env.AccessTokenSecret))
+	// protected.Use(middleware.JwtAuthMiddleware(env.AccessTokenSecret, app.Metrics))
 	
 	// protected.GET("/profile", h.GetProfile)
+}
+
+// newAuthRoutes mounts the auth endpoints on group. Every version shares the
+// handler, so the legacy aliases and /api/v1/auth share rate limit quotas too.
+func newAuthRoutes(app *bootstrap.Application, h *handler.UserHandler, group *openapi.Router) {
+	group.POST("/signup", handler.SignupOperation, middleware.RateLimitMiddleware(app.RateLimiter, signupPolicy), h.Signup)
+	group.POST("/login", handler.LoginOperation, middleware.RateLimitMiddleware(app.RateLimiter, loginPolicy), h.Login)
 }
\ No newline at end of file
@@ -0,0 +1,49 @@
+package route
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/middleware"
+	"github.com/Simpolette/HeartSteal/server/internal/openapi"
+)
+
+// API versions are path prefixes. Each version is its own group so a future
+// /api/v2 can mount new handlers for the endpoints that change while reusing
+// the same usecases, and keep serving the rest unchanged.
+const (
+	apiPrefix = "/api"
+	v1Prefix  = apiPrefix + "/v1"
+)
+
+// The unversioned routes predate /api/v1 and are served as deprecated
+// aliases until the sunset date.
+var (
+	legacyDeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	legacySunsetAt     = time.Date(2027, time.April, 19, 0, 0, 0, 0, time.UTC)
+)
+
+type apiVersions struct {
+	// Legacy is the unversioned /api group, kept for old clients.
+	Legacy *openapi.Router
+	V1     *openapi.Router
+}
+
+func newAPIVersions(root *openapi.Router) apiVersions {
+	return apiVersions{
+		Legacy: root.Group(apiPrefix),
+		V1:     root.Group(v1Prefix),
+	}
+}
+
+// legacyAlias returns a group under the unversioned prefix whose routes are
+// marked deprecated and point clients to the same path under successorPrefix.
+func (v apiVersions) legacyAlias(successorPrefix string) *openapi.Router {
+	return v.Legacy.Deprecated().Group("", middleware.DeprecationMiddleware(middleware.Deprecation{
+		Since:  legacyDeprecatedAt,
+		Sunset: legacySunsetAt,
+		Successor: func(fullPath string) string {
+			return successorPrefix + strings.TrimPrefix(fullPath, apiPrefix)
+		},
+	}))
+}
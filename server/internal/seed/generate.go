@@ -0,0 +1,251 @@
+// Package seed generates deterministic development data. The same Config
+// always produces the same users, IDs, friendships and finished matches, so
+// a seeded database can be reproduced, extended and cleaned up again.
+package seed
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/game"
+	"github.com/Simpolette/HeartSteal/server/internal/replay"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DefaultPassword is the password of every generated user unless
+// Config.Password overrides it.
+const DefaultPassword = "heartsteal-dev"
+
+// AvatarURL is formatted with the username to give every user a stable
+// generated avatar.
+var AvatarURL = "https://api.dicebear.com/9.x/thumbs/svg?seed=%s"
+
+// epoch anchors generated timestamps so they don't depend on when the
+// generator runs.
+var epoch = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// matchStream keeps the random numbers of each user's matches apart from
+// the users' own.
+const matchStream = 0x4d41544348
+
+// moveTime is how long each generated move takes, to date the end of a match.
+const moveTime = 20 * time.Second
+
+var (
+	adjectives = []string{"amber", "brave", "clever", "crimson", "dusky", "eager", "fancy", "gentle", "golden", "happy",
+		"icy", "jolly", "lucky", "mellow", "nimble", "proud", "quiet", "rapid", "silver", "sly", "sunny", "swift", "tiny", "wild"}
+	animals = []string{"badger", "bear", "crane", "deer", "dove", "falcon", "fox", "hare", "heron", "lynx", "moth", "otter",
+		"owl", "panda", "raven", "seal", "swan", "tiger", "viper", "wolf", "wren", "yak"}
+	locales = []string{"en", "fr", "vi"}
+)
+
+type Config struct {
+	Seed           int64
+	Users          int
+	FriendsPerUser int
+	MatchesPerUser int
+	Password       string
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Seed:           1,
+		Users:          50,
+		FriendsPerUser: 5,
+		MatchesPerUser: 4,
+		Password:       DefaultPassword,
+	}
+}
+
+func (cfg Config) Validate() error {
+	var errs []error
+	if cfg.Users <= 0 {
+		errs = append(errs, fmt.Errorf("users must be positive, got %d", cfg.Users))
+	}
+	if cfg.FriendsPerUser < 0 || (cfg.Users > 0 && cfg.FriendsPerUser >= cfg.Users) {
+		errs = append(errs, fmt.Errorf("friends per user must be between 0 and %d, got %d", max(cfg.Users-1, 0), cfg.FriendsPerUser))
+	}
+	if cfg.MatchesPerUser < 0 {
+		errs = append(errs, fmt.Errorf("matches per user must not be negative, got %d", cfg.MatchesPerUser))
+	}
+	if len(cfg.Password) < domain.PasswordMinLength || len(cfg.Password) > domain.PasswordMaxLength {
+		errs = append(errs, domain.ErrInvalidPassword)
+	}
+	return errors.Join(errs...)
+}
+
+// Dataset is everything Generate produces. Passwords are left in plain text;
+// the Seeder hashes them before writing. Matches are in the order they
+// finished.
+type Dataset struct {
+	Users   []domain.User
+	Matches []domain.Match
+}
+
+// Generate builds the dataset for cfg. The first user is an admin and the
+// second a moderator so role-gated screens can be tried out.
+func Generate(cfg Config) Dataset {
+	rng := rand.New(rand.NewPCG(uint64(cfg.Seed), 0x4845415254))
+
+	users := make([]domain.User, cfg.Users)
+	taken := make(map[string]bool, cfg.Users)
+	for i := range users {
+		adjective, animal, username := randomName(rng, taken)
+		createdAt := epoch.Add(time.Duration(rng.IntN(180*24)) * time.Hour)
+
+		users[i] = domain.User{
+			ID:          objectID(rng, createdAt),
+			Username:    username,
+			DisplayName: capitalize(adjective) + " " + capitalize(animal),
+			Email:       username + "@example.com",
+			Password:    cfg.Password,
+			AvatarUrl:   fmt.Sprintf(AvatarURL, username),
+			Locale:      locales[rng.IntN(len(locales))],
+			FriendsList: []primitive.ObjectID{},
+			CreatedAt:   createdAt,
+			UpdatedAt:   createdAt,
+		}
+	}
+	if len(users) > 0 {
+		users[0].Roles = []domain.Role{domain.RoleAdmin}
+	}
+	if len(users) > 1 {
+		users[1].Roles = []domain.Role{domain.RoleModerator}
+	}
+
+	for i, friends := range friendGraph(rng, cfg.Users, cfg.FriendsPerUser) {
+		for _, j := range friends {
+			users[i].FriendsList = append(users[i].FriendsList, users[j].ID)
+		}
+	}
+
+	return Dataset{Users: users, Matches: generateMatches(cfg, users)}
+}
+
+// generateMatches has every user but the first host MatchesPerUser finished
+// matches against users generated before them. Each host draws from their
+// own stream, so growing Users keeps the existing matches.
+func generateMatches(cfg Config, users []domain.User) []domain.Match {
+	matches := []domain.Match{}
+	for host := 1; host < len(users); host++ {
+		rng := rand.New(rand.NewPCG(uint64(cfg.Seed), matchStream+uint64(host)))
+		for range cfg.MatchesPerUser {
+			matches = append(matches, playMatch(rng, users, host))
+		}
+	}
+	slices.SortFunc(matches, func(a, b domain.Match) int { return a.FinishedAt.Compare(*b.FinishedAt) })
+	return matches
+}
+
+// playMatch plays a lobby match of two to four players, users[host] and
+// users generated before them, with random legal moves.
+func playMatch(rng *rand.Rand, users []domain.User, host int) domain.Match {
+	opponents := min(host, 1+rng.IntN(3))
+	seated := []int{host}
+	for len(seated) < opponents+1 {
+		if i := rng.IntN(host); !slices.Contains(seated, i) {
+			seated = append(seated, i)
+		}
+	}
+	rng.Shuffle(len(seated), func(i, j int) { seated[i], seated[j] = seated[j], seated[i] })
+
+	createdAt := epoch
+	for _, i := range seated {
+		createdAt = maxTime(createdAt, users[i].CreatedAt)
+	}
+	createdAt = createdAt.Add(time.Duration(1+rng.IntN(30*24)) * time.Hour)
+	match := domain.Match{
+		ID:          objectID(rng, createdAt),
+		Seed:        rng.Int64(),
+		TurnSeconds: domain.DefaultTurnSeconds,
+		Status:      domain.MatchFinished,
+		CreatedAt:   createdAt,
+	}
+	for seat, i := range seated {
+		match.Players = append(match.Players, domain.MatchPlayer{
+			UserID:      users[i].ID,
+			Username:    users[i].Username,
+			DisplayName: users[i].DisplayName,
+			Seat:        seat,
+		})
+	}
+
+	// Two to four players always deal, and their moves always encode.
+	s, _, _ := game.New(match.GameConfig(), match.Seed)
+	var actions []game.Action
+	for !s.Over {
+		legal := game.LegalActions(s)
+		action := legal[rng.IntN(len(legal))]
+		_, _ = game.Apply(s, action)
+		actions = append(actions, action)
+	}
+	match.Actions, _ = replay.Encode(actions)
+	finishedAt := createdAt.Add(time.Duration(len(actions)) * moveTime)
+	match.FinishedAt = &finishedAt
+	match.Placements = s.Placements
+	for i := range match.Players {
+		match.Players[i].Placement = s.Placements[i]
+	}
+	return match
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+func randomName(rng *rand.Rand, taken map[string]bool) (string, string, string) {
+	for {
+		adjective := adjectives[rng.IntN(len(adjectives))]
+		animal := animals[rng.IntN(len(animals))]
+		username := fmt.Sprintf("%s_%s%d", adjective, animal, rng.IntN(100))
+		if !taken[username] {
+			taken[username] = true
+			return adjective, animal, username
+		}
+	}
+}
+
+// friendGraph returns a symmetric adjacency list where every user has at most
+// degree friends and most have exactly that many.
+func friendGraph(rng *rand.Rand, n, degree int) [][]int {
+	friends := make([][]int, n)
+	linked := make(map[[2]int]bool)
+	for i := 0; i < n; i++ {
+		for attempt := 0; len(friends[i]) < degree && attempt < 4*degree; attempt++ {
+			j := rng.IntN(n)
+			pair := [2]int{min(i, j), max(i, j)}
+			if j == i || linked[pair] || len(friends[j]) >= degree {
+				continue
+			}
+			linked[pair] = true
+			friends[i] = append(friends[i], j)
+			friends[j] = append(friends[j], i)
+		}
+	}
+	for _, f := range friends {
+		slices.Sort(f)
+	}
+	return friends
+}
+
+// objectID builds an ObjectID from the RNG, keeping the timestamp prefix
+// consistent with createdAt.
+func objectID(rng *rand.Rand, createdAt time.Time) primitive.ObjectID {
+	var id primitive.ObjectID
+	binary.BigEndian.PutUint32(id[0:4], uint32(createdAt.Unix()))
+	binary.BigEndian.PutUint64(id[4:12], rng.Uint64())
+	return id
+}
+
+func capitalize(s string) string {
+	return strings.ToUpper(s[:1]) + s[1:]
+}
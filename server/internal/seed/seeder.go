@@ -0,0 +1,225 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// nameAttempts bounds how many suffixes are tried for a generated username
+// that another account already uses.
+const nameAttempts = 10
+
+// Result reports what Run or Clean did. Matches counts the matches created
+// or deleted.
+type Result struct {
+	Seed     int64  `json:"seed"`
+	Created  int    `json:"created"`
+	Existing int    `json:"existing"`
+	Renamed  int    `json:"renamed"`
+	Skipped  int    `json:"skipped"`
+	Matches  int    `json:"matches"`
+	Deleted  int    `json:"deleted"`
+	Password string `json:"password,omitempty"`
+}
+
+// Seeder writes generated data through the repository interfaces, so it
+// works on MongoDB, the in-memory repositories and dry runs alike.
+type Seeder struct {
+	repos repository.Repositories
+}
+
+func NewSeeder(repos repository.Repositories) *Seeder {
+	return &Seeder{repos: repos}
+}
+
+// Run creates the dataset for cfg. Users and matches that already exist are
+// left untouched, so running it again with the same config is a no-op and a
+// larger Users count only adds the missing ones. A generated username that
+// another account already uses gets a numeric suffix; if none is free the
+// user is skipped, along with their matches.
+func (s *Seeder) Run(ctx context.Context, cfg Config) (Result, error) {
+	result := Result{Seed: cfg.Seed, Password: cfg.Password}
+	if err := cfg.Validate(); err != nil {
+		return result, err
+	}
+
+	// Every user shares the password, so hash it once.
+	hashed, err := bcrypt.GenerateFromPassword([]byte(cfg.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return result, err
+	}
+
+	dataset := Generate(cfg)
+	skipped := make(map[primitive.ObjectID]bool)
+	renamed := make(map[primitive.ObjectID]string)
+	for _, user := range dataset.Users {
+		if _, err := s.repos.User.GetByID(ctx, user.ID.Hex()); err == nil {
+			result.Existing++
+			continue
+		} else if !errors.Is(err, domain.ErrUserNotFound) {
+			return result, err
+		}
+
+		generated := user.Username
+		free, err := s.claimName(ctx, &user)
+		if err != nil {
+			return result, fmt.Errorf("user %q: %w", generated, err)
+		}
+		if !free {
+			skipped[user.ID] = true
+			result.Skipped++
+			continue
+		}
+		if user.Username != generated {
+			renamed[user.ID] = user.Username
+			result.Renamed++
+		}
+
+		user.Password = string(hashed)
+		if err := s.repos.User.Create(ctx, &user); err != nil {
+			return result, fmt.Errorf("user %q: %w", user.Username, err)
+		}
+		result.Created++
+	}
+
+	for _, match := range dataset.Matches {
+		if playsAny(&match, skipped) {
+			continue
+		}
+		if _, err := s.repos.Match.GetByID(ctx, match.ID.Hex()); err == nil {
+			continue
+		} else if !errors.Is(err, domain.ErrMatchNotFound) {
+			return result, err
+		}
+		for i, p := range match.Players {
+			if username, ok := renamed[p.UserID]; ok {
+				match.Players[i].Username = username
+			}
+		}
+
+		// Like a match played out, the result and the stats are saved
+		// together so it is never counted twice.
+		err := s.repos.Tx.WithTransaction(ctx, func(ctx context.Context) error {
+			if err := s.repos.Match.Create(ctx, &match); err != nil {
+				return err
+			}
+			return s.addStats(ctx, &match)
+		})
+		if err != nil {
+			return result, fmt.Errorf("match %s: %w", match.ID.Hex(), err)
+		}
+		result.Matches++
+	}
+	return result, nil
+}
+
+// claimName gives user a username and email no other account uses, trying
+// numeric suffixes after the generated name, and reports whether it found
+// one.
+func (s *Seeder) claimName(ctx context.Context, user *domain.User) (bool, error) {
+	base := user.Username
+	for n := 1; n <= nameAttempts; n++ {
+		if n > 1 {
+			user.Username = fmt.Sprintf("%s_%d", base, n)
+		}
+		user.Email = user.Username + "@example.com"
+
+		taken, err := s.taken(ctx, user)
+		if err != nil {
+			return false, err
+		}
+		if !taken {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// taken reports whether another account uses user's username or email.
+func (s *Seeder) taken(ctx context.Context, user *domain.User) (bool, error) {
+	_, err := s.repos.User.GetByUsername(ctx, user.Username)
+	if err == nil {
+		return true, nil
+	}
+	if !errors.Is(err, domain.ErrUserNotFound) {
+		return false, err
+	}
+	_, err = s.repos.User.GetByEmail(ctx, user.Email)
+	if err == nil {
+		return true, nil
+	}
+	if !errors.Is(err, domain.ErrUserNotFound) {
+		return false, err
+	}
+	return false, nil
+}
+
+// addStats counts match in its players' stats.
+func (s *Seeder) addStats(ctx context.Context, match *domain.Match) error {
+	for _, p := range match.Players {
+		stats, err := s.repos.Stats.Get(ctx, p.UserID)
+		if err != nil {
+			return err
+		}
+		stats.Add(match.Mode, domain.ResultOf(p.Placement), *match.FinishedAt)
+		if err := s.repos.Stats.Save(ctx, stats); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func playsAny(match *domain.Match, users map[primitive.ObjectID]bool) bool {
+	for _, p := range match.Players {
+		if users[p.UserID] {
+			return true
+		}
+	}
+	return false
+}
+
+// Clean deletes the users and matches Run would create for cfg, with the
+// users' sessions and stats. Only Seed, Users and MatchesPerUser matter; use
+// the values the data was seeded with.
+func (s *Seeder) Clean(ctx context.Context, cfg Config) (Result, error) {
+	result := Result{Seed: cfg.Seed}
+	if cfg.Users <= 0 {
+		return result, fmt.Errorf("users must be positive, got %d", cfg.Users)
+	}
+
+	dataset := Generate(cfg)
+	for _, match := range dataset.Matches {
+		err := s.repos.Match.Delete(ctx, match.ID)
+		if errors.Is(err, domain.ErrMatchNotFound) {
+			continue
+		}
+		if err != nil {
+			return result, fmt.Errorf("match %s: %w", match.ID.Hex(), err)
+		}
+		result.Matches++
+	}
+
+	for _, user := range dataset.Users {
+		if _, err := s.repos.Session.DeleteByUser(ctx, user.ID); err != nil {
+			return result, err
+		}
+		if err := s.repos.Stats.Delete(ctx, user.ID); err != nil {
+			return result, err
+		}
+		err := s.repos.User.Delete(ctx, user.ID)
+		if errors.Is(err, domain.ErrUserNotFound) {
+			continue
+		}
+		if err != nil {
+			return result, fmt.Errorf("user %q: %w", user.Username, err)
+		}
+		result.Deleted++
+	}
+	return result, nil
+}
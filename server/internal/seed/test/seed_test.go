@@ -0,0 +1,239 @@
+package seed_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/repository"
+	"github.com/Simpolette/HeartSteal/server/internal/repository/memory"
+	"github.com/Simpolette/HeartSteal/server/internal/seed"
+	"github.com/Simpolette/HeartSteal/server/internal/validation"
+)
+
+func memoryRepos() repository.Repositories {
+	return repository.Repositories{
+		User:    memory.NewUserRepository(),
+		Session: memory.NewSessionRepository(),
+		Match:   memory.NewMatchRepository(),
+		Stats:   memory.NewStatsRepository(),
+		Tx:      memory.NewTransactor(),
+	}
+}
+
+func TestSeed_Generate(t *testing.T) {
+	cfg := seed.DefaultConfig()
+
+	t.Run("Deterministic", func(t *testing.T) {
+		assert.Equal(t, seed.Generate(cfg), seed.Generate(cfg))
+
+		other := cfg
+		other.Seed = 2
+		assert.NotEqual(t, seed.Generate(cfg).Users[0].ID, seed.Generate(other).Users[0].ID)
+	})
+
+	t.Run("GrowingKeepsExistingUsers", func(t *testing.T) {
+		more := cfg
+		more.Users = cfg.Users * 2
+		small, large := seed.Generate(cfg).Users, seed.Generate(more).Users
+		for i := range small {
+			assert.Equal(t, small[i].ID, large[i].ID)
+			assert.Equal(t, small[i].Username, large[i].Username)
+		}
+	})
+
+	t.Run("ValidUsers", func(t *testing.T) {
+		users := seed.Generate(cfg).Users
+		usernames := map[string]bool{}
+		for _, u := range users {
+			assert.False(t, usernames[u.Username], "duplicate username %s", u.Username)
+			usernames[u.Username] = true
+			assert.GreaterOrEqual(t, len(u.Username), validation.UsernameMinLength)
+			assert.LessOrEqual(t, len(u.Username), validation.UsernameMaxLength)
+			assert.False(t, validation.IsReservedUsername(u.Username))
+			assert.NotEmpty(t, u.AvatarUrl)
+			assert.Equal(t, u.CreatedAt.Unix(), u.ID.Timestamp().Unix())
+		}
+		assert.True(t, users[0].HasRole(domain.RoleAdmin))
+		assert.True(t, users[1].HasRole(domain.RoleModerator))
+	})
+
+	t.Run("GrowingKeepsExistingMatches", func(t *testing.T) {
+		more := cfg
+		more.Users = cfg.Users * 2
+		large := seed.Generate(more).Matches
+		for _, m := range seed.Generate(cfg).Matches {
+			assert.True(t, slices.ContainsFunc(large, func(l domain.Match) bool { return l.ID == m.ID }), "match %s is gone", m.ID.Hex())
+		}
+	})
+
+	t.Run("FinishedMatches", func(t *testing.T) {
+		dataset := seed.Generate(cfg)
+		created := map[string]domain.User{}
+		for _, u := range dataset.Users {
+			created[u.ID.Hex()] = u
+		}
+		require.Len(t, dataset.Matches, (cfg.Users-1)*cfg.MatchesPerUser)
+		for i, m := range dataset.Matches {
+			assert.Equal(t, domain.MatchFinished, m.Status)
+			assert.Equal(t, m.CreatedAt.Unix(), m.ID.Timestamp().Unix())
+			if i > 0 {
+				assert.False(t, m.FinishedAt.Before(*dataset.Matches[i-1].FinishedAt), "matches are in the order they finished")
+			}
+			for _, p := range m.Players {
+				assert.True(t, created[p.UserID.Hex()].CreatedAt.Before(m.CreatedAt), "%s plays before signing up", p.Username)
+				assert.Equal(t, m.Placements[p.Seat], p.Placement)
+			}
+
+			r, err := m.Replay()
+			require.NoError(t, err)
+			final, err := r.Final()
+			require.NoError(t, err)
+			assert.True(t, final.Over)
+			assert.Equal(t, m.Placements, final.Placements)
+		}
+	})
+
+	t.Run("SymmetricFriendGraph", func(t *testing.T) {
+		users := seed.Generate(cfg).Users
+		byID := map[string]domain.User{}
+		for _, u := range users {
+			byID[u.ID.Hex()] = u
+		}
+		for _, u := range users {
+			assert.LessOrEqual(t, len(u.FriendsList), cfg.FriendsPerUser)
+			for _, friendID := range u.FriendsList {
+				assert.NotEqual(t, u.ID, friendID)
+				friend := byID[friendID.Hex()]
+				assert.True(t, slices.Contains(friend.FriendsList, u.ID), "%s -> %s is one-way", u.Username, friend.Username)
+			}
+		}
+	})
+}
+
+func TestSeed_Validate(t *testing.T) {
+	assert.NoError(t, seed.DefaultConfig().Validate())
+	assert.Error(t, seed.Config{Users: 0, Password: seed.DefaultPassword}.Validate())
+	assert.Error(t, seed.Config{Users: 3, FriendsPerUser: 3, Password: seed.DefaultPassword}.Validate())
+	assert.Error(t, seed.Config{Users: 3, Password: "short"}.Validate())
+	assert.Error(t, seed.Config{Users: 3, MatchesPerUser: -1, Password: seed.DefaultPassword}.Validate())
+}
+
+func TestSeeder_Run(t *testing.T) {
+	ctx := context.Background()
+	cfg := seed.Config{Seed: 7, Users: 5, FriendsPerUser: 2, MatchesPerUser: 2, Password: seed.DefaultPassword}
+	dataset := seed.Generate(cfg)
+
+	t.Run("Success", func(t *testing.T) {
+		repos := memoryRepos()
+		seeder := seed.NewSeeder(repos)
+
+		result, err := seeder.Run(ctx, cfg)
+
+		require.NoError(t, err)
+		assert.Equal(t, 5, result.Created)
+		assert.Equal(t, 8, result.Matches)
+		first := dataset.Users[0]
+		stored, err := repos.User.GetByUsername(ctx, first.Username)
+		require.NoError(t, err)
+		assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(stored.Password), []byte(seed.DefaultPassword)))
+		assert.Equal(t, first.FriendsList, stored.FriendsList)
+		games := 0
+		for _, u := range dataset.Users {
+			stats, err := repos.Stats.Get(ctx, u.ID)
+			require.NoError(t, err)
+			assert.Equal(t, stats.Games, stats.Wins+stats.Losses)
+			games += stats.Games
+		}
+		players := 0
+		for _, m := range dataset.Matches {
+			_, err := repos.Match.GetByID(ctx, m.ID.Hex())
+			assert.NoError(t, err)
+			players += len(m.Players)
+		}
+		assert.Equal(t, players, games, "every seat is counted once")
+
+		again, err := seeder.Run(ctx, cfg)
+		require.NoError(t, err)
+		assert.Equal(t, seed.Result{Seed: cfg.Seed, Existing: 5, Password: cfg.Password}, again, "rerunning is a no-op")
+	})
+
+	t.Run("UsernameTaken", func(t *testing.T) {
+		repos := memoryRepos()
+		taken := dataset.Users[2]
+		require.NoError(t, repos.User.Create(ctx, &domain.User{Username: taken.Username, Email: "someone@example.com"}))
+
+		result, err := seed.NewSeeder(repos).Run(ctx, cfg)
+
+		require.NoError(t, err)
+		assert.Equal(t, 5, result.Created)
+		assert.Equal(t, 1, result.Renamed)
+		stored, err := repos.User.GetByID(ctx, taken.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, taken.Username+"_2", stored.Username)
+		assert.Equal(t, taken.Username+"_2@example.com", stored.Email)
+		for _, m := range dataset.Matches {
+			if seat := m.Seat(taken.ID); seat >= 0 {
+				played, err := repos.Match.GetByID(ctx, m.ID.Hex())
+				require.NoError(t, err)
+				assert.Equal(t, stored.Username, played.Players[seat].Username)
+			}
+		}
+	})
+
+	t.Run("NoFreeUsername", func(t *testing.T) {
+		repos := memoryRepos()
+		taken := dataset.Users[2]
+		require.NoError(t, repos.User.Create(ctx, &domain.User{Username: taken.Username, Email: "someone@example.com"}))
+		for n := 2; n <= 10; n++ {
+			username := fmt.Sprintf("%s_%d", taken.Username, n)
+			require.NoError(t, repos.User.Create(ctx, &domain.User{Username: username, Email: username + "@elsewhere.com"}))
+		}
+
+		result, err := seed.NewSeeder(repos).Run(ctx, cfg)
+
+		require.NoError(t, err)
+		assert.Equal(t, 4, result.Created)
+		assert.Equal(t, 1, result.Skipped)
+		for _, m := range dataset.Matches {
+			_, err := repos.Match.GetByID(ctx, m.ID.Hex())
+			if m.Seat(taken.ID) >= 0 {
+				assert.ErrorIs(t, err, domain.ErrMatchNotFound, "matches of a skipped user are skipped")
+			} else {
+				assert.NoError(t, err)
+			}
+		}
+	})
+}
+
+func TestSeeder_Clean(t *testing.T) {
+	repos := memoryRepos()
+	seeder := seed.NewSeeder(repos)
+	cfg := seed.Config{Seed: 7, Users: 5, FriendsPerUser: 2, MatchesPerUser: 2, Password: seed.DefaultPassword}
+	_, err := seeder.Run(context.Background(), cfg)
+	require.NoError(t, err)
+
+	result, err := seeder.Clean(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, 5, result.Deleted)
+	assert.Equal(t, 8, result.Matches)
+	dataset := seed.Generate(cfg)
+	for _, u := range dataset.Users {
+		_, err := repos.User.GetByID(context.Background(), u.ID.Hex())
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+		stats, err := repos.Stats.Get(context.Background(), u.ID)
+		require.NoError(t, err)
+		assert.Zero(t, stats.Games)
+	}
+	for _, m := range dataset.Matches {
+		_, err := repos.Match.GetByID(context.Background(), m.ID.Hex())
+		assert.ErrorIs(t, err, domain.ErrMatchNotFound)
+	}
+}
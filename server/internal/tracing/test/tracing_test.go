@@ -0,0 +1,68 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Simpolette/HeartSteal/server/internal/tracing"
+)
+
+// startSpan starts a span on a provider that records the spans it ends.
+func startSpan(t *testing.T) (trace.Span, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	_, span := provider.Tracer("test").Start(context.Background(), "userUseCase.Login")
+	return span, recorder
+}
+
+func TestEnd(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		span, recorder := startSpan(t)
+
+		tracing.End(span, nil)
+
+		ended := recorder.Ended()
+		require.Len(t, ended, 1)
+		assert.Equal(t, "userUseCase.Login", ended[0].Name())
+		assert.Equal(t, codes.Unset, ended[0].Status().Code)
+		assert.Empty(t, ended[0].Events())
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		span, recorder := startSpan(t)
+		err := errors.New("user not found")
+
+		tracing.End(span, err)
+
+		ended := recorder.Ended()
+		require.Len(t, ended, 1)
+		assert.Equal(t, codes.Error, ended[0].Status().Code)
+		assert.Equal(t, "user not found", ended[0].Status().Description)
+		require.Len(t, ended[0].Events(), 1)
+		assert.Equal(t, "exception", ended[0].Events()[0].Name)
+	})
+
+	t.Run("NamedReturn", func(t *testing.T) {
+		span, recorder := startSpan(t)
+		fail := func() (err error) {
+			defer func() { tracing.End(span, err) }()
+			return errors.New("timeout")
+		}
+
+		_ = fail()
+
+		ended := recorder.Ended()
+		require.Len(t, ended, 1)
+		assert.Equal(t, codes.Error, ended[0].Status().Code, "the deferred call sees the returned error")
+	})
+}
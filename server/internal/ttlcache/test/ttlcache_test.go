@@ -0,0 +1,85 @@
+package ttlcache_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Simpolette/HeartSteal/server/internal/ttlcache"
+)
+
+// counter returns a loader yielding 1, 2, 3... and how often it was called.
+func counter() (func() (int, error), *int) {
+	calls := 0
+	return func() (int, error) {
+		calls++
+		return calls, nil
+	}, &calls
+}
+
+func TestCache_Get(t *testing.T) {
+	t.Run("CachesUntilExpiry", func(t *testing.T) {
+		c := ttlcache.New[string, int](50*time.Millisecond, 0)
+		load, calls := counter()
+
+		first, err := c.Get("alice", load)
+		require.NoError(t, err)
+		second, _ := c.Get("alice", load)
+
+		assert.Equal(t, 1, first)
+		assert.Equal(t, 1, second)
+		assert.Equal(t, 1, *calls)
+		assert.Eventually(t, func() bool {
+			v, _ := c.Get("alice", load)
+			return v == 2
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		c := ttlcache.New[string, int](0, 0)
+		load, calls := counter()
+
+		_, _ = c.Get("alice", load)
+		_, _ = c.Get("alice", load)
+
+		assert.Equal(t, 2, *calls)
+		assert.Zero(t, c.Len())
+	})
+
+	t.Run("ErrorsAreNotCached", func(t *testing.T) {
+		c := ttlcache.New[string, int](time.Hour, 0)
+		errDown := errors.New("down")
+
+		_, err := c.Get("alice", func() (int, error) { return 0, errDown })
+		v, _ := c.Get("alice", func() (int, error) { return 7, nil })
+
+		assert.ErrorIs(t, err, errDown)
+		assert.Equal(t, 7, v)
+	})
+
+	t.Run("Bounded", func(t *testing.T) {
+		c := ttlcache.New[string, int](time.Hour, 3)
+
+		for i := range 10 {
+			_, _ = c.Get(strconv.Itoa(i), func() (int, error) { return i, nil })
+		}
+
+		assert.Equal(t, 3, c.Len())
+	})
+}
+
+func TestCache_Delete(t *testing.T) {
+	c := ttlcache.New[string, int](time.Hour, 0)
+	load, calls := counter()
+	_, _ = c.Get("alice", load)
+
+	c.Delete("alice")
+	v, _ := c.Get("alice", load)
+
+	assert.Equal(t, 2, v)
+	assert.Equal(t, 2, *calls)
+}
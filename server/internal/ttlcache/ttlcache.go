@@ -0,0 +1,101 @@
+// Package ttlcache is a small in-process cache whose entries expire a fixed
+// time after they were loaded. It keeps per-user values read on every request
+// from turning into a database query per request.
+package ttlcache
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultMaxEntries bounds a cache built with a non-positive size.
+const DefaultMaxEntries = 10000
+
+type entry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+// Cache maps keys to values loaded on demand. Safe for concurrent use; two
+// concurrent misses on one key may both load it.
+type Cache[K comparable, V any] struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[K]entry[V]
+	now        func() time.Time
+}
+
+// New returns a cache keeping values for ttl and at most maxEntries of them.
+// A non-positive ttl disables caching: every Get loads.
+func New[K comparable, V any](ttl time.Duration, maxEntries int) *Cache[K, V] {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+	return &Cache[K, V]{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[K]entry[V]),
+		now:        time.Now,
+	}
+}
+
+// Get returns the value cached for key, calling load when there is none or it
+// has expired. Errors from load are returned and not cached.
+func (c *Cache[K, V]) Get(key K, load func() (V, error)) (V, error) {
+	if c.ttl <= 0 {
+		return load()
+	}
+
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && c.now().Before(e.expires) {
+		return e.value, nil
+	}
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		c.evict()
+	}
+	c.entries[key] = entry[V]{value: value, expires: c.now().Add(c.ttl)}
+	return value, nil
+}
+
+// Delete drops key so the next Get loads it again.
+func (c *Cache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// Len returns the number of cached entries, expired or not.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// evict makes room for one entry: expired entries go first, then an
+// arbitrary one. Callers hold mu.
+func (c *Cache[K, V]) evict() {
+	now := c.now()
+	for key, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, key)
+		}
+	}
+	if len(c.entries) < c.maxEntries {
+		return
+	}
+	for key := range c.entries {
+		delete(c.entries, key)
+		return
+	}
+}
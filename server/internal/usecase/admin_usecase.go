@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/tracing"
+)
+
+var _ domain.AdminUsecase = &adminUseCase{}
+
+type adminUseCase struct {
+	userRepo       domain.UserRepository
+	sessionRepo    domain.SessionRepository
+	contextTimeout time.Duration
+	now            func() time.Time
+}
+
+func NewAdminUseCase(userRepo domain.UserRepository, sessionRepo domain.SessionRepository, timeout time.Duration) domain.AdminUsecase {
+	return &adminUseCase{
+		userRepo:       userRepo,
+		sessionRepo:    sessionRepo,
+		contextTimeout: timeout,
+		now:            func() time.Time { return time.Now().UTC() },
+	}
+}
+
+func (u *adminUseCase) CreateUser(c context.Context, user *domain.User) (err error) {
+	ctx, span := tracer.Start(c, "adminUseCase.CreateUser")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	if err := validatePassword(user.Password); err != nil {
+		return err
+	}
+	for _, role := range user.Roles {
+		if _, err := domain.ParseRole(string(role)); err != nil {
+			return err
+		}
+	}
+
+	if _, err := u.userRepo.GetByEmail(ctx, user.Email); err == nil {
+		return domain.ErrEmailExists
+	}
+	if _, err := u.userRepo.GetByUsername(ctx, user.Username); err == nil {
+		return domain.ErrUsernameExists
+	}
+
+	if user.DisplayName == "" {
+		user.DisplayName = user.Username
+	}
+	now := u.now()
+	user.CreatedAt, user.UpdatedAt = now, now
+
+	user.Password, err = hashPassword(ctx, user.Password)
+	if err != nil {
+		return err
+	}
+
+	return u.userRepo.Create(ctx, user)
+}
+
+func (u *adminUseCase) ResetPassword(c context.Context, username string, password string) (_ *domain.User, err error) {
+	ctx, span := tracer.Start(c, "adminUseCase.ResetPassword")
+	defer func() { tracing.End(span, err) }()
+
+	if err := validatePassword(password); err != nil {
+		return nil, err
+	}
+
+	return u.update(ctx, username, func(ctx context.Context, user *domain.User) (bool, error) {
+		hashed, err := hashPassword(ctx, password)
+		if err != nil {
+			return false, err
+		}
+		user.Password = hashed
+		user.RevokeTokens()
+		return true, nil
+	}, u.endSessions)
+}
+
+func (u *adminUseCase) GrantRole(c context.Context, username string, role domain.Role) (_ *domain.User, err error) {
+	ctx, span := tracer.Start(c, "adminUseCase.GrantRole")
+	defer func() { tracing.End(span, err) }()
+
+	if _, err := domain.ParseRole(string(role)); err != nil {
+		return nil, err
+	}
+
+	return u.update(ctx, username, func(_ context.Context, user *domain.User) (bool, error) {
+		if user.HasRole(role) {
+			return false, nil
+		}
+		user.Roles = append(user.Roles, role)
+		return true, nil
+	}, nil)
+}
+
+func (u *adminUseCase) RevokeRole(c context.Context, username string, role domain.Role) (_ *domain.User, err error) {
+	ctx, span := tracer.Start(c, "adminUseCase.RevokeRole")
+	defer func() { tracing.End(span, err) }()
+
+	if _, err := domain.ParseRole(string(role)); err != nil || role == domain.RolePlayer {
+		return nil, domain.ErrInvalidRole
+	}
+
+	return u.update(ctx, username, func(_ context.Context, user *domain.User) (bool, error) {
+		i := slices.Index(user.Roles, role)
+		if i < 0 {
+			return false, nil
+		}
+		user.Roles = slices.Delete(user.Roles, i, i+1)
+		return true, nil
+	}, nil)
+}
+
+func (u *adminUseCase) Ban(c context.Context, username string, reason string) (_ *domain.User, err error) {
+	ctx, span := tracer.Start(c, "adminUseCase.Ban")
+	defer func() { tracing.End(span, err) }()
+
+	return u.update(ctx, username, func(_ context.Context, user *domain.User) (bool, error) {
+		if !user.IsBanned() {
+			now := u.now()
+			user.BannedAt = &now
+		}
+		user.BanReason = reason
+		user.RevokeTokens()
+		return true, nil
+	}, u.endSessions)
+}
+
+func (u *adminUseCase) Unban(c context.Context, username string) (_ *domain.User, err error) {
+	ctx, span := tracer.Start(c, "adminUseCase.Unban")
+	defer func() { tracing.End(span, err) }()
+
+	return u.update(ctx, username, func(_ context.Context, user *domain.User) (bool, error) {
+		if !user.IsBanned() {
+			return false, nil
+		}
+		user.BannedAt = nil
+		user.BanReason = ""
+		return true, nil
+	}, nil)
+}
+
+func (u *adminUseCase) ListSessions(c context.Context, username string) (_ []domain.Session, err error) {
+	ctx, span := tracer.Start(c, "adminUseCase.ListSessions")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	user, err := u.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.sessionRepo.ListActiveByUser(ctx, user.ID, u.now())
+}
+
+// update loads username, applies change and saves the user if change reports
+// a modification, so repeated grants or unbans are no-ops. saved, if set,
+// runs once the change is stored; a failed save leaves everything as it was.
+func (u *adminUseCase) update(c context.Context, username string, change func(context.Context, *domain.User) (bool, error), saved func(context.Context, *domain.User) error) (*domain.User, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	user, err := u.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	changed, err := change(ctx, user)
+	if err != nil || !changed {
+		return user, err
+	}
+
+	user.UpdatedAt = u.now()
+	if err := u.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	if saved != nil {
+		if err := saved(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+	return user, nil
+}
+
+func (u *adminUseCase) endSessions(ctx context.Context, user *domain.User) error {
+	_, err := u.sessionRepo.DeleteByUser(ctx, user.ID)
+	return err
+}
+
+func validatePassword(password string) error {
+	if len(password) < domain.PasswordMinLength || len(password) > domain.PasswordMaxLength {
+		return domain.ErrInvalidPassword
+	}
+	return nil
+}
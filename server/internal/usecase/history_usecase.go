@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/tracing"
+)
+
+var _ domain.HistoryUsecase = &historyUseCase{}
+
+type historyUseCase struct {
+	matchRepo      domain.MatchRepository
+	statsRepo      domain.StatsRepository
+	userRepo       domain.UserRepository
+	contextTimeout time.Duration
+}
+
+func NewHistoryUseCase(matchRepo domain.MatchRepository, statsRepo domain.StatsRepository, userRepo domain.UserRepository, timeout time.Duration) domain.HistoryUsecase {
+	return &historyUseCase{
+		matchRepo:      matchRepo,
+		statsRepo:      statsRepo,
+		userRepo:       userRepo,
+		contextTimeout: timeout,
+	}
+}
+
+// Record runs on the caller's context and deadline, since it is part of
+// their transaction.
+func (u *historyUseCase) Record(c context.Context, match *domain.Match) (err error) {
+	ctx, span := tracer.Start(c, "historyUseCase.Record")
+	defer func() { tracing.End(span, err) }()
+
+	if match.Status != domain.MatchFinished || match.FinishedAt == nil {
+		return nil
+	}
+	for _, p := range match.Players {
+		// Bots have no statistics to keep.
+		if p.Placement == 0 || p.Bot != "" {
+			continue
+		}
+		stats, err := u.statsRepo.Get(ctx, p.UserID)
+		if err != nil {
+			return err
+		}
+		stats.Add(match.Mode, domain.ResultOf(p.Placement), *match.FinishedAt)
+		if err := u.statsRepo.Save(ctx, stats); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *historyUseCase) Matches(c context.Context, username string, filter domain.MatchFilter, limit int, cursor string) (_ []domain.HistoryEntry, _ string, err error) {
+	ctx, span := tracer.Start(c, "historyUseCase.Matches")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	if filter.Mode != "" && filter.Mode.Players() == 0 {
+		return nil, "", domain.ErrUnknownGameMode
+	}
+	after, err := domain.DecodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	user, err := u.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, "", err
+	}
+	limit = domain.PageLimit(limit)
+
+	// Fetch one extra match to learn whether there is a next page.
+	matches, err := u.matchRepo.ListFinishedByPlayer(ctx, user.ID, filter, limit+1, after)
+	if err != nil {
+		return nil, "", err
+	}
+	next := ""
+	if len(matches) > limit {
+		matches = matches[:limit]
+		last := matches[limit-1]
+		next = domain.Cursor{Time: *last.FinishedAt, ID: last.ID}.Encode()
+	}
+	entries := make([]domain.HistoryEntry, len(matches))
+	for i, m := range matches {
+		entries[i] = domain.HistoryEntry{Match: m, Self: *m.Player(user.ID)}
+	}
+	return entries, next, nil
+}
+
+func (u *historyUseCase) Stats(c context.Context, username string) (_ *domain.PlayerStats, err error) {
+	ctx, span := tracer.Start(c, "historyUseCase.Stats")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	user, err := u.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	return u.statsRepo.Get(ctx, user.ID)
+}
@@ -0,0 +1,496 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/bot"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/matchmaking"
+	"github.com/Simpolette/HeartSteal/server/internal/tracing"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var _ domain.LobbyUsecase = &lobbyUseCase{}
+
+// inviteAlphabet leaves out characters that are easy to misread (0/O, 1/I/L).
+const inviteAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+// lobbyAttempts bounds retries after a version conflict or an invite code
+// collision; both are rare, so a few attempts are plenty.
+const lobbyAttempts = 3
+
+type lobbyUseCase struct {
+	lobbyRepo      domain.LobbyRepository
+	userRepo       domain.UserRepository
+	matchRepo      domain.MatchRepository
+	matches        domain.MatchUsecase
+	queue          matchmaking.Queue
+	publisher      domain.Publisher
+	contextTimeout time.Duration
+	now            func() time.Time
+}
+
+// NewLobbyUseCase builds the lobby usecase. queue is the matchmaking queue,
+// whose players can't create or join a lobby, and neither can the players
+// of an active match in matchRepo.
+func NewLobbyUseCase(lobbyRepo domain.LobbyRepository, userRepo domain.UserRepository, matchRepo domain.MatchRepository, matches domain.MatchUsecase, queue matchmaking.Queue, publisher domain.Publisher, timeout time.Duration) domain.LobbyUsecase {
+	return &lobbyUseCase{
+		lobbyRepo:      lobbyRepo,
+		userRepo:       userRepo,
+		matchRepo:      matchRepo,
+		matches:        matches,
+		queue:          queue,
+		publisher:      publisher,
+		contextTimeout: timeout,
+		now:            func() time.Time { return time.Now().UTC() },
+	}
+}
+
+func (u *lobbyUseCase) Create(c context.Context, userID string, lobby *domain.Lobby) (err error) {
+	ctx, span := tracer.Start(c, "lobbyUseCase.Create")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := u.ensureNotInLobby(ctx, user.ID, primitive.NilObjectID); err != nil {
+		return err
+	}
+	if err := u.ensureNotQueued(ctx, userID); err != nil {
+		return err
+	}
+	if err := u.ensureNotPlaying(ctx, user.ID); err != nil {
+		return err
+	}
+
+	if lobby.Visibility == "" {
+		lobby.Visibility = domain.LobbyPublic
+	}
+	if lobby.Settings.TurnSeconds == 0 {
+		lobby.Settings.TurnSeconds = domain.DefaultTurnSeconds
+	}
+	now := u.now()
+	lobby.Status = domain.LobbyOpen
+	lobby.HostID = user.ID
+	lobby.Members = []domain.LobbyMember{newMember(user, now)}
+	lobby.Version = 0
+	lobby.CreatedAt, lobby.UpdatedAt = now, now
+
+	for attempt := 0; attempt < lobbyAttempts; attempt++ {
+		lobby.InviteCode = newInviteCode()
+		if err = u.lobbyRepo.Create(ctx, lobby); !errors.Is(err, domain.ErrInviteCodeTaken) {
+			break
+		}
+	}
+	return err
+}
+
+func (u *lobbyUseCase) Get(c context.Context, userID string, lobbyID string) (_ *domain.Lobby, err error) {
+	ctx, span := tracer.Start(c, "lobbyUseCase.Get")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	uid, _ := primitive.ObjectIDFromHex(userID)
+	lobby, err := u.lobbyRepo.GetByID(ctx, lobbyID)
+	if err != nil {
+		return nil, err
+	}
+	if lobby.Visibility == domain.LobbyPrivate && !lobby.IsMember(uid) {
+		return nil, domain.ErrLobbyNotFound
+	}
+	return lobby, nil
+}
+
+func (u *lobbyUseCase) Current(c context.Context, userID string) (_ *domain.Lobby, err error) {
+	ctx, span := tracer.Start(c, "lobbyUseCase.Current")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	uid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, domain.ErrLobbyNotFound
+	}
+	return u.lobbyRepo.GetByMember(ctx, uid)
+}
+
+func (u *lobbyUseCase) List(c context.Context, limit int, cursor string) (_ []domain.Lobby, _ string, err error) {
+	ctx, span := tracer.Start(c, "lobbyUseCase.List")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	after, err := domain.DecodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	limit = domain.PageLimit(limit)
+
+	// Fetch one extra lobby to learn whether there is a next page.
+	lobbies, err := u.lobbyRepo.ListOpenPublic(ctx, limit+1, after)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(lobbies) <= limit {
+		return lobbies, "", nil
+	}
+	lobbies = lobbies[:limit]
+	last := lobbies[limit-1]
+	return lobbies, domain.Cursor{Time: last.CreatedAt, ID: last.ID}.Encode(), nil
+}
+
+func (u *lobbyUseCase) Join(c context.Context, userID string, lobbyID string) (_ *domain.Lobby, err error) {
+	ctx, span := tracer.Start(c, "lobbyUseCase.Join")
+	defer func() { tracing.End(span, err) }()
+
+	return u.join(ctx, userID, func(ctx context.Context) (*domain.Lobby, error) {
+		lobby, err := u.lobbyRepo.GetByID(ctx, lobbyID)
+		if err == nil && lobby.Visibility == domain.LobbyPrivate {
+			return nil, domain.ErrLobbyNotFound
+		}
+		return lobby, err
+	})
+}
+
+func (u *lobbyUseCase) JoinByCode(c context.Context, userID string, code string) (_ *domain.Lobby, err error) {
+	ctx, span := tracer.Start(c, "lobbyUseCase.JoinByCode")
+	defer func() { tracing.End(span, err) }()
+
+	code = strings.ToUpper(strings.TrimSpace(code))
+	return u.join(ctx, userID, func(ctx context.Context) (*domain.Lobby, error) {
+		return u.lobbyRepo.GetByInviteCode(ctx, code)
+	})
+}
+
+func (u *lobbyUseCase) Leave(c context.Context, userID string, lobbyID string) (_ *domain.Lobby, err error) {
+	ctx, span := tracer.Start(c, "lobbyUseCase.Leave")
+	defer func() { tracing.End(span, err) }()
+
+	uid, _ := primitive.ObjectIDFromHex(userID)
+	lobby, err := u.update(ctx, lobbyID, func(lobby *domain.Lobby) (bool, error) {
+		// Its players stay seated until the match closes the lobby.
+		if lobby.Status == domain.LobbyInGame {
+			return false, domain.ErrLobbyInGame
+		}
+		return true, removeMember(lobby, uid)
+	})
+	if err != nil {
+		return nil, err
+	}
+	u.publisher.Unsubscribe(domain.LobbyTopic(lobbyID), userID)
+	return lobby, nil
+}
+
+func (u *lobbyUseCase) Kick(c context.Context, userID string, lobbyID string, memberID string) (_ *domain.Lobby, err error) {
+	ctx, span := tracer.Start(c, "lobbyUseCase.Kick")
+	defer func() { tracing.End(span, err) }()
+
+	uid, _ := primitive.ObjectIDFromHex(userID)
+	mid, err := primitive.ObjectIDFromHex(memberID)
+	if err != nil {
+		return nil, domain.ErrNotInLobby
+	}
+
+	kickedBot := false
+	lobby, err := u.update(ctx, lobbyID, func(lobby *domain.Lobby) (bool, error) {
+		if lobby.HostID != uid {
+			return false, domain.ErrNotLobbyHost
+		}
+		if mid == uid {
+			return false, domain.ErrCannotKickSelf
+		}
+		if lobby.Status == domain.LobbyInGame {
+			return false, domain.ErrLobbyInGame
+		}
+		if i := lobby.MemberIndex(mid); i >= 0 {
+			kickedBot = lobby.Members[i].Bot != ""
+		}
+		return true, removeMember(lobby, mid)
+	})
+	if err != nil || kickedBot {
+		return lobby, err
+	}
+	u.publisher.Unsubscribe(domain.LobbyTopic(lobbyID), memberID)
+	u.publisher.Publish(domain.UserTopic(memberID), domain.EventLobbyKicked, domain.LobbyEvent{LobbyID: lobbyID})
+	return lobby, nil
+}
+
+func (u *lobbyUseCase) AddBot(c context.Context, userID string, lobbyID string, level bot.Level) (_ *domain.Lobby, err error) {
+	ctx, span := tracer.Start(c, "lobbyUseCase.AddBot")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	if !level.Valid() {
+		return nil, bot.ErrUnknownLevel
+	}
+	uid, _ := primitive.ObjectIDFromHex(userID)
+	filled := false
+	lobby, err := u.update(ctx, lobbyID, func(lobby *domain.Lobby) (bool, error) {
+		filled = false
+		if lobby.HostID != uid {
+			return false, domain.ErrNotLobbyHost
+		}
+		if lobby.Status == domain.LobbyInGame {
+			return false, domain.ErrLobbyInGame
+		}
+		if lobby.IsFull() {
+			return false, domain.ErrLobbyFull
+		}
+		lobby.Members = append(lobby.Members, newBotMember(lobby, level, u.now()))
+		filled = claimMatch(lobby)
+		return true, nil
+	})
+	if err != nil || !filled {
+		return lobby, err
+	}
+	return u.startMatch(ctx, lobby)
+}
+
+func (u *lobbyUseCase) join(c context.Context, userID string, load func(context.Context) (*domain.Lobby, error)) (*domain.Lobby, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.ensureNotQueued(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := u.ensureNotPlaying(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	filled := false
+	lobby, err := u.retry(ctx, load, func(lobby *domain.Lobby) (bool, error) {
+		filled = false
+		if lobby.IsMember(user.ID) {
+			return false, nil
+		}
+		if err := u.ensureNotInLobby(ctx, user.ID, lobby.ID); err != nil {
+			return false, err
+		}
+		if lobby.Status == domain.LobbyInGame {
+			return false, domain.ErrLobbyInGame
+		}
+		if lobby.IsFull() {
+			return false, domain.ErrLobbyFull
+		}
+		lobby.Members = append(lobby.Members, newMember(user, u.now()))
+		filled = claimMatch(lobby)
+		return true, nil
+	})
+	if err != nil || !filled {
+		return lobby, err
+	}
+	return u.startMatch(ctx, lobby)
+}
+
+// claimMatch marks a lobby that just filled up as playing a new match. It is
+// saved in the same write that fills the room, so only one change starts it.
+func claimMatch(lobby *domain.Lobby) bool {
+	if !lobby.IsFull() {
+		return false
+	}
+	lobby.Status = domain.LobbyInGame
+	lobby.MatchID = primitive.NewObjectID()
+	return true
+}
+
+// startMatch starts the match a full lobby claimed. If it can't start, the
+// lobby is reopened so its members aren't stuck.
+func (u *lobbyUseCase) startMatch(ctx context.Context, lobby *domain.Lobby) (*domain.Lobby, error) {
+	match := &domain.Match{
+		ID:          lobby.MatchID,
+		LobbyID:     lobby.ID,
+		TurnSeconds: lobby.Settings.TurnSeconds,
+		Players:     make([]domain.MatchPlayer, len(lobby.Members)),
+	}
+	for seat, m := range lobby.Members {
+		match.Players[seat] = domain.MatchPlayer{UserID: m.UserID, Username: m.Username, DisplayName: m.DisplayName, Seat: seat, Bot: m.Bot}
+	}
+	err := u.matches.Start(ctx, match)
+	if err == nil {
+		return lobby, nil
+	}
+
+	_, reopenErr := u.retry(ctx, func(ctx context.Context) (*domain.Lobby, error) {
+		return u.lobbyRepo.GetByID(ctx, lobby.ID.Hex())
+	}, func(l *domain.Lobby) (bool, error) {
+		if l.MatchID != match.ID {
+			return false, nil
+		}
+		l.Status, l.MatchID = domain.LobbyOpen, primitive.NilObjectID
+		return true, nil
+	})
+	return nil, errors.Join(err, reopenErr)
+}
+
+func (u *lobbyUseCase) update(c context.Context, lobbyID string, change func(*domain.Lobby) (bool, error)) (*domain.Lobby, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	return u.retry(ctx, func(ctx context.Context) (*domain.Lobby, error) {
+		return u.lobbyRepo.GetByID(ctx, lobbyID)
+	}, change)
+}
+
+// retry loads a lobby, applies change and saves it, starting over when
+// another request saved the lobby in between. A lobby left without members
+// is deleted and nil is returned. Members are told about every saved change.
+func (u *lobbyUseCase) retry(ctx context.Context, load func(context.Context) (*domain.Lobby, error), change func(*domain.Lobby) (bool, error)) (*domain.Lobby, error) {
+	for attempt := 1; ; attempt++ {
+		lobby, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		changed, err := change(lobby)
+		if err != nil {
+			return nil, err
+		}
+		if !changed {
+			return lobby, nil
+		}
+
+		if len(lobby.Members) == 0 {
+			err = u.lobbyRepo.Delete(ctx, lobby)
+		} else {
+			lobby.UpdatedAt = u.now()
+			err = u.lobbyRepo.Update(ctx, lobby)
+		}
+		if errors.Is(err, domain.ErrLobbyConflict) && attempt < lobbyAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		topic := domain.LobbyTopic(lobby.ID.Hex())
+		if len(lobby.Members) == 0 {
+			u.publisher.Publish(topic, domain.EventLobbyClosed, domain.LobbyEvent{LobbyID: lobby.ID.Hex()})
+			return nil, nil
+		}
+		u.publisher.Publish(topic, domain.EventLobbyUpdated, lobby)
+		return lobby, nil
+	}
+}
+
+// ensureNotInLobby fails if userID is a member of any lobby except allowed.
+func (u *lobbyUseCase) ensureNotInLobby(ctx context.Context, userID, allowed primitive.ObjectID) error {
+	current, err := u.lobbyRepo.GetByMember(ctx, userID)
+	if errors.Is(err, domain.ErrLobbyNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if current.ID != allowed {
+		return domain.ErrAlreadyInLobby
+	}
+	return nil
+}
+
+// ensureNotQueued fails if userID waits in the matchmaking queue, so nobody
+// is seated in two games at once.
+func (u *lobbyUseCase) ensureNotQueued(ctx context.Context, userID string) error {
+	_, err := u.queue.Get(ctx, userID)
+	if errors.Is(err, matchmaking.ErrNotQueued) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return matchmaking.ErrAlreadyQueued
+}
+
+// ensureNotPlaying fails if userID plays an active match, e.g. one
+// matchmaking started, which no lobby records.
+func (u *lobbyUseCase) ensureNotPlaying(ctx context.Context, userID primitive.ObjectID) error {
+	active, err := u.matchRepo.ListActiveByPlayer(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if len(active) > 0 {
+		return domain.ErrAlreadyInMatch
+	}
+	return nil
+}
+
+// removeMember drops userID and passes the host role to the player who
+// joined earliest if the host left. Bots can't host, so a lobby left with
+// bots only loses them too.
+func removeMember(lobby *domain.Lobby, userID primitive.ObjectID) error {
+	i := lobby.MemberIndex(userID)
+	if i < 0 {
+		return domain.ErrNotInLobby
+	}
+	lobby.Members = slices.Delete(lobby.Members, i, i+1)
+	if lobby.HostID != userID {
+		return nil
+	}
+	host := slices.IndexFunc(lobby.Members, func(m domain.LobbyMember) bool { return m.Bot == "" })
+	if host < 0 {
+		lobby.Members = nil
+		return nil
+	}
+	lobby.HostID = lobby.Members[host].UserID
+	return nil
+}
+
+func newMember(user *domain.User, now time.Time) domain.LobbyMember {
+	return domain.LobbyMember{
+		UserID:      user.ID,
+		Username:    user.Username,
+		DisplayName: user.DisplayName,
+		JoinedAt:    now,
+	}
+}
+
+// newBotMember names a bot with the lowest number no other bot in the lobby
+// goes by, so a room can tell them apart.
+func newBotMember(lobby *domain.Lobby, level bot.Level, now time.Time) domain.LobbyMember {
+	n := 1
+	for slices.ContainsFunc(lobby.Members, func(m domain.LobbyMember) bool { return m.Username == fmt.Sprintf("bot%d", n) }) {
+		n++
+	}
+	return domain.LobbyMember{
+		UserID:      primitive.NewObjectID(),
+		Username:    fmt.Sprintf("bot%d", n),
+		DisplayName: fmt.Sprintf("Bot %d (%s)", n, level),
+		JoinedAt:    now,
+		Bot:         level,
+	}
+}
+
+func newInviteCode() string {
+	// Skip bytes past the last full multiple of the alphabet size so every
+	// character is equally likely.
+	limit := byte(256 - 256%len(inviteAlphabet))
+	code := make([]byte, 0, domain.InviteCodeLength)
+	buf := make([]byte, domain.InviteCodeLength)
+	for len(code) < domain.InviteCodeLength {
+		_, _ = rand.Read(buf)
+		for _, b := range buf {
+			if b < limit && len(code) < domain.InviteCodeLength {
+				code = append(code, inviteAlphabet[int(b)%len(inviteAlphabet)])
+			}
+		}
+	}
+	return string(code)
+}
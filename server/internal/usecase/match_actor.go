@@ -0,0 +1,368 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/bot"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/game"
+	"github.com/Simpolette/HeartSteal/server/internal/replay"
+)
+
+// matchRequest runs on the actor's goroutine, the only one that touches the
+// state. The events it returns are published and restart the turn timer.
+type matchRequest struct {
+	run  func(*game.State) ([]game.Event, error)
+	done chan error
+}
+
+type matchActor struct {
+	uc    *matchUseCase
+	match *domain.Match
+	state *game.State
+	turn  time.Duration
+	// actions logs every action applied, for the replay, and playedAt when
+	// each was.
+	actions  []game.Action
+	playedAt []time.Time
+	// delay is how far spectators are kept behind.
+	delay      time.Duration
+	spectators *spectatorFeed
+	// log holds every event published, so players can catch up on the ones
+	// they missed.
+	log []game.Event
+	// presence tracks each seat's connection, by seat.
+	presence []seatPresence
+	// bots holds, by seat, the bot playing it: for bot members from the
+	// start, for players while they are replaced.
+	bots []bot.Bot
+
+	requests chan matchRequest
+	// stopped is closed once the game is over and no request will be served.
+	stopped  chan struct{}
+	deadline time.Time
+}
+
+func newMatchActor(uc *matchUseCase, match *domain.Match, state *game.State, bots []bot.Bot) *matchActor {
+	delay := time.Duration(0)
+	if match.Ranked {
+		delay = uc.cfg.SpectatorDelay
+	}
+	a := &matchActor{
+		uc:         uc,
+		match:      match,
+		state:      state,
+		turn:       time.Duration(match.TurnSeconds) * time.Second,
+		delay:      delay,
+		spectators: &spectatorFeed{publisher: uc.publisher, topic: domain.SpectateTopic(match.ID.Hex()), delay: delay},
+		presence:   make([]seatPresence, len(match.Players)),
+		bots:       bots,
+		requests:   make(chan matchRequest),
+		stopped:    make(chan struct{}),
+	}
+	return a
+}
+
+// newBots returns the bots playing the bot seats of match, indexed by seat.
+func newBots(match *domain.Match, state *game.State) ([]bot.Bot, error) {
+	bots := make([]bot.Bot, len(match.Players))
+	for _, p := range match.Players {
+		if p.Bot == "" {
+			continue
+		}
+		b, err := newBot(state, p.Seat, p.Bot)
+		if err != nil {
+			return nil, err
+		}
+		bots[p.Seat] = b
+	}
+	return bots, nil
+}
+
+// newBot returns a bot for seat, seeded from the game so its moves are the
+// same whenever the game is.
+func newBot(state *game.State, seat int, level bot.Level) (bot.Bot, error) {
+	return bot.New(level, state.Seed+int64(seat))
+}
+
+// do runs fn on the actor and waits for it. Once the game is over it fails
+// with game.ErrGameOver.
+func (a *matchActor) do(ctx context.Context, fn func(*game.State) ([]game.Event, error)) error {
+	req := matchRequest{run: fn, done: make(chan error, 1)}
+	select {
+	case a.requests <- req:
+	case <-a.stopped:
+		return game.ErrGameOver
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	// A request the actor accepted is always answered.
+	return <-req.done
+}
+
+// run serves requests until the game is over. When the turn timer fires the
+// seat to move is played for: by its bot if one plays it, otherwise with
+// game.AutoAction. Bots' turns are timed by the bot move delay rather than
+// the turn time. The timer only restarts when the turn moves on or a bot
+// takes over or hands back the seat to move, not when someone forfeits out
+// of turn. The match's lease is renewed in between; should it be lost, the
+// actor stops without saving anything, since another instance has abandoned
+// the match.
+func (a *matchActor) run(initial []game.Event) {
+	timer := time.NewTimer(a.turnTime())
+	defer timer.Stop()
+	lease := time.NewTicker(a.uc.cfg.Lease / 3)
+	defer lease.Stop()
+	a.deadline = a.uc.now().Add(a.turnTime())
+	a.publish(initial)
+
+	for owned := true; owned && !a.state.Over; {
+		turn, botTurn := a.state.TurnNumber, a.botTurn()
+		var events []game.Event
+		select {
+		case req := <-a.requests:
+			var err error
+			events, err = req.run(a.state)
+			req.done <- err
+		case <-timer.C:
+			events = a.timeout()
+		case <-lease.C:
+			owned = a.renewLease()
+		}
+		if a.state.TurnNumber != turn || a.botTurn() != botTurn {
+			timer.Reset(a.turnTime())
+			a.deadline = a.uc.now().Add(a.turnTime())
+		}
+		if len(events) > 0 {
+			a.publish(events)
+		}
+	}
+
+	close(a.stopped)
+	if a.state.Over {
+		a.uc.finish(a)
+	} else {
+		a.uc.forget(a.match.ID)
+	}
+}
+
+// renewLease extends this instance's claim on the match and reports whether
+// it still has one. A failed renewal is retried on the next tick; only a
+// match that is no longer active and ours is lost.
+func (a *matchActor) renewLease() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), a.uc.contextTimeout)
+	defer cancel()
+	err := a.uc.matchRepo.RenewLease(ctx, a.match.ID, a.uc.cfg.Instance, a.uc.now().Add(a.uc.cfg.Lease))
+	if errors.Is(err, domain.ErrMatchNotFound) {
+		slog.Warn("Match lease lost to another instance", "match_id", a.match.ID.Hex(), "instance", a.uc.cfg.Instance)
+		return false
+	}
+	if err != nil {
+		slog.Error("Match lease can't be renewed", "match_id", a.match.ID.Hex(), "error", err)
+	}
+	return true
+}
+
+// replayUntil returns the game up to the last action played by cutoff. Only
+// the actor's goroutine may call it while the game runs.
+func (a *matchActor) replayUntil(cutoff time.Time) replay.Replay {
+	played := sort.Search(len(a.playedAt), func(i int) bool { return a.playedAt[i].After(cutoff) })
+	return replay.Replay{Config: a.match.GameConfig(), Seed: a.match.Seed, Actions: slices.Clone(a.actions[:played])}
+}
+
+func (a *matchActor) botTurn() bool {
+	return !a.state.Over && a.bots[a.state.Turn] != nil
+}
+
+// turnTime is how long the seat to move has before it is played for.
+func (a *matchActor) turnTime() time.Duration {
+	if a.botTurn() {
+		return a.uc.cfg.BotMove
+	}
+	return a.turn
+}
+
+// timeout plays for the seat to move. A bot sends its move through the same
+// checks as a player's; should the game reject it, game.AutoAction is
+// played instead. Moves made for a replaced player are marked Auto.
+func (a *matchActor) timeout() []game.Event {
+	seat := a.state.Turn
+	if b := a.bots[seat]; b != nil {
+		action := b.Act(a.state.View(seat))
+		action.Seat, action.Auto, action.Forfeit = seat, a.presence[seat].bot, false
+		if events, err := a.apply(action); err == nil {
+			return events
+		}
+	}
+	events, _ := a.apply(game.AutoAction(a.state))
+	return events
+}
+
+// apply plays action and logs it if it was legal. Only the actor's
+// goroutine may call it.
+func (a *matchActor) apply(action game.Action) ([]game.Event, error) {
+	events, err := game.Apply(a.state, action)
+	if err == nil {
+		a.actions = append(a.actions, action)
+		a.playedAt = append(a.playedAt, a.uc.now())
+	}
+	return events, err
+}
+
+// publish sends everyone the public side of events, spectators after the
+// delay, and each player the cards only they may see.
+func (a *matchActor) publish(events []game.Event) {
+	a.log = append(a.log, events...)
+	id := a.match.ID.Hex()
+	update := domain.MatchUpdate{
+		MatchID:    id,
+		Events:     make([]game.Event, len(events)),
+		Spectators: len(a.uc.publisher.Subscribers(a.spectators.topic)),
+	}
+	private := make(map[int][]game.Event)
+	for i, e := range events {
+		update.Events[i] = e.Public()
+		if e.Private {
+			private[e.Seat] = append(private[e.Seat], e)
+		}
+	}
+	if !a.state.Over {
+		deadline := a.deadline
+		update.TurnDeadline = &deadline
+	}
+	a.uc.publisher.Publish(domain.MatchTopic(id), domain.EventMatchUpdated, update)
+	a.spectators.push(update)
+
+	for _, p := range a.match.Players {
+		if events := private[p.Seat]; len(events) > 0 {
+			a.uc.publisher.Publish(domain.UserTopic(p.UserID.Hex()), domain.EventMatchPrivate,
+				domain.MatchUpdate{MatchID: id, Events: events})
+		}
+	}
+}
+
+// seatPresence tracks one player's connection. bot is set while a bot
+// replaces them. gen counts the changes, so a grace timer that fires after
+// the player came back does nothing.
+type seatPresence struct {
+	away     bool
+	deadline time.Time
+	bot      bool
+	gen      int
+}
+
+// setConnected records whether the player at seat follows the match. One
+// who drops while still in the game keeps their seat for the grace period;
+// one who comes back takes it over again from the server. Only the actor's
+// goroutine may call it.
+func (a *matchActor) setConnected(seat int, connected bool) {
+	p := &a.presence[seat]
+	if p.away != connected {
+		return
+	}
+	p.gen++
+	p.away, p.deadline = !connected, time.Time{}
+	if connected {
+		p.bot, a.bots[seat] = false, nil
+	} else if !a.state.Players[seat].Out {
+		grace := a.uc.cfg.ReconnectGrace
+		p.deadline = a.uc.now().Add(grace)
+		gen := p.gen
+		time.AfterFunc(grace, func() { a.expire(seat, gen) })
+	}
+	a.publishPresence(seat)
+}
+
+// expire applies the disconnect policy to seat if it has stayed away since
+// change gen.
+func (a *matchActor) expire(seat, gen int) {
+	ctx, cancel := context.WithTimeout(context.Background(), a.uc.contextTimeout)
+	defer cancel()
+	err := a.do(ctx, func(s *game.State) ([]game.Event, error) {
+		p := &a.presence[seat]
+		if p.gen != gen || s.Players[seat].Out {
+			return nil, nil
+		}
+		if a.uc.cfg.DisconnectPolicy == domain.BotOnDisconnect {
+			b, err := newBot(s, seat, cmp.Or(a.uc.cfg.DisconnectBot, bot.DefaultLevel))
+			if err != nil {
+				return nil, err
+			}
+			p.deadline = time.Time{}
+			p.bot = true
+			a.bots[seat] = b
+			a.publishPresence(seat)
+			return nil, nil
+		}
+		p.deadline = time.Time{}
+		a.publishPresence(seat)
+		return a.apply(game.Action{Seat: seat, Forfeit: true, Auto: true})
+	})
+	if err != nil && !errors.Is(err, game.ErrGameOver) {
+		slog.Error("Disconnect policy can't be applied", "match_id", a.match.ID.Hex(), "seat", seat, "error", err)
+	}
+}
+
+func (a *matchActor) seatPresence(seat int) domain.SeatPresence {
+	p := a.presence[seat]
+	presence := domain.SeatPresence{Seat: seat, Connected: !p.away, Bot: a.bots[seat] != nil}
+	if !p.deadline.IsZero() {
+		deadline := p.deadline
+		presence.GraceDeadline = &deadline
+	}
+	return presence
+}
+
+func (a *matchActor) publishPresence(seat int) {
+	id := a.match.ID.Hex()
+	a.uc.publisher.Publish(domain.MatchTopic(id), domain.EventMatchPresence,
+		domain.MatchPresence{MatchID: id, SeatPresence: a.seatPresence(seat)})
+}
+
+// spectatorFeed publishes a match's updates to its spectators delay after
+// they happened, in order. It outlives the actor by the delay.
+type spectatorFeed struct {
+	publisher domain.Publisher
+	topic     string
+	delay     time.Duration
+
+	mu      sync.Mutex
+	pending []delayedUpdate
+}
+
+type delayedUpdate struct {
+	due    time.Time
+	update domain.MatchUpdate
+}
+
+func (f *spectatorFeed) push(update domain.MatchUpdate) {
+	if f.delay <= 0 {
+		f.publisher.Publish(f.topic, domain.EventMatchUpdated, update)
+		return
+	}
+	// The deadline has passed by the time spectators see the turn.
+	update.TurnDeadline = nil
+	f.mu.Lock()
+	f.pending = append(f.pending, delayedUpdate{due: time.Now().Add(f.delay), update: update})
+	f.mu.Unlock()
+	time.AfterFunc(f.delay, f.flush)
+}
+
+// flush publishes every update that is due. Whichever timer fires first
+// sends them, so they can't overtake each other.
+func (f *spectatorFeed) flush() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := time.Now()
+	for len(f.pending) > 0 && !f.pending[0].due.After(now) {
+		f.publisher.Publish(f.topic, domain.EventMatchUpdated, f.pending[0].update)
+		f.pending = f.pending[1:]
+	}
+}
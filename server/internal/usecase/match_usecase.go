@@ -0,0 +1,552 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"log/slog"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/bot"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/game"
+	"github.com/Simpolette/HeartSteal/server/internal/replay"
+	"github.com/Simpolette/HeartSteal/server/internal/tracing"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var _ domain.MatchUsecase = &matchUseCase{}
+
+type MatchConfig struct {
+	// SpectatorDelay holds back what spectators of ranked matches see, so
+	// nobody watching can coach a player in real time.
+	SpectatorDelay time.Duration
+	// ReconnectGrace is how long a disconnected player keeps their seat
+	// before DisconnectPolicy applies; forfeiting unless it says otherwise.
+	ReconnectGrace   time.Duration
+	DisconnectPolicy domain.DisconnectPolicy
+	// DisconnectBot is the level of the bot that takes over under
+	// BotOnDisconnect; bot.DefaultLevel unless set.
+	DisconnectBot bot.Level
+	// BotMove is how long bots wait before each move, so players can follow.
+	BotMove time.Duration
+	// Instance names this process as the owner of the matches it runs; a
+	// random ID unless set, so a restarted process never owns the matches
+	// of the one before.
+	Instance string
+	// Lease is how long a match stays claimed by its owner without being
+	// renewed; DefaultMatchLease unless set. Owners renew it three times
+	// per period.
+	Lease time.Duration
+}
+
+// DefaultMatchLease is the lease of MatchConfig.Lease when it is unset.
+const DefaultMatchLease = 30 * time.Second
+
+// matchUseCase runs every match of this process as an actor: one goroutine
+// owns the game state and applies actions and timeouts one at a time.
+// Matches are not shared between instances, so a lobby's match runs where
+// the lobby filled up. Each actor holds a lease on its match, which lets
+// other instances tell a running match from one whose owner is gone.
+type matchUseCase struct {
+	matchRepo      domain.MatchRepository
+	lobbyRepo      domain.LobbyRepository
+	ratings        domain.RatingUsecase
+	history        domain.HistoryUsecase
+	tx             domain.Transactor
+	publisher      domain.Publisher
+	cfg            MatchConfig
+	contextTimeout time.Duration
+	now            func() time.Time
+
+	mu      sync.Mutex
+	running map[primitive.ObjectID]*matchActor
+	// trailing holds the actors of finished matches whose spectators are
+	// still behind.
+	trailing map[primitive.ObjectID]*matchActor
+}
+
+func NewMatchUseCase(matchRepo domain.MatchRepository, lobbyRepo domain.LobbyRepository, ratings domain.RatingUsecase, history domain.HistoryUsecase, tx domain.Transactor, publisher domain.Publisher, cfg MatchConfig, timeout time.Duration) domain.MatchUsecase {
+	if cfg.Instance == "" {
+		cfg.Instance = primitive.NewObjectID().Hex()
+	}
+	cfg.Lease = cmp.Or(cfg.Lease, DefaultMatchLease)
+	return &matchUseCase{
+		matchRepo:      matchRepo,
+		lobbyRepo:      lobbyRepo,
+		ratings:        ratings,
+		history:        history,
+		tx:             tx,
+		publisher:      publisher,
+		cfg:            cfg,
+		contextTimeout: timeout,
+		now:            func() time.Time { return time.Now().UTC() },
+		running:        make(map[primitive.ObjectID]*matchActor),
+		trailing:       make(map[primitive.ObjectID]*matchActor),
+	}
+}
+
+func (u *matchUseCase) Start(c context.Context, match *domain.Match) (err error) {
+	ctx, span := tracer.Start(c, "matchUseCase.Start")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	if match.Seed == 0 {
+		match.Seed = newSeed()
+	}
+	state, events, err := game.New(match.GameConfig(), match.Seed)
+	if err != nil {
+		return err
+	}
+	bots, err := newBots(match, state)
+	if err != nil {
+		return err
+	}
+	if match.TurnSeconds <= 0 {
+		match.TurnSeconds = domain.DefaultTurnSeconds
+	}
+	match.Status = domain.MatchActive
+	match.CreatedAt = u.now()
+	match.Owner = u.cfg.Instance
+	match.LeaseExpiresAt = match.CreatedAt.Add(u.cfg.Lease)
+	if err := u.matchRepo.Create(ctx, match); err != nil {
+		return err
+	}
+
+	actor := newMatchActor(u, match, state, bots)
+	u.mu.Lock()
+	u.running[match.ID] = actor
+	u.mu.Unlock()
+	go actor.run(events)
+	return nil
+}
+
+func (u *matchUseCase) Get(c context.Context, userID string, matchID string) (_ *domain.Match, _ *game.View, err error) {
+	ctx, span := tracer.Start(c, "matchUseCase.Get")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	match, actor, seat, err := u.find(ctx, userID, matchID)
+	if err != nil || actor == nil {
+		return match, nil, err
+	}
+
+	var view game.View
+	err = actor.do(ctx, func(s *game.State) ([]game.Event, error) {
+		view = s.View(seat)
+		return nil, nil
+	})
+	if errors.Is(err, game.ErrGameOver) {
+		// The match ended while we asked; return the stored record instead.
+		match, err = u.matchRepo.GetByID(ctx, matchID)
+		return match, nil, err
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return match, &view, nil
+}
+
+func (u *matchUseCase) Act(c context.Context, userID string, matchID string, action game.Action) (_ *game.View, err error) {
+	ctx, span := tracer.Start(c, "matchUseCase.Act")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	_, actor, seat, err := u.find(ctx, userID, matchID)
+	if err != nil {
+		return nil, err
+	}
+	if actor == nil {
+		return nil, game.ErrGameOver
+	}
+
+	// Players always act for their own seat and never as the timer.
+	action.Seat, action.Auto = seat, false
+	var view game.View
+	err = actor.do(ctx, func(s *game.State) ([]game.Event, error) {
+		events, err := actor.apply(action)
+		view = s.View(seat)
+		return events, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+func (u *matchUseCase) SetConnected(c context.Context, userID string, matchID string, connected bool) (err error) {
+	ctx, span := tracer.Start(c, "matchUseCase.SetConnected")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	_, actor, seat, err := u.find(ctx, userID, matchID)
+	if err != nil || actor == nil {
+		return err
+	}
+	err = actor.do(ctx, func(*game.State) ([]game.Event, error) {
+		actor.setConnected(seat, connected)
+		return nil, nil
+	})
+	if errors.Is(err, game.ErrGameOver) {
+		// Nobody's seat needs holding anymore.
+		return nil
+	}
+	return err
+}
+
+func (u *matchUseCase) Resume(c context.Context, userID string, matchID string, after int) (_ *domain.MatchResume, err error) {
+	ctx, span := tracer.Start(c, "matchUseCase.Resume")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	match, actor, seat, err := u.find(ctx, userID, matchID)
+	if err != nil {
+		return nil, err
+	}
+	resume := &domain.MatchResume{Match: match, Events: []game.Event{}, Presence: []domain.SeatPresence{}}
+	if actor == nil {
+		return resume, nil
+	}
+
+	var view game.View
+	err = actor.do(ctx, func(s *game.State) ([]game.Event, error) {
+		view = s.View(seat)
+		missed := sort.Search(len(actor.log), func(i int) bool { return actor.log[i].Seq > after })
+		for _, e := range actor.log[missed:] {
+			resume.Events = append(resume.Events, e.For(seat))
+		}
+		for other := range actor.presence {
+			resume.Presence = append(resume.Presence, actor.seatPresence(other))
+		}
+		deadline := actor.deadline
+		resume.TurnDeadline = &deadline
+		return nil, nil
+	})
+	if errors.Is(err, game.ErrGameOver) {
+		// The match ended while we asked; return the stored record instead.
+		if match, err = u.matchRepo.GetByID(ctx, matchID); err != nil {
+			return nil, err
+		}
+		return &domain.MatchResume{Match: match, Events: []game.Event{}, Presence: []domain.SeatPresence{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	resume.State = &view
+	return resume, nil
+}
+
+func (u *matchUseCase) Replay(c context.Context, matchID string, step int) (_ *domain.MatchReplay, err error) {
+	ctx, span := tracer.Start(c, "matchUseCase.Replay")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	match, err := u.matchRepo.GetByID(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+	r, err := match.Replay()
+	if err != nil {
+		return nil, err
+	}
+	if step < 0 || step > r.Steps() {
+		step = r.Steps()
+	}
+	state, events, err := r.At(step)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.MatchReplay{Match: match, Actions: r.Actions, Step: step, State: state, Events: events}, nil
+}
+
+func (u *matchUseCase) Spectate(c context.Context, matchID string) (_ *domain.SpectatedMatch, err error) {
+	ctx, span := tracer.Start(c, "matchUseCase.Spectate")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	mid, err := primitive.ObjectIDFromHex(matchID)
+	if err != nil {
+		return nil, domain.ErrMatchNotFound
+	}
+	spectated := &domain.SpectatedMatch{Spectators: len(u.publisher.Subscribers(domain.SpectateTopic(matchID)))}
+	actor := u.actor(mid)
+	if actor == nil {
+		actor = u.trailingActor(mid)
+	}
+	if actor == nil {
+		if spectated.Match, err = u.matchRepo.GetByID(ctx, matchID); err != nil {
+			return nil, err
+		}
+		return spectated, nil
+	}
+
+	spectated.Match, spectated.Delay = actor.match, actor.delay
+	if actor.delay <= 0 {
+		var view game.View
+		err = actor.do(ctx, func(s *game.State) ([]game.Event, error) {
+			view = s.View(-1)
+			return nil, nil
+		})
+		if errors.Is(err, game.ErrGameOver) {
+			// The match ended while we asked; return the stored record instead.
+			if spectated.Match, err = u.matchRepo.GetByID(ctx, matchID); err != nil {
+				return nil, err
+			}
+			return spectated, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		spectated.State = &view
+		return spectated, nil
+	}
+
+	// Rebuild the game as it was before the delay, which is what the
+	// spectators' events have reached. Once the game is over its log no
+	// longer changes, so it is read directly until the feed catches up.
+	var behind replay.Replay
+	err = actor.do(ctx, func(*game.State) ([]game.Event, error) {
+		behind = actor.replayUntil(u.now().Add(-actor.delay))
+		return nil, nil
+	})
+	if errors.Is(err, game.ErrGameOver) {
+		behind, err = actor.replayUntil(u.now().Add(-actor.delay)), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	s, err := behind.Final()
+	if err != nil {
+		return nil, err
+	}
+	view := s.View(-1)
+	spectated.State = &view
+	return spectated, nil
+}
+
+func (u *matchUseCase) AbandonExpired(c context.Context) (_ int, err error) {
+	ctx, span := tracer.Start(c, "matchUseCase.AbandonExpired")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	matches, err := u.matchRepo.ListLeaseExpired(ctx, u.now())
+	if err != nil {
+		return 0, err
+	}
+
+	abandoned := 0
+	for i := range matches {
+		match := &matches[i]
+		if u.actor(match.ID) != nil {
+			// Ours, but the lease couldn't be renewed lately.
+			continue
+		}
+		// The owner may have renewed the lease since it was listed; then
+		// the match is left alone.
+		now := u.now()
+		ok, err := u.matchRepo.AbandonLeaseExpired(ctx, match.ID, now)
+		if err != nil {
+			return abandoned, err
+		}
+		if !ok {
+			continue
+		}
+		match.Status = domain.MatchAbandoned
+		match.FinishedAt = &now
+		if err := u.closeLobby(ctx, match); err != nil {
+			return abandoned, err
+		}
+		abandoned++
+	}
+	return abandoned, nil
+}
+
+// find returns the match userID plays in, its actor while it runs and
+// userID's seat. Other users get ErrMatchNotFound so matches can't be probed.
+func (u *matchUseCase) find(ctx context.Context, userID string, matchID string) (*domain.Match, *matchActor, int, error) {
+	uid, _ := primitive.ObjectIDFromHex(userID)
+	mid, err := primitive.ObjectIDFromHex(matchID)
+	if err != nil {
+		return nil, nil, -1, domain.ErrMatchNotFound
+	}
+
+	actor := u.actor(mid)
+	match := (*domain.Match)(nil)
+	if actor != nil {
+		match = actor.match
+	} else if match, err = u.matchRepo.GetByID(ctx, matchID); err != nil {
+		return nil, nil, -1, err
+	}
+
+	seat := match.Seat(uid)
+	if seat < 0 {
+		return nil, nil, -1, domain.ErrMatchNotFound
+	}
+	return match, actor, seat, nil
+}
+
+func (u *matchUseCase) actor(id primitive.ObjectID) *matchActor {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.running[id]
+}
+
+// finish stores the result and action log of a match whose game is over,
+// rates it if it was ranked, adds it to its players' stats, closes its lobby
+// and forgets the actor. The result, the ratings and the stats are saved in
+// one transaction, so a match is never counted twice. The result is only
+// saved while the match is still active and ours: should another instance
+// have abandoned it after our lease lapsed, the transaction rolls back and
+// nothing is saved.
+func (u *matchUseCase) finish(a *matchActor) {
+	ctx, cancel := context.WithTimeout(context.Background(), u.contextTimeout)
+	defer cancel()
+
+	now := u.now()
+	actions, err := replay.Encode(a.actions)
+	if err != nil {
+		slog.Error("Match actions can't be recorded", "match_id", a.match.ID.Hex(), "error", err)
+	}
+	result := func() domain.Match {
+		match := *a.match
+		match.Players = slices.Clone(a.match.Players)
+		match.Status = domain.MatchFinished
+		match.Placements = a.state.Placements
+		match.FinishedAt = &now
+		match.Actions = actions
+		if len(match.Placements) == len(match.Players) {
+			for i := range match.Players {
+				match.Players[i].Placement = match.Placements[match.Players[i].Seat]
+			}
+		}
+		return match
+	}
+
+	match := result()
+	err = u.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		match = result()
+		if err := u.ratings.Record(ctx, &match); err != nil {
+			return err
+		}
+		if err := u.history.Record(ctx, &match); err != nil {
+			return err
+		}
+		return u.matchRepo.Finish(ctx, &match, u.cfg.Instance)
+	})
+	if err != nil && !errors.Is(err, domain.ErrMatchNotFound) {
+		// Nothing was committed; keep the result even if it goes unrated
+		// and out of the stats.
+		slog.Error("Match can't be rated or added to stats", "match_id", match.ID.Hex(), "error", err)
+		match = result()
+		err = u.matchRepo.Finish(ctx, &match, u.cfg.Instance)
+	}
+	if errors.Is(err, domain.ErrMatchNotFound) {
+		// Its lobby was closed along with it.
+		slog.Warn("Match result dropped, the match was abandoned meanwhile", "match_id", match.ID.Hex(), "instance", u.cfg.Instance)
+		u.retire(a)
+		return
+	}
+	if err != nil {
+		slog.Error("Match result can't be saved", "match_id", match.ID.Hex(), "error", err)
+	}
+	if err := u.closeLobby(ctx, &match); err != nil {
+		slog.Error("Lobby can't be closed after its match", "match_id", match.ID.Hex(), "lobby_id", match.LobbyID.Hex(), "error", err)
+	}
+
+	u.retire(a)
+}
+
+// retire drops the actor of a finished match. A delayed one is kept as
+// trailing until its spectator feed has caught up, so Spectate doesn't show
+// the ending early.
+func (u *matchUseCase) retire(a *matchActor) {
+	id := a.match.ID
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	delete(u.running, id)
+	if a.delay <= 0 {
+		return
+	}
+	u.trailing[id] = a
+	time.AfterFunc(a.delay, func() {
+		u.mu.Lock()
+		delete(u.trailing, id)
+		u.mu.Unlock()
+	})
+}
+
+func (u *matchUseCase) trailingActor(id primitive.ObjectID) *matchActor {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.trailing[id]
+}
+
+// forget drops the actor of match id once it has stopped.
+func (u *matchUseCase) forget(id primitive.ObjectID) {
+	u.mu.Lock()
+	delete(u.running, id)
+	u.mu.Unlock()
+}
+
+// closeLobby deletes the lobby that started match, unless it has since
+// emptied or moved on.
+func (u *matchUseCase) closeLobby(ctx context.Context, match *domain.Match) error {
+	if match.LobbyID.IsZero() {
+		return nil
+	}
+	for attempt := 1; ; attempt++ {
+		lobby, err := u.lobbyRepo.GetByID(ctx, match.LobbyID.Hex())
+		if errors.Is(err, domain.ErrLobbyNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if lobby.MatchID != match.ID {
+			return nil
+		}
+
+		err = u.lobbyRepo.Delete(ctx, lobby)
+		if errors.Is(err, domain.ErrLobbyConflict) && attempt < lobbyAttempts {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		lobbyID := lobby.ID.Hex()
+		u.publisher.Publish(domain.LobbyTopic(lobbyID), domain.EventLobbyClosed, domain.LobbyEvent{LobbyID: lobbyID})
+		return nil
+	}
+}
+
+// newSeed picks a random non-zero seed; zero asks Start to pick one.
+func newSeed() int64 {
+	var b [8]byte
+	for {
+		_, _ = rand.Read(b[:])
+		if seed := int64(binary.LittleEndian.Uint64(b[:])); seed != 0 {
+			return seed
+		}
+	}
+}
@@ -0,0 +1,389 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/matchmaking"
+	"github.com/Simpolette/HeartSteal/server/internal/tracing"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var _ domain.MatchmakingUsecase = &matchmakingUseCase{}
+
+// matchmakingAttempts bounds retries when a ticket is proposed or resolved
+// while a player cancels it.
+const matchmakingAttempts = 3
+
+// matchmakingUseCase pairs tickets as soon as they are queued and again on
+// every Run tick, when their windows have widened. The queue is the only
+// shared state, so several instances may run it against a shared Queue.
+type matchmakingUseCase struct {
+	queue          matchmaking.Queue
+	userRepo       domain.UserRepository
+	lobbyRepo      domain.LobbyRepository
+	matchRepo      domain.MatchRepository
+	matches        domain.MatchUsecase
+	ratings        domain.RatingSource
+	publisher      domain.Publisher
+	cfg            matchmaking.Config
+	contextTimeout time.Duration
+	now            func() time.Time
+}
+
+// NewMatchmakingUseCase builds the matchmaker. A nil ratings rates every
+// player matchmaking.DefaultRating.
+func NewMatchmakingUseCase(queue matchmaking.Queue, userRepo domain.UserRepository, lobbyRepo domain.LobbyRepository, matchRepo domain.MatchRepository, matches domain.MatchUsecase, ratings domain.RatingSource, publisher domain.Publisher, cfg matchmaking.Config, timeout time.Duration) domain.MatchmakingUsecase {
+	return &matchmakingUseCase{
+		queue:          queue,
+		userRepo:       userRepo,
+		lobbyRepo:      lobbyRepo,
+		matchRepo:      matchRepo,
+		matches:        matches,
+		ratings:        ratings,
+		publisher:      publisher,
+		cfg:            cfg,
+		contextTimeout: timeout,
+		now:            func() time.Time { return time.Now().UTC() },
+	}
+}
+
+func (u *matchmakingUseCase) Enqueue(c context.Context, userID string, mode domain.GameMode, party []string) (_ *matchmaking.Ticket, err error) {
+	ctx, span := tracer.Start(c, "matchmakingUseCase.Enqueue")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	if mode.Players() == 0 {
+		return nil, domain.ErrUnknownGameMode
+	}
+	members, err := u.party(ctx, userID, party)
+	if err != nil {
+		return nil, err
+	}
+	if len(members) > mode.Players() {
+		return nil, domain.ErrPartyTooLarge
+	}
+	if err := u.ensureAvailable(ctx, members); err != nil {
+		return nil, err
+	}
+
+	ratings, err := u.rate(ctx, mode, members)
+	if err != nil {
+		return nil, err
+	}
+	ticket := matchmaking.Ticket{
+		ID:       primitive.NewObjectID().Hex(),
+		Mode:     string(mode),
+		Players:  make([]matchmaking.Player, len(members)),
+		QueuedAt: u.now(),
+	}
+	for i, m := range members {
+		rating, ok := ratings[m.ID]
+		if !ok {
+			rating = matchmaking.DefaultRating
+		}
+		ticket.Players[i] = matchmaking.Player{UserID: m.ID.Hex(), Username: m.Username, DisplayName: m.DisplayName, Rating: rating}
+	}
+	if err := u.queue.Add(ctx, ticket); err != nil {
+		return nil, err
+	}
+	u.notify(ticket.Players, domain.EventMatchmakingQueued, domain.MatchmakingEvent{TicketID: ticket.ID, Mode: mode, Players: len(ticket.Players)})
+
+	u.match(ctx, mode)
+	if current, err := u.queue.Get(ctx, userID); err == nil && current.ID == ticket.ID {
+		return current, nil
+	}
+	return &ticket, nil
+}
+
+func (u *matchmakingUseCase) Current(c context.Context, userID string) (_ *matchmaking.Ticket, err error) {
+	ctx, span := tracer.Start(c, "matchmakingUseCase.Current")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	return u.queue.Get(ctx, userID)
+}
+
+func (u *matchmakingUseCase) Cancel(c context.Context, userID string) (err error) {
+	ctx, span := tracer.Start(c, "matchmakingUseCase.Cancel")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	for attempt := 1; ; attempt++ {
+		ticket, err := u.queue.Get(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		if ticket.ProposalID != "" {
+			err = u.decline(ctx, ticket.ProposalID, userID)
+		} else if err = u.queue.Remove(ctx, ticket.ID); err == nil {
+			u.notify(ticket.Players, domain.EventMatchmakingCancelled, domain.MatchmakingEvent{
+				TicketID: ticket.ID, Mode: domain.GameMode(ticket.Mode), Reason: domain.MatchmakingCancelled,
+			})
+		}
+		// The ticket was proposed or its proposal resolved while we looked.
+		if (errors.Is(err, matchmaking.ErrTicketTaken) || errors.Is(err, matchmaking.ErrProposalNotFound)) && attempt < matchmakingAttempts {
+			continue
+		}
+		return err
+	}
+}
+
+func (u *matchmakingUseCase) Accept(c context.Context, userID string) (_ *matchmaking.Proposal, err error) {
+	ctx, span := tracer.Start(c, "matchmakingUseCase.Accept")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	ticket, err := u.queue.Get(ctx, userID)
+	if errors.Is(err, matchmaking.ErrNotQueued) || (err == nil && ticket.ProposalID == "") {
+		return nil, matchmaking.ErrProposalNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	proposal, err := u.queue.Accept(ctx, ticket.ProposalID, userID)
+	if err != nil {
+		return nil, err
+	}
+	players := proposal.Players()
+	u.notify(players, domain.EventMatchmakingAccepted, domain.MatchmakingEvent{
+		ProposalID: proposal.ID, Mode: domain.GameMode(proposal.Mode), Players: len(players), Accepted: len(proposal.Accepted),
+	})
+	if !proposal.AllAccepted() {
+		return proposal, nil
+	}
+
+	resolved, err := u.queue.Resolve(ctx, proposal.ID)
+	if errors.Is(err, matchmaking.ErrProposalNotFound) {
+		// The other last player's accept is starting the match.
+		return proposal, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	resolved.Accepted = proposal.Accepted
+	return u.start(ctx, resolved)
+}
+
+func (u *matchmakingUseCase) Run(ctx context.Context) {
+	ticker := time.NewTicker(u.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			u.tick(ctx)
+		}
+	}
+}
+
+func (u *matchmakingUseCase) tick(c context.Context) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	u.expire(ctx)
+	for _, mode := range domain.GameModes {
+		u.match(ctx, mode)
+	}
+}
+
+// party loads userID and the friends queueing with them, without duplicates.
+// Friendship has to go both ways.
+func (u *matchmakingUseCase) party(ctx context.Context, userID string, party []string) ([]*domain.User, error) {
+	leader, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	members := []*domain.User{leader}
+	for _, id := range party {
+		friend, err := u.userRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if slices.ContainsFunc(members, func(m *domain.User) bool { return m.ID == friend.ID }) {
+			continue
+		}
+		// Both must list the other, so nobody can be queued by a user
+		// they never befriended.
+		if !slices.Contains(leader.FriendsList, friend.ID) || !slices.Contains(friend.FriendsList, leader.ID) {
+			return nil, domain.ErrPartyNotFriends
+		}
+		members = append(members, friend)
+	}
+	return members, nil
+}
+
+// ensureAvailable fails if any member is in a lobby or playing a match, so
+// nobody is seated in two games at once.
+func (u *matchmakingUseCase) ensureAvailable(ctx context.Context, members []*domain.User) error {
+	for _, m := range members {
+		_, err := u.lobbyRepo.GetByMember(ctx, m.ID)
+		if err == nil {
+			return domain.ErrAlreadyInLobby
+		}
+		if !errors.Is(err, domain.ErrLobbyNotFound) {
+			return err
+		}
+		active, err := u.matchRepo.ListActiveByPlayer(ctx, m.ID)
+		if err != nil {
+			return err
+		}
+		if len(active) > 0 {
+			return domain.ErrAlreadyInMatch
+		}
+	}
+	return nil
+}
+
+func (u *matchmakingUseCase) rate(ctx context.Context, mode domain.GameMode, users []*domain.User) (map[primitive.ObjectID]float64, error) {
+	if u.ratings == nil {
+		return nil, nil
+	}
+	ids := make([]primitive.ObjectID, len(users))
+	for i, user := range users {
+		ids[i] = user.ID
+	}
+	return u.ratings.Ratings(ctx, mode, ids)
+}
+
+// match proposes a match for every group of waiting tickets of mode that
+// are close enough. Tickets another matchmaker took meanwhile are skipped.
+func (u *matchmakingUseCase) match(ctx context.Context, mode domain.GameMode) {
+	waiting, err := u.queue.Waiting(ctx, string(mode))
+	if err != nil {
+		slog.Error("Matchmaking queue can't be read", "mode", mode, "error", err)
+		return
+	}
+
+	now := u.now()
+	for _, group := range matchmaking.Find(waiting, mode.Players(), now, u.cfg) {
+		proposal := matchmaking.Proposal{
+			ID:       primitive.NewObjectID().Hex(),
+			Mode:     string(mode),
+			Tickets:  group,
+			Deadline: now.Add(u.cfg.AcceptTimeout),
+		}
+		err := u.queue.Propose(ctx, proposal)
+		if errors.Is(err, matchmaking.ErrTicketTaken) {
+			continue
+		}
+		if err != nil {
+			slog.Error("Match can't be proposed", "mode", mode, "error", err)
+			return
+		}
+		players := proposal.Players()
+		u.notify(players, domain.EventMatchmakingFound, domain.MatchmakingEvent{
+			ProposalID: proposal.ID, Mode: mode, Players: len(players), Deadline: &proposal.Deadline,
+		})
+	}
+}
+
+// decline drops the proposal userID refused along with userID's party and
+// queues the other tickets again.
+func (u *matchmakingUseCase) decline(ctx context.Context, proposalID string, userID string) error {
+	proposal, err := u.queue.Resolve(ctx, proposalID)
+	if err != nil {
+		return err
+	}
+	for _, t := range proposal.Tickets {
+		if t.Has(userID) {
+			u.notify(t.Players, domain.EventMatchmakingCancelled, domain.MatchmakingEvent{
+				TicketID: t.ID, ProposalID: proposal.ID, Mode: domain.GameMode(t.Mode), Reason: domain.MatchmakingCancelled,
+			})
+			continue
+		}
+		u.requeue(ctx, t)
+	}
+	u.match(ctx, domain.GameMode(proposal.Mode))
+	return nil
+}
+
+// expire resolves proposals nobody finished accepting. Parties that had all
+// accepted keep their place in the queue; the others are dropped.
+func (u *matchmakingUseCase) expire(ctx context.Context) {
+	expired, err := u.queue.Expired(ctx, u.now())
+	if err != nil {
+		slog.Error("Expired match proposals can't be read", "error", err)
+		return
+	}
+	for _, p := range expired {
+		proposal, err := u.queue.Resolve(ctx, p.ID)
+		if err != nil {
+			// Accepted or declined meanwhile.
+			continue
+		}
+		for _, t := range proposal.Tickets {
+			declined := slices.ContainsFunc(t.Players, func(p matchmaking.Player) bool { return !proposal.HasAccepted(p.UserID) })
+			if !declined {
+				u.requeue(ctx, t)
+				continue
+			}
+			u.notify(t.Players, domain.EventMatchmakingCancelled, domain.MatchmakingEvent{
+				TicketID: t.ID, ProposalID: proposal.ID, Mode: domain.GameMode(t.Mode), Reason: domain.MatchmakingTimedOut,
+			})
+		}
+	}
+}
+
+// start starts the match every player of proposal accepted. If it can't
+// start, the tickets are queued again.
+func (u *matchmakingUseCase) start(ctx context.Context, proposal *matchmaking.Proposal) (*matchmaking.Proposal, error) {
+	players := proposal.Players()
+	// Matchmaking pairs players by rating, so its matches are the ranked ones.
+	match := &domain.Match{
+		ID:      primitive.NewObjectID(),
+		Mode:    domain.GameMode(proposal.Mode),
+		Ranked:  true,
+		Players: make([]domain.MatchPlayer, len(players)),
+	}
+	for seat, p := range players {
+		uid, _ := primitive.ObjectIDFromHex(p.UserID)
+		match.Players[seat] = domain.MatchPlayer{UserID: uid, Username: p.Username, DisplayName: p.DisplayName, Seat: seat}
+	}
+	if err := u.matches.Start(ctx, match); err != nil {
+		for _, t := range proposal.Tickets {
+			u.requeue(ctx, t)
+		}
+		return nil, err
+	}
+
+	proposal.MatchID = match.ID.Hex()
+	u.notify(players, domain.EventMatchmakingStarted, domain.MatchmakingEvent{
+		ProposalID: proposal.ID, Mode: domain.GameMode(proposal.Mode), Players: len(players), MatchID: proposal.MatchID,
+	})
+	return proposal, nil
+}
+
+// requeue puts a ticket back with its original QueuedAt, so the party keeps
+// its priority and widened window.
+func (u *matchmakingUseCase) requeue(ctx context.Context, ticket matchmaking.Ticket) {
+	if err := u.queue.Add(ctx, ticket); err != nil {
+		slog.Error("Ticket can't be queued again", "ticket_id", ticket.ID, "error", err)
+		return
+	}
+	u.notify(ticket.Players, domain.EventMatchmakingRequeued, domain.MatchmakingEvent{
+		TicketID: ticket.ID, Mode: domain.GameMode(ticket.Mode), Players: len(ticket.Players),
+	})
+}
+
+func (u *matchmakingUseCase) notify(players []matchmaking.Player, event string, data domain.MatchmakingEvent) {
+	for _, p := range players {
+		u.publisher.Publish(domain.UserTopic(p.UserID), event, data)
+	}
+}
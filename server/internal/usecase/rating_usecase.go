@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/rating"
+	"github.com/Simpolette/HeartSteal/server/internal/tracing"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var _ domain.RatingUsecase = &ratingUseCase{}
+
+type ratingUseCase struct {
+	ratingRepo     domain.RatingRepository
+	userRepo       domain.UserRepository
+	contextTimeout time.Duration
+	now            func() time.Time
+}
+
+func NewRatingUseCase(ratingRepo domain.RatingRepository, userRepo domain.UserRepository, timeout time.Duration) domain.RatingUsecase {
+	return &ratingUseCase{
+		ratingRepo:     ratingRepo,
+		userRepo:       userRepo,
+		contextTimeout: timeout,
+		now:            func() time.Time { return time.Now().UTC() },
+	}
+}
+
+func (u *ratingUseCase) Ratings(c context.Context, mode domain.GameMode, userIDs []primitive.ObjectID) (_ map[primitive.ObjectID]float64, err error) {
+	ctx, span := tracer.Start(c, "ratingUseCase.Ratings")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	ratings, err := u.ratingRepo.ListByUsers(ctx, mode, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[primitive.ObjectID]float64, len(ratings))
+	for _, r := range ratings {
+		res[r.UserID] = r.Rating
+	}
+	return res, nil
+}
+
+// Record runs on the caller's context and deadline, since it is part of
+// their transaction.
+func (u *ratingUseCase) Record(c context.Context, match *domain.Match) (err error) {
+	ctx, span := tracer.Start(c, "ratingUseCase.Record")
+	defer func() { tracing.End(span, err) }()
+
+	if !match.Ranked || len(match.Placements) != len(match.Players) {
+		return nil
+	}
+	playedAt := u.now()
+	if match.FinishedAt != nil {
+		playedAt = *match.FinishedAt
+	}
+
+	ids := make([]primitive.ObjectID, len(match.Players))
+	for i, p := range match.Players {
+		ids[i] = p.UserID
+	}
+	stored, err := u.ratingRepo.ListByUsers(ctx, match.Mode, ids)
+	if err != nil {
+		return err
+	}
+
+	players := make([]domain.PlayerRating, len(match.Players))
+	before := make([]rating.Rating, len(match.Players))
+	placements := make([]int, len(match.Players))
+	for i, p := range match.Players {
+		players[i] = domain.PlayerRating{UserID: p.UserID, Mode: match.Mode, LastPlayedAt: playedAt}
+		before[i] = rating.Default()
+		if j := slices.IndexFunc(stored, func(r domain.PlayerRating) bool { return r.UserID == p.UserID }); j >= 0 {
+			players[i] = stored[j]
+			before[i] = stored[j].Glicko(playedAt)
+		}
+		placements[i] = match.Placements[p.Seat]
+	}
+
+	after := rating.Placements(before, placements, rating.DefaultTau)
+	for i := range players {
+		p, r := &players[i], after[i]
+		p.Username, p.DisplayName = match.Players[i].Username, match.Players[i].DisplayName
+		p.Rating, p.RD, p.Volatility = r.Rating, r.RD, r.Volatility
+		p.Games++
+		p.LastPlayedAt = playedAt
+		if err := u.ratingRepo.Save(ctx, p); err != nil {
+			return err
+		}
+		match.Players[i].RatingChange = r.Rating - before[i].Rating
+	}
+	return nil
+}
+
+func (u *ratingUseCase) Leaderboard(c context.Context, userID string, mode domain.GameMode, scope domain.LeaderboardScope, limit int, cursor string) (_ []domain.LeaderboardEntry, _ string, err error) {
+	ctx, span := tracer.Start(c, "ratingUseCase.Leaderboard")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	if mode.Players() == 0 {
+		return nil, "", domain.ErrUnknownGameMode
+	}
+	after, err := domain.DecodeRankCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	userIDs, err := u.scope(ctx, userID, scope)
+	if err != nil {
+		return nil, "", err
+	}
+	limit = domain.PageLimit(limit)
+
+	// Fetch one extra entry to learn whether there is a next page.
+	ratings, err := u.ratingRepo.ListRanked(ctx, mode, userIDs, after, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+	rank := 1
+	if after != nil {
+		rank = after.Rank + 1
+	}
+	next := ""
+	if len(ratings) > limit {
+		ratings = ratings[:limit]
+		last := ratings[limit-1]
+		next = domain.RankCursor{Rating: last.Rating, UserID: last.UserID, Rank: rank + limit - 1}.Encode()
+	}
+	return u.entries(ratings, rank), next, nil
+}
+
+func (u *ratingUseCase) AroundMe(c context.Context, userID string, mode domain.GameMode, limit int) (_ []domain.LeaderboardEntry, err error) {
+	ctx, span := tracer.Start(c, "ratingUseCase.AroundMe")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	if mode.Players() == 0 {
+		return nil, domain.ErrUnknownGameMode
+	}
+	uid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, domain.ErrNotRanked
+	}
+	me, err := u.ratingRepo.Get(ctx, uid, mode)
+	if err != nil {
+		return nil, err
+	}
+	limit = domain.PageLimit(limit)
+
+	pos := domain.RankCursor{Rating: me.Rating, UserID: me.UserID}
+	ahead, err := u.ratingRepo.CountAhead(ctx, mode, nil, pos)
+	if err != nil {
+		return nil, err
+	}
+	above, err := u.ratingRepo.ListAhead(ctx, mode, nil, pos, limit)
+	if err != nil {
+		return nil, err
+	}
+	below, err := u.ratingRepo.ListRanked(ctx, mode, nil, &pos, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	board := append(append(above, *me), below...)
+	return u.entries(board, ahead+1-len(above)), nil
+}
+
+// scope returns the users on a board, nil meaning everyone.
+func (u *ratingUseCase) scope(ctx context.Context, userID string, scope domain.LeaderboardScope) ([]primitive.ObjectID, error) {
+	switch scope {
+	case "", domain.LeaderboardGlobal:
+		return nil, nil
+	case domain.LeaderboardFriends:
+		user, err := u.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		return append([]primitive.ObjectID{user.ID}, user.FriendsList...), nil
+	}
+	return nil, domain.ErrUnknownLeaderboardScope
+}
+
+// entries ranks ratings from first and shows each RD as of now.
+func (u *ratingUseCase) entries(ratings []domain.PlayerRating, first int) []domain.LeaderboardEntry {
+	now := u.now()
+	entries := make([]domain.LeaderboardEntry, len(ratings))
+	for i, r := range ratings {
+		r.RD = r.Glicko(now).RD
+		entries[i] = domain.LeaderboardEntry{Rank: first + i, PlayerRating: r}
+	}
+	return entries
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/tracing"
+)
+
+var _ domain.RealtimeUsecase = &realtimeUseCase{}
+
+// ticketBytes of randomness make a ticket unguessable within its short life.
+const ticketBytes = 32
+
+type realtimeUseCase struct {
+	ticketRepo     domain.TicketRepository
+	userRepo       domain.UserRepository
+	ticketTTL      time.Duration
+	contextTimeout time.Duration
+	now            func() time.Time
+}
+
+func NewRealtimeUseCase(ticketRepo domain.TicketRepository, userRepo domain.UserRepository, ticketTTL, timeout time.Duration) domain.RealtimeUsecase {
+	return &realtimeUseCase{
+		ticketRepo:     ticketRepo,
+		userRepo:       userRepo,
+		ticketTTL:      ticketTTL,
+		contextTimeout: timeout,
+		now:            func() time.Time { return time.Now().UTC() },
+	}
+}
+
+func (u *realtimeUseCase) IssueTicket(c context.Context, userID string) (_ *domain.Ticket, err error) {
+	ctx, span := tracer.Start(c, "realtimeUseCase.IssueTicket")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	// Access tokens outlive a ban, so check the account before handing out
+	// a connection.
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsBanned() {
+		return nil, domain.ErrUserBanned
+	}
+
+	buf := make([]byte, ticketBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	ticket := &domain.Ticket{
+		Value:     base64.RawURLEncoding.EncodeToString(buf),
+		UserID:    user.ID,
+		ExpiresAt: u.now().Add(u.ticketTTL),
+	}
+	if err := u.ticketRepo.Create(ctx, ticket); err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+func (u *realtimeUseCase) RedeemTicket(c context.Context, value string) (_ string, err error) {
+	ctx, span := tracer.Start(c, "realtimeUseCase.RedeemTicket")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	if value == "" {
+		return "", domain.ErrInvalidTicket
+	}
+	ticket, err := u.ticketRepo.Take(ctx, value, u.now())
+	if err != nil {
+		return "", err
+	}
+	return ticket.UserID.Hex(), nil
+}
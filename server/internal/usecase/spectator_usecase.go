@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/tracing"
+)
+
+var _ domain.SpectatorUsecase = &spectatorUseCase{}
+
+// spectatorUseCase decides who may watch a match; the matches usecase
+// builds what they see.
+type spectatorUseCase struct {
+	matchRepo      domain.MatchRepository
+	userRepo       domain.UserRepository
+	matches        domain.MatchUsecase
+	publisher      domain.Publisher
+	contextTimeout time.Duration
+}
+
+func NewSpectatorUseCase(matchRepo domain.MatchRepository, userRepo domain.UserRepository, matches domain.MatchUsecase, publisher domain.Publisher, timeout time.Duration) domain.SpectatorUsecase {
+	return &spectatorUseCase{
+		matchRepo:      matchRepo,
+		userRepo:       userRepo,
+		matches:        matches,
+		publisher:      publisher,
+		contextTimeout: timeout,
+	}
+}
+
+func (u *spectatorUseCase) SetPolicy(c context.Context, userID string, policy domain.SpectatorPolicy) (err error) {
+	ctx, span := tracer.Start(c, "spectatorUseCase.SetPolicy")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	if !policy.Valid() {
+		return domain.ErrUnknownSpectatorPolicy
+	}
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	user.SpectatorPolicy = policy
+	user.UpdatedAt = time.Now().UTC()
+	if err := u.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	active, err := u.matchRepo.ListActiveByPlayer(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	for i := range active {
+		match := &active[i]
+		topic := domain.SpectateTopic(match.ID.Hex())
+		viewers := u.publisher.Subscribers(topic)
+		if len(viewers) == 0 {
+			continue
+		}
+		players, err := u.players(ctx, match)
+		if err != nil {
+			return err
+		}
+		for _, viewer := range viewers {
+			if !watchable(match, players, viewer) {
+				u.publisher.Unsubscribe(topic, viewer)
+			}
+		}
+	}
+	return nil
+}
+
+func (u *spectatorUseCase) Watch(c context.Context, userID string, matchID string) (_ *domain.SpectatedMatch, err error) {
+	ctx, span := tracer.Start(c, "spectatorUseCase.Watch")
+	defer func() { tracing.End(span, err) }()
+
+	if err := u.CanWatch(ctx, userID, matchID); err != nil {
+		return nil, err
+	}
+	return u.matches.Spectate(ctx, matchID)
+}
+
+func (u *spectatorUseCase) CanWatch(c context.Context, userID string, matchID string) (err error) {
+	ctx, span := tracer.Start(c, "spectatorUseCase.CanWatch")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	match, err := u.matchRepo.GetByID(ctx, matchID)
+	if err != nil {
+		return err
+	}
+	players, err := u.players(ctx, match)
+	if err != nil {
+		return err
+	}
+	if !watchable(match, players, userID) {
+		return domain.ErrSpectatingForbidden
+	}
+	return nil
+}
+
+// players loads the users playing match. Deleted accounts no longer have a
+// say.
+func (u *spectatorUseCase) players(ctx context.Context, match *domain.Match) ([]*domain.User, error) {
+	players := make([]*domain.User, 0, len(match.Players))
+	for _, p := range match.Players {
+		user, err := u.userRepo.GetByID(ctx, p.UserID.Hex())
+		if errors.Is(err, domain.ErrUserNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		players = append(players, user)
+	}
+	return players, nil
+}
+
+// watchable reports whether every player lets viewerID watch match. The
+// players themselves always may.
+func watchable(match *domain.Match, players []*domain.User, viewerID string) bool {
+	id, err := primitive.ObjectIDFromHex(viewerID)
+	if err != nil {
+		return false
+	}
+	if match.Player(id) != nil {
+		return true
+	}
+	for _, p := range players {
+		if !p.AllowsSpectator(id) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,285 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/domain/mocks"
+	"github.com/Simpolette/HeartSteal/server/internal/usecase"
+)
+
+func setupAdmin() (*mocks.MockUserRepository, *mocks.MockSessionRepository, domain.AdminUsecase) {
+	userRepo := new(mocks.MockUserRepository)
+	sessionRepo := new(mocks.MockSessionRepository)
+	return userRepo, sessionRepo, usecase.NewAdminUseCase(userRepo, sessionRepo, 2*time.Second)
+}
+
+func TestAdminUseCase_CreateUser(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		userRepo, _, u := setupAdmin()
+		user := &domain.User{
+			Username: "admin",
+			Email:    "admin@example.com",
+			Password: "correct horse",
+			Roles:    []domain.Role{domain.RoleAdmin},
+		}
+		userRepo.On("GetByEmail", mock.Anything, "admin@example.com").Return(nil, domain.ErrUserNotFound)
+		userRepo.On("GetByUsername", mock.Anything, "admin").Return(nil, domain.ErrUserNotFound)
+		userRepo.On("Create", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+			return u.DisplayName == "admin" &&
+				bcrypt.CompareHashAndPassword([]byte(u.Password), []byte("correct horse")) == nil
+		})).Return(nil)
+
+		err := u.CreateUser(context.Background(), user)
+
+		assert.NoError(t, err)
+		userRepo.AssertExpectations(t)
+	})
+
+	t.Run("ErrorShortPassword", func(t *testing.T) {
+		userRepo, _, u := setupAdmin()
+
+		err := u.CreateUser(context.Background(), &domain.User{Username: "admin", Password: "short"})
+
+		assert.Equal(t, domain.ErrInvalidPassword, err)
+		userRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("ErrorUnknownRole", func(t *testing.T) {
+		_, _, u := setupAdmin()
+
+		err := u.CreateUser(context.Background(), &domain.User{
+			Username: "admin",
+			Password: "correct horse",
+			Roles:    []domain.Role{"root"},
+		})
+
+		assert.Equal(t, domain.ErrInvalidRole, err)
+	})
+
+	t.Run("ErrorUsernameExists", func(t *testing.T) {
+		userRepo, _, u := setupAdmin()
+		userRepo.On("GetByEmail", mock.Anything, "admin@example.com").Return(nil, domain.ErrUserNotFound)
+		userRepo.On("GetByUsername", mock.Anything, "admin").Return(&domain.User{Username: "admin"}, nil)
+
+		err := u.CreateUser(context.Background(), &domain.User{
+			Username: "admin",
+			Email:    "admin@example.com",
+			Password: "correct horse",
+		})
+
+		assert.Equal(t, domain.ErrUsernameExists, err)
+	})
+}
+
+func TestAdminUseCase_ResetPassword(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		userRepo, sessionRepo, u := setupAdmin()
+		user := &domain.User{ID: primitive.NewObjectID(), Username: "alice", Password: "old"}
+		userRepo.On("GetByUsername", mock.Anything, "alice").Return(user, nil)
+		sessionRepo.On("DeleteByUser", mock.Anything, user.ID).Return(int64(2), nil)
+		userRepo.On("Update", mock.Anything, user).Return(nil)
+
+		updated, err := u.ResetPassword(context.Background(), "alice", "new password")
+
+		assert.NoError(t, err)
+		assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(updated.Password), []byte("new password")))
+		assert.Equal(t, 1, updated.TokenVersion, "tokens issued before the reset are revoked")
+		userRepo.AssertExpectations(t)
+		sessionRepo.AssertExpectations(t)
+	})
+
+	t.Run("ErrorUserNotFound", func(t *testing.T) {
+		userRepo, _, u := setupAdmin()
+		userRepo.On("GetByUsername", mock.Anything, "ghost").Return(nil, domain.ErrUserNotFound)
+
+		_, err := u.ResetPassword(context.Background(), "ghost", "new password")
+
+		assert.Equal(t, domain.ErrUserNotFound, err)
+	})
+
+	t.Run("ErrorSaveKeepsSessions", func(t *testing.T) {
+		userRepo, sessionRepo, u := setupAdmin()
+		user := &domain.User{ID: primitive.NewObjectID(), Username: "alice", Password: "old"}
+		userRepo.On("GetByUsername", mock.Anything, "alice").Return(user, nil)
+		userRepo.On("Update", mock.Anything, user).Return(errors.New("db down"))
+
+		_, err := u.ResetPassword(context.Background(), "alice", "new password")
+
+		assert.Error(t, err)
+		sessionRepo.AssertNotCalled(t, "DeleteByUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("ErrorInvalidPassword", func(t *testing.T) {
+		userRepo, _, u := setupAdmin()
+
+		_, err := u.ResetPassword(context.Background(), "alice", "short")
+
+		assert.Equal(t, domain.ErrInvalidPassword, err)
+		userRepo.AssertNotCalled(t, "GetByUsername", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAdminUseCase_GrantRole(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		userRepo, _, u := setupAdmin()
+		user := &domain.User{Username: "alice"}
+		userRepo.On("GetByUsername", mock.Anything, "alice").Return(user, nil)
+		userRepo.On("Update", mock.Anything, user).Return(nil)
+
+		updated, err := u.GrantRole(context.Background(), "alice", domain.RoleModerator)
+
+		assert.NoError(t, err)
+		assert.True(t, updated.HasRole(domain.RoleModerator))
+		userRepo.AssertExpectations(t)
+	})
+
+	t.Run("AlreadyGranted", func(t *testing.T) {
+		userRepo, _, u := setupAdmin()
+		user := &domain.User{Username: "alice", Roles: []domain.Role{domain.RoleModerator}}
+		userRepo.On("GetByUsername", mock.Anything, "alice").Return(user, nil)
+
+		updated, err := u.GrantRole(context.Background(), "alice", domain.RoleModerator)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Role{domain.RoleModerator}, updated.Roles)
+		userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("ErrorInvalidRole", func(t *testing.T) {
+		_, _, u := setupAdmin()
+
+		_, err := u.GrantRole(context.Background(), "alice", "root")
+
+		assert.Equal(t, domain.ErrInvalidRole, err)
+	})
+}
+
+func TestAdminUseCase_RevokeRole(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		userRepo, _, u := setupAdmin()
+		user := &domain.User{Username: "alice", Roles: []domain.Role{domain.RoleModerator, domain.RoleAdmin}}
+		userRepo.On("GetByUsername", mock.Anything, "alice").Return(user, nil)
+		userRepo.On("Update", mock.Anything, user).Return(nil)
+
+		updated, err := u.RevokeRole(context.Background(), "alice", domain.RoleModerator)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Role{domain.RoleAdmin}, updated.Roles)
+		userRepo.AssertExpectations(t)
+	})
+
+	t.Run("ErrorPlayerRole", func(t *testing.T) {
+		_, _, u := setupAdmin()
+
+		_, err := u.RevokeRole(context.Background(), "alice", domain.RolePlayer)
+
+		assert.Equal(t, domain.ErrInvalidRole, err)
+	})
+}
+
+func TestAdminUseCase_Ban(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		userRepo, sessionRepo, u := setupAdmin()
+		user := &domain.User{ID: primitive.NewObjectID(), Username: "alice"}
+		userRepo.On("GetByUsername", mock.Anything, "alice").Return(user, nil)
+		sessionRepo.On("DeleteByUser", mock.Anything, user.ID).Return(int64(1), nil)
+		userRepo.On("Update", mock.Anything, user).Return(nil)
+
+		updated, err := u.Ban(context.Background(), "alice", "cheating")
+
+		assert.NoError(t, err)
+		assert.True(t, updated.IsBanned())
+		assert.Equal(t, "cheating", updated.BanReason)
+		assert.Equal(t, 1, updated.TokenVersion, "tokens issued before the ban are revoked")
+		userRepo.AssertExpectations(t)
+		sessionRepo.AssertExpectations(t)
+	})
+
+	t.Run("KeepsOriginalBanTime", func(t *testing.T) {
+		userRepo, sessionRepo, u := setupAdmin()
+		bannedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		user := &domain.User{Username: "alice", BannedAt: &bannedAt, BanReason: "spam"}
+		userRepo.On("GetByUsername", mock.Anything, "alice").Return(user, nil)
+		sessionRepo.On("DeleteByUser", mock.Anything, user.ID).Return(int64(0), nil)
+		userRepo.On("Update", mock.Anything, user).Return(nil)
+
+		updated, err := u.Ban(context.Background(), "alice", "spam and cheating")
+
+		assert.NoError(t, err)
+		assert.Equal(t, bannedAt, *updated.BannedAt)
+		assert.Equal(t, "spam and cheating", updated.BanReason)
+	})
+
+	t.Run("ErrorSaveKeepsSessions", func(t *testing.T) {
+		userRepo, sessionRepo, u := setupAdmin()
+		user := &domain.User{ID: primitive.NewObjectID(), Username: "alice"}
+		userRepo.On("GetByUsername", mock.Anything, "alice").Return(user, nil)
+		userRepo.On("Update", mock.Anything, user).Return(errors.New("db down"))
+
+		_, err := u.Ban(context.Background(), "alice", "cheating")
+
+		assert.Error(t, err)
+		sessionRepo.AssertNotCalled(t, "DeleteByUser", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAdminUseCase_Unban(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		userRepo, _, u := setupAdmin()
+		bannedAt := time.Now()
+		user := &domain.User{Username: "alice", BannedAt: &bannedAt, BanReason: "spam"}
+		userRepo.On("GetByUsername", mock.Anything, "alice").Return(user, nil)
+		userRepo.On("Update", mock.Anything, user).Return(nil)
+
+		updated, err := u.Unban(context.Background(), "alice")
+
+		assert.NoError(t, err)
+		assert.False(t, updated.IsBanned())
+		assert.Empty(t, updated.BanReason)
+		userRepo.AssertExpectations(t)
+	})
+
+	t.Run("NotBanned", func(t *testing.T) {
+		userRepo, _, u := setupAdmin()
+		userRepo.On("GetByUsername", mock.Anything, "alice").Return(&domain.User{Username: "alice"}, nil)
+
+		_, err := u.Unban(context.Background(), "alice")
+
+		assert.NoError(t, err)
+		userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAdminUseCase_ListSessions(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		userRepo, sessionRepo, u := setupAdmin()
+		user := &domain.User{ID: primitive.NewObjectID(), Username: "alice"}
+		sessions := []domain.Session{{ID: primitive.NewObjectID(), UserID: user.ID}}
+		userRepo.On("GetByUsername", mock.Anything, "alice").Return(user, nil)
+		sessionRepo.On("ListActiveByUser", mock.Anything, user.ID, mock.AnythingOfType("time.Time")).Return(sessions, nil)
+
+		got, err := u.ListSessions(context.Background(), "alice")
+
+		assert.NoError(t, err)
+		assert.Equal(t, sessions, got)
+	})
+
+	t.Run("ErrorUserNotFound", func(t *testing.T) {
+		userRepo, sessionRepo, u := setupAdmin()
+		userRepo.On("GetByUsername", mock.Anything, "ghost").Return(nil, domain.ErrUserNotFound)
+
+		_, err := u.ListSessions(context.Background(), "ghost")
+
+		assert.Equal(t, domain.ErrUserNotFound, err)
+		sessionRepo.AssertNotCalled(t, "ListActiveByUser", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
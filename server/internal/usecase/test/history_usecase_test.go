@@ -0,0 +1,211 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Simpolette/HeartSteal/server/internal/bot"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/domain/mocks"
+	"github.com/Simpolette/HeartSteal/server/internal/usecase"
+)
+
+func setupHistory() (*mocks.MockMatchRepository, *mocks.MockStatsRepository, *mocks.MockUserRepository, domain.HistoryUsecase) {
+	matchRepo := new(mocks.MockMatchRepository)
+	statsRepo := new(mocks.MockStatsRepository)
+	userRepo := new(mocks.MockUserRepository)
+	return matchRepo, statsRepo, userRepo, usecase.NewHistoryUseCase(matchRepo, statsRepo, userRepo, 2*time.Second)
+}
+
+// placedDuel is a finished duel alice won against bob, with each player's
+// placement filled in as finishing a match does.
+func placedDuel(alice, bob *domain.User) *domain.Match {
+	match := finishedDuel(alice, bob)
+	match.Players[0].Placement, match.Players[1].Placement = 1, 2
+	return match
+}
+
+func TestHistoryUseCase_Record(t *testing.T) {
+	alice, bob := lobbyUser("alice"), lobbyUser("bob")
+
+	t.Run("UpdatesBothPlayers", func(t *testing.T) {
+		_, statsRepo, _, u := setupHistory()
+		match := placedDuel(alice, bob)
+		statsRepo.On("Get", mock.Anything, alice.ID).Return(&domain.PlayerStats{
+			UserID: alice.ID, Games: 3, Wins: 1, Losses: 2, Streak: -2, BestWinStreak: 1,
+			ModeGames: map[domain.GameMode]int{domain.ModeTable: 3},
+		}, nil)
+		statsRepo.On("Get", mock.Anything, bob.ID).Return(&domain.PlayerStats{UserID: bob.ID, Games: 2, Wins: 2, Streak: 2, BestWinStreak: 2}, nil)
+		var saved []domain.PlayerStats
+		statsRepo.On("Save", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			saved = append(saved, *args.Get(1).(*domain.PlayerStats))
+		}).Return(nil)
+
+		require.NoError(t, u.Record(context.Background(), match))
+
+		require.Len(t, saved, 2)
+		won, lost := saved[0], saved[1]
+		assert.Equal(t, 4, won.Games)
+		assert.Equal(t, 2, won.Wins)
+		assert.Equal(t, 1, won.Streak, "a win ends a losing streak")
+		assert.Equal(t, 1, won.BestWinStreak)
+		assert.Equal(t, map[domain.GameMode]int{domain.ModeTable: 3, domain.ModeDuel: 1}, won.ModeGames)
+		assert.Equal(t, domain.ModeTable, won.FavoriteMode())
+		assert.Equal(t, match.FinishedAt, won.LastPlayedAt)
+
+		assert.Equal(t, 1, lost.Losses)
+		assert.Equal(t, -1, lost.Streak, "a loss ends a winning streak")
+		assert.Equal(t, 2, lost.BestWinStreak, "the best streak is kept")
+		assert.Equal(t, domain.ModeDuel, lost.FavoriteMode())
+	})
+
+	t.Run("WinStreakGrows", func(t *testing.T) {
+		_, statsRepo, _, u := setupHistory()
+		match := placedDuel(alice, bob)
+		match.Players[1].Placement = 1 // a draw: both share first place
+		statsRepo.On("Get", mock.Anything, alice.ID).Return(&domain.PlayerStats{UserID: alice.ID, Games: 2, Wins: 2, Streak: 2, BestWinStreak: 2}, nil)
+		statsRepo.On("Get", mock.Anything, bob.ID).Return(&domain.PlayerStats{UserID: bob.ID}, nil)
+		statsRepo.On("Save", mock.Anything, mock.MatchedBy(func(s *domain.PlayerStats) bool { return s.UserID == bob.ID })).Return(nil)
+		statsRepo.On("Save", mock.Anything, mock.MatchedBy(func(s *domain.PlayerStats) bool {
+			return s.UserID == alice.ID && s.Streak == 3 && s.BestWinStreak == 3
+		})).Return(nil)
+
+		require.NoError(t, u.Record(context.Background(), match))
+
+		statsRepo.AssertNumberOfCalls(t, "Save", 2)
+	})
+
+	t.Run("BotsAreSkipped", func(t *testing.T) {
+		_, statsRepo, _, u := setupHistory()
+		match := placedDuel(alice, bob)
+		match.Players[1].Bot = bot.Normal
+		statsRepo.On("Get", mock.Anything, alice.ID).Return(&domain.PlayerStats{UserID: alice.ID}, nil)
+		statsRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+		require.NoError(t, u.Record(context.Background(), match))
+
+		statsRepo.AssertNumberOfCalls(t, "Save", 1)
+		statsRepo.AssertNotCalled(t, "Get", mock.Anything, bob.ID)
+	})
+
+	t.Run("UnfinishedIsIgnored", func(t *testing.T) {
+		_, statsRepo, _, u := setupHistory()
+		match := placedDuel(alice, bob)
+		match.Status = domain.MatchAbandoned
+
+		require.NoError(t, u.Record(context.Background(), match))
+
+		statsRepo.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+	})
+
+	t.Run("ErrorSave", func(t *testing.T) {
+		_, statsRepo, _, u := setupHistory()
+		statsRepo.On("Get", mock.Anything, mock.Anything).Return(&domain.PlayerStats{}, nil)
+		statsRepo.On("Save", mock.Anything, mock.Anything).Return(errors.New("write conflict"))
+
+		err := u.Record(context.Background(), placedDuel(alice, bob))
+
+		assert.EqualError(t, err, "write conflict")
+	})
+}
+
+func TestHistoryUseCase_Matches(t *testing.T) {
+	alice, bob := lobbyUser("alice"), lobbyUser("bob")
+	older, newer := placedDuel(alice, bob), placedDuel(alice, bob)
+	earlier := newer.FinishedAt.Add(-time.Hour)
+	older.FinishedAt = &earlier
+
+	t.Run("FirstPage", func(t *testing.T) {
+		matchRepo, _, userRepo, u := setupHistory()
+		userRepo.On("GetByUsername", mock.Anything, "bob").Return(bob, nil)
+		filter := domain.MatchFilter{Mode: domain.ModeDuel}
+		matchRepo.On("ListFinishedByPlayer", mock.Anything, bob.ID, filter, 2, (*domain.Cursor)(nil)).
+			Return([]domain.Match{*newer, *older}, nil)
+
+		entries, next, err := u.Matches(context.Background(), "bob", filter, 1, "")
+
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, newer.ID, entries[0].ID)
+		assert.Equal(t, bob.ID, entries[0].Self.UserID)
+		assert.Equal(t, 2, entries[0].Self.Placement)
+
+		cursor, err := domain.DecodeCursor(next)
+		require.NoError(t, err)
+		assert.Equal(t, newer.ID, cursor.ID)
+		assert.True(t, cursor.Time.Equal(*newer.FinishedAt))
+	})
+
+	t.Run("LastPage", func(t *testing.T) {
+		matchRepo, _, userRepo, u := setupHistory()
+		userRepo.On("GetByUsername", mock.Anything, "alice").Return(alice, nil)
+		after := domain.Cursor{Time: *newer.FinishedAt, ID: newer.ID}
+		ranked := true
+		filter := domain.MatchFilter{Ranked: &ranked, Result: domain.MatchWon}
+		matchRepo.On("ListFinishedByPlayer", mock.Anything, alice.ID, filter, 21, mock.MatchedBy(func(c *domain.Cursor) bool {
+			return c.ID == after.ID && c.Time.Equal(after.Time)
+		})).Return([]domain.Match{*older}, nil)
+
+		entries, next, err := u.Matches(context.Background(), "alice", filter, 0, after.Encode())
+
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, domain.MatchWon, domain.ResultOf(entries[0].Self.Placement))
+		assert.Empty(t, next)
+	})
+
+	t.Run("ErrorUserNotFound", func(t *testing.T) {
+		_, _, userRepo, u := setupHistory()
+		userRepo.On("GetByUsername", mock.Anything, "nobody").Return(nil, domain.ErrUserNotFound)
+
+		_, _, err := u.Matches(context.Background(), "nobody", domain.MatchFilter{}, 10, "")
+
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+
+	t.Run("ErrorUnknownMode", func(t *testing.T) {
+		_, _, _, u := setupHistory()
+
+		_, _, err := u.Matches(context.Background(), "alice", domain.MatchFilter{Mode: "solo"}, 10, "")
+
+		assert.ErrorIs(t, err, domain.ErrUnknownGameMode)
+	})
+
+	t.Run("ErrorInvalidCursor", func(t *testing.T) {
+		_, _, _, u := setupHistory()
+
+		_, _, err := u.Matches(context.Background(), "alice", domain.MatchFilter{}, 10, "not-a-cursor")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+	})
+}
+
+func TestHistoryUseCase_Stats(t *testing.T) {
+	alice := lobbyUser("alice")
+
+	t.Run("Success", func(t *testing.T) {
+		_, statsRepo, userRepo, u := setupHistory()
+		userRepo.On("GetByUsername", mock.Anything, "alice").Return(alice, nil)
+		statsRepo.On("Get", mock.Anything, alice.ID).Return(&domain.PlayerStats{UserID: alice.ID, Games: 4, Wins: 3}, nil)
+
+		stats, err := u.Stats(context.Background(), "alice")
+
+		require.NoError(t, err)
+		assert.Equal(t, 0.75, stats.WinRate())
+	})
+
+	t.Run("ErrorUserNotFound", func(t *testing.T) {
+		_, _, userRepo, u := setupHistory()
+		userRepo.On("GetByUsername", mock.Anything, "nobody").Return(nil, domain.ErrUserNotFound)
+
+		_, err := u.Stats(context.Background(), "nobody")
+
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+}
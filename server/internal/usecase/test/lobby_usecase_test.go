@@ -0,0 +1,696 @@
+package usecase_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Simpolette/HeartSteal/server/internal/bot"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/domain/mocks"
+	"github.com/Simpolette/HeartSteal/server/internal/matchmaking"
+	"github.com/Simpolette/HeartSteal/server/internal/repository/memory"
+	"github.com/Simpolette/HeartSteal/server/internal/usecase"
+)
+
+type lobbyDeps struct {
+	lobbyRepo *mocks.MockLobbyRepository
+	userRepo  *mocks.MockUserRepository
+	matchRepo *mocks.MockMatchRepository
+	matches   *mocks.MockMatchUsecase
+	publisher *mocks.MockPublisher
+	queue     matchmaking.Queue
+}
+
+// setupLobby accepts any event; tests check the ones they care about with
+// AssertCalled. The matches usecase expects no match to start unless a test
+// says so, and nobody is queued for matchmaking until a test queues them.
+func setupLobby() (*lobbyDeps, domain.LobbyUsecase) {
+	deps := &lobbyDeps{
+		lobbyRepo: new(mocks.MockLobbyRepository),
+		userRepo:  new(mocks.MockUserRepository),
+		matchRepo: new(mocks.MockMatchRepository),
+		matches:   new(mocks.MockMatchUsecase),
+		publisher: new(mocks.MockPublisher),
+		queue:     matchmaking.NewMemoryQueue(),
+	}
+	deps.publisher.On("Publish", mock.Anything, mock.Anything, mock.Anything).Maybe()
+	deps.publisher.On("Unsubscribe", mock.Anything, mock.Anything).Maybe()
+	deps.matchRepo.On("ListActiveByPlayer", mock.Anything, mock.Anything).Return([]domain.Match{}, nil).Maybe()
+
+	u := usecase.NewLobbyUseCase(deps.lobbyRepo, deps.userRepo, deps.matchRepo, deps.matches, deps.queue, deps.publisher, 2*time.Second)
+	return deps, u
+}
+
+// queued puts user alone in queue for a duel.
+func queued(t *testing.T, queue matchmaking.Queue, user *domain.User) {
+	t.Helper()
+	require.NoError(t, queue.Add(context.Background(), matchmaking.Ticket{
+		ID:      primitive.NewObjectID().Hex(),
+		Mode:    string(domain.ModeDuel),
+		Players: []matchmaking.Player{{UserID: user.ID.Hex(), Username: user.Username}},
+	}))
+}
+
+func lobbyUser(username string) *domain.User {
+	return &domain.User{ID: primitive.NewObjectID(), Username: username, DisplayName: username}
+}
+
+// lobbyWith returns an open public lobby hosted by the first member.
+func lobbyWith(capacity int, members ...*domain.User) *domain.Lobby {
+	lobby := &domain.Lobby{
+		ID:         primitive.NewObjectID(),
+		Name:       "Friday night",
+		Visibility: domain.LobbyPublic,
+		Status:     domain.LobbyOpen,
+		Capacity:   capacity,
+		InviteCode: "ABC234",
+	}
+	for _, m := range members {
+		lobby.Members = append(lobby.Members, domain.LobbyMember{UserID: m.ID, Username: m.Username})
+	}
+	if len(members) > 0 {
+		lobby.HostID = members[0].ID
+	}
+	return lobby
+}
+
+// addBot seats a bot member in lobby.
+func addBot(lobby *domain.Lobby, username string) primitive.ObjectID {
+	id := primitive.NewObjectID()
+	lobby.Members = append(lobby.Members, domain.LobbyMember{UserID: id, Username: username, Bot: bot.Normal})
+	return id
+}
+
+func TestLobbyUseCase_Create(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		deps, u := setupLobby()
+		host := lobbyUser("host")
+		deps.userRepo.On("GetByID", mock.Anything, host.ID.Hex()).Return(host, nil)
+		deps.lobbyRepo.On("GetByMember", mock.Anything, host.ID).Return(nil, domain.ErrLobbyNotFound)
+		deps.lobbyRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		lobby := &domain.Lobby{Name: "Friday night", Capacity: 4}
+
+		err := u.Create(context.Background(), host.ID.Hex(), lobby)
+
+		require.NoError(t, err)
+		assert.Equal(t, host.ID, lobby.HostID)
+		assert.Equal(t, domain.LobbyPublic, lobby.Visibility)
+		assert.Equal(t, domain.LobbyOpen, lobby.Status)
+		assert.Equal(t, domain.DefaultTurnSeconds, lobby.Settings.TurnSeconds)
+		assert.Len(t, lobby.InviteCode, domain.InviteCodeLength)
+		assert.Len(t, lobby.Members, 1)
+		deps.lobbyRepo.AssertExpectations(t)
+	})
+
+	t.Run("ErrorQueued", func(t *testing.T) {
+		deps, u := setupLobby()
+		host := lobbyUser("host")
+		queued(t, deps.queue, host)
+		deps.userRepo.On("GetByID", mock.Anything, host.ID.Hex()).Return(host, nil)
+		deps.lobbyRepo.On("GetByMember", mock.Anything, host.ID).Return(nil, domain.ErrLobbyNotFound)
+
+		err := u.Create(context.Background(), host.ID.Hex(), &domain.Lobby{Name: "Friday night", Capacity: 4})
+
+		assert.ErrorIs(t, err, matchmaking.ErrAlreadyQueued)
+		deps.lobbyRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("ErrorInMatch", func(t *testing.T) {
+		deps, u := setupLobby()
+		host := lobbyUser("host")
+		deps.userRepo.On("GetByID", mock.Anything, host.ID.Hex()).Return(host, nil)
+		deps.lobbyRepo.On("GetByMember", mock.Anything, host.ID).Return(nil, domain.ErrLobbyNotFound)
+		deps.matchRepo.ExpectedCalls = nil
+		deps.matchRepo.On("ListActiveByPlayer", mock.Anything, host.ID).Return([]domain.Match{*newMatch(host, lobbyUser("guest"))}, nil)
+
+		err := u.Create(context.Background(), host.ID.Hex(), &domain.Lobby{Name: "Friday night", Capacity: 4})
+
+		assert.ErrorIs(t, err, domain.ErrAlreadyInMatch)
+		deps.lobbyRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("RetriesInviteCodeCollision", func(t *testing.T) {
+		deps, u := setupLobby()
+		host := lobbyUser("host")
+		deps.userRepo.On("GetByID", mock.Anything, host.ID.Hex()).Return(host, nil)
+		deps.lobbyRepo.On("GetByMember", mock.Anything, host.ID).Return(nil, domain.ErrLobbyNotFound)
+		deps.lobbyRepo.On("Create", mock.Anything, mock.Anything).Return(domain.ErrInviteCodeTaken).Once()
+		deps.lobbyRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+
+		err := u.Create(context.Background(), host.ID.Hex(), &domain.Lobby{Name: "Friday night", Capacity: 4})
+
+		assert.NoError(t, err)
+		deps.lobbyRepo.AssertNumberOfCalls(t, "Create", 2)
+	})
+
+	t.Run("ErrorAlreadyInLobby", func(t *testing.T) {
+		deps, u := setupLobby()
+		host := lobbyUser("host")
+		deps.userRepo.On("GetByID", mock.Anything, host.ID.Hex()).Return(host, nil)
+		deps.lobbyRepo.On("GetByMember", mock.Anything, host.ID).Return(lobbyWith(4, host), nil)
+
+		err := u.Create(context.Background(), host.ID.Hex(), &domain.Lobby{Name: "Another", Capacity: 4})
+
+		assert.Equal(t, domain.ErrAlreadyInLobby, err)
+		deps.lobbyRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	// Run with -race: every request passes the membership check before any
+	// lobby exists, so only the unique member index stops the extra ones.
+	t.Run("Concurrent", func(t *testing.T) {
+		lobbyRepo := memory.NewLobbyRepository()
+		userRepo := new(mocks.MockUserRepository)
+		publisher := new(mocks.MockPublisher)
+		u := usecase.NewLobbyUseCase(lobbyRepo, userRepo, memory.NewMatchRepository(), new(mocks.MockMatchUsecase), matchmaking.NewMemoryQueue(), publisher, 2*time.Second)
+		host := lobbyUser("host")
+		userRepo.On("GetByID", mock.Anything, host.ID.Hex()).Return(host, nil)
+
+		const requests = 8
+		errs := make(chan error, requests)
+		var wg sync.WaitGroup
+		for range requests {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs <- u.Create(context.Background(), host.ID.Hex(), &domain.Lobby{Name: "Friday night", Capacity: 4})
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		created := 0
+		for err := range errs {
+			if err == nil {
+				created++
+				continue
+			}
+			assert.ErrorIs(t, err, domain.ErrAlreadyInLobby)
+		}
+		assert.Equal(t, 1, created)
+		_, err := lobbyRepo.GetByMember(context.Background(), host.ID)
+		assert.NoError(t, err)
+	})
+}
+
+func TestLobbyUseCase_Get(t *testing.T) {
+	host, stranger := lobbyUser("host"), lobbyUser("stranger")
+
+	t.Run("PrivateHiddenFromStrangers", func(t *testing.T) {
+		deps, u := setupLobby()
+		lobby := lobbyWith(4, host)
+		lobby.Visibility = domain.LobbyPrivate
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil)
+
+		_, err := u.Get(context.Background(), stranger.ID.Hex(), lobby.ID.Hex())
+		assert.Equal(t, domain.ErrLobbyNotFound, err)
+
+		got, err := u.Get(context.Background(), host.ID.Hex(), lobby.ID.Hex())
+		assert.NoError(t, err)
+		assert.Equal(t, lobby.ID, got.ID)
+	})
+}
+
+func TestLobbyUseCase_Current(t *testing.T) {
+	deps, u := setupLobby()
+	host := lobbyUser("host")
+	lobby := lobbyWith(4, host)
+	deps.lobbyRepo.On("GetByMember", mock.Anything, host.ID).Return(lobby, nil)
+
+	got, err := u.Current(context.Background(), host.ID.Hex())
+
+	assert.NoError(t, err)
+	assert.Equal(t, lobby, got)
+}
+
+func TestLobbyUseCase_List(t *testing.T) {
+	t.Run("NextCursor", func(t *testing.T) {
+		deps, u := setupLobby()
+		base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		lobbies := make([]domain.Lobby, 3)
+		for i := range lobbies {
+			lobbies[i] = domain.Lobby{ID: primitive.NewObjectID(), CreatedAt: base.Add(-time.Duration(i) * time.Minute)}
+		}
+		deps.lobbyRepo.On("ListOpenPublic", mock.Anything, 3, (*domain.Cursor)(nil)).Return(lobbies, nil)
+
+		page, next, err := u.List(context.Background(), 2, "")
+
+		require.NoError(t, err)
+		assert.Len(t, page, 2)
+		cursor, err := domain.DecodeCursor(next)
+		require.NoError(t, err)
+		assert.Equal(t, lobbies[1].ID, cursor.ID)
+		assert.True(t, lobbies[1].CreatedAt.Equal(cursor.Time))
+	})
+
+	t.Run("LastPage", func(t *testing.T) {
+		deps, u := setupLobby()
+		deps.lobbyRepo.On("ListOpenPublic", mock.Anything, domain.DefaultPageLimit+1, (*domain.Cursor)(nil)).Return([]domain.Lobby{{}}, nil)
+
+		page, next, err := u.List(context.Background(), 0, "")
+
+		require.NoError(t, err)
+		assert.Len(t, page, 1)
+		assert.Empty(t, next)
+	})
+
+	t.Run("ErrorInvalidCursor", func(t *testing.T) {
+		_, u := setupLobby()
+
+		_, _, err := u.List(context.Background(), 10, "not a cursor")
+
+		assert.Equal(t, domain.ErrInvalidCursor, err)
+	})
+}
+
+func TestLobbyUseCase_Join(t *testing.T) {
+	host, player := lobbyUser("host"), lobbyUser("player")
+
+	t.Run("Success", func(t *testing.T) {
+		deps, u := setupLobby()
+		lobby := lobbyWith(4, host)
+		deps.userRepo.On("GetByID", mock.Anything, player.ID.Hex()).Return(player, nil)
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil)
+		deps.lobbyRepo.On("GetByMember", mock.Anything, player.ID).Return(nil, domain.ErrLobbyNotFound)
+		deps.lobbyRepo.On("Update", mock.Anything, lobby).Return(nil)
+
+		got, err := u.Join(context.Background(), player.ID.Hex(), lobby.ID.Hex())
+
+		require.NoError(t, err)
+		assert.True(t, got.IsMember(player.ID))
+		assert.Equal(t, domain.LobbyOpen, got.Status)
+		deps.lobbyRepo.AssertExpectations(t)
+		deps.publisher.AssertCalled(t, "Publish", domain.LobbyTopic(lobby.ID.Hex()), domain.EventLobbyUpdated, lobby)
+	})
+
+	t.Run("ErrorQueued", func(t *testing.T) {
+		deps, u := setupLobby()
+		lobby := lobbyWith(4, host)
+		queued(t, deps.queue, player)
+		deps.userRepo.On("GetByID", mock.Anything, player.ID.Hex()).Return(player, nil)
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil).Maybe()
+
+		_, err := u.Join(context.Background(), player.ID.Hex(), lobby.ID.Hex())
+
+		assert.ErrorIs(t, err, matchmaking.ErrAlreadyQueued)
+		deps.lobbyRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("ErrorInMatch", func(t *testing.T) {
+		deps, u := setupLobby()
+		lobby := lobbyWith(4, host)
+		deps.userRepo.On("GetByID", mock.Anything, player.ID.Hex()).Return(player, nil)
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil).Maybe()
+		deps.matchRepo.ExpectedCalls = nil
+		deps.matchRepo.On("ListActiveByPlayer", mock.Anything, player.ID).Return([]domain.Match{*newMatch(player, lobbyUser("rival"))}, nil)
+
+		_, err := u.Join(context.Background(), player.ID.Hex(), lobby.ID.Hex())
+
+		assert.ErrorIs(t, err, domain.ErrAlreadyInMatch)
+		deps.lobbyRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("StartsMatchWhenFull", func(t *testing.T) {
+		deps, u := setupLobby()
+		lobby := lobbyWith(2, host)
+		lobby.Settings.TurnSeconds = 20
+		deps.userRepo.On("GetByID", mock.Anything, player.ID.Hex()).Return(player, nil)
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil)
+		deps.lobbyRepo.On("GetByMember", mock.Anything, player.ID).Return(nil, domain.ErrLobbyNotFound)
+		deps.lobbyRepo.On("Update", mock.Anything, lobby).Return(nil)
+		deps.matches.On("Start", mock.Anything, mock.Anything).Return(nil)
+
+		got, err := u.Join(context.Background(), player.ID.Hex(), lobby.ID.Hex())
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.LobbyInGame, got.Status)
+		assert.False(t, got.MatchID.IsZero())
+		match := deps.matches.Calls[0].Arguments.Get(1).(*domain.Match)
+		assert.Equal(t, got.MatchID, match.ID)
+		assert.Equal(t, lobby.ID, match.LobbyID)
+		assert.Equal(t, 20, match.TurnSeconds)
+		assert.Equal(t, []domain.MatchPlayer{
+			{UserID: host.ID, Username: host.Username, Seat: 0},
+			{UserID: player.ID, Username: player.Username, DisplayName: player.DisplayName, Seat: 1},
+		}, match.Players)
+	})
+
+	t.Run("ReopensWhenMatchFails", func(t *testing.T) {
+		deps, u := setupLobby()
+		lobby := lobbyWith(2, host)
+		deps.userRepo.On("GetByID", mock.Anything, player.ID.Hex()).Return(player, nil)
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil)
+		deps.lobbyRepo.On("GetByMember", mock.Anything, player.ID).Return(nil, domain.ErrLobbyNotFound)
+		deps.lobbyRepo.On("Update", mock.Anything, lobby).Return(nil)
+		deps.matches.On("Start", mock.Anything, mock.Anything).Return(domain.ErrInternalServerError)
+
+		_, err := u.Join(context.Background(), player.ID.Hex(), lobby.ID.Hex())
+
+		assert.ErrorIs(t, err, domain.ErrInternalServerError)
+		assert.Equal(t, domain.LobbyOpen, lobby.Status)
+		assert.True(t, lobby.MatchID.IsZero())
+		deps.lobbyRepo.AssertNumberOfCalls(t, "Update", 2)
+	})
+
+	t.Run("ErrorInGame", func(t *testing.T) {
+		deps, u := setupLobby()
+		lobby := lobbyWith(4, host)
+		lobby.Status = domain.LobbyInGame
+		deps.userRepo.On("GetByID", mock.Anything, player.ID.Hex()).Return(player, nil)
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil)
+		deps.lobbyRepo.On("GetByMember", mock.Anything, player.ID).Return(nil, domain.ErrLobbyNotFound)
+
+		_, err := u.Join(context.Background(), player.ID.Hex(), lobby.ID.Hex())
+
+		assert.Equal(t, domain.ErrLobbyInGame, err)
+	})
+
+	t.Run("RetriesOnConflict", func(t *testing.T) {
+		deps, u := setupLobby()
+		deps.userRepo.On("GetByID", mock.Anything, player.ID.Hex()).Return(player, nil)
+		first, second := lobbyWith(4, host), lobbyWith(4, host)
+		second.ID = first.ID
+		deps.lobbyRepo.On("GetByID", mock.Anything, first.ID.Hex()).Return(first, nil).Once()
+		deps.lobbyRepo.On("GetByID", mock.Anything, first.ID.Hex()).Return(second, nil).Once()
+		deps.lobbyRepo.On("GetByMember", mock.Anything, player.ID).Return(nil, domain.ErrLobbyNotFound)
+		deps.lobbyRepo.On("Update", mock.Anything, first).Return(domain.ErrLobbyConflict).Once()
+		deps.lobbyRepo.On("Update", mock.Anything, second).Return(nil).Once()
+
+		got, err := u.Join(context.Background(), player.ID.Hex(), first.ID.Hex())
+
+		require.NoError(t, err)
+		assert.True(t, got.IsMember(player.ID))
+		deps.lobbyRepo.AssertNumberOfCalls(t, "Update", 2)
+	})
+
+	t.Run("ErrorFull", func(t *testing.T) {
+		deps, u := setupLobby()
+		lobby := lobbyWith(1, host)
+		deps.userRepo.On("GetByID", mock.Anything, player.ID.Hex()).Return(player, nil)
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil)
+		deps.lobbyRepo.On("GetByMember", mock.Anything, player.ID).Return(nil, domain.ErrLobbyNotFound)
+
+		_, err := u.Join(context.Background(), player.ID.Hex(), lobby.ID.Hex())
+
+		assert.Equal(t, domain.ErrLobbyFull, err)
+	})
+
+	t.Run("ErrorPrivate", func(t *testing.T) {
+		deps, u := setupLobby()
+		lobby := lobbyWith(4, host)
+		lobby.Visibility = domain.LobbyPrivate
+		deps.userRepo.On("GetByID", mock.Anything, player.ID.Hex()).Return(player, nil)
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil)
+
+		_, err := u.Join(context.Background(), player.ID.Hex(), lobby.ID.Hex())
+
+		assert.Equal(t, domain.ErrLobbyNotFound, err)
+	})
+
+	t.Run("ErrorInAnotherLobby", func(t *testing.T) {
+		deps, u := setupLobby()
+		lobby := lobbyWith(4, host)
+		deps.userRepo.On("GetByID", mock.Anything, player.ID.Hex()).Return(player, nil)
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil)
+		deps.lobbyRepo.On("GetByMember", mock.Anything, player.ID).Return(lobbyWith(4, player), nil)
+
+		_, err := u.Join(context.Background(), player.ID.Hex(), lobby.ID.Hex())
+
+		assert.Equal(t, domain.ErrAlreadyInLobby, err)
+	})
+}
+
+func TestLobbyUseCase_JoinByCode(t *testing.T) {
+	deps, u := setupLobby()
+	host, player := lobbyUser("host"), lobbyUser("player")
+	lobby := lobbyWith(4, host)
+	lobby.Visibility = domain.LobbyPrivate
+	deps.userRepo.On("GetByID", mock.Anything, player.ID.Hex()).Return(player, nil)
+	deps.lobbyRepo.On("GetByInviteCode", mock.Anything, "ABC234").Return(lobby, nil)
+	deps.lobbyRepo.On("GetByMember", mock.Anything, player.ID).Return(nil, domain.ErrLobbyNotFound)
+	deps.lobbyRepo.On("Update", mock.Anything, lobby).Return(nil)
+
+	got, err := u.JoinByCode(context.Background(), player.ID.Hex(), " abc234 ")
+
+	require.NoError(t, err)
+	assert.True(t, got.IsMember(player.ID))
+}
+
+func TestLobbyUseCase_Leave(t *testing.T) {
+	host, player := lobbyUser("host"), lobbyUser("player")
+
+	t.Run("HostMigration", func(t *testing.T) {
+		deps, u := setupLobby()
+		lobby := lobbyWith(4, host, player)
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil)
+		deps.lobbyRepo.On("Update", mock.Anything, lobby).Return(nil)
+
+		got, err := u.Leave(context.Background(), host.ID.Hex(), lobby.ID.Hex())
+
+		require.NoError(t, err)
+		assert.Equal(t, player.ID, got.HostID)
+		assert.False(t, got.IsMember(host.ID))
+	})
+
+	t.Run("BotsDontHost", func(t *testing.T) {
+		deps, u := setupLobby()
+		lobby := lobbyWith(4, host)
+		addBot(lobby, "bot1")
+		lobby.Members = append(lobby.Members, domain.LobbyMember{UserID: player.ID, Username: player.Username})
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil)
+		deps.lobbyRepo.On("Update", mock.Anything, lobby).Return(nil)
+
+		got, err := u.Leave(context.Background(), host.ID.Hex(), lobby.ID.Hex())
+
+		require.NoError(t, err)
+		assert.Equal(t, player.ID, got.HostID)
+		assert.Len(t, got.Members, 2)
+	})
+
+	t.Run("LastPlayerClosesLobby", func(t *testing.T) {
+		deps, u := setupLobby()
+		lobby := lobbyWith(4, host)
+		addBot(lobby, "bot1")
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil)
+		deps.lobbyRepo.On("Delete", mock.Anything, lobby).Return(nil)
+
+		got, err := u.Leave(context.Background(), host.ID.Hex(), lobby.ID.Hex())
+
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+		deps.lobbyRepo.AssertExpectations(t)
+	})
+
+	t.Run("LastMemberClosesLobby", func(t *testing.T) {
+		deps, u := setupLobby()
+		lobby := lobbyWith(4, host)
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil)
+		deps.lobbyRepo.On("Delete", mock.Anything, lobby).Return(nil)
+
+		got, err := u.Leave(context.Background(), host.ID.Hex(), lobby.ID.Hex())
+
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+		deps.lobbyRepo.AssertExpectations(t)
+		topic := domain.LobbyTopic(lobby.ID.Hex())
+		deps.publisher.AssertCalled(t, "Publish", topic, domain.EventLobbyClosed, domain.LobbyEvent{LobbyID: lobby.ID.Hex()})
+		deps.publisher.AssertCalled(t, "Unsubscribe", topic, host.ID.Hex())
+	})
+
+	t.Run("ErrorNotInLobby", func(t *testing.T) {
+		deps, u := setupLobby()
+		lobby := lobbyWith(4, host)
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil)
+
+		_, err := u.Leave(context.Background(), player.ID.Hex(), lobby.ID.Hex())
+
+		assert.Equal(t, domain.ErrNotInLobby, err)
+	})
+
+	t.Run("ErrorInGame", func(t *testing.T) {
+		deps, u := setupLobby()
+		lobby := lobbyWith(2, host, player)
+		lobby.Status, lobby.MatchID = domain.LobbyInGame, primitive.NewObjectID()
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil)
+
+		_, err := u.Leave(context.Background(), host.ID.Hex(), lobby.ID.Hex())
+
+		assert.Equal(t, domain.ErrLobbyInGame, err)
+		assert.True(t, lobby.IsMember(host.ID))
+		deps.lobbyRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		deps.lobbyRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+		deps.publisher.AssertNotCalled(t, "Unsubscribe", mock.Anything, mock.Anything)
+	})
+}
+
+func TestLobbyUseCase_Kick(t *testing.T) {
+	host, player := lobbyUser("host"), lobbyUser("player")
+
+	t.Run("Success", func(t *testing.T) {
+		deps, u := setupLobby()
+		lobby := lobbyWith(4, host, player)
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil)
+		deps.lobbyRepo.On("Update", mock.Anything, lobby).Return(nil)
+
+		got, err := u.Kick(context.Background(), host.ID.Hex(), lobby.ID.Hex(), player.ID.Hex())
+
+		require.NoError(t, err)
+		assert.False(t, got.IsMember(player.ID))
+		assert.Equal(t, host.ID, got.HostID)
+		deps.publisher.AssertCalled(t, "Unsubscribe", domain.LobbyTopic(lobby.ID.Hex()), player.ID.Hex())
+		deps.publisher.AssertCalled(t, "Publish", domain.UserTopic(player.ID.Hex()), domain.EventLobbyKicked, domain.LobbyEvent{LobbyID: lobby.ID.Hex()})
+	})
+
+	t.Run("Bot", func(t *testing.T) {
+		deps, u := setupLobby()
+		lobby := lobbyWith(4, host)
+		botID := addBot(lobby, "bot1")
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil)
+		deps.lobbyRepo.On("Update", mock.Anything, lobby).Return(nil)
+
+		got, err := u.Kick(context.Background(), host.ID.Hex(), lobby.ID.Hex(), botID.Hex())
+
+		require.NoError(t, err)
+		assert.False(t, got.IsMember(botID))
+		deps.publisher.AssertNotCalled(t, "Publish", domain.UserTopic(botID.Hex()), mock.Anything, mock.Anything)
+	})
+
+	t.Run("ErrorNotHost", func(t *testing.T) {
+		deps, u := setupLobby()
+		lobby := lobbyWith(4, host, player)
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil)
+
+		_, err := u.Kick(context.Background(), player.ID.Hex(), lobby.ID.Hex(), host.ID.Hex())
+
+		assert.Equal(t, domain.ErrNotLobbyHost, err)
+		deps.publisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("ErrorKickSelf", func(t *testing.T) {
+		deps, u := setupLobby()
+		lobby := lobbyWith(4, host, player)
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil)
+
+		_, err := u.Kick(context.Background(), host.ID.Hex(), lobby.ID.Hex(), host.ID.Hex())
+
+		assert.Equal(t, domain.ErrCannotKickSelf, err)
+	})
+
+	t.Run("ErrorInGame", func(t *testing.T) {
+		deps, u := setupLobby()
+		lobby := lobbyWith(2, host, player)
+		lobby.Status, lobby.MatchID = domain.LobbyInGame, primitive.NewObjectID()
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil)
+
+		_, err := u.Kick(context.Background(), host.ID.Hex(), lobby.ID.Hex(), player.ID.Hex())
+
+		assert.Equal(t, domain.ErrLobbyInGame, err)
+		assert.True(t, lobby.IsMember(player.ID))
+		deps.lobbyRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		deps.publisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestLobbyUseCase_AddBot(t *testing.T) {
+	host, player := lobbyUser("host"), lobbyUser("player")
+
+	t.Run("Success", func(t *testing.T) {
+		deps, u := setupLobby()
+		lobby := lobbyWith(4, host)
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil)
+		deps.lobbyRepo.On("Update", mock.Anything, lobby).Return(nil)
+
+		got, err := u.AddBot(context.Background(), host.ID.Hex(), lobby.ID.Hex(), bot.Hard)
+
+		require.NoError(t, err)
+		require.Len(t, got.Members, 2)
+		member := got.Members[1]
+		assert.Equal(t, bot.Hard, member.Bot)
+		assert.Equal(t, "bot1", member.Username)
+		assert.Equal(t, "Bot 1 (hard)", member.DisplayName)
+		assert.False(t, member.UserID.IsZero())
+		assert.Equal(t, domain.LobbyOpen, got.Status)
+		deps.publisher.AssertCalled(t, "Publish", domain.LobbyTopic(lobby.ID.Hex()), domain.EventLobbyUpdated, lobby)
+	})
+
+	t.Run("NamesAreUnique", func(t *testing.T) {
+		deps, u := setupLobby()
+		lobby := lobbyWith(5, host)
+		addBot(lobby, "bot2")
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil)
+		deps.lobbyRepo.On("Update", mock.Anything, lobby).Return(nil)
+
+		for _, want := range []string{"bot1", "bot3"} {
+			got, err := u.AddBot(context.Background(), host.ID.Hex(), lobby.ID.Hex(), bot.Easy)
+
+			require.NoError(t, err)
+			assert.Equal(t, want, got.Members[len(got.Members)-1].Username)
+		}
+	})
+
+	t.Run("StartsMatchWhenFull", func(t *testing.T) {
+		deps, u := setupLobby()
+		lobby := lobbyWith(2, host)
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil)
+		deps.lobbyRepo.On("Update", mock.Anything, lobby).Return(nil)
+		deps.matches.On("Start", mock.Anything, mock.Anything).Return(nil)
+
+		got, err := u.AddBot(context.Background(), host.ID.Hex(), lobby.ID.Hex(), bot.Easy)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.LobbyInGame, got.Status)
+		match := deps.matches.Calls[0].Arguments.Get(1).(*domain.Match)
+		assert.Equal(t, got.MatchID, match.ID)
+		require.Len(t, match.Players, 2)
+		assert.Empty(t, match.Players[0].Bot)
+		assert.Equal(t, bot.Easy, match.Players[1].Bot)
+		assert.Equal(t, got.Members[1].UserID, match.Players[1].UserID)
+	})
+
+	t.Run("ErrorNotHost", func(t *testing.T) {
+		deps, u := setupLobby()
+		lobby := lobbyWith(4, host, player)
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil)
+
+		_, err := u.AddBot(context.Background(), player.ID.Hex(), lobby.ID.Hex(), bot.Normal)
+
+		assert.Equal(t, domain.ErrNotLobbyHost, err)
+		deps.lobbyRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("ErrorFull", func(t *testing.T) {
+		deps, u := setupLobby()
+		lobby := lobbyWith(2, host, player)
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil)
+
+		_, err := u.AddBot(context.Background(), host.ID.Hex(), lobby.ID.Hex(), bot.Normal)
+
+		assert.Equal(t, domain.ErrLobbyFull, err)
+	})
+
+	t.Run("ErrorInGame", func(t *testing.T) {
+		deps, u := setupLobby()
+		lobby := lobbyWith(4, host, player)
+		lobby.Status = domain.LobbyInGame
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil)
+
+		_, err := u.AddBot(context.Background(), host.ID.Hex(), lobby.ID.Hex(), bot.Normal)
+
+		assert.Equal(t, domain.ErrLobbyInGame, err)
+	})
+
+	t.Run("ErrorUnknownLevel", func(t *testing.T) {
+		deps, u := setupLobby()
+
+		_, err := u.AddBot(context.Background(), host.ID.Hex(), primitive.NewObjectID().Hex(), "expert")
+
+		assert.ErrorIs(t, err, bot.ErrUnknownLevel)
+		deps.lobbyRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+}
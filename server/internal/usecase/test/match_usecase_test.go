@@ -0,0 +1,938 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Simpolette/HeartSteal/server/internal/bot"
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/domain/mocks"
+	"github.com/Simpolette/HeartSteal/server/internal/game"
+	"github.com/Simpolette/HeartSteal/server/internal/repository/memory"
+	"github.com/Simpolette/HeartSteal/server/internal/usecase"
+)
+
+// matchDeps are the match usecase's dependencies. A test sets the ones it
+// needs to control before calling setupMatch, which fills in the rest.
+type matchDeps struct {
+	cfg       usecase.MatchConfig
+	matchRepo *mocks.MockMatchRepository
+	lobbyRepo *mocks.MockLobbyRepository
+	ratings   *mocks.MockRatingUsecase
+	publisher *mocks.MockPublisher
+}
+
+// setupMatch runs transactions straight through. Ratings and stats are
+// accepted without being checked, any event is accepted and nobody
+// spectates; expectations a test set on its own mocks come first, so they
+// win over these.
+func setupMatch(deps matchDeps) (*matchDeps, domain.MatchUsecase) {
+	if deps.matchRepo == nil {
+		deps.matchRepo = new(mocks.MockMatchRepository)
+	}
+	if deps.lobbyRepo == nil {
+		deps.lobbyRepo = new(mocks.MockLobbyRepository)
+	}
+	if deps.ratings == nil {
+		deps.ratings = new(mocks.MockRatingUsecase)
+	}
+	if deps.publisher == nil {
+		deps.publisher = new(mocks.MockPublisher)
+	}
+	deps.matchRepo.On("RenewLease", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	deps.ratings.On("Record", mock.Anything, mock.Anything).Return(nil).Maybe()
+	deps.publisher.On("Publish", mock.Anything, mock.Anything, mock.Anything).Maybe()
+	deps.publisher.On("Subscribers", mock.Anything).Return([]string{}).Maybe()
+	tx := new(mocks.MockTransactor)
+	tx.On("WithTransaction", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).Maybe()
+	history := new(mocks.MockHistoryUsecase)
+	history.On("Record", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	u := usecase.NewMatchUseCase(deps.matchRepo, deps.lobbyRepo, deps.ratings, history, tx, deps.publisher, deps.cfg, 2*time.Second)
+	return &deps, u
+}
+
+// playOut plays the first legal move until match is over and returns the
+// final state.
+func playOut(t *testing.T, u domain.MatchUsecase, match *domain.Match, users ...*domain.User) *game.State {
+	t.Helper()
+	s := expectedGame(t, match)
+	for !s.Over {
+		action := game.LegalActions(s)[0]
+		_, err := u.Act(context.Background(), users[s.Turn].ID.Hex(), match.ID.Hex(), game.Action{Card: action.Card, Target: action.Target})
+		require.NoError(t, err)
+		_, err = game.Apply(s, action)
+		require.NoError(t, err)
+	}
+	return s
+}
+
+// newMatch seats the users in order.
+func newMatch(users ...*domain.User) *domain.Match {
+	match := &domain.Match{ID: primitive.NewObjectID(), Seed: 42, TurnSeconds: 30}
+	for seat, u := range users {
+		match.Players = append(match.Players, domain.MatchPlayer{UserID: u.ID, Username: u.Username, Seat: seat})
+	}
+	return match
+}
+
+// startMatch starts match on u; the result is saved but not checked.
+func startMatch(t *testing.T, matchRepo *mocks.MockMatchRepository, u domain.MatchUsecase, match *domain.Match) {
+	t.Helper()
+	matchRepo.On("Create", mock.Anything, match).Return(nil)
+	matchRepo.On("Finish", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	require.NoError(t, u.Start(context.Background(), match))
+}
+
+// expectedGame deals the game match will play, to know whose turn it is.
+func expectedGame(t *testing.T, match *domain.Match) *game.State {
+	t.Helper()
+	s, _, err := game.New(game.Config{Players: len(match.Players)}, match.Seed)
+	require.NoError(t, err)
+	return s
+}
+
+func TestMatchUseCase_Start(t *testing.T) {
+	alice, bob := lobbyUser("alice"), lobbyUser("bob")
+
+	t.Run("Success", func(t *testing.T) {
+		deps, u := setupMatch(matchDeps{})
+		match := newMatch(alice, bob)
+		match.Seed = 0
+
+		startMatch(t, deps.matchRepo, u, match)
+
+		assert.Equal(t, domain.MatchActive, match.Status)
+		assert.NotZero(t, match.Seed)
+		assert.False(t, match.CreatedAt.IsZero())
+		assert.NotEmpty(t, match.Owner)
+		assert.Equal(t, match.CreatedAt.Add(usecase.DefaultMatchLease), match.LeaseExpiresAt)
+		assert.Eventually(t, func() bool {
+			return deps.publisher.AssertCalled(&testing.T{}, "Publish", domain.MatchTopic(match.ID.Hex()), domain.EventMatchUpdated, mock.Anything) &&
+				deps.publisher.AssertCalled(&testing.T{}, "Publish", domain.UserTopic(alice.ID.Hex()), domain.EventMatchPrivate, mock.Anything) &&
+				deps.publisher.AssertCalled(&testing.T{}, "Publish", domain.UserTopic(bob.ID.Hex()), domain.EventMatchPrivate, mock.Anything)
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("StopsWhenLeaseLost", func(t *testing.T) {
+		matchRepo := memory.NewMatchRepository()
+		publisher := new(mocks.MockPublisher)
+		publisher.On("Publish", mock.Anything, mock.Anything, mock.Anything).Maybe()
+		publisher.On("Subscribers", mock.Anything).Return([]string{}).Maybe()
+		u := usecase.NewMatchUseCase(matchRepo, new(mocks.MockLobbyRepository), new(mocks.MockRatingUsecase), new(mocks.MockHistoryUsecase),
+			memory.NewTransactor(), publisher, usecase.MatchConfig{Lease: 30 * time.Millisecond}, 2*time.Second)
+		match := newMatch(alice, bob)
+		require.NoError(t, u.Start(context.Background(), match))
+
+		// Another instance abandons the match, as if this one had stalled.
+		abandoned := *match
+		abandoned.Status = domain.MatchAbandoned
+		require.NoError(t, matchRepo.Update(context.Background(), &abandoned))
+
+		assert.Eventually(t, func() bool {
+			got, view, err := u.Get(context.Background(), alice.ID.Hex(), match.ID.Hex())
+			return err == nil && view == nil && got.Status == domain.MatchAbandoned
+		}, time.Second, 10*time.Millisecond, "the actor stops and the stored record stands")
+	})
+
+	t.Run("DefaultTurnSeconds", func(t *testing.T) {
+		deps, u := setupMatch(matchDeps{})
+		match := newMatch(alice, bob)
+		match.TurnSeconds = 0
+
+		startMatch(t, deps.matchRepo, u, match)
+
+		assert.Equal(t, domain.DefaultTurnSeconds, match.TurnSeconds)
+	})
+
+	t.Run("BotsPlayToTheEnd", func(t *testing.T) {
+		deps, u := setupMatch(matchDeps{})
+		match := newMatch(alice, bob)
+		for i := range match.Players {
+			match.Players[i].Bot = bot.Hard
+		}
+
+		startMatch(t, deps.matchRepo, u, match)
+
+		assert.Eventually(t, func() bool {
+			return deps.matchRepo.AssertCalled(&testing.T{}, "Finish", mock.Anything, mock.MatchedBy(func(m *domain.Match) bool {
+				return m.Status == domain.MatchFinished
+			}), mock.Anything)
+		}, time.Second, 10*time.Millisecond)
+		deps.publisher.AssertNotCalled(t, "Publish", domain.MatchTopic(match.ID.Hex()), domain.EventMatchUpdated,
+			mock.MatchedBy(func(update domain.MatchUpdate) bool {
+				return slices.ContainsFunc(update.Events, func(e game.Event) bool { return e.Auto })
+			}))
+	})
+
+	t.Run("ErrorUnknownBotLevel", func(t *testing.T) {
+		deps, u := setupMatch(matchDeps{})
+		match := newMatch(alice, bob)
+		match.Players[1].Bot = "grandmaster"
+
+		err := u.Start(context.Background(), match)
+
+		assert.ErrorIs(t, err, bot.ErrUnknownLevel)
+		deps.matchRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("BotAnswersPlayer", func(t *testing.T) {
+		deps, u := setupMatch(matchDeps{})
+		match := newMatch(alice, bob)
+		match.Players[1].Bot = bot.Normal
+		startMatch(t, deps.matchRepo, u, match)
+		aliceTurn := func() bool {
+			_, view, err := u.Get(context.Background(), alice.ID.Hex(), match.ID.Hex())
+			return err == nil && view != nil && view.Turn == 0
+		}
+
+		require.Eventually(t, aliceTurn, time.Second, 10*time.Millisecond)
+		_, view, err := u.Get(context.Background(), alice.ID.Hex(), match.ID.Hex())
+		require.NoError(t, err)
+		card := view.Players[0].Hand[0]
+		_, err = u.Act(context.Background(), alice.ID.Hex(), match.ID.Hex(), game.Action{Card: card.ID, Target: 1})
+		require.NoError(t, err)
+
+		assert.Eventually(t, aliceTurn, time.Second, 10*time.Millisecond, "the bot plays its turn")
+		_, view, err = u.Get(context.Background(), alice.ID.Hex(), match.ID.Hex())
+		require.NoError(t, err)
+		assert.Greater(t, view.TurnNumber, 2)
+	})
+
+	t.Run("ErrorTooFewPlayers", func(t *testing.T) {
+		deps, u := setupMatch(matchDeps{})
+
+		err := u.Start(context.Background(), newMatch(alice))
+
+		assert.ErrorIs(t, err, game.ErrInvalidConfig)
+		deps.matchRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestMatchUseCase_Get(t *testing.T) {
+	alice, bob, eve := lobbyUser("alice"), lobbyUser("bob"), lobbyUser("eve")
+
+	t.Run("PlayerSeesOwnHand", func(t *testing.T) {
+		deps, u := setupMatch(matchDeps{})
+		match := newMatch(alice, bob)
+		startMatch(t, deps.matchRepo, u, match)
+		dealt := expectedGame(t, match)
+
+		got, view, err := u.Get(context.Background(), bob.ID.Hex(), match.ID.Hex())
+
+		require.NoError(t, err)
+		assert.Equal(t, match.ID, got.ID)
+		require.NotNil(t, view)
+		assert.Equal(t, 1, view.Seat)
+		assert.Equal(t, dealt.Players[1].Hand, view.Players[1].Hand)
+		assert.Nil(t, view.Players[0].Hand)
+	})
+
+	t.Run("ErrorNotAPlayer", func(t *testing.T) {
+		deps, u := setupMatch(matchDeps{})
+		match := newMatch(alice, bob)
+		startMatch(t, deps.matchRepo, u, match)
+
+		_, _, err := u.Get(context.Background(), eve.ID.Hex(), match.ID.Hex())
+
+		assert.Equal(t, domain.ErrMatchNotFound, err)
+	})
+
+	t.Run("FinishedMatch", func(t *testing.T) {
+		deps, u := setupMatch(matchDeps{})
+		match := newMatch(alice, bob)
+		match.Status = domain.MatchFinished
+		deps.matchRepo.On("GetByID", mock.Anything, match.ID.Hex()).Return(match, nil)
+
+		got, view, err := u.Get(context.Background(), alice.ID.Hex(), match.ID.Hex())
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.MatchFinished, got.Status)
+		assert.Nil(t, view)
+	})
+
+	t.Run("ErrorInvalidID", func(t *testing.T) {
+		_, u := setupMatch(matchDeps{})
+
+		_, _, err := u.Get(context.Background(), alice.ID.Hex(), "nope")
+
+		assert.Equal(t, domain.ErrMatchNotFound, err)
+	})
+}
+
+func TestMatchUseCase_Act(t *testing.T) {
+	alice, bob := lobbyUser("alice"), lobbyUser("bob")
+	users := []*domain.User{alice, bob}
+
+	t.Run("Success", func(t *testing.T) {
+		deps, u := setupMatch(matchDeps{})
+		match := newMatch(alice, bob)
+		startMatch(t, deps.matchRepo, u, match)
+		s := expectedGame(t, match)
+		action := game.LegalActions(s)[0]
+
+		view, err := u.Act(context.Background(), users[s.Turn].ID.Hex(), match.ID.Hex(), game.Action{Card: action.Card, Target: action.Target})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, view.TurnNumber)
+		assert.NotEqual(t, s.Turn, view.Turn)
+	})
+
+	t.Run("ErrorNotYourTurn", func(t *testing.T) {
+		deps, u := setupMatch(matchDeps{})
+		match := newMatch(alice, bob)
+		startMatch(t, deps.matchRepo, u, match)
+		s := expectedGame(t, match)
+		waiting := users[1-s.Turn]
+		card := s.Players[1-s.Turn].Hand[0]
+
+		// Acting for another seat isn't possible: the seat is the caller's.
+		_, err := u.Act(context.Background(), waiting.ID.Hex(), match.ID.Hex(), game.Action{Seat: s.Turn, Card: card.ID, Target: s.Turn})
+
+		assert.ErrorIs(t, err, game.ErrNotYourTurn)
+	})
+
+	t.Run("ErrorMatchOver", func(t *testing.T) {
+		deps, u := setupMatch(matchDeps{})
+		match := newMatch(alice, bob)
+		match.Status = domain.MatchFinished
+		deps.matchRepo.On("GetByID", mock.Anything, match.ID.Hex()).Return(match, nil)
+
+		_, err := u.Act(context.Background(), alice.ID.Hex(), match.ID.Hex(), game.Action{})
+
+		assert.ErrorIs(t, err, game.ErrGameOver)
+	})
+
+	t.Run("PlaysToTheEnd", func(t *testing.T) {
+		deps, u := setupMatch(matchDeps{})
+		lobby := lobbyWith(2, alice, bob)
+		lobby.Status = domain.LobbyInGame
+		match := newMatch(alice, bob)
+		match.LobbyID, lobby.MatchID = lobby.ID, match.ID
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil)
+		deps.lobbyRepo.On("Delete", mock.Anything, lobby).Return(nil)
+		startMatch(t, deps.matchRepo, u, match)
+		s := playOut(t, u, match, users...)
+
+		assert.Eventually(t, func() bool {
+			return deps.lobbyRepo.AssertCalled(&testing.T{}, "Delete", mock.Anything, lobby)
+		}, time.Second, 10*time.Millisecond)
+		deps.matchRepo.AssertCalled(t, "Finish", mock.Anything, mock.MatchedBy(func(m *domain.Match) bool {
+			return m.Status == domain.MatchFinished && m.FinishedAt != nil && assert.ObjectsAreEqual(s.Placements, m.Placements)
+		}), mock.Anything)
+		deps.publisher.AssertCalled(t, "Publish", domain.LobbyTopic(lobby.ID.Hex()), domain.EventLobbyClosed, domain.LobbyEvent{LobbyID: lobby.ID.Hex()})
+
+		deps.matchRepo.On("GetByID", mock.Anything, match.ID.Hex()).Return(match, nil)
+		_, err := u.Act(context.Background(), alice.ID.Hex(), match.ID.Hex(), game.Action{})
+		assert.ErrorIs(t, err, game.ErrGameOver)
+	})
+
+	t.Run("RanksResult", func(t *testing.T) {
+		ratings := new(mocks.MockRatingUsecase)
+		ratings.On("Record", mock.Anything, mock.MatchedBy(func(m *domain.Match) bool {
+			return m.Ranked && m.Status == domain.MatchFinished && len(m.Placements) == 2
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Match).Players[0].RatingChange = 12
+		}).Return(nil)
+		deps, u := setupMatch(matchDeps{ratings: ratings})
+		match := newMatch(alice, bob)
+		match.Mode, match.Ranked = domain.ModeDuel, true
+		startMatch(t, deps.matchRepo, u, match)
+
+		playOut(t, u, match, users...)
+
+		assert.Eventually(t, func() bool {
+			return deps.matchRepo.AssertCalled(&testing.T{}, "Finish", mock.Anything, mock.MatchedBy(func(m *domain.Match) bool {
+				return m.Status == domain.MatchFinished && m.Players[0].RatingChange == 12 &&
+					m.Players[0].Placement == m.Placements[0] && m.Players[1].Placement == m.Placements[1]
+			}), mock.Anything)
+		}, time.Second, 10*time.Millisecond)
+		assert.Zero(t, match.Players[0].RatingChange, "the running match is left alone")
+	})
+
+	t.Run("RatingFailureSavesUnratedResult", func(t *testing.T) {
+		ratings := new(mocks.MockRatingUsecase)
+		ratings.On("Record", mock.Anything, mock.Anything).Return(errors.New("write conflict"))
+		deps, u := setupMatch(matchDeps{ratings: ratings})
+		match := newMatch(alice, bob)
+		match.Mode, match.Ranked = domain.ModeDuel, true
+		startMatch(t, deps.matchRepo, u, match)
+
+		playOut(t, u, match, users...)
+
+		assert.Eventually(t, func() bool {
+			return deps.matchRepo.AssertCalled(&testing.T{}, "Finish", mock.Anything, mock.MatchedBy(func(m *domain.Match) bool {
+				return m.Status == domain.MatchFinished
+			}), mock.Anything)
+		}, time.Second, 10*time.Millisecond)
+		deps.matchRepo.AssertNumberOfCalls(t, "Finish", 1)
+	})
+
+	t.Run("AbandonedMeanwhileSavesNothing", func(t *testing.T) {
+		deps, u := setupMatch(matchDeps{})
+		lobby := lobbyWith(2, alice, bob)
+		match := newMatch(alice, bob)
+		match.LobbyID, lobby.MatchID = lobby.ID, match.ID
+		deps.matchRepo.On("Finish", mock.Anything, mock.Anything, mock.Anything).Return(domain.ErrMatchNotFound)
+		startMatch(t, deps.matchRepo, u, match)
+		abandoned := *match
+		abandoned.Status = domain.MatchAbandoned
+		deps.matchRepo.On("GetByID", mock.Anything, match.ID.Hex()).Return(&abandoned, nil)
+
+		playOut(t, u, match, users...)
+
+		assert.Eventually(t, func() bool {
+			got, view, err := u.Get(context.Background(), alice.ID.Hex(), match.ID.Hex())
+			return err == nil && view == nil && got.Status == domain.MatchAbandoned
+		}, time.Second, 10*time.Millisecond)
+		deps.matchRepo.AssertNumberOfCalls(t, "Finish", 1)
+		deps.lobbyRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("AutoPlaysOnTimeout", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("waits for a turn to time out")
+		}
+		deps, u := setupMatch(matchDeps{})
+		match := newMatch(alice, bob)
+		match.TurnSeconds = 1
+		startMatch(t, deps.matchRepo, u, match)
+		s := expectedGame(t, match)
+		auto := game.AutoAction(s)
+
+		assert.Eventually(t, func() bool {
+			return deps.publisher.AssertCalled(&testing.T{}, "Publish", domain.MatchTopic(match.ID.Hex()), domain.EventMatchUpdated,
+				mock.MatchedBy(func(update domain.MatchUpdate) bool {
+					e := update.Events[0]
+					return e.Type == game.EventCardPlayed && e.Auto && e.Seat == auto.Seat && e.Card.ID == auto.Card
+				}))
+		}, 3*time.Second, 20*time.Millisecond)
+
+		_, view, err := u.Get(context.Background(), alice.ID.Hex(), match.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, 2, view.TurnNumber)
+	})
+}
+
+func TestMatchUseCase_SetConnected(t *testing.T) {
+	alice, bob, eve := lobbyUser("alice"), lobbyUser("bob"), lobbyUser("eve")
+	users := []*domain.User{alice, bob}
+
+	// held starts match with cfg's grace period and policy.
+	held := func(t *testing.T, cfg usecase.MatchConfig, match *domain.Match) (*mocks.MockMatchRepository, *mocks.MockPublisher, domain.MatchUsecase) {
+		deps, u := setupMatch(matchDeps{cfg: cfg})
+		startMatch(t, deps.matchRepo, u, match)
+		return deps.matchRepo, deps.publisher, u
+	}
+
+	t.Run("HoldsSeat", func(t *testing.T) {
+		match := newMatch(alice, bob)
+		_, publisher, u := held(t, usecase.MatchConfig{ReconnectGrace: time.Hour}, match)
+
+		require.NoError(t, u.SetConnected(context.Background(), alice.ID.Hex(), match.ID.Hex(), false))
+
+		publisher.AssertCalled(t, "Publish", domain.MatchTopic(match.ID.Hex()), domain.EventMatchPresence,
+			mock.MatchedBy(func(p domain.MatchPresence) bool {
+				return p.Seat == 0 && !p.Connected && !p.Bot && p.GraceDeadline != nil &&
+					p.GraceDeadline.After(time.Now().Add(59*time.Minute))
+			}))
+		got, err := u.Resume(context.Background(), bob.ID.Hex(), match.ID.Hex(), 0)
+		require.NoError(t, err)
+		assert.False(t, got.Presence[0].Connected)
+		assert.True(t, got.Presence[1].Connected)
+
+		require.NoError(t, u.SetConnected(context.Background(), alice.ID.Hex(), match.ID.Hex(), true))
+
+		publisher.AssertCalled(t, "Publish", domain.MatchTopic(match.ID.Hex()), domain.EventMatchPresence,
+			domain.MatchPresence{MatchID: match.ID.Hex(), SeatPresence: domain.SeatPresence{Seat: 0, Connected: true}})
+	})
+
+	t.Run("ReconnectingInTimeKeepsSeat", func(t *testing.T) {
+		match := newMatch(alice, bob)
+		_, _, u := held(t, usecase.MatchConfig{ReconnectGrace: 50 * time.Millisecond}, match)
+
+		require.NoError(t, u.SetConnected(context.Background(), alice.ID.Hex(), match.ID.Hex(), false))
+		require.NoError(t, u.SetConnected(context.Background(), alice.ID.Hex(), match.ID.Hex(), true))
+		time.Sleep(150 * time.Millisecond)
+
+		_, view, err := u.Get(context.Background(), alice.ID.Hex(), match.ID.Hex())
+		require.NoError(t, err)
+		require.NotNil(t, view, "the match is still running")
+		assert.False(t, view.Players[0].Out)
+	})
+
+	t.Run("ForfeitsAfterGrace", func(t *testing.T) {
+		match := newMatch(alice, bob)
+		matchRepo, _, u := held(t, usecase.MatchConfig{ReconnectGrace: 50 * time.Millisecond, DisconnectPolicy: domain.ForfeitOnDisconnect}, match)
+
+		require.NoError(t, u.SetConnected(context.Background(), alice.ID.Hex(), match.ID.Hex(), false))
+
+		assert.Eventually(t, func() bool {
+			return matchRepo.AssertCalled(&testing.T{}, "Finish", mock.Anything, mock.MatchedBy(func(m *domain.Match) bool {
+				return m.Status == domain.MatchFinished && assert.ObjectsAreEqual([]int{2, 1}, m.Placements)
+			}), mock.Anything)
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("BotPlaysUntilReturn", func(t *testing.T) {
+		match := newMatch(alice, bob)
+		_, publisher, u := held(t, usecase.MatchConfig{ReconnectGrace: 50 * time.Millisecond, DisconnectPolicy: domain.BotOnDisconnect}, match)
+		s := expectedGame(t, match)
+		away := users[s.Turn]
+
+		require.NoError(t, u.SetConnected(context.Background(), away.ID.Hex(), match.ID.Hex(), false))
+
+		assert.Eventually(t, func() bool {
+			return publisher.AssertCalled(&testing.T{}, "Publish", domain.MatchTopic(match.ID.Hex()), domain.EventMatchUpdated,
+				mock.MatchedBy(func(update domain.MatchUpdate) bool {
+					e := update.Events[0]
+					return e.Type == game.EventCardPlayed && e.Auto && e.Seat == s.Turn
+				}))
+		}, time.Second, 10*time.Millisecond, "the bot plays its turn at once")
+		publisher.AssertCalled(t, "Publish", domain.MatchTopic(match.ID.Hex()), domain.EventMatchPresence,
+			mock.MatchedBy(func(p domain.MatchPresence) bool { return p.Seat == s.Turn && p.Bot && p.GraceDeadline == nil }))
+
+		require.NoError(t, u.SetConnected(context.Background(), away.ID.Hex(), match.ID.Hex(), true))
+		got, err := u.Resume(context.Background(), away.ID.Hex(), match.ID.Hex(), 0)
+		require.NoError(t, err)
+		assert.False(t, got.Presence[s.Turn].Bot)
+		assert.False(t, got.State.Players[s.Turn].Out)
+	})
+
+	t.Run("FinishedMatch", func(t *testing.T) {
+		deps, u := setupMatch(matchDeps{})
+		match := finishedDuel(alice, bob)
+		deps.matchRepo.On("GetByID", mock.Anything, match.ID.Hex()).Return(match, nil)
+
+		assert.NoError(t, u.SetConnected(context.Background(), alice.ID.Hex(), match.ID.Hex(), false))
+	})
+
+	t.Run("ErrorNotAPlayer", func(t *testing.T) {
+		match := newMatch(alice, bob)
+		_, _, u := held(t, usecase.MatchConfig{ReconnectGrace: time.Hour}, match)
+
+		err := u.SetConnected(context.Background(), eve.ID.Hex(), match.ID.Hex(), false)
+
+		assert.ErrorIs(t, err, domain.ErrMatchNotFound)
+	})
+}
+
+func TestMatchUseCase_Resume(t *testing.T) {
+	alice, bob, eve := lobbyUser("alice"), lobbyUser("bob"), lobbyUser("eve")
+	users := []*domain.User{alice, bob}
+
+	t.Run("MissedEvents", func(t *testing.T) {
+		deps, u := setupMatch(matchDeps{})
+		match := newMatch(alice, bob)
+		startMatch(t, deps.matchRepo, u, match)
+		s := expectedGame(t, match)
+		mover := users[s.Turn]
+
+		before, err := u.Resume(context.Background(), mover.ID.Hex(), match.ID.Hex(), 0)
+		require.NoError(t, err)
+		last := before.Events[len(before.Events)-1].Seq
+		action := game.LegalActions(s)[0]
+		_, err = u.Act(context.Background(), mover.ID.Hex(), match.ID.Hex(), game.Action{Card: action.Card, Target: action.Target})
+		require.NoError(t, err)
+
+		got, err := u.Resume(context.Background(), mover.ID.Hex(), match.ID.Hex(), last)
+
+		require.NoError(t, err)
+		require.NotEmpty(t, got.Events)
+		assert.Equal(t, last+1, got.Events[0].Seq)
+		assert.Equal(t, game.EventCardPlayed, got.Events[0].Type)
+		require.NotNil(t, got.State)
+		assert.Equal(t, s.Turn, got.State.Seat)
+		assert.Equal(t, 2, got.State.TurnNumber)
+		assert.NotNil(t, got.TurnDeadline)
+		assert.Equal(t, []domain.SeatPresence{{Seat: 0, Connected: true}, {Seat: 1, Connected: true}}, got.Presence)
+	})
+
+	t.Run("OnlyOwnCards", func(t *testing.T) {
+		deps, u := setupMatch(matchDeps{})
+		match := newMatch(alice, bob)
+		startMatch(t, deps.matchRepo, u, match)
+
+		got, err := u.Resume(context.Background(), bob.ID.Hex(), match.ID.Hex(), 0)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, got.Events[0].Seq, "every event from the deal")
+		dealt := 0
+		for _, e := range got.Events {
+			if e.Type != game.EventDealt {
+				continue
+			}
+			dealt++
+			if e.Seat == 1 {
+				assert.Len(t, e.Cards, game.DefaultHandSize)
+			} else {
+				assert.Empty(t, e.Cards)
+			}
+		}
+		assert.Equal(t, 2, dealt)
+	})
+
+	t.Run("FinishedMatch", func(t *testing.T) {
+		deps, u := setupMatch(matchDeps{})
+		match := finishedDuel(alice, bob)
+		deps.matchRepo.On("GetByID", mock.Anything, match.ID.Hex()).Return(match, nil)
+
+		got, err := u.Resume(context.Background(), alice.ID.Hex(), match.ID.Hex(), 0)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.MatchFinished, got.Match.Status)
+		assert.Nil(t, got.State)
+		assert.Empty(t, got.Events)
+	})
+
+	t.Run("ErrorNotAPlayer", func(t *testing.T) {
+		deps, u := setupMatch(matchDeps{})
+		match := newMatch(alice, bob)
+		startMatch(t, deps.matchRepo, u, match)
+
+		_, err := u.Resume(context.Background(), eve.ID.Hex(), match.ID.Hex(), 0)
+
+		assert.ErrorIs(t, err, domain.ErrMatchNotFound)
+	})
+}
+
+func TestMatchUseCase_Replay(t *testing.T) {
+	alice, bob := lobbyUser("alice"), lobbyUser("bob")
+	users := []*domain.User{alice, bob}
+
+	// recorded plays a match to the end and returns the result it saved.
+	recorded := func(t *testing.T) (*domain.Match, *game.State) {
+		deps, u := setupMatch(matchDeps{})
+		match := newMatch(alice, bob)
+		saved := make(chan *domain.Match, 1)
+		deps.matchRepo.On("Create", mock.Anything, match).Return(nil)
+		deps.matchRepo.On("Finish", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			m := *args.Get(1).(*domain.Match)
+			saved <- &m
+		}).Return(nil)
+		require.NoError(t, u.Start(context.Background(), match))
+		s := playOut(t, u, match, users...)
+		select {
+		case m := <-saved:
+			return m, s
+		case <-time.After(time.Second):
+			require.FailNow(t, "the result wasn't saved")
+			return nil, nil
+		}
+	}
+
+	t.Run("RecordedMatchReproducesResult", func(t *testing.T) {
+		match, want := recorded(t)
+		deps, u := setupMatch(matchDeps{})
+		deps.matchRepo.On("GetByID", mock.Anything, match.ID.Hex()).Return(match, nil)
+
+		got, err := u.Replay(context.Background(), match.ID.Hex(), -1)
+
+		require.NoError(t, err)
+		assert.Equal(t, want, got.State)
+		assert.Equal(t, match.Placements, got.State.Placements)
+		assert.Equal(t, len(got.Actions), got.Step)
+	})
+
+	t.Run("IntermediateStep", func(t *testing.T) {
+		match, _ := recorded(t)
+		deps, u := setupMatch(matchDeps{})
+		deps.matchRepo.On("GetByID", mock.Anything, match.ID.Hex()).Return(match, nil)
+
+		got, err := u.Replay(context.Background(), match.ID.Hex(), 1)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, got.Step)
+		assert.Equal(t, 2, got.State.TurnNumber)
+		assert.Equal(t, game.EventCardPlayed, got.Events[0].Type)
+	})
+
+	t.Run("ErrorNotFinished", func(t *testing.T) {
+		deps, u := setupMatch(matchDeps{})
+		match := newMatch(alice, bob)
+		match.Status = domain.MatchActive
+		deps.matchRepo.On("GetByID", mock.Anything, match.ID.Hex()).Return(match, nil)
+
+		_, err := u.Replay(context.Background(), match.ID.Hex(), -1)
+
+		assert.ErrorIs(t, err, domain.ErrReplayNotFound)
+	})
+
+	t.Run("ErrorMatchNotFound", func(t *testing.T) {
+		deps, u := setupMatch(matchDeps{})
+		deps.matchRepo.On("GetByID", mock.Anything, "nope").Return(nil, domain.ErrMatchNotFound)
+
+		_, err := u.Replay(context.Background(), "nope", -1)
+
+		assert.ErrorIs(t, err, domain.ErrMatchNotFound)
+	})
+}
+
+func TestMatchUseCase_Spectate(t *testing.T) {
+	alice, bob := lobbyUser("alice"), lobbyUser("bob")
+	users := []*domain.User{alice, bob}
+
+	// watched starts match with eve spectating it.
+	watched := func(t *testing.T, cfg usecase.MatchConfig, match *domain.Match) (*mocks.MockPublisher, domain.MatchUsecase) {
+		publisher := new(mocks.MockPublisher)
+		publisher.On("Subscribers", domain.SpectateTopic(match.ID.Hex())).Return([]string{lobbyUser("eve").ID.Hex()})
+		deps, u := setupMatch(matchDeps{cfg: cfg, publisher: publisher})
+		startMatch(t, deps.matchRepo, u, match)
+		return publisher, u
+	}
+
+	t.Run("HidesHands", func(t *testing.T) {
+		match := newMatch(alice, bob)
+		publisher, u := watched(t, usecase.MatchConfig{SpectatorDelay: time.Hour}, match)
+
+		got, err := u.Spectate(context.Background(), match.ID.Hex())
+
+		require.NoError(t, err)
+		assert.Equal(t, match.ID, got.Match.ID)
+		assert.Equal(t, 1, got.Spectators)
+		assert.Zero(t, got.Delay, "only ranked matches are delayed")
+		require.NotNil(t, got.State)
+		assert.Equal(t, -1, got.State.Seat)
+		for _, p := range got.State.Players {
+			assert.Nil(t, p.Hand)
+			assert.NotZero(t, p.HandCount)
+		}
+		assert.Eventually(t, func() bool {
+			return publisher.AssertCalled(&testing.T{}, "Publish", domain.SpectateTopic(match.ID.Hex()), domain.EventMatchUpdated,
+				mock.MatchedBy(func(update domain.MatchUpdate) bool {
+					for _, e := range update.Events {
+						if e.Cards != nil || e.Private {
+							return false
+						}
+					}
+					return update.Spectators == 1 && len(update.Events) > 0
+				}))
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("RankedIsDelayed", func(t *testing.T) {
+		match := newMatch(alice, bob)
+		match.Ranked = true
+		publisher, u := watched(t, usecase.MatchConfig{SpectatorDelay: time.Hour}, match)
+		s := expectedGame(t, match)
+		action := game.LegalActions(s)[0]
+		_, err := u.Act(context.Background(), users[s.Turn].ID.Hex(), match.ID.Hex(), game.Action{Card: action.Card, Target: action.Target})
+		require.NoError(t, err)
+
+		got, err := u.Spectate(context.Background(), match.ID.Hex())
+
+		require.NoError(t, err)
+		assert.Equal(t, time.Hour, got.Delay)
+		assert.Equal(t, s.View(-1), *got.State, "the move is still an hour away")
+		publisher.AssertNotCalled(t, "Publish", domain.SpectateTopic(match.ID.Hex()), mock.Anything, mock.Anything)
+	})
+
+	t.Run("DelayedEventsArrive", func(t *testing.T) {
+		match := newMatch(alice, bob)
+		match.Ranked = true
+		delay := 50 * time.Millisecond
+		publisher, u := watched(t, usecase.MatchConfig{SpectatorDelay: delay}, match)
+		s := expectedGame(t, match)
+		action := game.LegalActions(s)[0]
+		_, err := u.Act(context.Background(), users[s.Turn].ID.Hex(), match.ID.Hex(), game.Action{Card: action.Card, Target: action.Target})
+		require.NoError(t, err)
+		_, err = game.Apply(s, action)
+		require.NoError(t, err)
+
+		assert.Eventually(t, func() bool {
+			return publisher.AssertCalled(&testing.T{}, "Publish", domain.SpectateTopic(match.ID.Hex()), domain.EventMatchUpdated,
+				mock.MatchedBy(func(update domain.MatchUpdate) bool {
+					return update.Events[0].Type == game.EventCardPlayed && update.TurnDeadline == nil
+				}))
+		}, time.Second, 10*time.Millisecond)
+		got, err := u.Spectate(context.Background(), match.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, s.View(-1), *got.State)
+	})
+
+	t.Run("RankedStaysDelayedAfterEnd", func(t *testing.T) {
+		match := newMatch(alice, bob)
+		match.Ranked = true
+		deps, u := setupMatch(matchDeps{cfg: usecase.MatchConfig{SpectatorDelay: time.Hour}})
+		startMatch(t, deps.matchRepo, u, match)
+		playOut(t, u, match, alice, bob)
+		assert.Eventually(t, func() bool {
+			return deps.matchRepo.AssertCalled(&testing.T{}, "Finish", mock.Anything, mock.MatchedBy(func(m *domain.Match) bool {
+				return m.Status == domain.MatchFinished
+			}), mock.Anything)
+		}, time.Second, 10*time.Millisecond)
+
+		got, err := u.Spectate(context.Background(), match.ID.Hex())
+
+		require.NoError(t, err)
+		assert.Equal(t, time.Hour, got.Delay)
+		assert.Equal(t, domain.MatchActive, got.Match.Status, "spectators haven't seen the end yet")
+		assert.Equal(t, expectedGame(t, match).View(-1), *got.State)
+		deps.matchRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("RankedShowsResultOnceCaughtUp", func(t *testing.T) {
+		match := newMatch(alice, bob)
+		match.Ranked = true
+		deps, u := setupMatch(matchDeps{cfg: usecase.MatchConfig{SpectatorDelay: 50 * time.Millisecond}})
+		startMatch(t, deps.matchRepo, u, match)
+		deps.matchRepo.On("GetByID", mock.Anything, match.ID.Hex()).Return(finishedDuel(alice, bob), nil)
+		playOut(t, u, match, alice, bob)
+
+		assert.Eventually(t, func() bool {
+			got, err := u.Spectate(context.Background(), match.ID.Hex())
+			return err == nil && got.Delay == 0 && got.Match.Status == domain.MatchFinished
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("FinishedMatch", func(t *testing.T) {
+		deps, u := setupMatch(matchDeps{})
+		match := finishedDuel(alice, bob)
+		deps.matchRepo.On("GetByID", mock.Anything, match.ID.Hex()).Return(match, nil)
+
+		got, err := u.Spectate(context.Background(), match.ID.Hex())
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.MatchFinished, got.Match.Status)
+		assert.Nil(t, got.State)
+	})
+
+	t.Run("ErrorMatchNotFound", func(t *testing.T) {
+		_, u := setupMatch(matchDeps{})
+
+		_, err := u.Spectate(context.Background(), "nope")
+
+		assert.ErrorIs(t, err, domain.ErrMatchNotFound)
+	})
+}
+
+func TestMatchUseCase_AbandonExpired(t *testing.T) {
+	alice, bob := lobbyUser("alice"), lobbyUser("bob")
+
+	t.Run("Success", func(t *testing.T) {
+		deps, u := setupMatch(matchDeps{})
+		lobby := lobbyWith(2, alice, bob)
+		stale := newMatch(alice, bob)
+		stale.Status = domain.MatchActive
+		stale.LobbyID, lobby.MatchID = lobby.ID, stale.ID
+		lone := newMatch(alice, bob)
+		deps.matchRepo.On("ListLeaseExpired", mock.Anything, mock.Anything).Return([]domain.Match{*stale, *lone}, nil)
+		deps.matchRepo.On("AbandonLeaseExpired", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil)
+		deps.lobbyRepo.On("Delete", mock.Anything, lobby).Return(nil)
+
+		count, err := u.AbandonExpired(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		deps.matchRepo.AssertCalled(t, "AbandonLeaseExpired", mock.Anything, stale.ID, mock.Anything)
+		deps.lobbyRepo.AssertExpectations(t)
+	})
+
+	t.Run("KeepsLobbyThatMovedOn", func(t *testing.T) {
+		deps, u := setupMatch(matchDeps{})
+		lobby := lobbyWith(2, alice, bob)
+		stale := newMatch(alice, bob)
+		stale.LobbyID = lobby.ID
+		deps.matchRepo.On("ListLeaseExpired", mock.Anything, mock.Anything).Return([]domain.Match{*stale}, nil)
+		deps.matchRepo.On("AbandonLeaseExpired", mock.Anything, stale.ID, mock.Anything).Return(true, nil)
+		deps.lobbyRepo.On("GetByID", mock.Anything, lobby.ID.Hex()).Return(lobby, nil)
+
+		_, err := u.AbandonExpired(context.Background())
+
+		require.NoError(t, err)
+		deps.lobbyRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("RenewedMeanwhile", func(t *testing.T) {
+		deps, u := setupMatch(matchDeps{})
+		lobby := lobbyWith(2, alice, bob)
+		renewed := newMatch(alice, bob)
+		renewed.LobbyID, lobby.MatchID = lobby.ID, renewed.ID
+		deps.matchRepo.On("ListLeaseExpired", mock.Anything, mock.Anything).Return([]domain.Match{*renewed}, nil)
+		deps.matchRepo.On("AbandonLeaseExpired", mock.Anything, renewed.ID, mock.Anything).Return(false, nil)
+
+		count, err := u.AbandonExpired(context.Background())
+
+		require.NoError(t, err)
+		assert.Zero(t, count)
+		deps.lobbyRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("KeepsRunningMatch", func(t *testing.T) {
+		deps, u := setupMatch(matchDeps{})
+		running := newMatch(alice, bob)
+		startMatch(t, deps.matchRepo, u, running)
+		deps.matchRepo.On("ListLeaseExpired", mock.Anything, mock.Anything).Return([]domain.Match{*running}, nil)
+
+		count, err := u.AbandonExpired(context.Background())
+
+		require.NoError(t, err)
+		assert.Zero(t, count)
+		deps.matchRepo.AssertNotCalled(t, "AbandonLeaseExpired", mock.Anything, mock.Anything, mock.Anything)
+		_, view, err := u.Get(context.Background(), alice.ID.Hex(), running.ID.Hex())
+		require.NoError(t, err)
+		assert.NotNil(t, view, "the match is still running")
+	})
+
+	t.Run("ErrorDatabase", func(t *testing.T) {
+		deps, u := setupMatch(matchDeps{})
+		deps.matchRepo.On("ListLeaseExpired", mock.Anything, mock.Anything).Return(nil, errors.New("db down"))
+
+		_, err := u.AbandonExpired(context.Background())
+
+		assert.Error(t, err)
+	})
+
+	// Two instances share the match store: each keeps its own match alive
+	// and only a match whose owner is gone is abandoned.
+	t.Run("TwoInstances", func(t *testing.T) {
+		matchRepo := memory.NewMatchRepository()
+		cfg := usecase.MatchConfig{Lease: 60 * time.Millisecond}
+		newInstance := func(name string) domain.MatchUsecase {
+			ratings := new(mocks.MockRatingUsecase)
+			history := new(mocks.MockHistoryUsecase)
+			publisher := new(mocks.MockPublisher)
+			publisher.On("Publish", mock.Anything, mock.Anything, mock.Anything).Maybe()
+			publisher.On("Subscribers", mock.Anything).Return([]string{}).Maybe()
+			cfg.Instance = name
+			return usecase.NewMatchUseCase(matchRepo, new(mocks.MockLobbyRepository), ratings, history, memory.NewTransactor(), publisher, cfg, 2*time.Second)
+		}
+		first, second := newInstance("first"), newInstance("second")
+		firstMatch, secondMatch := newMatch(alice, bob), newMatch(alice, bob)
+		firstMatch.TurnSeconds, secondMatch.TurnSeconds = 60, 60
+		require.NoError(t, first.Start(context.Background(), firstMatch))
+		require.NoError(t, second.Start(context.Background(), secondMatch))
+		crashed := newMatch(alice, bob)
+		crashed.Status, crashed.Owner, crashed.LeaseExpiresAt = domain.MatchActive, "crashed", time.Now().Add(-time.Second)
+		require.NoError(t, matchRepo.Create(context.Background(), crashed))
+
+		// Long past the lease: only renewals keep the running matches.
+		time.Sleep(3 * cfg.Lease)
+		firstCount, err := first.AbandonExpired(context.Background())
+		require.NoError(t, err)
+		secondCount, err := second.AbandonExpired(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, firstCount+secondCount)
+		for _, m := range []*domain.Match{firstMatch, secondMatch} {
+			stored, err := matchRepo.GetByID(context.Background(), m.ID.Hex())
+			require.NoError(t, err)
+			assert.Equal(t, domain.MatchActive, stored.Status)
+		}
+		stored, err := matchRepo.GetByID(context.Background(), crashed.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, domain.MatchAbandoned, stored.Status)
+		assert.NotNil(t, stored.FinishedAt)
+	})
+}
@@ -0,0 +1,367 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/domain/mocks"
+	"github.com/Simpolette/HeartSteal/server/internal/matchmaking"
+	"github.com/Simpolette/HeartSteal/server/internal/usecase"
+)
+
+type matchmakingDeps struct {
+	lobbyRepo *mocks.MockLobbyRepository
+	matchRepo *mocks.MockMatchRepository
+	matches   *mocks.MockMatchUsecase
+	publisher *mocks.MockPublisher
+}
+
+// matchmakingConfig never widens windows or expires proposals on its own;
+// tests that need either set them.
+func matchmakingConfig() matchmaking.Config {
+	return matchmaking.Config{InitialWindow: 100, AcceptTimeout: time.Minute, Interval: 10 * time.Millisecond}
+}
+
+// setupMatchmaking knows users, none of whom is in a lobby or a match, rated
+// by ratings or matchmaking.DefaultRating.
+func setupMatchmaking(cfg matchmaking.Config, ratings map[primitive.ObjectID]float64, users ...*domain.User) (*matchmakingDeps, domain.MatchmakingUsecase) {
+	userRepo := new(mocks.MockUserRepository)
+	deps := &matchmakingDeps{
+		lobbyRepo: new(mocks.MockLobbyRepository),
+		matchRepo: new(mocks.MockMatchRepository),
+		matches:   new(mocks.MockMatchUsecase),
+		publisher: new(mocks.MockPublisher),
+	}
+	for _, user := range users {
+		userRepo.On("GetByID", mock.Anything, user.ID.Hex()).Return(user, nil).Maybe()
+		deps.lobbyRepo.On("GetByMember", mock.Anything, user.ID).Return(nil, domain.ErrLobbyNotFound).Maybe()
+		deps.matchRepo.On("ListActiveByPlayer", mock.Anything, user.ID).Return([]domain.Match{}, nil).Maybe()
+	}
+	userRepo.On("GetByID", mock.Anything, mock.Anything).Return(nil, domain.ErrUserNotFound).Maybe()
+	ratingSource := new(mocks.MockRatingSource)
+	ratingSource.On("Ratings", mock.Anything, mock.Anything, mock.Anything).Return(ratings, nil).Maybe()
+	deps.publisher.On("Publish", mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	u := usecase.NewMatchmakingUseCase(matchmaking.NewMemoryQueue(), userRepo, deps.lobbyRepo, deps.matchRepo, deps.matches, ratingSource, deps.publisher, cfg, 2*time.Second)
+	return deps, u
+}
+
+// queue queues user alone for a duel.
+func queue(t *testing.T, u domain.MatchmakingUsecase, user *domain.User) *matchmaking.Ticket {
+	t.Helper()
+	ticket, err := u.Enqueue(context.Background(), user.ID.Hex(), domain.ModeDuel, nil)
+	require.NoError(t, err)
+	return ticket
+}
+
+// published reports whether user was sent event with a payload match accepts.
+func published(publisher *mocks.MockPublisher, user *domain.User, event string, match func(domain.MatchmakingEvent) bool) bool {
+	return publisher.AssertCalled(&testing.T{}, "Publish", domain.UserTopic(user.ID.Hex()), event, mock.MatchedBy(match))
+}
+
+func anyEvent(domain.MatchmakingEvent) bool { return true }
+
+func TestMatchmakingUseCase_Enqueue(t *testing.T) {
+	alice, bob, carol := lobbyUser("alice"), lobbyUser("bob"), lobbyUser("carol")
+	alice.FriendsList = []primitive.ObjectID{bob.ID, carol.ID}
+	bob.FriendsList = []primitive.ObjectID{alice.ID}
+	carol.FriendsList = []primitive.ObjectID{alice.ID}
+	ratings := map[primitive.ObjectID]float64{alice.ID: 1500, bob.ID: 1550, carol.ID: 1900}
+
+	t.Run("WaitsAlone", func(t *testing.T) {
+		deps, u := setupMatchmaking(matchmakingConfig(), ratings, alice)
+
+		ticket := queue(t, u, alice)
+
+		require.Len(t, ticket.Players, 1)
+		assert.Equal(t, 1500.0, ticket.Players[0].Rating)
+		assert.Equal(t, string(domain.ModeDuel), ticket.Mode)
+		assert.Empty(t, ticket.ProposalID)
+		assert.True(t, published(deps.publisher, alice, domain.EventMatchmakingQueued, anyEvent))
+	})
+
+	t.Run("MatchesSimilarRating", func(t *testing.T) {
+		deps, u := setupMatchmaking(matchmakingConfig(), ratings, alice, bob)
+		queue(t, u, alice)
+
+		ticket := queue(t, u, bob)
+
+		require.NotEmpty(t, ticket.ProposalID)
+		for _, user := range []*domain.User{alice, bob} {
+			assert.True(t, published(deps.publisher, user, domain.EventMatchmakingFound, func(e domain.MatchmakingEvent) bool {
+				return e.ProposalID == ticket.ProposalID && e.Players == 2 && e.Deadline != nil
+			}))
+		}
+	})
+
+	t.Run("KeepsApartDistantRatings", func(t *testing.T) {
+		_, u := setupMatchmaking(matchmakingConfig(), ratings, alice, carol)
+		queue(t, u, alice)
+
+		ticket := queue(t, u, carol)
+
+		assert.Empty(t, ticket.ProposalID)
+	})
+
+	t.Run("DefaultRating", func(t *testing.T) {
+		_, u := setupMatchmaking(matchmakingConfig(), nil, alice)
+
+		ticket := queue(t, u, alice)
+
+		assert.Equal(t, float64(matchmaking.DefaultRating), ticket.Players[0].Rating)
+	})
+
+	t.Run("Party", func(t *testing.T) {
+		deps, u := setupMatchmaking(matchmakingConfig(), ratings, alice, bob)
+
+		ticket, err := u.Enqueue(context.Background(), alice.ID.Hex(), domain.ModeTable, []string{bob.ID.Hex(), bob.ID.Hex()})
+
+		require.NoError(t, err)
+		require.Len(t, ticket.Players, 2, "duplicates are dropped")
+		assert.Equal(t, alice.ID.Hex(), ticket.Players[0].UserID)
+		assert.Equal(t, 1525.0, ticket.Rating())
+		assert.True(t, published(deps.publisher, bob, domain.EventMatchmakingQueued, anyEvent))
+		current, err := u.Current(context.Background(), bob.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, ticket.ID, current.ID)
+	})
+
+	t.Run("ErrorPartyNotFriends", func(t *testing.T) {
+		dave := lobbyUser("dave")
+		dave.FriendsList = []primitive.ObjectID{alice.ID}
+		_, u := setupMatchmaking(matchmakingConfig(), ratings, alice, dave)
+
+		_, err := u.Enqueue(context.Background(), dave.ID.Hex(), domain.ModeDuel, []string{alice.ID.Hex()})
+		assert.ErrorIs(t, err, domain.ErrPartyNotFriends, "dave lists alice, but alice doesn't list dave")
+
+		_, err = u.Enqueue(context.Background(), alice.ID.Hex(), domain.ModeDuel, []string{dave.ID.Hex()})
+		assert.ErrorIs(t, err, domain.ErrPartyNotFriends, "alice can't queue dave either")
+	})
+
+	t.Run("ErrorPartyTooLarge", func(t *testing.T) {
+		_, u := setupMatchmaking(matchmakingConfig(), ratings, alice, bob, carol)
+
+		_, err := u.Enqueue(context.Background(), alice.ID.Hex(), domain.ModeDuel, []string{bob.ID.Hex(), carol.ID.Hex()})
+
+		assert.ErrorIs(t, err, domain.ErrPartyTooLarge)
+	})
+
+	t.Run("ErrorAlreadyQueued", func(t *testing.T) {
+		_, u := setupMatchmaking(matchmakingConfig(), ratings, alice)
+		queue(t, u, alice)
+
+		_, err := u.Enqueue(context.Background(), alice.ID.Hex(), domain.ModeTable, nil)
+
+		assert.ErrorIs(t, err, matchmaking.ErrAlreadyQueued)
+	})
+
+	t.Run("ErrorInLobby", func(t *testing.T) {
+		deps, u := setupMatchmaking(matchmakingConfig(), ratings, alice)
+		deps.lobbyRepo.ExpectedCalls = nil
+		deps.lobbyRepo.On("GetByMember", mock.Anything, alice.ID).Return(lobbyWith(4, alice), nil)
+
+		_, err := u.Enqueue(context.Background(), alice.ID.Hex(), domain.ModeDuel, nil)
+
+		assert.ErrorIs(t, err, domain.ErrAlreadyInLobby)
+	})
+
+	t.Run("ErrorInMatch", func(t *testing.T) {
+		deps, u := setupMatchmaking(matchmakingConfig(), ratings, alice, bob)
+		deps.matchRepo.ExpectedCalls = nil
+		deps.matchRepo.On("ListActiveByPlayer", mock.Anything, alice.ID).Return([]domain.Match{}, nil)
+		deps.matchRepo.On("ListActiveByPlayer", mock.Anything, bob.ID).Return([]domain.Match{*newMatch(bob, carol)}, nil)
+
+		_, err := u.Enqueue(context.Background(), alice.ID.Hex(), domain.ModeTable, []string{bob.ID.Hex()})
+
+		assert.ErrorIs(t, err, domain.ErrAlreadyInMatch, "a party member playing a match keeps the party out")
+		current, err := u.Current(context.Background(), alice.ID.Hex())
+		assert.Nil(t, current)
+		assert.ErrorIs(t, err, matchmaking.ErrNotQueued)
+	})
+
+	t.Run("ErrorUnknownMode", func(t *testing.T) {
+		_, u := setupMatchmaking(matchmakingConfig(), ratings, alice)
+
+		_, err := u.Enqueue(context.Background(), alice.ID.Hex(), domain.GameMode("solo"), nil)
+
+		assert.ErrorIs(t, err, domain.ErrUnknownGameMode)
+	})
+}
+
+func TestMatchmakingUseCase_Current(t *testing.T) {
+	alice := lobbyUser("alice")
+
+	t.Run("Queued", func(t *testing.T) {
+		_, u := setupMatchmaking(matchmakingConfig(), nil, alice)
+		queued := queue(t, u, alice)
+
+		ticket, err := u.Current(context.Background(), alice.ID.Hex())
+
+		require.NoError(t, err)
+		assert.Equal(t, queued.ID, ticket.ID)
+	})
+
+	t.Run("ErrorNotQueued", func(t *testing.T) {
+		_, u := setupMatchmaking(matchmakingConfig(), nil, alice)
+
+		_, err := u.Current(context.Background(), alice.ID.Hex())
+
+		assert.ErrorIs(t, err, matchmaking.ErrNotQueued)
+	})
+}
+
+func TestMatchmakingUseCase_Cancel(t *testing.T) {
+	alice, bob := lobbyUser("alice"), lobbyUser("bob")
+
+	t.Run("WhileWaiting", func(t *testing.T) {
+		deps, u := setupMatchmaking(matchmakingConfig(), nil, alice)
+		queue(t, u, alice)
+
+		err := u.Cancel(context.Background(), alice.ID.Hex())
+
+		require.NoError(t, err)
+		_, err = u.Current(context.Background(), alice.ID.Hex())
+		assert.ErrorIs(t, err, matchmaking.ErrNotQueued)
+		assert.True(t, published(deps.publisher, alice, domain.EventMatchmakingCancelled, func(e domain.MatchmakingEvent) bool {
+			return e.Reason == domain.MatchmakingCancelled
+		}))
+	})
+
+	t.Run("DeclinesMatchFound", func(t *testing.T) {
+		deps, u := setupMatchmaking(matchmakingConfig(), nil, alice, bob)
+		first := queue(t, u, alice)
+		require.NotEmpty(t, queue(t, u, bob).ProposalID)
+
+		err := u.Cancel(context.Background(), bob.ID.Hex())
+
+		require.NoError(t, err)
+		_, err = u.Current(context.Background(), bob.ID.Hex())
+		assert.ErrorIs(t, err, matchmaking.ErrNotQueued)
+		ticket, err := u.Current(context.Background(), alice.ID.Hex())
+		require.NoError(t, err)
+		assert.Empty(t, ticket.ProposalID)
+		assert.Equal(t, first.QueuedAt, ticket.QueuedAt, "the other player keeps their place")
+		assert.True(t, published(deps.publisher, alice, domain.EventMatchmakingRequeued, anyEvent))
+	})
+
+	t.Run("ErrorNotQueued", func(t *testing.T) {
+		_, u := setupMatchmaking(matchmakingConfig(), nil, alice)
+
+		err := u.Cancel(context.Background(), alice.ID.Hex())
+
+		assert.ErrorIs(t, err, matchmaking.ErrNotQueued)
+	})
+}
+
+func TestMatchmakingUseCase_Accept(t *testing.T) {
+	alice, bob := lobbyUser("alice"), lobbyUser("bob")
+
+	t.Run("StartsWhenEveryoneAccepts", func(t *testing.T) {
+		deps, u := setupMatchmaking(matchmakingConfig(), nil, alice, bob)
+		queue(t, u, alice)
+		queue(t, u, bob)
+		deps.matches.On("Start", mock.Anything, mock.MatchedBy(func(m *domain.Match) bool {
+			return len(m.Players) == 2 && m.LobbyID.IsZero() && m.Ranked && m.Mode == domain.ModeDuel &&
+				m.Players[0].UserID == alice.ID && m.Players[1].UserID == bob.ID && m.Players[1].Seat == 1
+		})).Return(nil).Once()
+
+		proposal, err := u.Accept(context.Background(), alice.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, []string{alice.ID.Hex()}, proposal.Accepted)
+		assert.Empty(t, proposal.MatchID)
+		deps.matches.AssertNotCalled(t, "Start", mock.Anything, mock.Anything)
+
+		proposal, err = u.Accept(context.Background(), bob.ID.Hex())
+
+		require.NoError(t, err)
+		require.NotEmpty(t, proposal.MatchID)
+		deps.matches.AssertExpectations(t)
+		_, err = u.Current(context.Background(), alice.ID.Hex())
+		assert.ErrorIs(t, err, matchmaking.ErrNotQueued)
+		assert.True(t, published(deps.publisher, alice, domain.EventMatchmakingStarted, func(e domain.MatchmakingEvent) bool {
+			return e.MatchID == proposal.MatchID
+		}))
+	})
+
+	t.Run("RequeuesWhenStartFails", func(t *testing.T) {
+		deps, u := setupMatchmaking(matchmakingConfig(), nil, alice, bob)
+		queue(t, u, alice)
+		queue(t, u, bob)
+		deps.matches.On("Start", mock.Anything, mock.Anything).Return(errors.New("db down"))
+		_, err := u.Accept(context.Background(), alice.ID.Hex())
+		require.NoError(t, err)
+
+		_, err = u.Accept(context.Background(), bob.ID.Hex())
+
+		require.Error(t, err)
+		for _, user := range []*domain.User{alice, bob} {
+			ticket, err := u.Current(context.Background(), user.ID.Hex())
+			require.NoError(t, err)
+			assert.Empty(t, ticket.ProposalID)
+		}
+	})
+
+	t.Run("ErrorNoMatchFound", func(t *testing.T) {
+		_, u := setupMatchmaking(matchmakingConfig(), nil, alice)
+		queue(t, u, alice)
+
+		_, err := u.Accept(context.Background(), alice.ID.Hex())
+
+		assert.ErrorIs(t, err, matchmaking.ErrProposalNotFound)
+	})
+}
+
+func TestMatchmakingUseCase_Run(t *testing.T) {
+	alice, bob := lobbyUser("alice"), lobbyUser("bob")
+
+	t.Run("WidensWindows", func(t *testing.T) {
+		cfg := matchmakingConfig()
+		cfg.WindowGrowth = 2000
+		ratings := map[primitive.ObjectID]float64{alice.ID: 1500, bob.ID: 1900}
+		_, u := setupMatchmaking(cfg, ratings, alice, bob)
+		queue(t, u, alice)
+		require.Empty(t, queue(t, u, bob).ProposalID)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go u.Run(ctx)
+
+		assert.Eventually(t, func() bool {
+			ticket, err := u.Current(context.Background(), alice.ID.Hex())
+			return err == nil && ticket.ProposalID != ""
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("ExpiresUnacceptedMatches", func(t *testing.T) {
+		cfg := matchmakingConfig()
+		cfg.AcceptTimeout = 50 * time.Millisecond
+		deps, u := setupMatchmaking(cfg, nil, alice, bob)
+		queue(t, u, alice)
+		queue(t, u, bob)
+		_, err := u.Accept(context.Background(), alice.ID.Hex())
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go u.Run(ctx)
+
+		assert.Eventually(t, func() bool {
+			_, err := u.Current(context.Background(), bob.ID.Hex())
+			return errors.Is(err, matchmaking.ErrNotQueued)
+		}, time.Second, 10*time.Millisecond)
+		ticket, err := u.Current(context.Background(), alice.ID.Hex())
+		require.NoError(t, err, "players who accepted stay queued")
+		assert.Empty(t, ticket.ProposalID)
+		assert.True(t, published(deps.publisher, bob, domain.EventMatchmakingCancelled, func(e domain.MatchmakingEvent) bool {
+			return e.Reason == domain.MatchmakingTimedOut
+		}))
+	})
+}
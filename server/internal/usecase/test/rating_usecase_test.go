@@ -0,0 +1,242 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/domain/mocks"
+	"github.com/Simpolette/HeartSteal/server/internal/rating"
+	"github.com/Simpolette/HeartSteal/server/internal/usecase"
+)
+
+func setupRating() (*mocks.MockRatingRepository, *mocks.MockUserRepository, domain.RatingUsecase) {
+	ratingRepo := new(mocks.MockRatingRepository)
+	userRepo := new(mocks.MockUserRepository)
+	return ratingRepo, userRepo, usecase.NewRatingUseCase(ratingRepo, userRepo, 2*time.Second)
+}
+
+// ratingOf rates u in duels, last played just now.
+func ratingOf(u *domain.User, value float64) domain.PlayerRating {
+	return domain.PlayerRating{
+		ID: primitive.NewObjectID(), UserID: u.ID, Username: u.Username, Mode: domain.ModeDuel,
+		Rating: value, RD: 80, Volatility: rating.DefaultVolatility, Games: 10, LastPlayedAt: time.Now().UTC(),
+	}
+}
+
+// finishedDuel is a ranked duel alice won against bob.
+func finishedDuel(alice, bob *domain.User) *domain.Match {
+	match := newMatch(alice, bob)
+	now := time.Now().UTC()
+	match.Mode, match.Ranked = domain.ModeDuel, true
+	match.Status, match.Placements, match.FinishedAt = domain.MatchFinished, []int{1, 2}, &now
+	return match
+}
+
+func TestRatingUseCase_Ratings(t *testing.T) {
+	alice, bob := lobbyUser("alice"), lobbyUser("bob")
+	ratingRepo, _, u := setupRating()
+	ids := []primitive.ObjectID{alice.ID, bob.ID}
+	ratingRepo.On("ListByUsers", mock.Anything, domain.ModeDuel, ids).Return([]domain.PlayerRating{ratingOf(alice, 1620)}, nil)
+
+	got, err := u.Ratings(context.Background(), domain.ModeDuel, ids)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[primitive.ObjectID]float64{alice.ID: 1620}, got, "unrated players are left to the caller")
+}
+
+func TestRatingUseCase_Record(t *testing.T) {
+	alice, bob := lobbyUser("alice"), lobbyUser("bob")
+
+	t.Run("NewPlayers", func(t *testing.T) {
+		ratingRepo, _, u := setupRating()
+		match := finishedDuel(alice, bob)
+		ratingRepo.On("ListByUsers", mock.Anything, domain.ModeDuel, []primitive.ObjectID{alice.ID, bob.ID}).Return([]domain.PlayerRating{}, nil)
+		var saved []domain.PlayerRating
+		ratingRepo.On("Save", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			saved = append(saved, *args.Get(1).(*domain.PlayerRating))
+		}).Return(nil)
+
+		err := u.Record(context.Background(), match)
+
+		require.NoError(t, err)
+		require.Len(t, saved, 2)
+		winner, loser := saved[0], saved[1]
+		assert.Equal(t, alice.ID, winner.UserID)
+		assert.Equal(t, "alice", winner.Username)
+		assert.Equal(t, domain.ModeDuel, winner.Mode)
+		assert.Equal(t, 1, winner.Games)
+		assert.Equal(t, *match.FinishedAt, winner.LastPlayedAt)
+		assert.Greater(t, winner.Rating, float64(rating.DefaultRating))
+		assert.Less(t, loser.Rating, float64(rating.DefaultRating))
+		assert.Less(t, winner.RD, float64(rating.DefaultRD))
+		assert.InDelta(t, winner.Rating-rating.DefaultRating, match.Players[0].RatingChange, 1e-9)
+		assert.Negative(t, match.Players[1].RatingChange)
+	})
+
+	t.Run("DecaysIdlePlayers", func(t *testing.T) {
+		ratingRepo, _, u := setupRating()
+		match := finishedDuel(alice, bob)
+		active, idle := ratingOf(alice, 1600), ratingOf(bob, 1600)
+		idle.LastPlayedAt = match.FinishedAt.Add(-180 * domain.RatingPeriod)
+		ratingRepo.On("ListByUsers", mock.Anything, domain.ModeDuel, mock.Anything).Return([]domain.PlayerRating{active, idle}, nil)
+		var saved []domain.PlayerRating
+		ratingRepo.On("Save", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			saved = append(saved, *args.Get(1).(*domain.PlayerRating))
+		}).Return(nil)
+
+		require.NoError(t, u.Record(context.Background(), match))
+
+		require.Len(t, saved, 2)
+		assert.Equal(t, active.ID, saved[0].ID, "existing ratings are updated in place")
+		assert.Equal(t, 11, saved[0].Games)
+		assert.Greater(t, -match.Players[1].RatingChange, match.Players[0].RatingChange,
+			"the idle player's rating is less certain, so it moves more")
+	})
+
+	t.Run("UnrankedIsIgnored", func(t *testing.T) {
+		ratingRepo, _, u := setupRating()
+		match := finishedDuel(alice, bob)
+		match.Ranked = false
+
+		require.NoError(t, u.Record(context.Background(), match))
+
+		ratingRepo.AssertNotCalled(t, "ListByUsers", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("ErrorSave", func(t *testing.T) {
+		ratingRepo, _, u := setupRating()
+		match := finishedDuel(alice, bob)
+		ratingRepo.On("ListByUsers", mock.Anything, mock.Anything, mock.Anything).Return([]domain.PlayerRating{}, nil)
+		ratingRepo.On("Save", mock.Anything, mock.Anything).Return(errors.New("write conflict"))
+
+		err := u.Record(context.Background(), match)
+
+		assert.EqualError(t, err, "write conflict")
+	})
+}
+
+func TestRatingUseCase_Leaderboard(t *testing.T) {
+	alice, bob, carol := lobbyUser("alice"), lobbyUser("bob"), lobbyUser("carol")
+	board := []domain.PlayerRating{ratingOf(alice, 1700), ratingOf(bob, 1650), ratingOf(carol, 1600)}
+
+	t.Run("FirstPage", func(t *testing.T) {
+		ratingRepo, _, u := setupRating()
+		ratingRepo.On("ListRanked", mock.Anything, domain.ModeDuel, []primitive.ObjectID(nil), (*domain.RankCursor)(nil), 3).Return(board, nil)
+
+		entries, next, err := u.Leaderboard(context.Background(), alice.ID.Hex(), domain.ModeDuel, domain.LeaderboardGlobal, 2, "")
+
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, 1, entries[0].Rank)
+		assert.Equal(t, bob.ID, entries[1].UserID)
+		assert.Equal(t, 2, entries[1].Rank)
+
+		cursor, err := domain.DecodeRankCursor(next)
+		require.NoError(t, err)
+		assert.Equal(t, domain.RankCursor{Rating: 1650, UserID: bob.ID, Rank: 2}, *cursor)
+	})
+
+	t.Run("NextPageKeepsCounting", func(t *testing.T) {
+		ratingRepo, _, u := setupRating()
+		after := domain.RankCursor{Rating: 1650, UserID: bob.ID, Rank: 2}
+		ratingRepo.On("ListRanked", mock.Anything, domain.ModeDuel, []primitive.ObjectID(nil), &after, 3).Return(board[2:], nil)
+
+		entries, next, err := u.Leaderboard(context.Background(), alice.ID.Hex(), domain.ModeDuel, "", 2, after.Encode())
+
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, 3, entries[0].Rank)
+		assert.Empty(t, next)
+	})
+
+	t.Run("Friends", func(t *testing.T) {
+		ratingRepo, userRepo, u := setupRating()
+		me := *alice
+		me.FriendsList = []primitive.ObjectID{carol.ID}
+		userRepo.On("GetByID", mock.Anything, alice.ID.Hex()).Return(&me, nil)
+		ratingRepo.On("ListRanked", mock.Anything, domain.ModeDuel, []primitive.ObjectID{alice.ID, carol.ID}, (*domain.RankCursor)(nil), 21).
+			Return([]domain.PlayerRating{board[0], board[2]}, nil)
+
+		entries, _, err := u.Leaderboard(context.Background(), alice.ID.Hex(), domain.ModeDuel, domain.LeaderboardFriends, 0, "")
+
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, carol.ID, entries[1].UserID)
+		assert.Equal(t, 2, entries[1].Rank)
+	})
+
+	t.Run("ShowsDecayedRD", func(t *testing.T) {
+		ratingRepo, _, u := setupRating()
+		idle := ratingOf(alice, 1700)
+		idle.LastPlayedAt = idle.LastPlayedAt.Add(-90 * domain.RatingPeriod)
+		ratingRepo.On("ListRanked", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]domain.PlayerRating{idle}, nil)
+
+		entries, _, err := u.Leaderboard(context.Background(), alice.ID.Hex(), domain.ModeDuel, domain.LeaderboardGlobal, 10, "")
+
+		require.NoError(t, err)
+		assert.Greater(t, entries[0].RD, idle.RD)
+		assert.Equal(t, idle.Rating, entries[0].Rating)
+	})
+
+	t.Run("ErrorUnknownMode", func(t *testing.T) {
+		_, _, u := setupRating()
+
+		_, _, err := u.Leaderboard(context.Background(), alice.ID.Hex(), "solo", domain.LeaderboardGlobal, 10, "")
+
+		assert.ErrorIs(t, err, domain.ErrUnknownGameMode)
+	})
+
+	t.Run("ErrorUnknownScope", func(t *testing.T) {
+		_, _, u := setupRating()
+
+		_, _, err := u.Leaderboard(context.Background(), alice.ID.Hex(), domain.ModeDuel, "clan", 10, "")
+
+		assert.ErrorIs(t, err, domain.ErrUnknownLeaderboardScope)
+	})
+
+	t.Run("ErrorInvalidCursor", func(t *testing.T) {
+		_, _, u := setupRating()
+
+		_, _, err := u.Leaderboard(context.Background(), alice.ID.Hex(), domain.ModeDuel, domain.LeaderboardGlobal, 10, "not-a-cursor")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+	})
+}
+
+func TestRatingUseCase_AroundMe(t *testing.T) {
+	alice, bob, carol := lobbyUser("alice"), lobbyUser("bob"), lobbyUser("carol")
+
+	t.Run("Success", func(t *testing.T) {
+		ratingRepo, _, u := setupRating()
+		me := ratingOf(bob, 1650)
+		pos := domain.RankCursor{Rating: 1650, UserID: bob.ID}
+		ratingRepo.On("Get", mock.Anything, bob.ID, domain.ModeDuel).Return(&me, nil)
+		ratingRepo.On("CountAhead", mock.Anything, domain.ModeDuel, []primitive.ObjectID(nil), pos).Return(41, nil)
+		ratingRepo.On("ListAhead", mock.Anything, domain.ModeDuel, []primitive.ObjectID(nil), pos, 1).Return([]domain.PlayerRating{ratingOf(alice, 1700)}, nil)
+		ratingRepo.On("ListRanked", mock.Anything, domain.ModeDuel, []primitive.ObjectID(nil), &pos, 1).Return([]domain.PlayerRating{ratingOf(carol, 1600)}, nil)
+
+		entries, err := u.AroundMe(context.Background(), bob.ID.Hex(), domain.ModeDuel, 1)
+
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+		assert.Equal(t, []int{41, 42, 43}, []int{entries[0].Rank, entries[1].Rank, entries[2].Rank})
+		assert.Equal(t, []primitive.ObjectID{alice.ID, bob.ID, carol.ID}, []primitive.ObjectID{entries[0].UserID, entries[1].UserID, entries[2].UserID})
+	})
+
+	t.Run("ErrorNotRanked", func(t *testing.T) {
+		ratingRepo, _, u := setupRating()
+		ratingRepo.On("Get", mock.Anything, bob.ID, domain.ModeDuel).Return(nil, domain.ErrNotRanked)
+
+		_, err := u.AroundMe(context.Background(), bob.ID.Hex(), domain.ModeDuel, 5)
+
+		assert.ErrorIs(t, err, domain.ErrNotRanked)
+	})
+}
@@ -0,0 +1,106 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/domain/mocks"
+	"github.com/Simpolette/HeartSteal/server/internal/usecase"
+)
+
+func setupRealtime() (*mocks.MockTicketRepository, *mocks.MockUserRepository, domain.RealtimeUsecase) {
+	ticketRepo := new(mocks.MockTicketRepository)
+	userRepo := new(mocks.MockUserRepository)
+	return ticketRepo, userRepo, usecase.NewRealtimeUseCase(ticketRepo, userRepo, 30*time.Second, 2*time.Second)
+}
+
+func TestRealtimeUseCase_IssueTicket(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		ticketRepo, userRepo, u := setupRealtime()
+		user := &domain.User{ID: primitive.NewObjectID(), Username: "alice"}
+		userRepo.On("GetByID", mock.Anything, user.ID.Hex()).Return(user, nil)
+		ticketRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Ticket")).Return(nil)
+
+		ticket, err := u.IssueTicket(context.Background(), user.ID.Hex())
+
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, ticket.UserID)
+		assert.GreaterOrEqual(t, len(ticket.Value), 43)
+		assert.WithinDuration(t, time.Now().Add(30*time.Second), ticket.ExpiresAt, 5*time.Second)
+		ticketRepo.AssertExpectations(t)
+	})
+
+	t.Run("TicketsAreUnique", func(t *testing.T) {
+		ticketRepo, userRepo, u := setupRealtime()
+		user := &domain.User{ID: primitive.NewObjectID()}
+		userRepo.On("GetByID", mock.Anything, user.ID.Hex()).Return(user, nil)
+		ticketRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		first, err := u.IssueTicket(context.Background(), user.ID.Hex())
+		require.NoError(t, err)
+		second, err := u.IssueTicket(context.Background(), user.ID.Hex())
+		require.NoError(t, err)
+
+		assert.NotEqual(t, first.Value, second.Value)
+	})
+
+	t.Run("ErrorBanned", func(t *testing.T) {
+		ticketRepo, userRepo, u := setupRealtime()
+		bannedAt := time.Now()
+		user := &domain.User{ID: primitive.NewObjectID(), BannedAt: &bannedAt}
+		userRepo.On("GetByID", mock.Anything, user.ID.Hex()).Return(user, nil)
+
+		_, err := u.IssueTicket(context.Background(), user.ID.Hex())
+
+		assert.Equal(t, domain.ErrUserBanned, err)
+		ticketRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("ErrorUserNotFound", func(t *testing.T) {
+		_, userRepo, u := setupRealtime()
+		userRepo.On("GetByID", mock.Anything, "missing").Return(nil, domain.ErrUserNotFound)
+
+		_, err := u.IssueTicket(context.Background(), "missing")
+
+		assert.Equal(t, domain.ErrUserNotFound, err)
+	})
+}
+
+func TestRealtimeUseCase_RedeemTicket(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		ticketRepo, _, u := setupRealtime()
+		userID := primitive.NewObjectID()
+		ticketRepo.On("Take", mock.Anything, "ticket", mock.AnythingOfType("time.Time")).
+			Return(&domain.Ticket{Value: "ticket", UserID: userID}, nil)
+
+		got, err := u.RedeemTicket(context.Background(), "ticket")
+
+		require.NoError(t, err)
+		assert.Equal(t, userID.Hex(), got)
+	})
+
+	t.Run("ErrorEmpty", func(t *testing.T) {
+		ticketRepo, _, u := setupRealtime()
+
+		_, err := u.RedeemTicket(context.Background(), "")
+
+		assert.Equal(t, domain.ErrInvalidTicket, err)
+		ticketRepo.AssertNotCalled(t, "Take", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("ErrorInvalid", func(t *testing.T) {
+		ticketRepo, _, u := setupRealtime()
+		ticketRepo.On("Take", mock.Anything, "used", mock.Anything).Return(nil, domain.ErrInvalidTicket)
+
+		_, err := u.RedeemTicket(context.Background(), "used")
+
+		assert.Equal(t, domain.ErrInvalidTicket, err)
+	})
+}
@@ -0,0 +1,178 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/domain/mocks"
+	"github.com/Simpolette/HeartSteal/server/internal/usecase"
+)
+
+func setupSpectator() (*mocks.MockMatchRepository, *mocks.MockUserRepository, *mocks.MockMatchUsecase, *mocks.MockPublisher, domain.SpectatorUsecase) {
+	matchRepo := new(mocks.MockMatchRepository)
+	userRepo := new(mocks.MockUserRepository)
+	matches := new(mocks.MockMatchUsecase)
+	publisher := new(mocks.MockPublisher)
+	return matchRepo, userRepo, matches, publisher, usecase.NewSpectatorUseCase(matchRepo, userRepo, matches, publisher, 2*time.Second)
+}
+
+// spectatingUser has the given policy and friends.
+func spectatingUser(username string, policy domain.SpectatorPolicy, friends ...*domain.User) *domain.User {
+	user := lobbyUser(username)
+	user.SpectatorPolicy = policy
+	for _, f := range friends {
+		user.FriendsList = append(user.FriendsList, f.ID)
+	}
+	return user
+}
+
+// knownUsers lets userRepo find users by ID.
+func knownUsers(userRepo *mocks.MockUserRepository, users ...*domain.User) {
+	for _, u := range users {
+		userRepo.On("GetByID", mock.Anything, u.ID.Hex()).Return(u, nil)
+	}
+}
+
+func TestSpectatorUseCase_SetPolicy(t *testing.T) {
+	carol, eve := lobbyUser("carol"), lobbyUser("eve")
+
+	t.Run("DropsSpectatorsWhoLostAccess", func(t *testing.T) {
+		matchRepo, userRepo, _, publisher, u := setupSpectator()
+		alice := spectatingUser("alice", domain.SpectatePublic, carol)
+		bob := spectatingUser("bob", domain.SpectatePublic)
+		knownUsers(userRepo, alice, bob)
+		userRepo.On("Update", mock.Anything, alice).Return(nil)
+		playing, other := newMatch(alice, bob), newMatch(bob, eve)
+		matchRepo.On("ListActiveByPlayer", mock.Anything, alice.ID).Return([]domain.Match{*playing}, nil)
+		topic := domain.SpectateTopic(playing.ID.Hex())
+		publisher.On("Subscribers", topic).Return([]string{bob.ID.Hex(), carol.ID.Hex(), eve.ID.Hex()})
+		publisher.On("Unsubscribe", topic, eve.ID.Hex()).Return()
+
+		err := u.SetPolicy(context.Background(), alice.ID.Hex(), domain.SpectateFriends)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.SpectateFriends, alice.SpectatorPolicy)
+		publisher.AssertNumberOfCalls(t, "Unsubscribe", 1)
+		publisher.AssertNotCalled(t, "Subscribers", domain.SpectateTopic(other.ID.Hex()))
+	})
+
+	t.Run("ErrorUnknownPolicy", func(t *testing.T) {
+		_, userRepo, _, _, u := setupSpectator()
+
+		err := u.SetPolicy(context.Background(), carol.ID.Hex(), "everyone")
+
+		assert.ErrorIs(t, err, domain.ErrUnknownSpectatorPolicy)
+		userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("ErrorUserNotFound", func(t *testing.T) {
+		_, userRepo, _, _, u := setupSpectator()
+		userRepo.On("GetByID", mock.Anything, carol.ID.Hex()).Return(nil, domain.ErrUserNotFound)
+
+		err := u.SetPolicy(context.Background(), carol.ID.Hex(), domain.SpectateOff)
+
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+}
+
+func TestSpectatorUseCase_Watch(t *testing.T) {
+	eve := lobbyUser("eve")
+
+	t.Run("Success", func(t *testing.T) {
+		matchRepo, userRepo, matches, _, u := setupSpectator()
+		alice, bob := spectatingUser("alice", domain.SpectatePublic), spectatingUser("bob", domain.SpectatePublic)
+		knownUsers(userRepo, alice, bob)
+		match := newMatch(alice, bob)
+		matchRepo.On("GetByID", mock.Anything, match.ID.Hex()).Return(match, nil)
+		want := &domain.SpectatedMatch{Match: match, Spectators: 2}
+		matches.On("Spectate", mock.Anything, match.ID.Hex()).Return(want, nil)
+
+		got, err := u.Watch(context.Background(), eve.ID.Hex(), match.ID.Hex())
+
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("ErrorSpectatingForbidden", func(t *testing.T) {
+		matchRepo, userRepo, matches, _, u := setupSpectator()
+		alice, bob := spectatingUser("alice", domain.SpectatePublic), spectatingUser("bob", domain.SpectateOff)
+		knownUsers(userRepo, alice, bob)
+		match := newMatch(alice, bob)
+		matchRepo.On("GetByID", mock.Anything, match.ID.Hex()).Return(match, nil)
+
+		_, err := u.Watch(context.Background(), eve.ID.Hex(), match.ID.Hex())
+
+		assert.ErrorIs(t, err, domain.ErrSpectatingForbidden)
+		matches.AssertNotCalled(t, "Spectate", mock.Anything, mock.Anything)
+	})
+}
+
+func TestSpectatorUseCase_CanWatch(t *testing.T) {
+	eve := lobbyUser("eve")
+
+	tests := []struct {
+		name    string
+		alice   domain.SpectatorPolicy
+		friends bool
+		err     error
+	}{
+		{"Public", domain.SpectatePublic, false, nil},
+		{"Friend", domain.SpectateFriends, true, nil},
+		{"DefaultIsFriends", "", true, nil},
+		{"ErrorNotAFriend", domain.SpectateFriends, false, domain.ErrSpectatingForbidden},
+		{"ErrorOff", domain.SpectateOff, true, domain.ErrSpectatingForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matchRepo, userRepo, _, _, u := setupSpectator()
+			alice, bob := spectatingUser("alice", tt.alice), spectatingUser("bob", domain.SpectatePublic)
+			if tt.friends {
+				alice.FriendsList = []primitive.ObjectID{eve.ID}
+			}
+			knownUsers(userRepo, alice, bob)
+			match := newMatch(alice, bob)
+			matchRepo.On("GetByID", mock.Anything, match.ID.Hex()).Return(match, nil)
+
+			err := u.CanWatch(context.Background(), eve.ID.Hex(), match.ID.Hex())
+
+			assert.Equal(t, tt.err, err)
+		})
+	}
+
+	t.Run("PlayersMayWatchTheirOwn", func(t *testing.T) {
+		matchRepo, userRepo, _, _, u := setupSpectator()
+		alice, bob := spectatingUser("alice", domain.SpectateOff), spectatingUser("bob", domain.SpectatePublic)
+		knownUsers(userRepo, alice, bob)
+		match := newMatch(alice, bob)
+		matchRepo.On("GetByID", mock.Anything, match.ID.Hex()).Return(match, nil)
+
+		assert.NoError(t, u.CanWatch(context.Background(), alice.ID.Hex(), match.ID.Hex()))
+	})
+
+	t.Run("DeletedPlayersAreIgnored", func(t *testing.T) {
+		matchRepo, userRepo, _, _, u := setupSpectator()
+		alice, bob := spectatingUser("alice", domain.SpectatePublic), spectatingUser("bob", domain.SpectateOff)
+		knownUsers(userRepo, alice)
+		userRepo.On("GetByID", mock.Anything, bob.ID.Hex()).Return(nil, domain.ErrUserNotFound)
+		match := newMatch(alice, bob)
+		matchRepo.On("GetByID", mock.Anything, match.ID.Hex()).Return(match, nil)
+
+		assert.NoError(t, u.CanWatch(context.Background(), eve.ID.Hex(), match.ID.Hex()))
+	})
+
+	t.Run("ErrorMatchNotFound", func(t *testing.T) {
+		matchRepo, _, _, _, u := setupSpectator()
+		matchRepo.On("GetByID", mock.Anything, "nope").Return(nil, domain.ErrMatchNotFound)
+
+		err := u.CanWatch(context.Background(), eve.ID.Hex(), "nope")
+
+		assert.ErrorIs(t, err, domain.ErrMatchNotFound)
+	})
+}
@@ -7,11 +7,13 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/Simpolette/HeartSteal/server/internal/domain"
 	"github.com/Simpolette/HeartSteal/server/internal/domain/mocks"
 	"github.com/Simpolette/HeartSteal/server/internal/usecase"
+	tokenutil "github.com/Simpolette/HeartSteal/server/utils"
 )
 
 func TestUserUseCase_Register(t *testing.T) {
@@ -19,7 +21,7 @@ func TestUserUseCase_Register(t *testing.T) {
 	setup := func() (*mocks.MockUserRepository, domain.UserUsecase) {
         mockRepo := new(mocks.MockUserRepository)
         timeout := 2 * time.Second
-        u := usecase.NewUserUseCase(mockRepo, timeout, "secret", 3600)
+        u := usecase.NewUserUseCase(mockRepo, new(mocks.MockSessionRepository), timeout, "secret", 3600)
         return mockRepo, u
     }
 	
@@ -87,10 +89,12 @@ func TestUserUseCase_Register(t *testing.T) {
 }
 
 func TestUserUseCase_Login(t *testing.T) {
+	var mockSessionRepo *mocks.MockSessionRepository
 	setup := func() (*mocks.MockUserRepository, domain.UserUsecase) {
         mockRepo := new(mocks.MockUserRepository)
+        mockSessionRepo = new(mocks.MockSessionRepository)
         timeout := 2 * time.Second
-        u := usecase.NewUserUseCase(mockRepo, timeout, "my_secret_key", 3600)
+        u := usecase.NewUserUseCase(mockRepo, mockSessionRepo, timeout, "my_secret_key", 3600)
         return mockRepo, u
     }
 	// Helper: Pre-hash a password so bcrypt.Compare works
@@ -109,6 +113,9 @@ func TestUserUseCase_Login(t *testing.T) {
 		}
 
 		mockRepo.On("GetByUsername", mock.Anything, username).Return(foundUser, nil)
+		mockSessionRepo.On("Create", mock.Anything, mock.MatchedBy(func(s *domain.Session) bool {
+			return s.UserID == foundUser.ID && s.ExpiresAt.After(s.CreatedAt)
+		})).Return(nil)
 
 		// Execute
 		token, err := u.Login(context.Background(), username, plainPass)
@@ -117,6 +124,38 @@ func TestUserUseCase_Login(t *testing.T) {
 		assert.NoError(t, err)
 		assert.NotEmpty(t, token) // JWT should be generated
 		mockRepo.AssertExpectations(t)
+		mockSessionRepo.AssertExpectations(t)
+	})
+
+	t.Run("SignsTokenVersion", func(t *testing.T) {
+		mockRepo, u := setup()
+		foundUser := &domain.User{ID: primitive.NewObjectID(), Username: "test", Password: hashedPass, TokenVersion: 2}
+		mockRepo.On("GetByUsername", mock.Anything, "test").Return(foundUser, nil)
+		mockSessionRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		token, err := u.Login(context.Background(), "test", plainPass)
+
+		assert.NoError(t, err)
+		claims, err := tokenutil.ExtractClaims(token, "my_secret_key")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, claims.TokenVersion)
+	})
+
+	t.Run("ErrorBanned", func(t *testing.T) {
+		mockRepo, u := setup()
+		bannedAt := time.Now()
+		foundUser := &domain.User{
+			Username: "banned",
+			Password: hashedPass,
+			BannedAt: &bannedAt,
+		}
+		mockRepo.On("GetByUsername", mock.Anything, "banned").Return(foundUser, nil)
+
+		token, err := u.Login(context.Background(), "banned", plainPass)
+
+		assert.Equal(t, domain.ErrUserBanned, err)
+		assert.Empty(t, token)
+		mockSessionRepo.AssertNotCalled(t, "Create")
 	})
 
 	t.Run("ErrorUserNotFound", func(t *testing.T) {
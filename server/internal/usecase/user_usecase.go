@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/tracing"
 	"github.com/Simpolette/HeartSteal/server/utils"
 
 	"golang.org/x/crypto/bcrypt"
@@ -12,27 +13,34 @@ import (
 
 var _ domain.UserUsecase = &userUseCase{}
 
+var tracer = tracing.Tracer("github.com/Simpolette/HeartSteal/server/internal/usecase")
+
 type userUseCase struct {
 	userRepo          domain.UserRepository
+	sessionRepo       domain.SessionRepository
 	contextTimeout    time.Duration
 	accessTokenSecret string
 	accessTokenExpiry int
 }
 
-func NewUserUseCase(userRepo domain.UserRepository, timeout time.Duration, secret string, expiry int) domain.UserUsecase {
+func NewUserUseCase(userRepo domain.UserRepository, sessionRepo domain.SessionRepository, timeout time.Duration, secret string, expiry int) domain.UserUsecase {
 	return &userUseCase{
 		userRepo:          userRepo,
+		sessionRepo:       sessionRepo,
 		contextTimeout:    timeout,
 		accessTokenSecret: secret,
 		accessTokenExpiry: expiry,
 	}
 }
 
-func (u *userUseCase) Register(c context.Context, user *domain.User) error {
-	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+func (u *userUseCase) Register(c context.Context, user *domain.User) (err error) {
+	ctx, span := tracer.Start(c, "userUseCase.Register")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
 	defer cancel()
 
-	_, err := u.userRepo.GetByEmail(ctx, user.Email)
+	_, err = u.userRepo.GetByEmail(ctx, user.Email)
 	if err == nil {
 		return domain.ErrEmailExists
 	}
@@ -42,17 +50,19 @@ func (u *userUseCase) Register(c context.Context, user *domain.User) error {
 		return domain.ErrUsernameExists
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), 10)
+	user.Password, err = hashPassword(ctx, user.Password)
 	if err != nil {
-		return domain.ErrInternalServerError
+		return err
 	}
-	user.Password = string(hashedPassword)
 
 	return u.userRepo.Create(ctx, user)
 }
 
-func (u *userUseCase) Login(c context.Context, username string, password string) (string, error) {
-	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+func (u *userUseCase) Login(c context.Context, username string, password string) (token string, err error) {
+	ctx, span := tracer.Start(c, "userUseCase.Login")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
 	defer cancel()
 
 	user, err := u.userRepo.GetByUsername(ctx, username)
@@ -60,15 +70,43 @@ func (u *userUseCase) Login(c context.Context, username string, password string)
 		return "", domain.ErrInvalidCredentials
 	}
 
+	_, compareSpan := tracer.Start(ctx, "bcrypt.CompareHashAndPassword")
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	compareSpan.End()
 	if err != nil {
 		return "", domain.ErrInvalidCredentials
 	}
 
-	accessToken, err := tokenutil.CreateAccessToken(user.ID.Hex(), u.accessTokenSecret, u.accessTokenExpiry)
+	// Checked after the password so a ban doesn't reveal that an account exists.
+	if user.IsBanned() {
+		return "", domain.ErrUserBanned
+	}
+
+	accessToken, err := tokenutil.CreateAccessToken(user.ID.Hex(), user.TokenVersion, u.accessTokenSecret, u.accessTokenExpiry)
 	if err != nil {
 		return "", domain.ErrInternalServerError
 	}
 
+	now := time.Now().UTC()
+	session := &domain.Session{
+		UserID:    user.ID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Duration(u.accessTokenExpiry) * time.Hour),
+	}
+	if err := u.sessionRepo.Create(ctx, session); err != nil {
+		return "", err
+	}
+
 	return accessToken, nil
-}
\ No newline at end of file
+}
+
+func hashPassword(ctx context.Context, password string) (string, error) {
+	_, span := tracer.Start(ctx, "bcrypt.GenerateFromPassword")
+	defer span.End()
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), 10)
+	if err != nil {
+		return "", domain.ErrInternalServerError
+	}
+	return string(hashed), nil
+}
@@ -0,0 +1,124 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/i18n"
+	"github.com/go-playground/validator/v10"
+)
+
+// Translate turns binding and validation failures into field-level details
+// with default-locale messages. The second return value is false when err is
+// not an input error at all.
+func Translate(err error) ([]domain.FieldError, bool) {
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		details := make([]domain.FieldError, 0, len(verrs))
+		for _, fe := range verrs {
+			key, params := messageKey(fe)
+			details = append(details, newFieldError(fieldPath(fe), fe.Tag(), fe.Param(), key, params))
+		}
+		return details, true
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		typ := jsonType(typeErr.Type.Kind().String())
+		return []domain.FieldError{
+			newFieldError(typeErr.Field, "type", typ, "validation.type", i18n.Params{"param": typ}),
+		}, true
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return []domain.FieldError{
+			newFieldError("body", "json", "", "validation.json", nil),
+		}, true
+	}
+
+	if errors.Is(err, io.EOF) {
+		return []domain.FieldError{
+			newFieldError("body", "required", "", "validation.required", nil),
+		}, true
+	}
+
+	return nil, false
+}
+
+func newFieldError(field, rule, param, key string, params i18n.Params) domain.FieldError {
+	return domain.FieldError{
+		Field:         field,
+		Rule:          rule,
+		Param:         param,
+		Message:       i18n.Default().Translate(i18n.DefaultLocale, key, params),
+		MessageKey:    key,
+		MessageParams: params,
+	}
+}
+
+// fieldPath drops the top-level struct name so nested fields read like
+// "settings.max_players" rather than "createRoomRequest.settings.max_players".
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.Index(ns, "."); i >= 0 {
+		return ns[i+1:]
+	}
+	return fe.Field()
+}
+
+// messageKey maps a failed rule to its catalog key and placeholders.
+func messageKey(fe validator.FieldError) (string, i18n.Params) {
+	params := i18n.Params{"param": fe.Param(), "rule": fe.Tag()}
+
+	switch fe.Tag() {
+	case "required", "email":
+		return "validation." + fe.Tag(), params
+	case "min", "max":
+		if fe.Kind().String() == "string" {
+			params["count"], _ = strconv.Atoi(fe.Param())
+			return "validation." + fe.Tag() + ".string", params
+		}
+		return "validation." + fe.Tag(), params
+	case "len":
+		params["count"], _ = strconv.Atoi(fe.Param())
+		return "validation.len", params
+	case "oneof":
+		params["param"] = strings.ReplaceAll(fe.Param(), " ", ", ")
+		return "validation.oneof", params
+	case "username":
+		if s, ok := fe.Value().(string); ok && IsReservedUsername(s) {
+			return "validation.username.reserved", params
+		}
+		params["min"] = UsernameMinLength
+		params["max"] = UsernameMaxLength
+		return "validation.username", params
+	case "displayname":
+		params["max"] = DisplayNameMaxLength
+		return "validation.displayname", params
+	case "locale":
+		params["param"] = strings.Join(i18n.Default().Locales(), ", ")
+		return "validation.locale", params
+	default:
+		return "validation.default", params
+	}
+}
+
+func jsonType(kind string) string {
+	switch {
+	case strings.HasPrefix(kind, "int"), strings.HasPrefix(kind, "uint"), strings.HasPrefix(kind, "float"):
+		return "number"
+	case kind == "bool":
+		return "boolean"
+	case kind == "slice", kind == "array":
+		return "array"
+	case kind == "struct", kind == "map":
+		return "object"
+	default:
+		return kind
+	}
+}
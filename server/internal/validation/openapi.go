@@ -0,0 +1,32 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/Simpolette/HeartSteal/server/internal/i18n"
+	"github.com/Simpolette/HeartSteal/server/internal/openapi"
+)
+
+// Describe documents the custom rules registered by Register in spec.
+func Describe(spec *openapi.Spec) {
+	spec.Rule("username", func(s *openapi3.Schema) {
+		s.MinLength = UsernameMinLength
+		maxLength := uint64(UsernameMaxLength)
+		s.MaxLength = &maxLength
+		s.Pattern = usernamePattern.String()
+		s.Description = "Letters, digits, '.', '_' or '-'. Reserved names such as admin are rejected."
+	})
+	spec.Rule("displayname", func(s *openapi3.Schema) {
+		s.MinLength = 1
+		maxLength := uint64(DisplayNameMaxLength)
+		s.MaxLength = &maxLength
+		s.Description = "Printable characters without leading or trailing spaces."
+	})
+	spec.Rule("locale", func(s *openapi3.Schema) {
+		s.Description = fmt.Sprintf("BCP 47 tag whose language is one of %s, e.g. vi or vi-VN. Defaults to %s.",
+			strings.Join(i18n.Default().Locales(), ", "), i18n.DefaultLocale)
+	})
+}
@@ -0,0 +1,111 @@
+package validation_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Simpolette/HeartSteal/server/internal/domain"
+	"github.com/Simpolette/HeartSteal/server/internal/validation"
+)
+
+type profileRequest struct {
+	Username    string `json:"username"     binding:"required,username"`
+	DisplayName string `json:"display_name" binding:"omitempty,displayname"`
+	Email       string `json:"email"        binding:"required,email"`
+	Page        int    `form:"page"         binding:"min=1"`
+}
+
+func validate(req profileRequest) []domain.FieldError {
+	validation.Register()
+	err := binding.Validator.ValidateStruct(&req)
+	if err == nil {
+		return nil
+	}
+	details, _ := validation.Translate(err)
+	return details
+}
+
+func TestValidation_Username(t *testing.T) {
+	valid := profileRequest{Email: "a@b.co", Page: 1}
+
+	cases := []struct {
+		name     string
+		username string
+		ok       bool
+		message  string
+	}{
+		{"Valid", "heart_thief.99", true, ""},
+		{"TooShort", "ab", false, "must be 3-20 characters"},
+		{"TooLong", strings.Repeat("a", 21), false, "must be 3-20 characters"},
+		{"BadCharacters", "bad name!", false, "must be 3-20 characters"},
+		{"Reserved", "Admin", false, "is reserved"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := valid
+			req.Username = tc.username
+			details := validate(req)
+
+			if tc.ok {
+				assert.Empty(t, details)
+				return
+			}
+			assert.Len(t, details, 1)
+			assert.Equal(t, "username", details[0].Field)
+			assert.Equal(t, "username", details[0].Rule)
+			assert.Contains(t, details[0].Message, tc.message)
+		})
+	}
+}
+
+func TestValidation_DisplayName(t *testing.T) {
+	valid := profileRequest{Username: "player", Email: "a@b.co", Page: 1}
+
+	cases := []struct {
+		name        string
+		displayName string
+		ok          bool
+	}{
+		{"Empty", "", true},
+		{"Unicode", "Cœur Volé ♥", true},
+		{"LeadingSpace", " Heart", false},
+		{"ControlCharacter", "Heart\u0007", false},
+		{"TooLong", strings.Repeat("♥", 33), false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := valid
+			req.DisplayName = tc.displayName
+			details := validate(req)
+
+			if tc.ok {
+				assert.Empty(t, details)
+				return
+			}
+			assert.Len(t, details, 1)
+			assert.Equal(t, "display_name", details[0].Field)
+		})
+	}
+}
+
+func TestValidation_Translate(t *testing.T) {
+	t.Run("FieldNamesFollowTags", func(t *testing.T) {
+		details := validate(profileRequest{})
+
+		fields := map[string]string{}
+		for _, d := range details {
+			fields[d.Field] = d.Rule
+		}
+		assert.Equal(t, map[string]string{"username": "required", "email": "required", "page": "min"}, fields)
+	})
+
+	t.Run("NotAnInputError", func(t *testing.T) {
+		_, ok := validation.Translate(assert.AnError)
+		assert.False(t, ok)
+	})
+}
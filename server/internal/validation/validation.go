@@ -0,0 +1,112 @@
+package validation
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Simpolette/HeartSteal/server/internal/i18n"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	UsernameMinLength    = 3
+	UsernameMaxLength    = 20
+	DisplayNameMaxLength = 32
+)
+
+var usernamePattern = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
+// reservedUsernames can't be registered because they would impersonate staff
+// or collide with routes and system accounts.
+var reservedUsernames = map[string]struct{}{
+	"admin":         {},
+	"administrator": {},
+	"root":          {},
+	"system":        {},
+	"support":       {},
+	"moderator":     {},
+	"staff":         {},
+	"heartsteal":    {},
+	"api":           {},
+	"me":            {},
+	"null":          {},
+	"undefined":     {},
+}
+
+var registerOnce sync.Once
+
+// Register installs the custom rules and JSON field naming on gin's validator.
+// It is safe to call more than once.
+func Register() {
+	registerOnce.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+
+		v.RegisterTagNameFunc(fieldName)
+		_ = v.RegisterValidation("username", validateUsername)
+		_ = v.RegisterValidation("displayname", validateDisplayName)
+		_ = v.RegisterValidation("locale", validateLocale)
+	})
+}
+
+// fieldName reports the name clients actually send: the json key for bodies,
+// the form key for query strings and the uri key for path parameters.
+func fieldName(f reflect.StructField) string {
+	for _, tag := range []string{"json", "form", "uri"} {
+		name := strings.SplitN(f.Tag.Get(tag), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+func IsReservedUsername(username string) bool {
+	_, ok := reservedUsernames[strings.ToLower(username)]
+	return ok
+}
+
+func validateUsername(fl validator.FieldLevel) bool {
+	username := fl.Field().String()
+	if len(username) < UsernameMinLength || len(username) > UsernameMaxLength {
+		return false
+	}
+	if !usernamePattern.MatchString(username) {
+		return false
+	}
+	return !IsReservedUsername(username)
+}
+
+func validateDisplayName(fl validator.FieldLevel) bool {
+	name := fl.Field().String()
+	if !utf8.ValidString(name) {
+		return false
+	}
+	if n := utf8.RuneCountInString(name); n == 0 || n > DisplayNameMaxLength {
+		return false
+	}
+	if strings.TrimSpace(name) != name {
+		return false
+	}
+	for _, r := range name {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func validateLocale(fl validator.FieldLevel) bool {
+	_, ok := i18n.Default().Supported(fl.Field().String())
+	return ok
+}
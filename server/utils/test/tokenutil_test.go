@@ -0,0 +1,80 @@
+package tokenutil_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tokenutil "github.com/Simpolette/HeartSteal/server/utils"
+)
+
+const secret = "secret"
+
+func sign(t *testing.T, claims jwt.Claims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	require.NoError(t, err)
+	return token
+}
+
+// The ID used to be read from an "id" claim that createToken never writes, so
+// every token the server issued panicked. It is the registered "sub" claim.
+func TestExtractIDFromToken(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		token, err := tokenutil.CreateAccessToken("65f1c0ffee00000000000001", 0, secret, 1)
+		require.NoError(t, err)
+
+		id, err := tokenutil.ExtractIDFromToken(token, secret)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "65f1c0ffee00000000000001", id)
+	})
+
+	t.Run("ErrorMissingSubject", func(t *testing.T) {
+		token := sign(t, jwt.MapClaims{"id": "65f1c0ffee00000000000001", "exp": time.Now().Add(time.Hour).Unix()})
+
+		assert.NotPanics(t, func() {
+			_, err := tokenutil.ExtractIDFromToken(token, secret)
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("ErrorWrongSecret", func(t *testing.T) {
+		token, err := tokenutil.CreateAccessToken("65f1c0ffee00000000000001", 0, "other", 1)
+		require.NoError(t, err)
+
+		_, err = tokenutil.ExtractIDFromToken(token, secret)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("ErrorExpired", func(t *testing.T) {
+		token := sign(t, jwt.RegisteredClaims{Subject: "65f1c0ffee00000000000001", ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute))})
+
+		_, err := tokenutil.ExtractIDFromToken(token, secret)
+
+		assert.ErrorIs(t, err, jwt.ErrTokenExpired)
+	})
+
+	t.Run("ErrorSigningMethod", func(t *testing.T) {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.RegisteredClaims{Subject: "65f1c0ffee00000000000001"}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+		require.NoError(t, err)
+
+		_, err = tokenutil.ExtractIDFromToken(token, secret)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestExtractClaims(t *testing.T) {
+	token, err := tokenutil.CreateAccessToken("65f1c0ffee00000000000001", 3, secret, 1)
+	require.NoError(t, err)
+
+	claims, err := tokenutil.ExtractClaims(token, secret)
+
+	require.NoError(t, err)
+	assert.Equal(t, "65f1c0ffee00000000000001", claims.Subject)
+	assert.Equal(t, 3, claims.TokenVersion)
+}
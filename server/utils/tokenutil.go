@@ -7,11 +7,21 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func createToken(userID string, secret string, expiryHour int) (string, error) {
-	claims := &jwt.RegisteredClaims{
-		Subject:   userID,
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * time.Duration(expiryHour))),
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
+// Claims are the claims of the tokens the server issues: the user ID in the
+// registered "sub" claim and the user's token version in "ver".
+type Claims struct {
+	jwt.RegisteredClaims
+	TokenVersion int `json:"ver,omitempty"`
+}
+
+func createToken(userID string, tokenVersion int, secret string, expiryHour int) (string, error) {
+	claims := &Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * time.Duration(expiryHour))),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+		TokenVersion: tokenVersion,
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -23,12 +33,14 @@ func createToken(userID string, secret string, expiryHour int) (string, error) {
 	return t, nil
 }
 
-func CreateAccessToken(userID string, secret string, expiryHour int) (accessToken string, err error) {
-	return createToken(userID, secret, expiryHour)	
+// CreateAccessToken signs an access token for userID at tokenVersion, which
+// must match the user's current version for the token to be accepted.
+func CreateAccessToken(userID string, tokenVersion int, secret string, expiryHour int) (accessToken string, err error) {
+	return createToken(userID, tokenVersion, secret, expiryHour)
 }
 
 func CreateRefreshToken(userID string, secret string, expiryHour int) (refreshToken string, err error) {
-	return createToken(userID, secret, expiryHour)
+	return createToken(userID, 0, secret, expiryHour)
 }
 
 func IsAuthorized(requestToken string, secret string) (bool, error) {
@@ -45,7 +57,18 @@ func IsAuthorized(requestToken string, secret string) (bool, error) {
 }
 
 func ExtractIDFromToken(requestToken string, secret string) (string, error) {
-	token, err := jwt.Parse(requestToken, func(token *jwt.Token) (interface{}, error) {
+	claims, err := ExtractClaims(requestToken, secret)
+	if err != nil {
+		return "", err
+	}
+	return claims.Subject, nil
+}
+
+// ExtractClaims verifies requestToken and returns its claims. Tokens without
+// a subject are rejected.
+func ExtractClaims(requestToken string, secret string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(requestToken, claims, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
@@ -53,14 +76,17 @@ func ExtractIDFromToken(requestToken string, secret string) (string, error) {
 	})
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
+	if !token.Valid {
+		return nil, fmt.Errorf("invalid Token")
+	}
 
-	if !ok && !token.Valid {
-		return "", fmt.Errorf("invalid Token")
+	// Tokens carry the user ID in the registered "sub" claim (see createToken).
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("invalid Token")
 	}
 
-	return claims["id"].(string), nil
+	return claims, nil
 }